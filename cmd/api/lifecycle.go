@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// lifecycle stops what run started, in order: readiness drains, the
+// servers finish in-flight requests, the background workers stop, and then
+// the resources they used are closed. Each part registers as it is started,
+// so an early return stops exactly what was started.
+type lifecycle struct {
+	// drain fails readiness so load balancers stop sending traffic. It is
+	// nil until the health checker exists.
+	drain      func()
+	drainDelay time.Duration
+	// timeout bounds stopping the servers and the workers.
+	timeout time.Duration
+
+	workerCtx   context.Context
+	stopWorkers context.CancelFunc
+	workers     workerGroup
+
+	servers []func(ctx context.Context) error
+	closers []func() error
+	stopped bool
+}
+
+func newLifecycle(ctx context.Context, drainDelay, timeout time.Duration) *lifecycle {
+	workerCtx, stopWorkers := context.WithCancel(ctx)
+	return &lifecycle{
+		drainDelay:  drainDelay,
+		timeout:     timeout,
+		workerCtx:   workerCtx,
+		stopWorkers: stopWorkers,
+	}
+}
+
+// goWorker runs fn in the background until the workers are stopped.
+func (l *lifecycle) goWorker(fn func(ctx context.Context)) {
+	l.workers.run(func() { fn(l.workerCtx) })
+}
+
+// onStop registers a server. stop should stop accepting work and return
+// once what is in flight finished, cutting it off when ctx is done.
+func (l *lifecycle) onStop(stop func(ctx context.Context) error) {
+	l.servers = append(l.servers, stop)
+}
+
+// onClose registers a resource to release once the servers and the
+// workers stopped. Resources close in the reverse order they registered.
+func (l *lifecycle) onClose(close func() error) {
+	l.closers = append(l.closers, close)
+}
+
+// run blocks until ctx is done or a server fails, then shuts down. Only a
+// done ctx drains first: a failed server is not worth waiting for.
+func (l *lifecycle) run(ctx context.Context, serveErr <-chan error) error {
+	var err error
+	select {
+	case serr := <-serveErr:
+		err = fmt.Errorf("failed to start server: %w", serr)
+	case <-ctx.Done():
+		log.Printf("shutting down, draining for %s", l.drainDelay)
+		if l.drain != nil {
+			l.drain()
+		}
+		time.Sleep(l.drainDelay)
+	}
+	return errors.Join(err, l.shutdown())
+}
+
+// shutdown stops the servers and the workers and closes the resources.
+// Only the first call does anything.
+func (l *lifecycle) shutdown() error {
+	if l.stopped {
+		return nil
+	}
+	l.stopped = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	errs := make([]error, len(l.servers))
+	var wg sync.WaitGroup
+	for i, stop := range l.servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = stop(ctx)
+		}()
+	}
+	wg.Wait()
+
+	l.stopWorkers()
+	if err := l.workers.wait(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("background workers: %w", err))
+	}
+	shutdownErr := errors.Join(errs...)
+	if shutdownErr != nil {
+		log.Printf("shutdown deadline passed: %v", shutdownErr)
+	}
+
+	for i := len(l.closers) - 1; i >= 0; i-- {
+		shutdownErr = errors.Join(shutdownErr, l.closers[i]())
+	}
+	log.Printf("shutdown complete")
+	return shutdownErr
+}
+
+// workerGroup tracks the background workers so shutdown can wait for them.
+type workerGroup struct {
+	wg sync.WaitGroup
+}
+
+func (g *workerGroup) run(fn func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn()
+	}()
+}
+
+// wait returns once every worker returned, or ctx's error if it is done
+// first.
+func (g *workerGroup) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
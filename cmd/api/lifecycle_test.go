@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// events records the shutdown steps in the order they happen.
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.list...)
+}
+
+// testLifecycle registers a server, a worker and two resources that
+// record when they stop into e.
+func testLifecycle(e *events) *lifecycle {
+	lc := newLifecycle(context.Background(), 10*time.Millisecond, time.Second)
+	lc.drain = func() { e.add("drain") }
+	lc.onStop(func(ctx context.Context) error {
+		e.add("server")
+		return nil
+	})
+	lc.goWorker(func(ctx context.Context) {
+		<-ctx.Done()
+		// a worker finishing its run after being stopped
+		time.Sleep(20 * time.Millisecond)
+		e.add("worker")
+	})
+	lc.onClose(func() error {
+		e.add("traces")
+		return nil
+	})
+	lc.onClose(func() error {
+		e.add("database")
+		return nil
+	})
+	return lc
+}
+
+func TestLifecycle_SignalShutsDownInOrder(t *testing.T) {
+	var e events
+	lc := testLifecycle(&e)
+
+	signal, stop := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- lc.run(signal, make(chan error)) }()
+
+	time.Sleep(10 * time.Millisecond)
+	if got := e.get(); len(got) != 0 {
+		t.Fatalf("expected nothing to stop before the signal, got %v", got)
+	}
+	stop()
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	want := []string{"drain", "server", "worker", "database", "traces"}
+	if got := e.get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// the deferred shutdown in run does not close anything twice
+	if err := lc.shutdown(); err != nil {
+		t.Fatalf("expected a second shutdown to do nothing, got %v", err)
+	}
+	if got := e.get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected a second shutdown to do nothing, got %v", got)
+	}
+}
+
+func TestLifecycle_ServerFailureStopsWorkers(t *testing.T) {
+	var e events
+	lc := testLifecycle(&e)
+
+	serveErr := make(chan error, 1)
+	serveErr <- errors.New("address already in use")
+	err := lc.run(context.Background(), serveErr)
+	if err == nil {
+		t.Fatal("expected the server error")
+	}
+
+	// no drain for a server that never served, but the workers are still
+	// waited for before anything closes
+	want := []string{"server", "worker", "database", "traces"}
+	if got := e.get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLifecycle_EarlyReturnStopsWhatStarted(t *testing.T) {
+	var e events
+	lc := testLifecycle(&e)
+
+	// run returning before serving only has the deferred shutdown
+	if err := lc.shutdown(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	want := []string{"server", "worker", "database", "traces"}
+	if got := e.get(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLifecycle_WorkerDeadline(t *testing.T) {
+	var e events
+	lc := newLifecycle(context.Background(), 0, 20*time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	lc.goWorker(func(ctx context.Context) { <-block })
+	lc.onClose(func() error {
+		e.add("database")
+		return nil
+	})
+
+	if err := lc.shutdown(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the worker deadline to be reported, got %v", err)
+	}
+	// resources still close so the process can exit
+	if got := e.get(); !reflect.DeepEqual(got, []string{"database"}) {
+		t.Fatalf("expected the database to close, got %v", got)
+	}
+}
@@ -2,42 +2,72 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 
 	"payment-service/internal/adapters/provider"
+	"payment-service/internal/adapters/pubsub"
 	"payment-service/internal/adapters/sqlite"
+	"payment-service/internal/adapters/storage"
+	"payment-service/internal/adapters/vault"
+	"payment-service/internal/auth"
 	"payment-service/internal/config"
+	"payment-service/internal/core/domain"
 	"payment-service/internal/core/usecase"
+	grpcserver "payment-service/internal/grpc"
+	"payment-service/internal/health"
 	"payment-service/internal/http/handler"
 	"payment-service/internal/http/middleware"
+	"payment-service/internal/http/openapi"
 	"payment-service/internal/http/router"
 	"payment-service/internal/observability"
+	"payment-service/internal/worker"
 
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func run() error {
+func run() (err error) {
 	fmt.Println("Starting Payment Service...")
 	ctx := context.Background()
 
 	// --- load config ---
 	cfg := config.LoadConfig()
 
+	// everything started below is stopped on every way out of run
+	lc := newLifecycle(ctx, cfg.App.DrainDelay, cfg.App.ShutdownTimeout)
+	defer func() {
+		err = errors.Join(err, lc.shutdown())
+	}()
+
 	// Set up OpenTelemetry.
 	otelShutdown, err := observability.SetupOTelSDK(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to setup telemetry: %w", err)
 	}
-	// Handle shutdown properly so nothing leaks.
-	defer func() {
-		_ = errors.Join(err, otelShutdown(context.Background()))
-	}()
+	// spans of the last requests and runs are exported before exiting
+	lc.onClose(func() error {
+		flushCtx, cancel := context.WithTimeout(ctx, traceFlushTimeout)
+		defer cancel()
+		if err := otelShutdown(flushCtx); err != nil {
+			log.Printf("failed to flush traces: %v", err)
+		}
+		return nil
+	})
 
 	// init tracker
 	observability.InitTracer(cfg.App.ServiceName)
@@ -50,7 +80,12 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("failed to connect database: %w", err)
 	}
-	defer db.Close()
+	lc.onClose(func() error {
+		if err := db.Close(); err != nil {
+			return fmt.Errorf("close database: %w", err)
+		}
+		return nil
+	})
 
 	chaosCfg := config.ChaosConfig{
 		Enabled:          true,
@@ -64,44 +99,715 @@ func run() error {
 	paymentRepoWithMetrics := sqlite.NewPaymentRepositoryMetrics(
 		defaultPaymentRepo,
 	)
-	paymentRepo := sqlite.NewPaymentRepositoryChaos(
+	paymentRepoWithChaos := sqlite.NewPaymentRepositoryChaos(
 		paymentRepoWithMetrics,
 		chaosCfg,
 	)
+	// status changes are published once they are stored
+	paymentEvents := pubsub.NewPaymentEventBroker(
+		cfg.Events.History,
+		cfg.Events.Retention,
+	)
+	paymentRepo := sqlite.NewPaymentRepositoryEvents(
+		paymentRepoWithChaos,
+		paymentEvents,
+	)
+	payerRepo := sqlite.NewPayerRepository(db)
+	paymentMethodRepo := sqlite.NewPaymentMethodRepository(db)
+	planRepo := sqlite.NewPlanRepository(db)
+	subscriptionRepo := sqlite.NewSubscriptionRepository(db)
+	paymentLinkRepo := sqlite.NewPaymentLinkRepository(db)
+	virtualAccountRepo := sqlite.NewVirtualAccountRepository(db)
+	ewalletRepo := sqlite.NewEWalletAuthorizationRepository(db)
+	qrCodeRepo := sqlite.NewQRCodeRepository(db)
+	threeDSRepo := sqlite.NewThreeDSRepository(db)
+	riskHistory := sqlite.NewRiskHistory(db)
+	spendingCounter := sqlite.NewSpendingCounter(db)
+	reviewRepo := sqlite.NewReviewRepository(db)
+	disputeRepo := sqlite.NewDisputeRepository(db)
+	ledgerRepo := sqlite.NewLedgerRepository(db)
+	evidenceStore := storage.NewLocalEvidenceStore(cfg.Dispute.EvidenceDir)
+	payoutRepo := sqlite.NewPayoutRepository(db)
+	paymentBatchRepo := sqlite.NewPaymentBatchRepository(db)
+	splitRepo := sqlite.NewSplitRepository(db)
+	balanceRepo := sqlite.NewBalanceRepository(db)
+	merchantRepo := sqlite.NewMerchantRepository(db)
+	apiKeyRepo := sqlite.NewAPIKeyRepository(db)
+
+	// --- card vault ---
+	vaultKey, err := loadVaultKey(cfg.Vault.EncryptionKey, cfg.App.Dev())
+	if err != nil {
+		return fmt.Errorf("invalid vault key: %w", err)
+	}
+	secretBox, err := vault.NewAESGCMBox(vaultKey)
+	if err != nil {
+		return fmt.Errorf("failed to init vault: %w", err)
+	}
+
+	// --- webhook tokens ---
+	bankWebhookToken, err := loadToken("BANK_WEBHOOK_TOKEN", cfg.BankTransfer.WebhookToken, cfg.App.Dev())
+	if err != nil {
+		return err
+	}
+	ewalletWebhookToken, err := loadToken("EWALLET_WEBHOOK_TOKEN", cfg.EWallet.WebhookToken, cfg.App.Dev())
+	if err != nil {
+		return err
+	}
+	qrWebhookToken, err := loadToken("QR_WEBHOOK_TOKEN", cfg.QR.WebhookToken, cfg.App.Dev())
+	if err != nil {
+		return err
+	}
+	disputeWebhookToken, err := loadToken("DISPUTE_WEBHOOK_TOKEN", cfg.Dispute.WebhookToken, cfg.App.Dev())
+	if err != nil {
+		return err
+	}
 
 	// --- payment provider
 	paymentProvider := provider.NewFakePaymentProvider()
+	// every provider call goes through a circuit breaker so an unhealthy
+	// provider fails fast and shows up in readiness
+	chargeProvider := provider.NewCircuitBreaker(
+		paymentProvider,
+		cfg.Provider.CircuitFailures,
+		cfg.Provider.CircuitCooldown,
+	)
+	payoutProvider := provider.NewFakePayoutProvider(cfg.Payout.SettleAfter)
+	fakeWallet := provider.NewFakeWallet(
+		cfg.Checkout.BaseURL,
+		ewalletWebhookToken,
+	)
+	fakeQRNetwork := provider.NewFakeQRNetwork(
+		provider.QRMerchant{
+			ID:           cfg.QR.MerchantID,
+			Name:         cfg.QR.MerchantName,
+			City:         cfg.QR.MerchantCity,
+			Country:      cfg.QR.MerchantCountry,
+			CategoryCode: cfg.QR.MerchantCategory,
+		},
+		cfg.QR.TTL,
+		cfg.Checkout.BaseURL,
+		qrWebhookToken,
+	)
+	fakeACS := provider.NewFakeACS(
+		cfg.Checkout.BaseURL,
+		cfg.ThreeDS.ChallengeThreshold,
+	)
 
 	// --- init usecases ---
 	createPaymentUC := usecase.NewCreatePaymentUsecase(
 		paymentRepo,
-		paymentProvider,
+		payerRepo,
+		paymentMethodRepo,
+		chargeProvider,
+	).WithBankTransfer(
+		chargeProvider,
+		virtualAccountRepo,
+		cfg.BankTransfer.VirtualAccountTTL,
+	).WithEWallet(
+		fakeWallet,
+		ewalletRepo,
+		cfg.EWallet.SessionTTL,
+	).WithQR(
+		fakeQRNetwork,
+		qrCodeRepo,
+	).WithThreeDS(
+		fakeACS,
+		threeDSRepo,
+		cfg.ThreeDS.ChallengeTTL,
+	).WithRisk(
+		riskPolicy(cfg.Risk),
+		riskHistory,
+	).WithSpendingLimits(
+		spendingCounter,
+		spendingLimits(cfg.SpendingLimits),
+	).WithReviews(
+		holdPolicy(cfg.Review),
+		riskHistory,
+		reviewRepo,
+		cfg.Review.SLA,
+	).WithSplits(
+		splitRepo,
+	).WithTransactor(
+		sqlite.NewTransactor(db),
+	)
+	getPaymentUC := usecase.NewGetPaymentUsecase(paymentRepo).WithSplits(splitRepo)
+	watchPaymentUC := usecase.NewWatchPaymentUsecase(paymentRepo, paymentEvents)
+	createPaymentBatchUC := usecase.NewCreatePaymentBatchUsecase(
+		paymentBatchRepo,
+		cfg.Batch.MaxItems,
+	)
+	getPaymentBatchUC := usecase.NewGetPaymentBatchUsecase(paymentBatchRepo)
+	importPaymentsUC := usecase.NewImportPaymentsUsecase(
+		paymentBatchRepo,
+		cfg.Batch.MaxItems,
+		int64(cfg.Batch.MaxFileSize),
+	)
+	processPaymentBatchesUC := usecase.NewProcessPaymentBatchesUsecase(
+		paymentBatchRepo,
+		createPaymentUC,
+		cfg.Batch.Concurrency,
+	)
+	createPayerUC := usecase.NewCreatePayerUsecase(payerRepo)
+	getPayerUC := usecase.NewGetPayerUsecase(payerRepo)
+	listPayersUC := usecase.NewListPayersUsecase(payerRepo)
+	updatePayerUC := usecase.NewUpdatePayerUsecase(payerRepo)
+	deletePayerUC := usecase.NewDeletePayerUsecase(payerRepo)
+	listPayerPaymentsUC := usecase.NewListPayerPaymentsUsecase(
+		payerRepo,
+		paymentRepo,
+	)
+	tokenizeCardUC := usecase.NewTokenizeCardUsecase(
+		payerRepo,
+		paymentMethodRepo,
+		secretBox,
+	)
+	getPaymentMethodUC := usecase.NewGetPaymentMethodUsecase(paymentMethodRepo)
+	listPaymentMethodsUC := usecase.NewListPaymentMethodsUsecase(
+		payerRepo,
+		paymentMethodRepo,
+	)
+	detachPaymentMethodUC := usecase.NewDetachPaymentMethodUsecase(paymentMethodRepo)
+	createPlanUC := usecase.NewCreatePlanUsecase(planRepo)
+	getPlanUC := usecase.NewGetPlanUsecase(planRepo)
+	listPlansUC := usecase.NewListPlansUsecase(planRepo)
+	createSubscriptionUC := usecase.NewCreateSubscriptionUsecase(
+		planRepo,
+		subscriptionRepo,
+		payerRepo,
+		paymentMethodRepo,
 	)
-	getPaymentUC := usecase.NewGetPaymentUsecase(paymentRepo)
+	getSubscriptionUC := usecase.NewGetSubscriptionUsecase(subscriptionRepo)
+	pauseSubscriptionUC := usecase.NewPauseSubscriptionUsecase(subscriptionRepo)
+	resumeSubscriptionUC := usecase.NewResumeSubscriptionUsecase(subscriptionRepo)
+	cancelSubscriptionUC := usecase.NewCancelSubscriptionUsecase(subscriptionRepo)
+	billSubscriptionsUC := usecase.NewBillSubscriptionsUsecase(
+		subscriptionRepo,
+		planRepo,
+		createPaymentUC,
+		usecase.BillingPolicy{
+			Provider:          cfg.Billing.Provider,
+			RetryAfter:        cfg.Billing.RetryAfter,
+			PendingRetryAfter: cfg.Billing.PendingRetryAfter,
+		},
+	)
+	createPaymentLinkUC := usecase.NewCreatePaymentLinkUsecase(paymentLinkRepo)
+	getPaymentLinkUC := usecase.NewGetPaymentLinkUsecase(paymentLinkRepo)
+	deactivatePaymentLinkUC := usecase.NewDeactivatePaymentLinkUsecase(paymentLinkRepo)
+	payPaymentLinkUC := usecase.NewPayPaymentLinkUsecase(
+		paymentLinkRepo,
+		paymentRepo,
+		payerRepo,
+		paymentMethodRepo,
+		tokenizeCardUC,
+		createPaymentUC,
+		cfg.Checkout.Provider,
+	)
+	getVirtualAccountUC := usecase.NewGetVirtualAccountUsecase(virtualAccountRepo)
+	handleBankCreditUC := usecase.NewHandleBankCreditUsecase(
+		virtualAccountRepo,
+		paymentRepo,
+	)
+	handleEWalletCallbackUC := usecase.NewHandleEWalletCallbackUsecase(
+		ewalletRepo,
+		paymentRepo,
+	)
+	getEWalletReturnUC := usecase.NewGetEWalletReturnUsecase(
+		ewalletRepo,
+		paymentRepo,
+	)
+	getPaymentQRUC := usecase.NewGetPaymentQRUsecase(qrCodeRepo, paymentRepo)
+	handleQRPaymentUC := usecase.NewHandleQRPaymentUsecase(qrCodeRepo, paymentRepo)
+	completeThreeDSUC := usecase.NewCompleteThreeDSUsecase(
+		threeDSRepo,
+		paymentRepo,
+		fakeACS,
+		chargeProvider,
+	)
+	listReviewsUC := usecase.NewListReviewsUsecase(paymentRepo)
+	getReviewUC := usecase.NewGetReviewUsecase(paymentRepo, reviewRepo)
+	decideReviewUC := usecase.NewDecideReviewUsecase(
+		paymentRepo,
+		reviewRepo,
+		createPaymentUC,
+	)
+	handleDisputeEventUC := usecase.NewHandleDisputeEventUsecase(
+		disputeRepo,
+		paymentRepo,
+		ledgerRepo,
+		cfg.Dispute.EvidenceWindow,
+		cfg.Dispute.ChargebackFee,
+	)
+	getDisputeUC := usecase.NewGetDisputeUsecase(disputeRepo, ledgerRepo)
+	listPaymentDisputesUC := usecase.NewListPaymentDisputesUsecase(paymentRepo, disputeRepo)
+	uploadDisputeEvidenceUC := usecase.NewUploadDisputeEvidenceUsecase(
+		disputeRepo,
+		evidenceStore,
+		int64(cfg.Dispute.MaxEvidenceSize),
+	)
+	submitDisputeEvidenceUC := usecase.NewSubmitDisputeEvidenceUsecase(disputeRepo)
+	payouts := payoutPolicy(cfg.Payout)
+	getBalancesUC := usecase.NewGetBalancesUsecase(balanceRepo, payouts.SettlementDelay)
+	createPayoutUC := usecase.NewCreatePayoutUsecase(
+		payoutRepo,
+		balanceRepo,
+		merchantRepo,
+		payouts,
+		cfg.Payout.Destination,
+	)
+	getPayoutUC := usecase.NewGetPayoutUsecase(payoutRepo)
+	listPayoutsUC := usecase.NewListPayoutsUsecase(payoutRepo)
+	runPayoutsUC := usecase.NewRunPayoutsUsecase(
+		payoutRepo,
+		balanceRepo,
+		merchantRepo,
+		payoutProvider,
+		payouts,
+		cfg.Payout.Destination,
+		100,
+	)
+	authenticateAPIKeyUC := usecase.NewAuthenticateAPIKeyUsecase(apiKeyRepo, merchantRepo)
+	authenticateServiceTokenUC := usecase.NewAuthenticateServiceTokenUsecase(merchantRepo)
+	createMerchantUC := usecase.NewCreateMerchantUsecase(merchantRepo, apiKeyRepo)
+	issueAPIKeyUC := usecase.NewIssueAPIKeyUsecase(merchantRepo, apiKeyRepo)
+	rotateAPIKeyUC := usecase.NewRotateAPIKeyUsecase(apiKeyRepo, cfg.Auth.KeyRotationGrace)
+	revokeAPIKeyUC := usecase.NewRevokeAPIKeyUsecase(apiKeyRepo)
+	listAPIKeysUC := usecase.NewListAPIKeysUsecase(apiKeyRepo)
+	expirePaymentsUC := usecase.NewExpirePaymentsUsecase(
+		paymentRepo,
+		virtualAccountRepo,
+		100,
+	).WithReviews(reviewRepo)
+
+	// --- background workers ---
+	billingScheduler := worker.NewBillingScheduler(
+		billSubscriptionsUC,
+		cfg.Billing.Interval,
+	)
+	lc.goWorker(billingScheduler.Run)
+
+	expiryScheduler := worker.NewExpiryScheduler(
+		expirePaymentsUC,
+		cfg.BankTransfer.ExpiryInterval,
+	)
+	lc.goWorker(expiryScheduler.Run)
+
+	payoutScheduler := worker.NewPayoutScheduler(
+		runPayoutsUC,
+		cfg.Payout.Interval,
+	)
+	lc.goWorker(payoutScheduler.Run)
+
+	batchScheduler := worker.NewBatchScheduler(
+		processPaymentBatchesUC,
+		cfg.Batch.Interval,
+	)
+	lc.goWorker(batchScheduler.Run)
+
+	// --- readiness ---
+	healthChecker := health.NewChecker(
+		cfg.Health.CheckTimeout,
+		health.Check{Name: "database", Critical: true, Func: db.PingContext},
+		health.Check{Name: "migrations", Critical: true, Func: func(ctx context.Context) error {
+			return sqlite.CheckSchema(ctx, db)
+		}},
+		health.Check{Name: "payment_provider", Func: func(context.Context) error {
+			if state := chargeProvider.State(); state == provider.CircuitOpen {
+				return fmt.Errorf("circuit is %s", state)
+			}
+			return nil
+		}},
+		health.Check{Name: "trace_exporter", Func: func(context.Context) error {
+			return observability.TraceExporterStatus()
+		}},
+	)
+	lc.drain = healthChecker.Drain
 
 	// --- init handlers ---
+	healthHandler := handler.NewHealthHandler(healthChecker)
 	paymentHandler := handler.NewPaymentHandler(
 		createPaymentUC,
 		getPaymentUC,
 	)
+	paymentEventsHandler := handler.NewPaymentEventsHandler(
+		watchPaymentUC,
+		cfg.Events.Heartbeat,
+		cfg.Events.MaxStreams,
+		cfg.Events.MaxStreamsPerMerchant,
+	)
+	paymentBatchHandler := handler.NewPaymentBatchHandler(
+		createPaymentBatchUC,
+		getPaymentBatchUC,
+		importPaymentsUC,
+	)
+	payerHandler := handler.NewPayerHandler(
+		createPayerUC,
+		getPayerUC,
+		listPayersUC,
+		updatePayerUC,
+		deletePayerUC,
+		listPayerPaymentsUC,
+	)
+	paymentMethodHandler := handler.NewPaymentMethodHandler(
+		tokenizeCardUC,
+		getPaymentMethodUC,
+		listPaymentMethodsUC,
+		detachPaymentMethodUC,
+	)
+	subscriptionHandler := handler.NewSubscriptionHandler(
+		createPlanUC,
+		getPlanUC,
+		listPlansUC,
+		createSubscriptionUC,
+		getSubscriptionUC,
+		pauseSubscriptionUC,
+		resumeSubscriptionUC,
+		cancelSubscriptionUC,
+	)
+	paymentLinkHandler := handler.NewPaymentLinkHandler(
+		createPaymentLinkUC,
+		getPaymentLinkUC,
+		deactivatePaymentLinkUC,
+		cfg.Checkout.BaseURL,
+	)
+	checkoutHandler := handler.NewCheckoutHandler(
+		getPaymentLinkUC,
+		payPaymentLinkUC,
+		getPaymentUC,
+		getVirtualAccountUC,
+		cfg.Checkout.BaseURL,
+	)
+	webhookHandler := handler.NewWebhookHandler(
+		handleBankCreditUC,
+		bankWebhookToken,
+	)
+	ewalletHandler := handler.NewEWalletHandler(
+		handleEWalletCallbackUC,
+		getEWalletReturnUC,
+		ewalletWebhookToken,
+	)
+	qrHandler := handler.NewQRHandler(
+		getPaymentQRUC,
+		handleQRPaymentUC,
+		qrWebhookToken,
+	)
+	threeDSHandler := handler.NewThreeDSHandler(completeThreeDSUC)
+	reviewHandler := handler.NewReviewHandler(
+		listReviewsUC,
+		getReviewUC,
+		decideReviewUC,
+	)
+	disputeHandler := handler.NewDisputeHandler(
+		handleDisputeEventUC,
+		getDisputeUC,
+		listPaymentDisputesUC,
+		uploadDisputeEvidenceUC,
+		submitDisputeEvidenceUC,
+		disputeWebhookToken,
+	)
+	payoutHandler := handler.NewPayoutHandler(
+		getBalancesUC,
+		createPayoutUC,
+		getPayoutUC,
+		listPayoutsUC,
+	)
+	merchantHandler := handler.NewMerchantHandler(
+		createMerchantUC,
+		issueAPIKeyUC,
+		rotateAPIKeyUC,
+		revokeAPIKeyUC,
+		listAPIKeysUC,
+	)
+	var jwtVerifier *auth.JWTVerifier
+	if cfg.Auth.JWKSFile != "" {
+		keys, err := auth.LoadJWKS(cfg.Auth.JWKSFile)
+		if err != nil {
+			return fmt.Errorf("failed to load jwks: %w", err)
+		}
+		jwtVerifier = auth.NewJWTVerifier(keys, cfg.Auth.JWTIssuer, cfg.Auth.JWTAudience).
+			WithReload(func() (map[string]auth.JWK, error) {
+				return auth.LoadJWKS(cfg.Auth.JWKSFile)
+			}, cfg.Auth.JWKSReloadInterval)
+	}
+	merchantAuthenticator := auth.NewMerchantAuthenticator(authenticateAPIKeyUC, authenticateServiceTokenUC, jwtVerifier)
+	adminToken := cfg.Auth.AdminToken
+	if jwtVerifier == nil {
+		adminToken, err = loadToken("ADMIN_API_KEY", adminToken, cfg.App.Dev())
+		if err != nil {
+			return fmt.Errorf("%w (or set JWT_JWKS_FILE)", err)
+		}
+	}
+	if len(cfg.Auth.SigningKeys) == 0 {
+		log.Printf("SIGNING_KEYS is not set, request signatures are not checked")
+	}
+
+	// uploads are signed whole, so their routes get room for the largest
+	// file plus the multipart framing
+	requestSigning := middleware.NewRequestSigning(cfg.Auth.SigningKeys, cfg.Auth.SigningTolerance).
+		WithBodyLimit("/v1/disputes/:dispute_id/evidence", int64(cfg.Dispute.MaxEvidenceSize)+multipartOverhead).
+		WithBodyLimit("/v1/payments/imports", int64(cfg.Batch.MaxFileSize)+multipartOverhead)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.App.ValidateResponses {
+		spec.WithResponseValidation()
+	}
+
+	// --- start grpc server ---
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+	if err != nil {
+		return fmt.Errorf("failed to listen for grpc: %w", err)
+	}
+	grpcServer := grpcserver.NewServer(
+		grpcserver.NewPaymentServer(
+			createPaymentUC,
+			getPaymentUC,
+			listPayerPaymentsUC,
+			watchPaymentUC,
+		),
+		grpcserver.NewAuthenticator(merchantAuthenticator),
+	)
+	lc.onStop(func(ctx context.Context) error {
+		return stopGRPC(ctx, grpcServer)
+	})
+	go func() {
+		log.Printf("starting grpc server on :%s", cfg.GRPC.Port)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Printf("grpc server stopped: %v", err)
+		}
+	}()
 
 	// --- init gin ---
-	r := gin.New()
+	r, err := router.NewEngine(cfg.App.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Use(otelgin.Middleware(cfg.App.ServiceName))
 	r.Use(middleware.MetricsMiddleware())
 
 	// --- register routes ---
-	router.Register(r, paymentHandler)
+	router.Register(r, router.Deps{
+		Payments:       paymentHandler,
+		PaymentEvents:  paymentEventsHandler,
+		PaymentBatches: paymentBatchHandler,
+		Payers:         payerHandler,
+		PaymentMethods: paymentMethodHandler,
+		Subscriptions:  subscriptionHandler,
+		PaymentLinks:   paymentLinkHandler,
+		Checkout:       checkoutHandler,
+		Webhooks:       webhookHandler,
+		EWallet:        ewalletHandler,
+		QR:             qrHandler,
+		ThreeDS:        threeDSHandler,
+		Reviews:        reviewHandler,
+		Disputes:       disputeHandler,
+		Payouts:        payoutHandler,
+		Merchants:      merchantHandler,
+		MerchantAuth:   middleware.MerchantAuth(merchantAuthenticator),
+		AdminAuth:      middleware.AdminAuth(adminToken, jwtVerifier),
+		RequestSigning: requestSigning,
+		RateLimiter:    middleware.NewRateLimiter(rateLimits(cfg.RateLimits)),
+		Spec:           spec,
+	})
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.GET("/healthz", healthHandler.Live)
+	r.GET("/readyz", healthHandler.Ready)
+
+	// simulated approval pages for local testing; they let anyone approve
+	// a payment for its payer, so they are only served in dev
+	if cfg.App.Dev() {
+		r.Any("/sim/ewallet/:reference", gin.WrapH(fakeWallet))
+		r.POST("/sim/qr/scan", gin.WrapH(fakeQRNetwork))
+		r.Any("/sim/3ds/:reference", gin.WrapH(fakeACS))
+	}
 
 	// --- start server ---
-	log.Printf("starting http server on :%s", cfg.App.Port)
-	if err := r.Run(":" + cfg.App.Port); err != nil {
-		return fmt.Errorf("failed to start server: %w", err)
+	srv := &http.Server{
+		Addr:              ":" + cfg.App.Port,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// event streams never finish on their own; ending them lets their
+	// clients reconnect elsewhere
+	srv.RegisterOnShutdown(paymentEvents.Close)
+
+	signalCtx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	// a second signal kills the process right away
+	go func() {
+		<-signalCtx.Done()
+		stopSignals()
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("starting http server on :%s", cfg.App.Port)
+		serveErr <- srv.ListenAndServe()
+	}()
+	lc.onStop(func(ctx context.Context) error {
+		if err := srv.Shutdown(ctx); err != nil {
+			_ = srv.Close()
+			return fmt.Errorf("http server: %w", err)
+		}
+		return nil
+	})
+
+	return lc.run(signalCtx, serveErr)
+}
+
+// traceFlushTimeout bounds exporting the spans still buffered at shutdown.
+const traceFlushTimeout = 5 * time.Second
+
+// multipartOverhead is the room left for multipart headers and boundaries
+// around an uploaded file.
+const multipartOverhead = 64 << 10
+
+// stopGRPC lets in-flight calls finish, cutting them off when ctx is done.
+func stopGRPC(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return fmt.Errorf("grpc server: %w", ctx.Err())
+	}
+}
+
+// loadVaultKey decodes the configured vault key. A fixed development key,
+// readable by anyone with the source, is only used when dev is set.
+func loadVaultKey(encoded string, dev bool) ([]byte, error) {
+	if encoded == "" {
+		if !dev {
+			return nil, errors.New("VAULT_ENCRYPTION_KEY is required")
+		}
+		log.Println("VAULT_ENCRYPTION_KEY is not set, using insecure development key")
+		key := sha256.Sum256([]byte("payment-service-dev-vault-key"))
+		return key[:], nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("VAULT_ENCRYPTION_KEY must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// loadToken returns the secret named name that callers must send. Outside
+// dev the service does not start without it; in dev a random token is made
+// up for the run.
+func loadToken(name, token string, dev bool) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+	if !dev {
+		return "", fmt.Errorf("%s is required", name)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token = hex.EncodeToString(b)
+	log.Printf("%s is not set, using %s for this run", name, token)
+	return token, nil
+}
+
+// riskPolicy turns the risk settings into the rules the engine scores
+// payments with.
+func riskPolicy(cfg config.RiskConfig) domain.RiskPolicy {
+	policy := domain.RiskPolicy{
+		PayerVelocity: domain.VelocityRule{
+			Window: cfg.PayerVelocityWindow,
+			Max:    cfg.PayerVelocityMax,
+			Score:  30,
+		},
+		OrderVelocity: domain.VelocityRule{
+			Window: cfg.OrderVelocityWindow,
+			Max:    cfg.OrderVelocityMax,
+			Score:  30,
+		},
+		AmountThresholds:  cfg.AmountThresholds,
+		AmountScore:       40,
+		BlockedPayers:     map[int]bool{},
+		BlockedIPs:        map[string]bool{},
+		BlockedBINs:       map[string]bool{},
+		BlocklistScore:    100,
+		CountryCurrencies: cfg.CountryCurrencies,
+		MismatchScore:     20,
+		ReviewScore:       cfg.ReviewScore,
+		BlockScore:        cfg.BlockScore,
+	}
+	for _, id := range cfg.BlockedPayers {
+		policy.BlockedPayers[id] = true
+	}
+	for _, ip := range cfg.BlockedIPs {
+		policy.BlockedIPs[ip] = true
+	}
+	for _, bin := range cfg.BlockedBINs {
+		policy.BlockedBINs[bin] = true
+	}
+	return policy
+}
+
+func holdPolicy(cfg config.ReviewConfig) domain.HoldPolicy {
+	policy := domain.HoldPolicy{
+		MinAmount:       cfg.HoldAmount,
+		FirstTimePayers: cfg.HoldFirstTimePayers,
+		Methods:         map[string]bool{},
+	}
+	for _, m := range cfg.HoldMethods {
+		policy.Methods[m] = true
+	}
+	return policy
+}
+
+func payoutPolicy(cfg config.PayoutConfig) domain.PayoutPolicy {
+	schedule := domain.PayoutSchedule(cfg.Schedule)
+	switch schedule {
+	case domain.PayoutScheduleDaily, domain.PayoutScheduleWeekly, domain.PayoutScheduleManual:
+	default:
+		log.Printf("unknown payout schedule %q, paying out on request only", cfg.Schedule)
+		schedule = domain.PayoutScheduleManual
+	}
+	return domain.PayoutPolicy{
+		Schedule:        schedule,
+		Weekday:         cfg.Weekday,
+		Minimums:        cfg.Minimums,
+		SettlementDelay: cfg.SettlementDelay,
+	}
+}
+
+func spendingLimits(cfg []config.SpendingLimit) []domain.SpendingLimit {
+	limits := make([]domain.SpendingLimit, 0, len(cfg))
+	for _, l := range cfg {
+		limits = append(limits, domain.SpendingLimit{
+			Name:      l.Name,
+			Method:    l.Method,
+			Currency:  l.Currency,
+			Window:    l.Window,
+			MaxCount:  l.MaxCount,
+			MaxAmount: l.MaxAmount,
+		})
+	}
+	return limits
+}
+
+func rateLimits(cfg map[string]config.RateLimit) map[string]middleware.RateLimit {
+	limits := make(map[string]middleware.RateLimit, len(cfg))
+	for class, l := range cfg {
+		limits[class] = middleware.RateLimit{Rate: l.Rate, Burst: l.Burst, By: l.By}
 	}
-	return nil
+	return limits
 }
 
 func main() {
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"sync"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fails calls straight away until the cooldown is over.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single trial call through to see whether the
+	// provider recovered.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreaker stops calling a payment provider that keeps failing. It
+// opens after threshold failures in a row and tries again after cooldown.
+// Charges and, when the provider issues them, virtual accounts share the
+// circuit.
+type CircuitBreaker struct {
+	next      ports.PaymentProvider
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// trial is set while the half-open trial call is in flight.
+	trial bool
+}
+
+var (
+	_ ports.PaymentProvider        = (*CircuitBreaker)(nil)
+	_ ports.VirtualAccountProvider = (*CircuitBreaker)(nil)
+)
+
+func NewCircuitBreaker(
+	next ports.PaymentProvider,
+	threshold int,
+	cooldown time.Duration,
+) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     CircuitClosed,
+	}
+}
+
+func (b *CircuitBreaker) Process(ctx context.Context, method string) error {
+	return b.call(func() error {
+		return b.next.Process(ctx, method)
+	})
+}
+
+func (b *CircuitBreaker) IssueVirtualAccount(
+	ctx context.Context,
+	bankCode string,
+	paymentID string,
+) (string, error) {
+	va, ok := b.next.(ports.VirtualAccountProvider)
+	if !ok {
+		return "", errors.New("payment provider does not issue virtual accounts")
+	}
+	var number string
+	err := b.call(func() error {
+		var err error
+		number, err = va.IssueVirtualAccount(ctx, bankCode, paymentID)
+		return err
+	})
+	return number, err
+}
+
+// call runs fn unless the circuit is open and records how it went.
+func (b *CircuitBreaker) call(fn func() error) error {
+	ok, trial := b.allow()
+	if !ok {
+		return domain.ErrProviderUnavailable
+	}
+	err := fn()
+	b.record(err, trial)
+	return err
+}
+
+// State is the circuit's state as of now.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a call may go through and whether it is the
+// half-open trial.
+func (b *CircuitBreaker) allow() (ok, trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false, false
+		}
+		b.transition(CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.trial {
+			return false, false
+		}
+		b.trial = true
+		return true, true
+	}
+	return true, false
+}
+
+func (b *CircuitBreaker) record(err error, trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		b.trial = false
+	}
+	// the caller giving up says nothing about the provider
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err == nil {
+		b.failures = 0
+		if b.state != CircuitClosed {
+			b.transition(CircuitClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if trial || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		if b.state != CircuitOpen {
+			b.transition(CircuitOpen)
+		}
+	}
+}
+
+func (b *CircuitBreaker) transition(state CircuitState) {
+	log.Printf("payment provider circuit %s -> %s", b.state, state)
+	b.state = state
+}
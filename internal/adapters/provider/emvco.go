@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EMVCo merchant-presented QR tags used by the fake QR network.
+const (
+	emvPayloadFormat     = "00"
+	emvPointOfInitiation = "01"
+	emvMerchantAccount   = "26"
+	emvMerchantCategory  = "52"
+	emvCurrency          = "53"
+	emvAmount            = "54"
+	emvCountry           = "58"
+	emvMerchantName      = "59"
+	emvMerchantCity      = "60"
+	emvAdditionalData    = "62"
+	emvCRC               = "63"
+
+	// inside the merchant account template
+	emvGUID       = "00"
+	emvMerchantID = "01"
+
+	// inside the additional data template
+	emvBillNumber     = "01"
+	emvReferenceLabel = "05"
+
+	// emvDynamic marks a QR that is valid for one payment only.
+	emvDynamic = "12"
+)
+
+var errInvalidEMVPayload = errors.New("invalid emv qr payload")
+
+// emvCurrencyCodes maps ISO 4217 alpha codes to the numeric codes EMVCo uses.
+var emvCurrencyCodes = map[string]string{
+	"IDR": "360",
+	"MYR": "458",
+	"PHP": "608",
+	"SGD": "702",
+	"THB": "764",
+	"VND": "704",
+	"USD": "840",
+}
+
+// emvField encodes one ID-length-value data object.
+func emvField(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// crc16CCITT is the CRC-16/CCITT-FALSE checksum EMVCo requires.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// withEMVCRC appends the CRC data object. The checksum covers the payload
+// including the CRC tag and length.
+func withEMVCRC(payload string) string {
+	payload += emvCRC + "04"
+	return payload + fmt.Sprintf("%04X", crc16CCITT([]byte(payload)))
+}
+
+// parseEMV splits a payload into its top-level data objects after checking
+// the CRC.
+func parseEMV(payload string) (map[string]string, error) {
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != emvCRC+"04" {
+		return nil, errInvalidEMVPayload
+	}
+	want := fmt.Sprintf("%04X", crc16CCITT([]byte(payload[:len(payload)-4])))
+	if !strings.EqualFold(want, payload[len(payload)-4:]) {
+		return nil, fmt.Errorf("%w: crc mismatch", errInvalidEMVPayload)
+	}
+	return parseEMVObjects(payload[:len(payload)-8])
+}
+
+func parseEMVObjects(s string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for len(s) > 0 {
+		if len(s) < 4 {
+			return nil, errInvalidEMVPayload
+		}
+		n, err := strconv.Atoi(s[2:4])
+		if err != nil || len(s) < 4+n {
+			return nil, errInvalidEMVPayload
+		}
+		fields[s[:2]] = s[4 : 4+n]
+		s = s[4+n:]
+	}
+	return fields, nil
+}
+
+// truncate keeps s within the maximum length of an EMV field.
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
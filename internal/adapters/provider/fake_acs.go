@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"html/template"
+	"log"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var challengePage = template.Must(template.ParseFS(simulatorTemplates, "templates/challenge.html"))
+
+const (
+	fakeACSOTP         = "123456"
+	fakeACSMaxAttempts = 3
+)
+
+// Test cards are recognised by their last four digits.
+const (
+	// always challenged, whatever the amount
+	challengeCardLast4 = "3184"
+	// fails authentication without a challenge
+	declinedCardLast4 = "3063"
+)
+
+// acsSession is a challenge as the fake issuer sees it.
+type acsSession struct {
+	Reference string
+	PaymentID string
+	Brand     string
+	Last4     string
+	Amount    int
+	Currency  string
+	Attempts  int
+	Status    domain.ThreeDSStatus
+}
+
+// FakeACS simulates the issuer's 3-D Secure access control server. Payments
+// at or above the challenge threshold are challenged with a one-time code
+// entered on a page served under /sim/3ds; smaller ones pass frictionless.
+type FakeACS struct {
+	baseURL            string
+	challengeThreshold int
+
+	mu       sync.Mutex
+	sessions map[string]*acsSession
+	mux      *http.ServeMux
+}
+
+var (
+	_ ports.CardAuthenticator = (*FakeACS)(nil)
+	_ http.Handler            = (*FakeACS)(nil)
+)
+
+func NewFakeACS(baseURL string, challengeThreshold int) *FakeACS {
+	a := &FakeACS{
+		baseURL:            strings.TrimRight(baseURL, "/"),
+		challengeThreshold: challengeThreshold,
+		sessions:           make(map[string]*acsSession),
+		mux:                http.NewServeMux(),
+	}
+	a.mux.HandleFunc("GET /sim/3ds/{reference}", a.show)
+	a.mux.HandleFunc("POST /sim/3ds/{reference}", a.verify)
+	return a
+}
+
+func (a *FakeACS) Authenticate(
+	ctx context.Context,
+	payment *domain.Payment,
+	card *domain.PaymentMethod,
+) (*domain.ThreeDSAuthentication, error) {
+	_, span := observability.Tracer().
+		Start(ctx, "CardAuthenticator.Authenticate")
+	defer span.End()
+
+	auth := &domain.ThreeDSAuthentication{
+		Reference: "3ds_" + uuid.NewString(),
+	}
+
+	switch {
+	case card.Last4 == declinedCardLast4:
+		auth.Status = domain.ThreeDSStatusFailed
+		return auth, nil
+	case card.Last4 != challengeCardLast4 && payment.Amount < a.challengeThreshold:
+		auth.Status = domain.ThreeDSStatusAuthenticated
+		return auth, nil
+	}
+
+	auth.Status = domain.ThreeDSStatusChallengeRequired
+	auth.ChallengeURL = a.baseURL + "/sim/3ds/" + auth.Reference
+
+	a.mu.Lock()
+	a.sessions[auth.Reference] = &acsSession{
+		Reference: auth.Reference,
+		PaymentID: payment.PublicID,
+		Brand:     string(card.Brand),
+		Last4:     card.Last4,
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+		Status:    domain.ThreeDSStatusChallengeRequired,
+	}
+	a.mu.Unlock()
+
+	return auth, nil
+}
+
+func (a *FakeACS) Result(ctx context.Context, reference string) (domain.ThreeDSStatus, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.sessions[reference]
+	if !ok {
+		return "", domain.ErrThreeDSNotFound
+	}
+	return s.Status, nil
+}
+
+func (a *FakeACS) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(rw, r)
+}
+
+func (a *FakeACS) show(rw http.ResponseWriter, r *http.Request) {
+	reference := r.PathValue("reference")
+
+	a.mu.Lock()
+	s, ok := a.sessions[reference]
+	var session acsSession
+	if ok {
+		session = *s
+	}
+	a.mu.Unlock()
+
+	if ok && session.Status.IsFinal() {
+		a.finish(rw, r, session)
+		return
+	}
+	a.render(rw, session, ok, "")
+}
+
+func (a *FakeACS) verify(rw http.ResponseWriter, r *http.Request) {
+	reference := r.PathValue("reference")
+
+	a.mu.Lock()
+	s, ok := a.sessions[reference]
+	var session acsSession
+	if ok {
+		if !s.Status.IsFinal() {
+			s.Attempts++
+			switch {
+			case r.FormValue("otp") == fakeACSOTP:
+				s.Status = domain.ThreeDSStatusAuthenticated
+			case s.Attempts >= fakeACSMaxAttempts:
+				s.Status = domain.ThreeDSStatusFailed
+			}
+		}
+		session = *s
+	}
+	a.mu.Unlock()
+
+	if !ok {
+		a.render(rw, session, false, "")
+		return
+	}
+	if !session.Status.IsFinal() {
+		a.render(rw, session, true, "That code is not correct.")
+		return
+	}
+	a.finish(rw, r, session)
+}
+
+// finish sends the payer back to the service to resume the payment.
+func (a *FakeACS) finish(rw http.ResponseWriter, r *http.Request, s acsSession) {
+	http.Redirect(
+		rw,
+		r,
+		a.baseURL+"/v1/payments/"+s.PaymentID+"/3ds/complete",
+		http.StatusSeeOther,
+	)
+}
+
+func (a *FakeACS) render(rw http.ResponseWriter, s acsSession, ok bool, message string) {
+	data := struct {
+		Session      *acsSession
+		Error        string
+		OTP          string
+		AttemptsLeft int
+	}{
+		Error:        message,
+		OTP:          fakeACSOTP,
+		AttemptsLeft: fakeACSMaxAttempts - s.Attempts,
+	}
+	if ok {
+		data.Session = &s
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+	}
+	if err := challengePage.Execute(rw, data); err != nil {
+		log.Printf("fake acs: render failed: %v", err)
+	}
+}
@@ -13,10 +13,15 @@ import (
 
 type FakeProvider struct{}
 
-func NewFakePaymentProvider() ports.PaymentProvider {
+func NewFakePaymentProvider() *FakeProvider {
 	return &FakeProvider{}
 }
 
+var (
+	_ ports.PaymentProvider        = (*FakeProvider)(nil)
+	_ ports.VirtualAccountProvider = (*FakeProvider)(nil)
+)
+
 func (p *FakeProvider) Process(ctx context.Context, method string) error {
 	ctx, span := observability.Tracer().
 		Start(ctx, "PaymentProvider.Process")
@@ -25,10 +30,6 @@ func (p *FakeProvider) Process(ctx context.Context, method string) error {
 	switch method {
 	case "credit_card":
 		time.Sleep(100 * time.Millisecond)
-	case "bank_transfer":
-		time.Sleep(200 * time.Millisecond)
-	case "ewallet":
-		time.Sleep(400 * time.Millisecond)
 	}
 
 	if rand.Float64() < 0.15 {
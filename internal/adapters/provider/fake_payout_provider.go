@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"math/rand/v2"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sentPayout is a payout as the fake bank sees it.
+type sentPayout struct {
+	sentAt time.Time
+	failed bool
+}
+
+// FakePayoutProvider simulates a bank transfer rail. Payouts stay in transit
+// for settleAfter and then succeed, except for a small share that bounce.
+type FakePayoutProvider struct {
+	settleAfter time.Duration
+
+	mu      sync.Mutex
+	payouts map[string]sentPayout
+}
+
+var _ ports.PayoutProvider = (*FakePayoutProvider)(nil)
+
+func NewFakePayoutProvider(settleAfter time.Duration) *FakePayoutProvider {
+	return &FakePayoutProvider{
+		settleAfter: settleAfter,
+		payouts:     make(map[string]sentPayout),
+	}
+}
+
+func (p *FakePayoutProvider) Send(ctx context.Context, payout *domain.Payout) (string, error) {
+	_, span := observability.Tracer().Start(ctx, "PayoutProvider.Send")
+	defer span.End()
+
+	reference := "tr_" + uuid.NewString()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.payouts[reference] = sentPayout{
+		sentAt: time.Now(),
+		failed: rand.Float64() < 0.05,
+	}
+	return reference, nil
+}
+
+func (p *FakePayoutProvider) Status(ctx context.Context, reference string) (ports.PayoutResult, error) {
+	_, span := observability.Tracer().Start(ctx, "PayoutProvider.Status")
+	defer span.End()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sent, ok := p.payouts[reference]
+	if !ok {
+		// transfers are forgotten on restart; the fake bank settles them
+		return ports.PayoutResult{Status: domain.PayoutStatusPaid}, nil
+	}
+	if time.Since(sent.sentAt) < p.settleAfter {
+		return ports.PayoutResult{Status: domain.PayoutStatusInTransit}, nil
+	}
+
+	delete(p.payouts, reference)
+	if sent.failed {
+		return ports.PayoutResult{
+			Status:        domain.PayoutStatusFailed,
+			FailureReason: "rejected by the beneficiary bank",
+		}, nil
+	}
+	return ports.PayoutResult{Status: domain.PayoutStatusPaid}, nil
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QRMerchant is the merchant identity printed into every QR payload.
+type QRMerchant struct {
+	ID      string
+	Name    string
+	City    string
+	Country string
+	// CategoryCode is the ISO 18245 merchant category code.
+	CategoryCode string
+}
+
+const fakeQRNetworkGUID = "COM.PAYMENTSERVICE.FAKEQR"
+
+// FakeQRNetwork issues EMVCo dynamic QR codes and simulates a payer's app
+// scanning and paying one through POST /sim/qr/scan.
+type FakeQRNetwork struct {
+	merchant      QRMerchant
+	ttl           time.Duration
+	baseURL       string
+	callbackToken string
+	client        *http.Client
+	mux           *http.ServeMux
+}
+
+var (
+	_ ports.QRProvider = (*FakeQRNetwork)(nil)
+	_ http.Handler     = (*FakeQRNetwork)(nil)
+)
+
+// NewFakeQRNetwork returns a network whose codes are valid for ttl and
+// whose scan simulator pays them by calling back baseURL.
+func NewFakeQRNetwork(
+	merchant QRMerchant,
+	ttl time.Duration,
+	baseURL string,
+	callbackToken string,
+) *FakeQRNetwork {
+	n := &FakeQRNetwork{
+		merchant:      merchant,
+		ttl:           ttl,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		callbackToken: callbackToken,
+		client:        &http.Client{Timeout: 5 * time.Second},
+		mux:           http.NewServeMux(),
+	}
+	n.mux.HandleFunc("POST /sim/qr/scan", n.scan)
+	return n
+}
+
+func (n *FakeQRNetwork) IssueQR(
+	ctx context.Context,
+	payment *domain.Payment,
+) (*domain.QRCode, error) {
+	_, span := observability.Tracer().
+		Start(ctx, "QRProvider.IssueQR")
+	defer span.End()
+
+	currency, ok := emvCurrencyCodes[strings.ToUpper(payment.Currency)]
+	if !ok {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+
+	var b [10]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	reference := strings.ToUpper(hex.EncodeToString(b[:]))
+
+	account := emvField(emvGUID, fakeQRNetworkGUID) +
+		emvField(emvMerchantID, n.merchant.ID)
+	additional := emvField(emvBillNumber, reference) +
+		emvField(emvReferenceLabel, truncate(payment.PublicID, 25))
+
+	payload := withEMVCRC(
+		emvField(emvPayloadFormat, "01") +
+			emvField(emvPointOfInitiation, emvDynamic) +
+			emvField(emvMerchantAccount, account) +
+			emvField(emvMerchantCategory, n.merchant.CategoryCode) +
+			emvField(emvCurrency, currency) +
+			emvField(emvAmount, strconv.Itoa(payment.Amount)) +
+			emvField(emvCountry, n.merchant.Country) +
+			emvField(emvMerchantName, truncate(n.merchant.Name, 25)) +
+			emvField(emvMerchantCity, truncate(n.merchant.City, 15)) +
+			emvField(emvAdditionalData, additional),
+	)
+
+	return &domain.QRCode{
+		PaymentID: payment.PublicID,
+		Reference: reference,
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(n.ttl),
+	}, nil
+}
+
+func (n *FakeQRNetwork) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	n.mux.ServeHTTP(rw, r)
+}
+
+type qrScanRequest struct {
+	Payload string `json:"payload"`
+}
+
+// scan plays the payer's banking app: it reads the payload, checks the
+// CRC and pays the encoded amount.
+func (n *FakeQRNetwork) scan(rw http.ResponseWriter, r *http.Request) {
+	var req qrScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fields, err := parseEMV(strings.TrimSpace(req.Payload))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	additional, err := parseEMVObjects(fields[emvAdditionalData])
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	amount, err := strconv.Atoi(fields[emvAmount])
+	if err != nil {
+		http.Error(rw, "payload has no amount", http.StatusBadRequest)
+		return
+	}
+
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = postCallback(
+		r.Context(),
+		n.client,
+		n.baseURL+"/v1/webhooks/qr",
+		n.callbackToken,
+		map[string]any{
+			"reference":        additional[emvBillNumber],
+			"amount":           amount,
+			"issuer_reference": "iss_" + hex.EncodeToString(b[:]),
+		},
+	)
+	if err != nil {
+		http.Error(rw, "payment failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(map[string]any{
+		"paid":      true,
+		"reference": additional[emvBillNumber],
+		"amount":    amount,
+	})
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"embed"
+	"html/template"
+	"log"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//go:embed templates/*.html
+var simulatorTemplates embed.FS
+
+var walletPage = template.Must(template.ParseFS(simulatorTemplates, "templates/wallet.html"))
+
+const defaultWallet = "FAKEPAY"
+
+var supportedWallets = map[string]bool{
+	defaultWallet: true,
+	"DANA":        true,
+	"GOPAY":       true,
+	"LINKAJA":     true,
+	"OVO":         true,
+	"SHOPEEPAY":   true,
+}
+
+// walletSession is an approval request as the fake wallet sees it.
+type walletSession struct {
+	Reference string
+	PaymentID string
+	Wallet    string
+	Amount    int
+	Currency  string
+	// State is pending, acknowledged, approved or declined.
+	State string
+}
+
+// FakeWallet simulates an e-wallet for local testing. Approval requests are
+// kept in memory and approved on a page served under /sim/ewallet, which
+// calls the service back like a real wallet would.
+type FakeWallet struct {
+	baseURL       string
+	callbackToken string
+	client        *http.Client
+
+	mu       sync.Mutex
+	sessions map[string]*walletSession
+	mux      *http.ServeMux
+}
+
+var (
+	_ ports.EWalletProvider = (*FakeWallet)(nil)
+	_ http.Handler          = (*FakeWallet)(nil)
+)
+
+// NewFakeWallet returns a wallet that links to and calls back baseURL,
+// sending callbackToken with every callback.
+func NewFakeWallet(baseURL string, callbackToken string) *FakeWallet {
+	w := &FakeWallet{
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		callbackToken: callbackToken,
+		client:        &http.Client{Timeout: 5 * time.Second},
+		sessions:      make(map[string]*walletSession),
+		mux:           http.NewServeMux(),
+	}
+	w.mux.HandleFunc("GET /sim/ewallet/{reference}", w.show)
+	w.mux.HandleFunc("POST /sim/ewallet/{reference}", w.decide)
+	return w
+}
+
+func (w *FakeWallet) AuthorizeEWallet(
+	ctx context.Context,
+	payment *domain.Payment,
+	auth *domain.EWalletAuthorization,
+) error {
+	_, span := observability.Tracer().
+		Start(ctx, "EWalletProvider.AuthorizeEWallet")
+	defer span.End()
+
+	if auth.Wallet == "" {
+		auth.Wallet = defaultWallet
+	}
+	if !supportedWallets[auth.Wallet] {
+		return domain.ErrUnsupportedWallet
+	}
+
+	reference := "ewr_" + uuid.NewString()
+	approvalURL := w.baseURL + "/sim/ewallet/" + reference
+
+	auth.Reference = reference
+	auth.NextAction = domain.NextAction{
+		Type:        domain.NextActionTypeForChannel(auth.Channel),
+		RedirectURL: approvalURL,
+		Deeplink:    strings.ToLower(auth.Wallet) + "://pay?reference=" + reference,
+		// scanning the code opens the same approval page
+		QRString: approvalURL,
+	}
+
+	w.mu.Lock()
+	w.sessions[reference] = &walletSession{
+		Reference: reference,
+		PaymentID: payment.PublicID,
+		Wallet:    auth.Wallet,
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+		State:     "pending",
+	}
+	w.mu.Unlock()
+
+	return nil
+}
+
+func (w *FakeWallet) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mux.ServeHTTP(rw, r)
+}
+
+// session returns a copy of the session so it can be read without the lock.
+func (w *FakeWallet) session(reference string) (walletSession, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, ok := w.sessions[reference]
+	if !ok {
+		return walletSession{}, false
+	}
+	return *s, true
+}
+
+// advance moves a session from one of the from states to state and reports
+// whether it did.
+func (w *FakeWallet) advance(reference, state string, from ...string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, ok := w.sessions[reference]
+	if !ok {
+		return false
+	}
+	for _, f := range from {
+		if s.State == f {
+			s.State = state
+			return true
+		}
+	}
+	return false
+}
+
+// show renders the approval page. Opening it is the moment the payer's
+// wallet acknowledges the request.
+func (w *FakeWallet) show(rw http.ResponseWriter, r *http.Request) {
+	reference := r.PathValue("reference")
+
+	s, ok := w.session(reference)
+	if ok && w.advance(reference, "acknowledged", "pending") {
+		if err := w.callback(r.Context(), s, domain.EWalletEventAcknowledged); err != nil {
+			log.Printf("fake wallet: acknowledge callback failed: %v", err)
+		}
+		s.State = "acknowledged"
+	}
+
+	w.render(rw, reference, s, ok)
+}
+
+func (w *FakeWallet) decide(rw http.ResponseWriter, r *http.Request) {
+	reference := r.PathValue("reference")
+
+	s, ok := w.session(reference)
+	if !ok {
+		w.render(rw, reference, s, false)
+		return
+	}
+
+	event, state := domain.EWalletEventDeclined, "declined"
+	if r.FormValue("action") == "approve" {
+		event, state = domain.EWalletEventApproved, "approved"
+	}
+
+	if w.advance(reference, state, "pending", "acknowledged") {
+		if err := w.callback(r.Context(), s, event); err != nil {
+			// let the payer retry
+			w.advance(reference, s.State, state)
+			http.Error(rw, "wallet could not reach the merchant: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	http.Redirect(
+		rw,
+		r,
+		w.baseURL+"/v1/payments/"+s.PaymentID+"/return",
+		http.StatusSeeOther,
+	)
+}
+
+func (w *FakeWallet) render(rw http.ResponseWriter, reference string, s walletSession, ok bool) {
+	data := struct {
+		Wallet    string
+		Reference string
+		Session   *walletSession
+		Done      bool
+	}{
+		Wallet:    defaultWallet,
+		Reference: reference,
+	}
+	if ok {
+		data.Wallet = s.Wallet
+		data.Session = &s
+		data.Done = s.State == "approved" || s.State == "declined"
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+	}
+	if err := walletPage.Execute(rw, data); err != nil {
+		log.Printf("fake wallet: render failed: %v", err)
+	}
+}
+
+// callback notifies the service the way a wallet's webhook would.
+func (w *FakeWallet) callback(
+	ctx context.Context,
+	s walletSession,
+	event domain.EWalletEvent,
+) error {
+	return postCallback(
+		ctx,
+		w.client,
+		w.baseURL+"/v1/webhooks/ewallet",
+		w.callbackToken,
+		map[string]string{
+			"payment_id": s.PaymentID,
+			"reference":  s.Reference,
+			"event":      string(event),
+		},
+	)
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+	"strings"
+)
+
+const virtualAccountLength = 16
+
+// bankPrefixes are the company codes the fake banks put in front of every
+// virtual account they issue.
+var bankPrefixes = map[string]string{
+	"BCA":     "39358",
+	"BNI":     "8808",
+	"BRI":     "26215",
+	"MANDIRI": "88908",
+	"PERMATA": "8528",
+}
+
+func (p *FakeProvider) IssueVirtualAccount(
+	ctx context.Context,
+	bankCode string,
+	paymentID string,
+) (string, error) {
+	_, span := observability.Tracer().
+		Start(ctx, "PaymentProvider.IssueVirtualAccount")
+	defer span.End()
+
+	prefix, ok := bankPrefixes[strings.ToUpper(bankCode)]
+	if !ok {
+		return "", domain.ErrUnsupportedBank
+	}
+
+	// derive the customer part from the payment so issuing is repeatable
+	sum := sha256.Sum256([]byte(strings.ToUpper(bankCode) + ":" + paymentID))
+	width := virtualAccountLength - len(prefix)
+
+	mod := uint64(1)
+	for i := 0; i < width; i++ {
+		mod *= 10
+	}
+	n := binary.BigEndian.Uint64(sum[:8]) % mod
+
+	return fmt.Sprintf("%s%0*d", prefix, width, n), nil
+}
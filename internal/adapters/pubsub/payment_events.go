@@ -0,0 +1,147 @@
+// Package pubsub passes events between the requests of one instance of the
+// service.
+package pubsub
+
+import (
+	"sync"
+	"time"
+
+	"payment-service/internal/core/domain"
+)
+
+// subscriberBuffer is how many events a subscriber may lag behind before it
+// is dropped.
+const subscriberBuffer = 16
+
+type subscriber struct {
+	events chan domain.PaymentEvent
+}
+
+type topic struct {
+	history []domain.PaymentEvent
+	subs    map[*subscriber]struct{}
+	last    time.Time
+}
+
+// PaymentEventBroker keeps the last few events of every payment and hands
+// new ones to the payment's subscribers. It lives in memory, so subscribers
+// only see status changes made by the same instance.
+type PaymentEventBroker struct {
+	mu     sync.Mutex
+	topics map[string]*topic
+	pruned time.Time
+	closed bool
+
+	history   int
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewPaymentEventBroker keeps up to history events per payment for
+// retention after the last one was published.
+func NewPaymentEventBroker(history int, retention time.Duration) *PaymentEventBroker {
+	return &PaymentEventBroker{
+		topics:    make(map[string]*topic),
+		history:   history,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+func (b *PaymentEventBroker) Publish(event domain.PaymentEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+
+	t := b.topic(event.Payment.PublicID)
+	t.last = now
+	t.history = append(t.history, event)
+	if len(t.history) > b.history {
+		t.history = t.history[len(t.history)-b.history:]
+	}
+
+	for s := range t.subs {
+		select {
+		case s.events <- event:
+		default:
+			// the subscriber resumes from the history once it reconnects
+			close(s.events)
+			delete(t.subs, s)
+		}
+	}
+}
+
+func (b *PaymentEventBroker) Subscribe(
+	paymentID string,
+	afterID int64,
+) ([]domain.PaymentEvent, <-chan domain.PaymentEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t := b.topic(paymentID)
+	t.last = b.now()
+
+	var past []domain.PaymentEvent
+	for _, e := range t.history {
+		if e.ID > afterID {
+			past = append(past, e)
+		}
+	}
+
+	s := &subscriber{events: make(chan domain.PaymentEvent, subscriberBuffer)}
+	if b.closed {
+		close(s.events)
+		return past, s.events, func() {}
+	}
+	t.subs[s] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := t.subs[s]; ok {
+			close(s.events)
+			delete(t.subs, s)
+		}
+	}
+	return past, s.events, cancel
+}
+
+// Close drops every subscriber, so open streams end and their clients
+// reconnect to another instance. Subscribing afterwards gets a closed
+// channel.
+func (b *PaymentEventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, t := range b.topics {
+		for s := range t.subs {
+			close(s.events)
+			delete(t.subs, s)
+		}
+	}
+}
+
+func (b *PaymentEventBroker) topic(paymentID string) *topic {
+	t, ok := b.topics[paymentID]
+	if !ok {
+		t = &topic{subs: make(map[*subscriber]struct{})}
+		b.topics[paymentID] = t
+	}
+	return t
+}
+
+// prune forgets payments nobody watches that had no events for a while.
+func (b *PaymentEventBroker) prune(now time.Time) {
+	if now.Sub(b.pruned) < b.retention {
+		return
+	}
+	for id, t := range b.topics {
+		if len(t.subs) == 0 && now.Sub(t.last) >= b.retention {
+			delete(b.topics, id)
+		}
+	}
+	b.pruned = now
+}
@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const apiKeyColumns = `
+		id, merchant_id, mode, prefix, hash,
+		created_at, expires_at, revoked_at`
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) ports.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var k domain.APIKey
+	var expiresAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&k.ID,
+		&k.MerchantID,
+		&k.Mode,
+		&k.Prefix,
+		&k.Hash,
+		&k.CreatedAt,
+		&expiresAt,
+		&revokedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	k.ExpiresAt = timePtr(expiresAt)
+	k.RevokedAt = timePtr(revokedAt)
+
+	return &k, nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, k *domain.APIKey) error {
+	ctx, span := observability.Tracer().Start(ctx, "apiKeyRepository.Create")
+	defer span.End()
+
+	k.CreatedAt = time.Now()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO api_keys (merchant_id, mode, prefix, hash, created_at, expires_at, revoked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		k.MerchantID,
+		k.Mode,
+		k.Prefix,
+		k.Hash,
+		k.CreatedAt,
+		nullTime(k.ExpiresAt),
+		nullTime(k.RevokedAt),
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	k.ID = int(id)
+
+	return nil
+}
+
+func (r *apiKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	ctx, span := observability.Tracer().Start(ctx, "apiKeyRepository.FindByHash")
+	defer span.End()
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE hash = ?`
+
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, hash))
+}
+
+func (r *apiKeyRepository) FindByID(ctx context.Context, id int) (*domain.APIKey, error) {
+	ctx, span := observability.Tracer().Start(ctx, "apiKeyRepository.FindByID")
+	defer span.End()
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = ?`
+
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *apiKeyRepository) ListByMerchantID(
+	ctx context.Context,
+	merchantID int,
+) ([]*domain.APIKey, error) {
+	ctx, span := observability.Tracer().Start(ctx, "apiKeyRepository.ListByMerchantID")
+	defer span.End()
+
+	query := `
+	SELECT ` + apiKeyColumns + `
+	FROM api_keys
+	WHERE merchant_id = ?
+	ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*domain.APIKey, 0)
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) Expire(ctx context.Context, id int, at time.Time) error {
+	ctx, span := observability.Tracer().Start(ctx, "apiKeyRepository.Expire")
+	defer span.End()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE api_keys SET expires_at = ?
+		WHERE id = ? AND (expires_at IS NULL OR expires_at > ?)`,
+		at.UTC(),
+		id,
+		at.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.requireKey(ctx, res, id)
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int, at time.Time) error {
+	ctx, span := observability.Tracer().Start(ctx, "apiKeyRepository.Revoke")
+	defer span.End()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`,
+		at.UTC(),
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.requireKey(ctx, res, id)
+}
+
+// requireKey tells a missing key apart from an update that had nothing to
+// change, which is not an error.
+func (r *apiKeyRepository) requireKey(ctx context.Context, res sql.Result, id int) error {
+	affected, err := res.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+	_, err = r.FindByID(ctx, id)
+	return err
+}
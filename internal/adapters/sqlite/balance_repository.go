@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+// balanceQuery adds up, per currency, a merchant's successful live payments,
+// ledger adjustments and payouts that did not fail. paid_at is stored in
+// local time, so both sides are normalised with datetime() before comparing.
+const balanceQuery = `
+WITH movements (currency, available, pending, in_transit) AS (
+	SELECT
+		currency,
+		CASE WHEN datetime(COALESCE(paid_at, updated_at)) <= datetime(?) THEN amount ELSE 0 END,
+		CASE WHEN datetime(COALESCE(paid_at, updated_at)) > datetime(?) THEN amount ELSE 0 END,
+		0
+	FROM payments
+	WHERE status = ? AND mode = ?
+	  AND (? = 0 OR merchant_id = ?)
+
+	UNION ALL
+
+	SELECT currency, amount, 0, 0
+	FROM ledger_entries
+	WHERE (? = 0 OR merchant_id = ?)
+
+	UNION ALL
+
+	SELECT
+		currency,
+		-amount,
+		0,
+		CASE WHEN status IN (?, ?) THEN amount ELSE 0 END
+	FROM payouts
+	WHERE status != ?
+	  AND (? = 0 OR merchant_id = ?)
+)
+SELECT currency, SUM(available), SUM(pending), SUM(in_transit)
+FROM movements
+WHERE ? = '' OR currency = ?
+GROUP BY currency
+ORDER BY currency
+`
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func queryBalances(
+	ctx context.Context,
+	q queryer,
+	merchantID int,
+	settledBefore time.Time,
+	currency string,
+) ([]domain.Balance, error) {
+	settledBefore = settledBefore.UTC()
+
+	rows, err := q.QueryContext(
+		ctx,
+		balanceQuery,
+		settledBefore,
+		settledBefore,
+		domain.PaymentStatusSuccess,
+		domain.ModeLive,
+		merchantID, merchantID,
+		merchantID, merchantID,
+		domain.PayoutStatusPending,
+		domain.PayoutStatusInTransit,
+		domain.PayoutStatusFailed,
+		merchantID, merchantID,
+		currency,
+		currency,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := make([]domain.Balance, 0)
+	for rows.Next() {
+		var b domain.Balance
+		if err := rows.Scan(&b.Currency, &b.Available, &b.Pending, &b.InTransit); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+
+	return balances, rows.Err()
+}
+
+type balanceRepository struct {
+	db *sql.DB
+}
+
+func NewBalanceRepository(db *sql.DB) ports.BalanceRepository {
+	return &balanceRepository{db: db}
+}
+
+func (r *balanceRepository) Balances(
+	ctx context.Context,
+	settledBefore time.Time,
+) ([]domain.Balance, error) {
+	ctx, span := observability.Tracer().Start(ctx, "balanceRepository.Balances")
+	defer span.End()
+
+	return queryBalances(ctx, r.db, merchantScope(ctx), settledBefore, "")
+}
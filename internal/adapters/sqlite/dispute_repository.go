@@ -0,0 +1,301 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const disputeColumns = `
+		id, public_id, payment_id, reference,
+		reason, amount, currency, status,
+		evidence_due_by, created_at, updated_at, closed_at,
+		merchant_id`
+
+type disputeRepository struct {
+	db *sql.DB
+}
+
+func NewDisputeRepository(db *sql.DB) ports.DisputeRepository {
+	return &disputeRepository{db: db}
+}
+
+func scanDispute(row rowScanner) (*domain.Dispute, error) {
+	var d domain.Dispute
+	var closedAt sql.NullTime
+
+	err := row.Scan(
+		&d.ID,
+		&d.PublicID,
+		&d.PaymentID,
+		&d.Reference,
+		&d.Reason,
+		&d.Amount,
+		&d.Currency,
+		&d.Status,
+		&d.EvidenceDueBy,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&closedAt,
+		&d.MerchantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrDisputeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.ClosedAt = timePtr(closedAt)
+	return &d, nil
+}
+
+func (r *disputeRepository) Create(ctx context.Context, d *domain.Dispute) error {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	d.MerchantID = merchantID
+	d.CreatedAt = now
+	d.UpdatedAt = now
+
+	query := `
+	INSERT INTO disputes (
+	public_id,
+	payment_id,
+	reference,
+	reason,
+	amount,
+	currency,
+	status,
+	evidence_due_by,
+	created_at,
+	updated_at,
+	merchant_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		d.PublicID,
+		d.PaymentID,
+		d.Reference,
+		d.Reason,
+		d.Amount,
+		d.Currency,
+		d.Status,
+		d.EvidenceDueBy.UTC(),
+		d.CreatedAt,
+		d.UpdatedAt,
+		d.MerchantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	d.ID = int(id)
+
+	return nil
+}
+
+func (r *disputeRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.Dispute, error) {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.FindByPublicID")
+	defer span.End()
+
+	query := `SELECT ` + disputeColumns + ` FROM disputes
+	WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanDispute(r.db.QueryRowContext(ctx, query, publicID, scope, scope))
+}
+
+func (r *disputeRepository) FindByReference(
+	ctx context.Context,
+	reference string,
+) (*domain.Dispute, error) {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.FindByReference")
+	defer span.End()
+
+	query := `SELECT ` + disputeColumns + ` FROM disputes
+	WHERE reference = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanDispute(r.db.QueryRowContext(ctx, query, reference, scope, scope))
+}
+
+func (r *disputeRepository) ListByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) ([]*domain.Dispute, error) {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.ListByPaymentID")
+	defer span.End()
+
+	query := `SELECT ` + disputeColumns + ` FROM disputes
+	WHERE payment_id = ? AND (? = 0 OR merchant_id = ?)
+	ORDER BY id`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(ctx, query, paymentID, scope, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	disputes := make([]*domain.Dispute, 0)
+	for rows.Next() {
+		d, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, d)
+	}
+
+	return disputes, rows.Err()
+}
+
+func (r *disputeRepository) UpdateStatus(
+	ctx context.Context,
+	d *domain.Dispute,
+	from domain.DisputeStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.UpdateStatus")
+	defer span.End()
+
+	query := `
+	UPDATE disputes SET
+		status = ?,
+		updated_at = ?,
+		closed_at = ?
+	WHERE id = ? AND status = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		d.Status,
+		d.UpdatedAt,
+		nullTime(d.ClosedAt),
+		d.ID,
+		from,
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrInvalidDisputeStatus
+	}
+
+	return nil
+}
+
+func (r *disputeRepository) AddEvidence(
+	ctx context.Context,
+	ev *domain.DisputeEvidence,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.AddEvidence")
+	defer span.End()
+
+	ev.CreatedAt = time.Now()
+
+	query := `
+	INSERT INTO dispute_evidence (
+	dispute_id,
+	file_name,
+	content_type,
+	size,
+	path,
+	note,
+	created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		ev.DisputeID,
+		ev.FileName,
+		ev.ContentType,
+		ev.Size,
+		ev.Path,
+		ev.Note,
+		ev.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	ev.ID = int(id)
+
+	return nil
+}
+
+func (r *disputeRepository) ListEvidence(
+	ctx context.Context,
+	disputeID int,
+) ([]*domain.DisputeEvidence, error) {
+	ctx, span := observability.Tracer().Start(ctx, "disputeRepository.ListEvidence")
+	defer span.End()
+
+	query := `
+	SELECT id, dispute_id, file_name, content_type, size, path, note, created_at
+	FROM dispute_evidence
+	WHERE dispute_id = ?
+	ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	evidence := make([]*domain.DisputeEvidence, 0)
+	for rows.Next() {
+		var ev domain.DisputeEvidence
+		err := rows.Scan(
+			&ev.ID,
+			&ev.DisputeID,
+			&ev.FileName,
+			&ev.ContentType,
+			&ev.Size,
+			&ev.Path,
+			&ev.Note,
+			&ev.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, &ev)
+	}
+
+	return evidence, rows.Err()
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const ewalletAuthorizationColumns = `
+		id, payment_id, wallet, channel, reference, return_url,
+		action_type, redirect_url, deeplink, qr_string,
+		created_at, updated_at`
+
+type ewalletAuthorizationRepository struct {
+	db *sql.DB
+}
+
+func NewEWalletAuthorizationRepository(db *sql.DB) ports.EWalletAuthorizationRepository {
+	return &ewalletAuthorizationRepository{db: db}
+}
+
+func scanEWalletAuthorization(row rowScanner) (*domain.EWalletAuthorization, error) {
+	var a domain.EWalletAuthorization
+	var returnURL, redirectURL, deeplink, qrString sql.NullString
+
+	err := row.Scan(
+		&a.ID,
+		&a.PaymentID,
+		&a.Wallet,
+		&a.Channel,
+		&a.Reference,
+		&returnURL,
+		&a.NextAction.Type,
+		&redirectURL,
+		&deeplink,
+		&qrString,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrEWalletAuthorizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.ReturnURL = returnURL.String
+	a.NextAction.RedirectURL = redirectURL.String
+	a.NextAction.Deeplink = deeplink.String
+	a.NextAction.QRString = qrString.String
+
+	return &a, nil
+}
+
+func (r *ewalletAuthorizationRepository) Create(
+	ctx context.Context,
+	a *domain.EWalletAuthorization,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "ewalletAuthorizationRepository.Create")
+	defer span.End()
+
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	query := `
+	INSERT INTO ewallet_authorizations (
+	payment_id,
+	wallet,
+	channel,
+	reference,
+	return_url,
+	action_type,
+	redirect_url,
+	deeplink,
+	qr_string,
+	created_at,
+	updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		a.PaymentID,
+		a.Wallet,
+		a.Channel,
+		a.Reference,
+		a.ReturnURL,
+		a.NextAction.Type,
+		a.NextAction.RedirectURL,
+		a.NextAction.Deeplink,
+		a.NextAction.QRString,
+		a.CreatedAt,
+		a.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = int(id)
+
+	return nil
+}
+
+func (r *ewalletAuthorizationRepository) FindByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) (*domain.EWalletAuthorization, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ewalletAuthorizationRepository.FindByPaymentID")
+	defer span.End()
+
+	query := `SELECT ` + ewalletAuthorizationColumns + ` FROM ewallet_authorizations WHERE payment_id = ?`
+
+	return scanEWalletAuthorization(conn(ctx, r.db).QueryRowContext(ctx, query, paymentID))
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+type ledgerRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) ports.LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+func (r *ledgerRepository) Post(ctx context.Context, entries []*domain.LedgerEntry) error {
+	ctx, span := observability.Tracer().Start(ctx, "ledgerRepository.Post")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+	for _, e := range entries {
+		e.CreatedAt = now
+		res, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO ledger_entries (
+			payment_id, type, amount, currency, reference, description, created_at, merchant_id
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(reference) DO NOTHING`,
+			e.PaymentID,
+			e.Type,
+			e.Amount,
+			e.Currency,
+			e.Reference,
+			e.Description,
+			e.CreatedAt,
+			merchantID,
+		)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 1 {
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			e.ID = int(id)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ledgerRepository) ListByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) ([]*domain.LedgerEntry, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ledgerRepository.ListByPaymentID")
+	defer span.End()
+
+	query := `
+	SELECT id, payment_id, type, amount, currency, reference, description, created_at
+	FROM ledger_entries
+	WHERE payment_id = ?
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY id
+	`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(ctx, query, paymentID, scope, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.LedgerEntry, 0)
+	for rows.Next() {
+		var e domain.LedgerEntry
+		err := rows.Scan(
+			&e.ID,
+			&e.PaymentID,
+			&e.Type,
+			&e.Amount,
+			&e.Currency,
+			&e.Reference,
+			&e.Description,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const merchantColumns = `
+		id, public_id, name, status, payout_destination, created_at`
+
+type merchantRepository struct {
+	db *sql.DB
+}
+
+func NewMerchantRepository(db *sql.DB) ports.MerchantRepository {
+	return &merchantRepository{db: db}
+}
+
+func scanMerchant(row rowScanner) (*domain.Merchant, error) {
+	var m domain.Merchant
+
+	err := row.Scan(
+		&m.ID,
+		&m.PublicID,
+		&m.Name,
+		&m.Status,
+		&m.PayoutDestination,
+		&m.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrMerchantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *merchantRepository) Create(ctx context.Context, m *domain.Merchant) error {
+	ctx, span := observability.Tracer().Start(ctx, "merchantRepository.Create")
+	defer span.End()
+
+	m.CreatedAt = time.Now()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO merchants (public_id, name, status, payout_destination, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		m.PublicID,
+		m.Name,
+		m.Status,
+		m.PayoutDestination,
+		m.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	m.ID = int(id)
+
+	return nil
+}
+
+func (r *merchantRepository) FindByID(ctx context.Context, id int) (*domain.Merchant, error) {
+	ctx, span := observability.Tracer().Start(ctx, "merchantRepository.FindByID")
+	defer span.End()
+
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE id = ?`
+
+	return scanMerchant(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *merchantRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.Merchant, error) {
+	ctx, span := observability.Tracer().Start(ctx, "merchantRepository.FindByPublicID")
+	defer span.End()
+
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE public_id = ?`
+
+	return scanMerchant(r.db.QueryRowContext(ctx, query, publicID))
+}
+
+func (r *merchantRepository) ListActive(ctx context.Context) ([]*domain.Merchant, error) {
+	ctx, span := observability.Tracer().Start(ctx, "merchantRepository.ListActive")
+	defer span.End()
+
+	query := `
+	SELECT ` + merchantColumns + `
+	FROM merchants
+	WHERE status = ?
+	ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.MerchantStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	merchants := make([]*domain.Merchant, 0)
+	for rows.Next() {
+		m, err := scanMerchant(rows)
+		if err != nil {
+			return nil, err
+		}
+		merchants = append(merchants, m)
+	}
+
+	return merchants, rows.Err()
+}
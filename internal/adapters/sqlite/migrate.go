@@ -1,14 +1,106 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	_ "embed"
+	"fmt"
 )
 
 //go:embed schema.sql
 var schema string
 
+// migrations alter tables that already exist in schema.sql. They are applied
+// in order on top of it and the number applied so far is tracked in
+// PRAGMA user_version, so append only — never edit or reorder entries.
+var migrations = []string{
+	`ALTER TABLE payments ADD COLUMN payment_method_token TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN expires_at DATETIME`,
+	`CREATE INDEX IF NOT EXISTS idx_payments_status_expires_at
+		ON payments(status, expires_at)`,
+	`ALTER TABLE payments ADD COLUMN three_ds_status TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN liability_shift INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE payments ADD COLUMN risk_score INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE payments ADD COLUMN risk_decision TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN risk_rules TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX IF NOT EXISTS idx_payments_payer_id_created_at
+		ON payments(payer_id, created_at)`,
+	// rows that predate merchants belong to the default merchant
+	`INSERT OR IGNORE INTO merchants (id, public_id, name, status, created_at)
+		VALUES (1, 'mer_default', 'Default merchant', 'active', CURRENT_TIMESTAMP)`,
+	`ALTER TABLE payments ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE payments ADD COLUMN mode TEXT NOT NULL DEFAULT 'live'`,
+	`ALTER TABLE payers ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE payment_methods ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE plans ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE subscriptions ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE payment_links ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE disputes ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE ledger_entries ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	`ALTER TABLE payouts ADD COLUMN merchant_id INTEGER NOT NULL DEFAULT 1`,
+	// idempotency keys and payer references only need to be unique within
+	// a merchant
+	`DROP INDEX IF EXISTS ux_payments_idempotency`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_merchant_idempotency
+		ON payments(merchant_id, idempotency_key)`,
+	`DROP INDEX IF EXISTS ux_payers_external_reference`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS ux_payers_merchant_external_reference
+		ON payers(merchant_id, external_reference)
+		WHERE external_reference <> ''`,
+	`CREATE INDEX IF NOT EXISTS idx_payouts_merchant_currency
+		ON payouts(merchant_id, currency)`,
+	`ALTER TABLE payments ADD COLUMN created_by TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payment_batches ADD COLUMN source TEXT NOT NULL DEFAULT 'api'`,
+	`ALTER TABLE payment_batches ADD COLUMN file_name TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payment_batches ADD COLUMN client_ip TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN bank_code TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN wallet TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN channel TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN return_url TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE payments ADD COLUMN off_session INTEGER NOT NULL DEFAULT 0`,
+}
+
 func migrate(db *sql.DB) error {
-	_, err := db.Exec(schema)
-	return err
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		// PRAGMA does not accept bind parameters
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckSchema reports whether db is reachable and has every migration
+// applied, e.g. not rolled back by an older release sharing the file.
+func CheckSchema(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version != len(migrations) {
+		return fmt.Errorf("schema is at version %d, want %d", version, len(migrations))
+	}
+	return nil
 }
@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+	"time"
+)
+
+const payerColumns = `
+		id, name, email, country, external_reference,
+		status, created_at, updated_at, merchant_id`
+
+type payerRepository struct {
+	db *sql.DB
+}
+
+func NewPayerRepository(db *sql.DB) ports.PayerRepository {
+	return &payerRepository{db: db}
+}
+
+func scanPayer(row rowScanner) (*domain.Payer, error) {
+	var p domain.Payer
+
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Email,
+		&p.Country,
+		&p.ExternalReference,
+		&p.Status,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&p.MerchantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPayerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func isUniqueConstraintError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func (r *payerRepository) Create(ctx context.Context, p *domain.Payer) error {
+	ctx, span := observability.Tracer().Start(ctx, "payerRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	p.MerchantID = merchantID
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	query := `
+	INSERT INTO payers (
+	name,
+	email,
+	country,
+	external_reference,
+	status,
+	created_at,
+	updated_at,
+	merchant_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		p.Name,
+		p.Email,
+		p.Country,
+		p.ExternalReference,
+		p.Status,
+		p.CreatedAt,
+		p.UpdatedAt,
+		p.MerchantID,
+	)
+	if isUniqueConstraintError(err) {
+		return domain.ErrPayerAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID = int(id)
+
+	return nil
+}
+
+func (r *payerRepository) Update(ctx context.Context, p *domain.Payer) error {
+	ctx, span := observability.Tracer().Start(ctx, "payerRepository.Update")
+	defer span.End()
+
+	p.UpdatedAt = time.Now()
+
+	query := `
+	UPDATE payers SET
+		name = ?,
+		email = ?,
+		country = ?,
+		external_reference = ?,
+		status = ?,
+		updated_at = ?
+	WHERE id = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		p.Name,
+		p.Email,
+		p.Country,
+		p.ExternalReference,
+		p.Status,
+		p.UpdatedAt,
+		p.ID,
+		scope, scope,
+	)
+	if isUniqueConstraintError(err) {
+		return domain.ErrPayerAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPayerNotFound
+	}
+
+	return nil
+}
+
+func (r *payerRepository) FindByID(
+	ctx context.Context,
+	id int,
+) (*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payerRepository.FindByID")
+	defer span.End()
+
+	query := `
+	SELECT ` + payerColumns + `
+	FROM payers
+	WHERE id = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	return scanPayer(r.db.QueryRowContext(ctx, query, id, scope, scope))
+}
+
+func (r *payerRepository) FindByEmail(
+	ctx context.Context,
+	email string,
+) (*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payerRepository.FindByEmail")
+	defer span.End()
+
+	query := `
+	SELECT ` + payerColumns + `
+	FROM payers
+	WHERE email = ? COLLATE NOCASE
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY id
+	LIMIT 1
+	`
+
+	scope := merchantScope(ctx)
+	return scanPayer(r.db.QueryRowContext(ctx, query, email, scope, scope))
+}
+
+func (r *payerRepository) List(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payerRepository.List")
+	defer span.End()
+
+	query := `
+	SELECT ` + payerColumns + `
+	FROM payers
+	WHERE (? = 0 OR merchant_id = ?)
+	ORDER BY id
+	LIMIT ? OFFSET ?
+	`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(ctx, query, scope, scope, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payers := make([]*domain.Payer, 0)
+	for rows.Next() {
+		p, err := scanPayer(rows)
+		if err != nil {
+			return nil, err
+		}
+		payers = append(payers, p)
+	}
+
+	return payers, rows.Err()
+}
@@ -0,0 +1,392 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const paymentBatchColumns = `
+		id, public_id, merchant_id, mode, created_by, client_ip, source, file_name,
+		status, created_at, updated_at, completed_at`
+
+const paymentBatchItemColumns = `
+		id, batch_id, position, idempotency_key, request, status, attempts,
+		payment_id, payment_status, error_code, error`
+
+// batchItemRequest is how an item's payment fields are kept in the request
+// column.
+type batchItemRequest struct {
+	OrderID            string              `json:"order_id"`
+	PayerID            int                 `json:"payer_id"`
+	Amount             int                 `json:"amount"`
+	Currency           string              `json:"currency"`
+	Provider           string              `json:"provider"`
+	Method             string              `json:"method,omitempty"`
+	PaymentMethodToken string              `json:"payment_method,omitempty"`
+	BankCode           string              `json:"bank_code,omitempty"`
+	Wallet             string              `json:"wallet,omitempty"`
+	Channel            string              `json:"channel,omitempty"`
+	ReturnURL          string              `json:"return_url,omitempty"`
+	Splits             []domain.SplitShare `json:"splits,omitempty"`
+}
+
+type paymentBatchRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentBatchRepository(db *sql.DB) ports.PaymentBatchRepository {
+	return &paymentBatchRepository{db: db}
+}
+
+func scanPaymentBatch(row rowScanner) (*domain.PaymentBatch, error) {
+	var b domain.PaymentBatch
+	var completedAt sql.NullTime
+
+	err := row.Scan(
+		&b.ID,
+		&b.PublicID,
+		&b.MerchantID,
+		&b.Mode,
+		&b.CreatedBy,
+		&b.ClientIP,
+		&b.Source,
+		&b.FileName,
+		&b.Status,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+		&completedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPaymentBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b.CompletedAt = timePtr(completedAt)
+	return &b, nil
+}
+
+func scanPaymentBatchItem(row rowScanner) (domain.PaymentBatchItem, error) {
+	var item domain.PaymentBatchItem
+	var request string
+
+	err := row.Scan(
+		&item.ID,
+		&item.BatchID,
+		&item.Position,
+		&item.IdempotencyKey,
+		&request,
+		&item.Status,
+		&item.Attempts,
+		&item.PaymentID,
+		&item.PaymentStatus,
+		&item.ErrorCode,
+		&item.Error,
+	)
+	if err != nil {
+		return item, err
+	}
+
+	var req batchItemRequest
+	if err := json.Unmarshal([]byte(request), &req); err != nil {
+		return item, err
+	}
+	item.OrderID = req.OrderID
+	item.PayerID = req.PayerID
+	item.Amount = req.Amount
+	item.Currency = req.Currency
+	item.Provider = req.Provider
+	item.Method = req.Method
+	item.PaymentMethodToken = req.PaymentMethodToken
+	item.BankCode = req.BankCode
+	item.Wallet = req.Wallet
+	item.Channel = req.Channel
+	item.ReturnURL = req.ReturnURL
+	item.Splits = req.Splits
+	return item, nil
+}
+
+func (r *paymentBatchRepository) Create(ctx context.Context, b *domain.PaymentBatch) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentBatchRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+	b.MerchantID = merchantID
+	b.CreatedAt = now
+	b.UpdatedAt = now
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO payment_batches (
+		public_id, merchant_id, mode, created_by, client_ip, source, file_name,
+		status, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.PublicID,
+		b.MerchantID,
+		b.Mode,
+		b.CreatedBy,
+		b.ClientIP,
+		b.Source,
+		b.FileName,
+		b.Status,
+		b.CreatedAt,
+		b.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	b.ID = int(id)
+
+	stmt, err := tx.PrepareContext(
+		ctx,
+		`INSERT INTO payment_batch_items (
+		batch_id, position, idempotency_key, request, status,
+		error_code, error, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range b.Items {
+		item := &b.Items[i]
+		request, err := json.Marshal(batchItemRequest{
+			OrderID:            item.OrderID,
+			PayerID:            item.PayerID,
+			Amount:             item.Amount,
+			Currency:           item.Currency,
+			Provider:           item.Provider,
+			Method:             item.Method,
+			PaymentMethodToken: item.PaymentMethodToken,
+			BankCode:           item.BankCode,
+			Wallet:             item.Wallet,
+			Channel:            item.Channel,
+			ReturnURL:          item.ReturnURL,
+			Splits:             item.Splits,
+		})
+		if err != nil {
+			return err
+		}
+
+		item.BatchID = b.ID
+		res, err := stmt.ExecContext(
+			ctx,
+			item.BatchID,
+			item.Position,
+			item.IdempotencyKey,
+			string(request),
+			item.Status,
+			item.ErrorCode,
+			item.Error,
+			now,
+		)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		item.ID = int(id)
+	}
+
+	return tx.Commit()
+}
+
+func (r *paymentBatchRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.PaymentBatch, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentBatchRepository.FindByPublicID")
+	defer span.End()
+
+	scope := merchantScope(ctx)
+	b, err := scanPaymentBatch(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+paymentBatchColumns+`
+		FROM payment_batches
+		WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`,
+		publicID,
+		scope, scope,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	b.Items, err = r.listItems(ctx, b.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *paymentBatchRepository) ListProcessing(
+	ctx context.Context,
+	limit int,
+) ([]*domain.PaymentBatch, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentBatchRepository.ListProcessing")
+	defer span.End()
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+paymentBatchColumns+`
+		FROM payment_batches
+		WHERE status = ? AND (? = 0 OR merchant_id = ?)
+		ORDER BY id
+		LIMIT ?`,
+		domain.PaymentBatchStatusProcessing,
+		scope, scope,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []*domain.PaymentBatch
+	for rows.Next() {
+		b, err := scanPaymentBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, b := range batches {
+		b.Items, err = r.listItems(ctx, b.ID, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return batches, nil
+}
+
+// listItems returns the batch's items in submitted order, only the pending
+// ones when pendingOnly is set.
+func (r *paymentBatchRepository) listItems(
+	ctx context.Context,
+	batchID int,
+	pendingOnly bool,
+) ([]domain.PaymentBatchItem, error) {
+	query := `SELECT ` + paymentBatchItemColumns + `
+		FROM payment_batch_items
+		WHERE batch_id = ?`
+	args := []any{batchID}
+	if pendingOnly {
+		query += ` AND status = ?`
+		args = append(args, domain.PaymentBatchItemPending)
+	}
+	query += ` ORDER BY position`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.PaymentBatchItem, 0)
+	for rows.Next() {
+		item, err := scanPaymentBatchItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *paymentBatchRepository) UpdateItem(
+	ctx context.Context,
+	item *domain.PaymentBatchItem,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentBatchRepository.UpdateItem")
+	defer span.End()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE payment_batch_items SET
+			status = ?,
+			attempts = ?,
+			payment_id = ?,
+			payment_status = ?,
+			error_code = ?,
+			error = ?,
+			updated_at = ?
+		WHERE id = ?`,
+		item.Status,
+		item.Attempts,
+		item.PaymentID,
+		item.PaymentStatus,
+		item.ErrorCode,
+		item.Error,
+		time.Now(),
+		item.ID,
+	)
+	return err
+}
+
+func (r *paymentBatchRepository) Complete(
+	ctx context.Context,
+	b *domain.PaymentBatch,
+	now time.Time,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentBatchRepository.Complete")
+	defer span.End()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE payment_batches SET status = ?, updated_at = ?, completed_at = ?
+		WHERE id = ? AND status = ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM payment_batch_items WHERE batch_id = ? AND status = ?
+		  )`,
+		domain.PaymentBatchStatusCompleted,
+		now,
+		now,
+		b.ID,
+		domain.PaymentBatchStatusProcessing,
+		b.ID,
+		domain.PaymentBatchItemPending,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		b.Status = domain.PaymentBatchStatusCompleted
+		b.UpdatedAt = now
+		b.CompletedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const paymentLinkColumns = `
+		id, public_id, amount, currency, description,
+		single_use, usage_count, status, expires_at,
+		created_at, updated_at, merchant_id`
+
+type paymentLinkRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentLinkRepository(db *sql.DB) ports.PaymentLinkRepository {
+	return &paymentLinkRepository{db: db}
+}
+
+func scanPaymentLink(row rowScanner) (*domain.PaymentLink, error) {
+	var l domain.PaymentLink
+	var expiresAt sql.NullTime
+
+	err := row.Scan(
+		&l.ID,
+		&l.PublicID,
+		&l.Amount,
+		&l.Currency,
+		&l.Description,
+		&l.SingleUse,
+		&l.UsageCount,
+		&l.Status,
+		&expiresAt,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+		&l.MerchantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPaymentLinkNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l.ExpiresAt = timePtr(expiresAt)
+
+	return &l, nil
+}
+
+func (r *paymentLinkRepository) Create(
+	ctx context.Context,
+	l *domain.PaymentLink,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentLinkRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	l.MerchantID = merchantID
+	l.CreatedAt = now
+	l.UpdatedAt = now
+
+	query := `
+	INSERT INTO payment_links (
+	public_id,
+	amount,
+	currency,
+	description,
+	single_use,
+	usage_count,
+	status,
+	expires_at,
+	created_at,
+	updated_at,
+	merchant_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		l.PublicID,
+		l.Amount,
+		l.Currency,
+		l.Description,
+		l.SingleUse,
+		l.UsageCount,
+		l.Status,
+		nullTime(l.ExpiresAt),
+		l.CreatedAt,
+		l.UpdatedAt,
+		l.MerchantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	l.ID = int(id)
+
+	return nil
+}
+
+func (r *paymentLinkRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.PaymentLink, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentLinkRepository.FindByPublicID")
+	defer span.End()
+
+	query := `SELECT ` + paymentLinkColumns + ` FROM payment_links
+	WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanPaymentLink(r.db.QueryRowContext(ctx, query, publicID, scope, scope))
+}
+
+func (r *paymentLinkRepository) UpdateStatus(
+	ctx context.Context,
+	publicID string,
+	status domain.PaymentLinkStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentLinkRepository.UpdateStatus")
+	defer span.End()
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE payment_links SET status = ?, updated_at = ?
+		WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`,
+		status,
+		time.Now(),
+		publicID,
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPaymentLinkNotFound
+	}
+
+	return nil
+}
+
+func (r *paymentLinkRepository) Claim(
+	ctx context.Context,
+	publicID string,
+	now time.Time,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentLinkRepository.Claim")
+	defer span.End()
+
+	// the status check and the update happen in one statement so two
+	// concurrent checkouts can never both use a single-use link
+	query := `
+	UPDATE payment_links SET
+		usage_count = usage_count + 1,
+		status = CASE WHEN single_use = 1 THEN ? ELSE status END,
+		updated_at = ?
+	WHERE public_id = ?
+	  AND status = ?
+	  AND (expires_at IS NULL OR expires_at > ?)
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		domain.PaymentLinkStatusCompleted,
+		time.Now(),
+		publicID,
+		domain.PaymentLinkStatusActive,
+		now.UTC(),
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPaymentLinkUnavailable
+	}
+
+	return nil
+}
+
+func (r *paymentLinkRepository) Release(
+	ctx context.Context,
+	publicID string,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentLinkRepository.Release")
+	defer span.End()
+
+	query := `
+	UPDATE payment_links SET
+		usage_count = usage_count - 1,
+		status = CASE WHEN single_use = 1 AND status = ? THEN ? ELSE status END,
+		updated_at = ?
+	WHERE public_id = ? AND usage_count > 0
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		domain.PaymentLinkStatusCompleted,
+		domain.PaymentLinkStatusActive,
+		time.Now(),
+		publicID,
+		scope, scope,
+	)
+	return err
+}
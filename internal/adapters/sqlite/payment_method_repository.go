@@ -0,0 +1,242 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const paymentMethodColumns = `
+		id, token, payer_id, type,
+		brand, last4, bin, exp_month, exp_year, holder_name,
+		encrypted_pan, fingerprint, status,
+		created_at, updated_at`
+
+type paymentMethodRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentMethodRepository(db *sql.DB) ports.PaymentMethodRepository {
+	return &paymentMethodRepository{db: db}
+}
+
+func scanPaymentMethod(row rowScanner) (*domain.PaymentMethod, error) {
+	var m domain.PaymentMethod
+
+	err := row.Scan(
+		&m.ID,
+		&m.Token,
+		&m.PayerID,
+		&m.Type,
+		&m.Brand,
+		&m.Last4,
+		&m.BIN,
+		&m.ExpMonth,
+		&m.ExpYear,
+		&m.HolderName,
+		&m.EncryptedPAN,
+		&m.Fingerprint,
+		&m.Status,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPaymentMethodNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *paymentMethodRepository) Create(
+	ctx context.Context,
+	m *domain.PaymentMethod,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentMethodRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+
+	query := `
+	INSERT INTO payment_methods (
+	token,
+	payer_id,
+	type,
+	brand,
+	last4,
+	bin,
+	exp_month,
+	exp_year,
+	holder_name,
+	encrypted_pan,
+	fingerprint,
+	status,
+	created_at,
+	updated_at,
+	merchant_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		m.Token,
+		m.PayerID,
+		m.Type,
+		m.Brand,
+		m.Last4,
+		m.BIN,
+		m.ExpMonth,
+		m.ExpYear,
+		m.HolderName,
+		m.EncryptedPAN,
+		m.Fingerprint,
+		m.Status,
+		m.CreatedAt,
+		m.UpdatedAt,
+		merchantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	m.ID = int(id)
+
+	return nil
+}
+
+func (r *paymentMethodRepository) FindByToken(
+	ctx context.Context,
+	token string,
+) (*domain.PaymentMethod, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentMethodRepository.FindByToken")
+	defer span.End()
+
+	query := `
+	SELECT ` + paymentMethodColumns + `
+	FROM payment_methods
+	WHERE token = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	return scanPaymentMethod(r.db.QueryRowContext(ctx, query, token, scope, scope))
+}
+
+func (r *paymentMethodRepository) FindByFingerprint(
+	ctx context.Context,
+	payerID int,
+	fingerprint string,
+) (*domain.PaymentMethod, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentMethodRepository.FindByFingerprint")
+	defer span.End()
+
+	query := `
+	SELECT ` + paymentMethodColumns + `
+	FROM payment_methods
+	WHERE payer_id = ? AND fingerprint = ? AND status = ?
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY id DESC
+	LIMIT 1
+	`
+
+	scope := merchantScope(ctx)
+	return scanPaymentMethod(r.db.QueryRowContext(
+		ctx,
+		query,
+		payerID,
+		fingerprint,
+		domain.PaymentMethodStatusActive,
+		scope, scope,
+	))
+}
+
+func (r *paymentMethodRepository) ListByPayerID(
+	ctx context.Context,
+	payerID int,
+) ([]*domain.PaymentMethod, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentMethodRepository.ListByPayerID")
+	defer span.End()
+
+	query := `
+	SELECT ` + paymentMethodColumns + `
+	FROM payment_methods
+	WHERE payer_id = ? AND status = ?
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY id
+	`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		payerID,
+		domain.PaymentMethodStatusActive,
+		scope, scope,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := make([]*domain.PaymentMethod, 0)
+	for rows.Next() {
+		m, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+
+	return methods, rows.Err()
+}
+
+func (r *paymentMethodRepository) UpdateStatus(
+	ctx context.Context,
+	token string,
+	status domain.PaymentMethodStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentMethodRepository.UpdateStatus")
+	defer span.End()
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE payment_methods SET status = ?, updated_at = ?
+		WHERE token = ? AND (? = 0 OR merchant_id = ?)`,
+		status,
+		time.Now(),
+		token,
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPaymentMethodNotFound
+	}
+
+	return nil
+}
@@ -8,9 +8,79 @@ import (
 	"payment-service/internal/core/domain"
 	"payment-service/internal/core/ports"
 	"payment-service/internal/observability"
+	"strings"
 	"time"
 )
 
+const paymentColumns = `
+		id, public_id, order_id, payer_id,
+		amount, currency, status,
+		provider, method, payment_method_token, idempotency_key,
+		created_at, updated_at, paid_at, expires_at,
+		three_ds_status, liability_shift,
+		risk_score, risk_decision, risk_rules,
+		merchant_id, mode, created_by,
+		bank_code, wallet, channel, return_url, off_session`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPayment(row rowScanner) (*domain.Payment, error) {
+	var p domain.Payment
+	var paidAt, expiresAt sql.NullTime
+	var riskRules string
+
+	err := row.Scan(
+		&p.ID,
+		&p.PublicID,
+		&p.OrderID,
+		&p.PayerID,
+		&p.Amount,
+		&p.Currency,
+		&p.Status,
+		&p.Provider,
+		&p.Method,
+		&p.PaymentMethodToken,
+		&p.IdempotencyKey,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&paidAt,
+		&expiresAt,
+		&p.ThreeDSStatus,
+		&p.LiabilityShift,
+		&p.RiskScore,
+		&p.RiskDecision,
+		&riskRules,
+		&p.MerchantID,
+		&p.Mode,
+		&p.CreatedBy,
+		&p.BankCode,
+		&p.Wallet,
+		&p.Channel,
+		&p.ReturnURL,
+		&p.OffSession,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if paidAt.Valid {
+		p.PaidAt = &paidAt.Time
+	}
+	p.ExpiresAt = timePtr(expiresAt)
+	if riskRules != "" {
+		p.RiskRules = strings.Split(riskRules, ",")
+	}
+
+	return &p, nil
+}
+
 type paymentRepository struct {
 	db *sql.DB
 }
@@ -29,6 +99,16 @@ func (r *paymentRepository) Create(ctx context.Context, p *domain.Payment) error
 		return errors.New("simulated db error")
 	}
 
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return domain.ErrMerchantRequired
+	}
+
+	p.MerchantID = tenant.MerchantID
+	p.Mode = tenant.Mode
+	if p.Mode == "" {
+		p.Mode = domain.ModeLive
+	}
 	p.CreatedAt = now
 	p.UpdatedAt = now
 
@@ -42,13 +122,29 @@ func (r *paymentRepository) Create(ctx context.Context, p *domain.Payment) error
 	status,
 	provider,
 	method,
+	payment_method_token,
 	idempotency_key,
 	created_at,
-	updated_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	updated_at,
+	paid_at,
+	expires_at,
+	three_ds_status,
+	liability_shift,
+	risk_score,
+	risk_decision,
+	risk_rules,
+	merchant_id,
+	mode,
+	created_by,
+	bank_code,
+	wallet,
+	channel,
+	return_url,
+	off_session
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		p.PublicID,
@@ -59,9 +155,25 @@ func (r *paymentRepository) Create(ctx context.Context, p *domain.Payment) error
 		p.Status,
 		p.Provider,
 		p.Method,
+		p.PaymentMethodToken,
 		p.IdempotencyKey,
 		p.CreatedAt,
 		p.UpdatedAt,
+		nullTime(p.PaidAt),
+		nullTime(p.ExpiresAt),
+		p.ThreeDSStatus,
+		p.LiabilityShift,
+		p.RiskScore,
+		p.RiskDecision,
+		strings.Join(p.RiskRules, ","),
+		p.MerchantID,
+		p.Mode,
+		p.CreatedBy,
+		p.BankCode,
+		p.Wallet,
+		p.Channel,
+		p.ReturnURL,
+		p.OffSession,
 	)
 
 	return err
@@ -79,45 +191,16 @@ func (r *paymentRepository) FindByIdempotencyKey(
 	}
 
 	query := `
-	SELECT
-		id, public_id, order_id, payer_id,
-		amount, currency, status,
-		provider, method, idempotency_key,
-		created_at, updated_at, paid_at
+	SELECT ` + paymentColumns + `
 	FROM payments
 	WHERE idempotency_key = ?
+	  AND (? = 0 OR merchant_id = ?)
 	`
 
-	row := r.db.QueryRowContext(ctx, query, key)
-
-	var p domain.Payment
-	var paidAt sql.NullTime
-
-	err := row.Scan(
-		&p.ID,
-		&p.PublicID,
-		&p.OrderID,
-		&p.PayerID,
-		&p.Amount,
-		&p.Currency,
-		&p.Status,
-		&p.Provider,
-		&p.Method,
-		&p.IdempotencyKey,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-		&paidAt,
-	)
+	scope := merchantScope(ctx)
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, key, scope, scope)
 
-	if err != nil {
-		return nil, err
-	}
-
-	if paidAt.Valid {
-		p.PaidAt = &paidAt.Time
-	}
-
-	return &p, nil
+	return scanPayment(row)
 }
 
 func (r *paymentRepository) FindbyPublicID(
@@ -135,43 +218,195 @@ func (r *paymentRepository) FindbyPublicID(
 	}
 
 	query := `
-	SELECT
-		id, public_id, order_id, payer_id,
-		amount, currency, status,
-		provider, method, idempotency_key,
-		created_at, updated_at, paid_at
+	SELECT ` + paymentColumns + `
 	FROM payments
 	WHERE public_id = ?
+	  AND (? = 0 OR merchant_id = ?)
 	`
 
-	row := r.db.QueryRowContext(ctx, query, publicID)
+	scope := merchantScope(ctx)
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, publicID, scope, scope)
 
-	var p domain.Payment
-	var paidAt sql.NullTime
+	return scanPayment(row)
+}
 
-	err := row.Scan(
-		&p.ID,
-		&p.PublicID,
-		&p.OrderID,
-		&p.PayerID,
-		&p.Amount,
-		&p.Currency,
-		&p.Status,
-		&p.Provider,
-		&p.Method,
-		&p.IdempotencyKey,
-		&p.CreatedAt,
-		&p.UpdatedAt,
-		&paidAt,
+func (r *paymentRepository) ListByPayerID(
+	ctx context.Context,
+	payerID int,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(
+		ctx,
+		"paymentRepository.ListByPayerID",
 	)
+	defer span.End()
+
+	query := `
+	SELECT ` + paymentColumns + `
+	FROM payments
+	WHERE payer_id = ?
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY created_at DESC, id DESC
+	LIMIT ? OFFSET ?
+	`
 
+	scope := merchantScope(ctx)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, payerID, scope, scope, limit, offset)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	if paidAt.Valid {
-		p.PaidAt = &paidAt.Time
+	payments := make([]*domain.Payment, 0)
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
 	}
 
-	return &p, nil
+	return payments, rows.Err()
+}
+
+func (r *paymentRepository) UpdateStatus(
+	ctx context.Context,
+	p *domain.Payment,
+	from domain.PaymentStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "paymentRepository.UpdateStatus")
+	defer span.End()
+
+	p.UpdatedAt = time.Now()
+
+	query := `
+	UPDATE payments SET
+		status = ?,
+		updated_at = ?,
+		paid_at = ?,
+		expires_at = ?,
+		three_ds_status = ?,
+		liability_shift = ?
+	WHERE public_id = ? AND status = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	res, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		p.Status,
+		p.UpdatedAt,
+		nullTime(p.PaidAt),
+		nullTime(p.ExpiresAt),
+		p.ThreeDSStatus,
+		p.LiabilityShift,
+		p.PublicID,
+		from,
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPaymentStatusConflict
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) ListExpired(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentRepository.ListExpired")
+	defer span.End()
+
+	query := `
+	SELECT ` + paymentColumns + `
+	FROM payments
+	WHERE status IN (?, ?, ?, ?)
+	  AND expires_at IS NOT NULL
+	  AND expires_at <= ?
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY expires_at
+	LIMIT ?
+	`
+
+	scope := merchantScope(ctx)
+	rows, err := conn(ctx, r.db).QueryContext(
+		ctx,
+		query,
+		domain.PaymentStatusPending,
+		domain.PaymentStatusRequiresAction,
+		domain.PaymentStatusReview,
+		domain.PaymentStatusOnHold,
+		now.UTC(),
+		scope, scope,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]*domain.Payment, 0)
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, rows.Err()
+}
+
+func (r *paymentRepository) ListAwaitingReview(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "paymentRepository.ListAwaitingReview")
+	defer span.End()
+
+	query := `
+	SELECT ` + paymentColumns + `
+	FROM payments
+	WHERE status IN (?, ?)
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY id
+	LIMIT ? OFFSET ?
+	`
+
+	scope := merchantScope(ctx)
+	rows, err := conn(ctx, r.db).QueryContext(
+		ctx,
+		query,
+		domain.PaymentStatusReview,
+		domain.PaymentStatusOnHold,
+		scope, scope,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]*domain.Payment, 0)
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, rows.Err()
 }
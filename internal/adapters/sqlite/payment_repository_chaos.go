@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"context"
+	"time"
 
 	"payment-service/internal/chaos"
 	"payment-service/internal/config"
@@ -89,3 +90,90 @@ func (r *PaymentRepositoryChaos) FindbyPublicID(
 
 	return r.next.FindbyPublicID(ctx, publicID)
 }
+
+func (r *PaymentRepositoryChaos) ListByPayerID(
+	ctx context.Context,
+	payerID int,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentRepositoryChaos.ListByPayerID")
+	defer span.End()
+
+	if r.cfg.Enabled {
+		chaos.MaybeDelay(
+			r.cfg.DelayProbability,
+			r.cfg.MaxDelay,
+		)
+
+		if err := chaos.MaybeError(r.cfg.ErrorProbability); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.next.ListByPayerID(ctx, payerID, limit, offset)
+}
+
+func (r *PaymentRepositoryChaos) UpdateStatus(
+	ctx context.Context,
+	payment *domain.Payment,
+	from domain.PaymentStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentRepositoryChaos.UpdateStatus")
+	defer span.End()
+
+	if r.cfg.Enabled {
+		chaos.MaybeDelay(
+			r.cfg.DelayProbability,
+			r.cfg.MaxDelay,
+		)
+
+		if err := chaos.MaybeError(r.cfg.ErrorProbability); err != nil {
+			return err
+		}
+	}
+
+	return r.next.UpdateStatus(ctx, payment, from)
+}
+
+func (r *PaymentRepositoryChaos) ListExpired(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentRepositoryChaos.ListExpired")
+	defer span.End()
+
+	if r.cfg.Enabled {
+		chaos.MaybeDelay(
+			r.cfg.DelayProbability,
+			r.cfg.MaxDelay,
+		)
+
+		if err := chaos.MaybeError(r.cfg.ErrorProbability); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.next.ListExpired(ctx, now, limit)
+}
+
+func (r *PaymentRepositoryChaos) ListAwaitingReview(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentRepositoryChaos.ListAwaitingReview")
+	defer span.End()
+
+	if r.cfg.Enabled {
+		chaos.MaybeDelay(
+			r.cfg.DelayProbability,
+			r.cfg.MaxDelay,
+		)
+
+		if err := chaos.MaybeError(r.cfg.ErrorProbability); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.next.ListAwaitingReview(ctx, limit, offset)
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+)
+
+// PaymentRepositoryEvents publishes every status change it stores. All
+// payment status changes go through UpdateStatus, so watchers see each of
+// them no matter which usecase made it.
+type PaymentRepositoryEvents struct {
+	ports.PaymentRepository
+	events ports.PaymentEvents
+}
+
+func NewPaymentRepositoryEvents(
+	next ports.PaymentRepository,
+	events ports.PaymentEvents,
+) ports.PaymentRepository {
+	return &PaymentRepositoryEvents{
+		PaymentRepository: next,
+		events:            events,
+	}
+}
+
+func (r *PaymentRepositoryEvents) UpdateStatus(
+	ctx context.Context,
+	payment *domain.Payment,
+	from domain.PaymentStatus,
+) error {
+	if err := r.PaymentRepository.UpdateStatus(ctx, payment, from); err != nil {
+		return err
+	}
+	if payment.Status != from {
+		r.events.Publish(domain.NewPaymentEvent(payment, from))
+	}
+	return nil
+}
@@ -69,3 +69,82 @@ func (r *PaymentRepositoryMetrics) FindbyPublicID(
 
 	return payment, err
 }
+
+func (r *PaymentRepositoryMetrics) ListByPayerID(
+	ctx context.Context,
+	payerID int,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	start := time.Now()
+
+	payments, err := r.next.ListByPayerID(ctx, payerID, limit, offset)
+
+	duration := time.Since(start).Seconds()
+
+	observability.DBQueryDuration.WithLabelValues("select").Observe(duration)
+
+	if err != nil {
+		observability.DBErrors.WithLabelValues("select").Inc()
+	}
+
+	return payments, err
+}
+
+func (r *PaymentRepositoryMetrics) UpdateStatus(
+	ctx context.Context,
+	payment *domain.Payment,
+	from domain.PaymentStatus,
+) error {
+	start := time.Now()
+
+	err := r.next.UpdateStatus(ctx, payment, from)
+
+	duration := time.Since(start).Seconds()
+
+	observability.DBQueryDuration.WithLabelValues("update").Observe(duration)
+
+	if err != nil {
+		observability.DBErrors.WithLabelValues("update").Inc()
+	}
+
+	return err
+}
+
+func (r *PaymentRepositoryMetrics) ListExpired(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*domain.Payment, error) {
+	start := time.Now()
+
+	payments, err := r.next.ListExpired(ctx, now, limit)
+
+	duration := time.Since(start).Seconds()
+
+	observability.DBQueryDuration.WithLabelValues("select").Observe(duration)
+
+	if err != nil {
+		observability.DBErrors.WithLabelValues("select").Inc()
+	}
+
+	return payments, err
+}
+
+func (r *PaymentRepositoryMetrics) ListAwaitingReview(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	start := time.Now()
+
+	payments, err := r.next.ListAwaitingReview(ctx, limit, offset)
+
+	duration := time.Since(start).Seconds()
+
+	observability.DBQueryDuration.WithLabelValues("select").Observe(duration)
+
+	if err != nil {
+		observability.DBErrors.WithLabelValues("select").Inc()
+	}
+
+	return payments, err
+}
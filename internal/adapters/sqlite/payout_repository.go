@@ -0,0 +1,259 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const payoutColumns = `
+		id, public_id, amount, currency, status, trigger, destination,
+		reference, failure_reason, created_at, updated_at, sent_at, completed_at,
+		merchant_id`
+
+type payoutRepository struct {
+	db *sql.DB
+}
+
+func NewPayoutRepository(db *sql.DB) ports.PayoutRepository {
+	return &payoutRepository{db: db}
+}
+
+func scanPayout(row rowScanner) (*domain.Payout, error) {
+	var p domain.Payout
+	var sentAt, completedAt sql.NullTime
+
+	err := row.Scan(
+		&p.ID,
+		&p.PublicID,
+		&p.Amount,
+		&p.Currency,
+		&p.Status,
+		&p.Trigger,
+		&p.Destination,
+		&p.Reference,
+		&p.FailureReason,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&sentAt,
+		&completedAt,
+		&p.MerchantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPayoutNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.SentAt = timePtr(sentAt)
+	p.CompletedAt = timePtr(completedAt)
+	return &p, nil
+}
+
+func scanPayouts(rows *sql.Rows) ([]*domain.Payout, error) {
+	defer rows.Close()
+
+	payouts := make([]*domain.Payout, 0)
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+
+	return payouts, rows.Err()
+}
+
+func (r *payoutRepository) CreateWithinBalance(
+	ctx context.Context,
+	p *domain.Payout,
+	settledBefore time.Time,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "payoutRepository.CreateWithinBalance")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now()
+	p.MerchantID = merchantID
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	// Writing first takes SQLite's write lock, so concurrent payouts are
+	// checked one after another against a balance that includes this one.
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO payouts (
+		public_id, amount, currency, status, trigger, destination, created_at, updated_at,
+		merchant_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.PublicID,
+		p.Amount,
+		p.Currency,
+		p.Status,
+		p.Trigger,
+		p.Destination,
+		p.CreatedAt,
+		p.UpdatedAt,
+		p.MerchantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	balances, err := queryBalances(ctx, tx, p.MerchantID, settledBefore, p.Currency)
+	if err != nil {
+		return err
+	}
+	if len(balances) == 0 || balances[0].Available < 0 {
+		return domain.ErrInsufficientBalance
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID = int(id)
+
+	return tx.Commit()
+}
+
+func (r *payoutRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payoutRepository.FindByPublicID")
+	defer span.End()
+
+	query := `SELECT ` + payoutColumns + ` FROM payouts
+	WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanPayout(r.db.QueryRowContext(ctx, query, publicID, scope, scope))
+}
+
+func (r *payoutRepository) List(
+	ctx context.Context,
+	limit int,
+	offset int,
+) ([]*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payoutRepository.List")
+	defer span.End()
+
+	query := `SELECT ` + payoutColumns + ` FROM payouts
+	WHERE (? = 0 OR merchant_id = ?)
+	ORDER BY id DESC LIMIT ? OFFSET ?`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(ctx, query, scope, scope, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayouts(rows)
+}
+
+func (r *payoutRepository) ListByStatus(
+	ctx context.Context,
+	status domain.PayoutStatus,
+	limit int,
+) ([]*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payoutRepository.ListByStatus")
+	defer span.End()
+
+	query := `SELECT ` + payoutColumns + ` FROM payouts
+	WHERE status = ? AND (? = 0 OR merchant_id = ?)
+	ORDER BY id LIMIT ?`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(ctx, query, status, scope, scope, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanPayouts(rows)
+}
+
+func (r *payoutRepository) LastScheduled(
+	ctx context.Context,
+	currency string,
+) (*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "payoutRepository.LastScheduled")
+	defer span.End()
+
+	query := `SELECT ` + payoutColumns + ` FROM payouts
+	WHERE currency = ? AND trigger = ?
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY id DESC LIMIT 1`
+
+	scope := merchantScope(ctx)
+	return scanPayout(r.db.QueryRowContext(
+		ctx,
+		query,
+		currency,
+		domain.PayoutTriggerScheduled,
+		scope, scope,
+	))
+}
+
+func (r *payoutRepository) UpdateStatus(
+	ctx context.Context,
+	p *domain.Payout,
+	from domain.PayoutStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "payoutRepository.UpdateStatus")
+	defer span.End()
+
+	query := `
+	UPDATE payouts SET
+		status = ?,
+		reference = ?,
+		failure_reason = ?,
+		updated_at = ?,
+		sent_at = ?,
+		completed_at = ?
+	WHERE id = ? AND status = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		p.Status,
+		p.Reference,
+		p.FailureReason,
+		p.UpdatedAt,
+		nullTime(p.SentAt),
+		nullTime(p.CompletedAt),
+		p.ID,
+		from,
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrPayoutStatusConflict
+	}
+
+	return nil
+}
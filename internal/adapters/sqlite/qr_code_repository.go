@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const qrCodeColumns = `id, payment_id, reference, payload, expires_at, created_at`
+
+type qrCodeRepository struct {
+	db *sql.DB
+}
+
+func NewQRCodeRepository(db *sql.DB) ports.QRCodeRepository {
+	return &qrCodeRepository{db: db}
+}
+
+func scanQRCode(row rowScanner) (*domain.QRCode, error) {
+	var qr domain.QRCode
+
+	err := row.Scan(
+		&qr.ID,
+		&qr.PaymentID,
+		&qr.Reference,
+		&qr.Payload,
+		&qr.ExpiresAt,
+		&qr.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrQRCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &qr, nil
+}
+
+func (r *qrCodeRepository) Create(ctx context.Context, qr *domain.QRCode) error {
+	ctx, span := observability.Tracer().Start(ctx, "qrCodeRepository.Create")
+	defer span.End()
+
+	qr.CreatedAt = time.Now()
+
+	query := `
+	INSERT INTO qr_codes (
+	payment_id,
+	reference,
+	payload,
+	expires_at,
+	created_at
+	) VALUES (?, ?, ?, ?, ?)
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		qr.PaymentID,
+		qr.Reference,
+		qr.Payload,
+		qr.ExpiresAt.UTC(),
+		qr.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	qr.ID = int(id)
+
+	return nil
+}
+
+func (r *qrCodeRepository) FindByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) (*domain.QRCode, error) {
+	ctx, span := observability.Tracer().Start(ctx, "qrCodeRepository.FindByPaymentID")
+	defer span.End()
+
+	query := `SELECT ` + qrCodeColumns + ` FROM qr_codes WHERE payment_id = ?`
+
+	return scanQRCode(conn(ctx, r.db).QueryRowContext(ctx, query, paymentID))
+}
+
+func (r *qrCodeRepository) FindByReference(
+	ctx context.Context,
+	reference string,
+) (*domain.QRCode, error) {
+	ctx, span := observability.Tracer().Start(ctx, "qrCodeRepository.FindByReference")
+	defer span.End()
+
+	query := `SELECT ` + qrCodeColumns + ` FROM qr_codes WHERE reference = ?`
+
+	return scanQRCode(conn(ctx, r.db).QueryRowContext(ctx, query, reference))
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+type reviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) ports.ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *domain.PaymentReview) error {
+	ctx, span := observability.Tracer().Start(ctx, "reviewRepository.Create")
+	defer span.End()
+
+	review.CreatedAt = time.Now()
+
+	query := `
+	INSERT INTO payment_reviews (
+	payment_id,
+	action,
+	reason,
+	reviewer,
+	created_at
+	) VALUES (?, ?, ?, ?, ?)
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		review.PaymentID,
+		review.Action,
+		review.Reason,
+		review.Reviewer,
+		review.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	review.ID = int(id)
+
+	return nil
+}
+
+func (r *reviewRepository) ListByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) ([]*domain.PaymentReview, error) {
+	ctx, span := observability.Tracer().Start(ctx, "reviewRepository.ListByPaymentID")
+	defer span.End()
+
+	query := `
+	SELECT id, payment_id, action, reason, reviewer, created_at
+	FROM payment_reviews
+	WHERE payment_id = ?
+	ORDER BY id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]*domain.PaymentReview, 0)
+	for rows.Next() {
+		var review domain.PaymentReview
+		err := rows.Scan(
+			&review.ID,
+			&review.PaymentID,
+			&review.Action,
+			&review.Reason,
+			&review.Reviewer,
+			&review.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, rows.Err()
+}
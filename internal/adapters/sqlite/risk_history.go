@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+type riskHistory struct {
+	db *sql.DB
+}
+
+func NewRiskHistory(db *sql.DB) ports.RiskHistory {
+	return &riskHistory{db: db}
+}
+
+// payments.created_at is stored in local time, so both sides are normalised
+// with datetime() before comparing.
+
+func (r *riskHistory) CountPayerPayments(
+	ctx context.Context,
+	payerID int,
+	since time.Time,
+) (int, error) {
+	ctx, span := observability.Tracer().Start(ctx, "riskHistory.CountPayerPayments")
+	defer span.End()
+
+	query := `
+	SELECT COUNT(*) FROM payments
+	WHERE payer_id = ? AND datetime(created_at) >= datetime(?)
+	`
+
+	var n int
+	err := r.db.QueryRowContext(ctx, query, payerID, since.UTC()).Scan(&n)
+	return n, err
+}
+
+func (r *riskHistory) CountOrderPayments(
+	ctx context.Context,
+	orderID string,
+	since time.Time,
+) (int, error) {
+	ctx, span := observability.Tracer().Start(ctx, "riskHistory.CountOrderPayments")
+	defer span.End()
+
+	query := `
+	SELECT COUNT(*) FROM payments
+	WHERE order_id = ? AND datetime(created_at) >= datetime(?)
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	var n int
+	err := r.db.QueryRowContext(ctx, query, orderID, since.UTC(), scope, scope).Scan(&n)
+	return n, err
+}
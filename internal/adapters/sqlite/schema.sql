@@ -24,8 +24,376 @@ CREATE TABLE IF NOT EXISTS payments (
 CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_public_id
     ON payments(public_id);
 
-CREATE UNIQUE INDEX IF NOT EXISTS ux_payments_idempotency
-    ON payments(idempotency_key);
-
 CREATE INDEX IF NOT EXISTS idx_payments_order_id
     ON payments(order_id);
+
+CREATE INDEX IF NOT EXISTS idx_payments_payer_id
+    ON payments(payer_id);
+
+CREATE TABLE IF NOT EXISTS payers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    country TEXT NOT NULL,
+    external_reference TEXT NOT NULL DEFAULT '',
+
+    status TEXT NOT NULL,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token TEXT NOT NULL UNIQUE,
+
+    payer_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+
+    brand TEXT NOT NULL,
+    last4 TEXT NOT NULL,
+    bin TEXT NOT NULL,
+    exp_month INTEGER NOT NULL,
+    exp_year INTEGER NOT NULL,
+    holder_name TEXT NOT NULL,
+
+    encrypted_pan BLOB NOT NULL,
+    fingerprint TEXT NOT NULL,
+
+    status TEXT NOT NULL,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_methods_payer_fingerprint
+    ON payment_methods(payer_id, fingerprint);
+
+CREATE TABLE IF NOT EXISTS plans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+
+    name TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+
+    billing_interval TEXT NOT NULL,
+    interval_count INTEGER NOT NULL,
+    trial_days INTEGER NOT NULL DEFAULT 0,
+
+    active INTEGER NOT NULL DEFAULT 1,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+
+    plan_id INTEGER NOT NULL,
+    payer_id INTEGER NOT NULL,
+    payment_method_token TEXT NOT NULL,
+
+    status TEXT NOT NULL,
+
+    cycle INTEGER NOT NULL DEFAULT 0,
+    current_period_start DATETIME NOT NULL,
+    current_period_end DATETIME NOT NULL,
+
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    next_retry_at DATETIME,
+
+    last_payment_id TEXT NOT NULL DEFAULT '',
+
+    canceled_at DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status_period_end
+    ON subscriptions(status, current_period_end);
+
+CREATE INDEX IF NOT EXISTS idx_payers_email
+    ON payers(email COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS payment_links (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+
+    single_use INTEGER NOT NULL,
+    usage_count INTEGER NOT NULL DEFAULT 0,
+
+    status TEXT NOT NULL,
+    expires_at DATETIME,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS virtual_accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL UNIQUE,
+
+    bank_code TEXT NOT NULL,
+    account_number TEXT NOT NULL,
+
+    expected_amount INTEGER NOT NULL,
+    paid_amount INTEGER NOT NULL DEFAULT 0,
+
+    status TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_virtual_accounts_bank_account
+    ON virtual_accounts(bank_code, account_number);
+
+CREATE TABLE IF NOT EXISTS bank_credits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    virtual_account_id INTEGER NOT NULL,
+
+    reference TEXT NOT NULL UNIQUE,
+    amount INTEGER NOT NULL,
+
+    received_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ewallet_authorizations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL UNIQUE,
+
+    wallet TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    reference TEXT NOT NULL UNIQUE,
+    return_url TEXT,
+
+    action_type TEXT NOT NULL,
+    redirect_url TEXT,
+    deeplink TEXT,
+    qr_string TEXT,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS qr_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL UNIQUE,
+
+    reference TEXT NOT NULL UNIQUE,
+    payload TEXT NOT NULL,
+
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS three_ds_authentications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL UNIQUE,
+
+    reference TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL,
+    challenge_url TEXT,
+    return_url TEXT,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- spending_usage counts payment attempts for the per payer spending limits
+CREATE TABLE IF NOT EXISTS spending_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payer_id INTEGER NOT NULL,
+    method TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    idempotency_key TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_spending_usage_payer_created_at
+    ON spending_usage(payer_id, created_at);
+
+CREATE TABLE IF NOT EXISTS payment_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL,
+
+    action TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    reviewer TEXT NOT NULL,
+
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_reviews_payment_id
+    ON payment_reviews(payment_id);
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+
+    payment_id TEXT NOT NULL,
+    reference TEXT NOT NULL UNIQUE,
+
+    reason TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL,
+
+    evidence_due_by DATETIME NOT NULL,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    closed_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_disputes_payment_id
+    ON disputes(payment_id);
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dispute_id INTEGER NOT NULL,
+
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    path TEXT NOT NULL,
+    note TEXT NOT NULL,
+
+    created_at DATETIME NOT NULL,
+
+    FOREIGN KEY (dispute_id) REFERENCES disputes(id)
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL,
+
+    type TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    reference TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL,
+
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_payment_id
+    ON ledger_entries(payment_id);
+
+CREATE TABLE IF NOT EXISTS payouts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL,
+    trigger TEXT NOT NULL,
+    destination TEXT NOT NULL,
+
+    reference TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    completed_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_payouts_status
+    ON payouts(status);
+
+CREATE TABLE IF NOT EXISTS payment_splits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    payment_id TEXT NOT NULL,
+
+    recipient TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    percentage_bps INTEGER NOT NULL,
+    fee_bearer BOOLEAN NOT NULL,
+
+    UNIQUE (payment_id, recipient)
+);
+
+CREATE TABLE IF NOT EXISTS merchants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+
+    name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    payout_destination TEXT NOT NULL DEFAULT '',
+
+    created_at DATETIME NOT NULL
+);
+
+-- api_keys stores a SHA-256 of each secret, never the secret itself
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    merchant_id INTEGER NOT NULL,
+
+    mode TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    hash TEXT NOT NULL UNIQUE,
+
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME,
+    revoked_at DATETIME,
+
+    FOREIGN KEY (merchant_id) REFERENCES merchants(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_merchant_id
+    ON api_keys(merchant_id);
+
+CREATE TABLE IF NOT EXISTS payment_batches (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    public_id TEXT NOT NULL UNIQUE,
+    merchant_id INTEGER NOT NULL,
+
+    mode TEXT NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    completed_at DATETIME,
+
+    FOREIGN KEY (merchant_id) REFERENCES merchants(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_batches_status
+    ON payment_batches(status);
+
+-- request holds the item's payment fields as JSON; they are only read back
+-- to create the payment
+CREATE TABLE IF NOT EXISTS payment_batch_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    batch_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+
+    idempotency_key TEXT NOT NULL,
+    request TEXT NOT NULL,
+
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    payment_id TEXT NOT NULL DEFAULT '',
+    payment_status TEXT NOT NULL DEFAULT '',
+    error_code TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+
+    updated_at DATETIME NOT NULL,
+
+    UNIQUE (batch_id, position),
+    FOREIGN KEY (batch_id) REFERENCES payment_batches(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_batch_items_batch_status
+    ON payment_batch_items(batch_id, status);
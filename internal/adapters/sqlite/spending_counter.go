@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+type spendingCounter struct {
+	db *sql.DB
+}
+
+func NewSpendingCounter(db *sql.DB) ports.SpendingCounter {
+	return &spendingCounter{db: db}
+}
+
+func (r *spendingCounter) Record(
+	ctx context.Context,
+	attempt domain.SpendingAttempt,
+	limits []domain.SpendingLimit,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "spendingCounter.Record")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	at := attempt.At.UTC()
+	// idempotency keys are only unique per merchant, and so per payer
+	key := fmt.Sprintf("%d:%s", attempt.PayerID, attempt.IdempotencyKey)
+
+	// Writing first takes SQLite's write lock, so a concurrent Record for
+	// the same payer waits here instead of reading stale totals.
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO spending_usage (payer_id, method, currency, amount, idempotency_key, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(idempotency_key) DO NOTHING`,
+		attempt.PayerID,
+		attempt.Method,
+		attempt.Currency,
+		attempt.Amount,
+		key,
+		at,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		// a retried request was already counted
+		return err
+	}
+
+	var longest time.Duration
+	for _, limit := range limits {
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+
+		var usage domain.SpendingUsage
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM spending_usage
+			WHERE payer_id = ? AND created_at > ?
+			AND (? = '' OR method = ?)
+			AND (? = '' OR currency = ?)`,
+			attempt.PayerID,
+			at.Add(-limit.Window),
+			limit.Method, limit.Method,
+			limit.Currency, limit.Currency,
+		).Scan(&usage.Count, &usage.Amount)
+		if err != nil {
+			return err
+		}
+		if limit.ExceededBy(usage) {
+			return &domain.LimitExceededError{Limit: limit.Name}
+		}
+	}
+
+	// nothing older than the longest window is ever read again
+	_, err = tx.ExecContext(
+		ctx,
+		`DELETE FROM spending_usage WHERE payer_id = ? AND created_at <= ?`,
+		attempt.PayerID,
+		at.Add(-longest),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
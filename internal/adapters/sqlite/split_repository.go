@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+)
+
+type splitRepository struct {
+	db *sql.DB
+}
+
+func NewSplitRepository(db *sql.DB) ports.SplitRepository {
+	return &splitRepository{db: db}
+}
+
+func (r *splitRepository) Create(ctx context.Context, splits []domain.PaymentSplit) error {
+	ctx, span := observability.Tracer().Start(ctx, "splitRepository.Create")
+	defer span.End()
+
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for i := range splits {
+			s := &splits[i]
+			res, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO payment_splits (payment_id, recipient, amount, percentage_bps, fee_bearer)
+				VALUES (?, ?, ?, ?, ?)`,
+				s.PaymentID,
+				s.Recipient,
+				s.Amount,
+				s.PercentageBps,
+				s.FeeBearer,
+			)
+			if err != nil {
+				return err
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			s.ID = int(id)
+		}
+		return nil
+	})
+}
+
+func (r *splitRepository) ListByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) ([]domain.PaymentSplit, error) {
+	ctx, span := observability.Tracer().Start(ctx, "splitRepository.ListByPaymentID")
+	defer span.End()
+
+	query := `
+	SELECT id, payment_id, recipient, amount, percentage_bps, fee_bearer
+	FROM payment_splits
+	WHERE payment_id = ?
+	ORDER BY id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []domain.PaymentSplit
+	for rows.Next() {
+		var s domain.PaymentSplit
+		err := rows.Scan(
+			&s.ID,
+			&s.PaymentID,
+			&s.Recipient,
+			&s.Amount,
+			&s.PercentageBps,
+			&s.FeeBearer,
+		)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, s)
+	}
+
+	return splits, rows.Err()
+}
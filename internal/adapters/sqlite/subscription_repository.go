@@ -0,0 +1,419 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const planColumns = `
+		id, public_id, name, amount, currency,
+		billing_interval, interval_count, trial_days, active,
+		created_at, updated_at`
+
+const subscriptionColumns = `
+		id, public_id, plan_id, payer_id, payment_method_token,
+		status, cycle, current_period_start, current_period_end,
+		failed_attempts, next_retry_at, last_payment_id,
+		canceled_at, created_at, updated_at, merchant_id`
+
+// nullTime converts an optional timestamp for storage. Timestamps that are
+// compared in SQL are stored in UTC so string comparison orders them right.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type planRepository struct {
+	db *sql.DB
+}
+
+func NewPlanRepository(db *sql.DB) ports.PlanRepository {
+	return &planRepository{db: db}
+}
+
+func scanPlan(row rowScanner) (*domain.Plan, error) {
+	var p domain.Plan
+
+	err := row.Scan(
+		&p.ID,
+		&p.PublicID,
+		&p.Name,
+		&p.Amount,
+		&p.Currency,
+		&p.Interval,
+		&p.IntervalCount,
+		&p.TrialDays,
+		&p.Active,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *planRepository) Create(ctx context.Context, p *domain.Plan) error {
+	ctx, span := observability.Tracer().Start(ctx, "planRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	query := `
+	INSERT INTO plans (
+	public_id,
+	name,
+	amount,
+	currency,
+	billing_interval,
+	interval_count,
+	trial_days,
+	active,
+	created_at,
+	updated_at,
+	merchant_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		p.PublicID,
+		p.Name,
+		p.Amount,
+		p.Currency,
+		p.Interval,
+		p.IntervalCount,
+		p.TrialDays,
+		p.Active,
+		p.CreatedAt,
+		p.UpdatedAt,
+		merchantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID = int(id)
+
+	return nil
+}
+
+func (r *planRepository) FindByID(ctx context.Context, id int) (*domain.Plan, error) {
+	ctx, span := observability.Tracer().Start(ctx, "planRepository.FindByID")
+	defer span.End()
+
+	query := `SELECT ` + planColumns + ` FROM plans
+	WHERE id = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanPlan(r.db.QueryRowContext(ctx, query, id, scope, scope))
+}
+
+func (r *planRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.Plan, error) {
+	ctx, span := observability.Tracer().Start(ctx, "planRepository.FindByPublicID")
+	defer span.End()
+
+	query := `SELECT ` + planColumns + ` FROM plans
+	WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanPlan(r.db.QueryRowContext(ctx, query, publicID, scope, scope))
+}
+
+func (r *planRepository) List(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Plan, error) {
+	ctx, span := observability.Tracer().Start(ctx, "planRepository.List")
+	defer span.End()
+
+	query := `
+	SELECT ` + planColumns + `
+	FROM plans
+	WHERE (? = 0 OR merchant_id = ?)
+	ORDER BY id
+	LIMIT ? OFFSET ?
+	`
+
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(ctx, query, scope, scope, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]*domain.Plan, 0)
+	for rows.Next() {
+		p, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, p)
+	}
+
+	return plans, rows.Err()
+}
+
+type subscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) ports.SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func scanSubscription(row rowScanner) (*domain.Subscription, error) {
+	var s domain.Subscription
+	var nextRetryAt, canceledAt sql.NullTime
+
+	err := row.Scan(
+		&s.ID,
+		&s.PublicID,
+		&s.PlanID,
+		&s.PayerID,
+		&s.PaymentMethodToken,
+		&s.Status,
+		&s.Cycle,
+		&s.CurrentPeriodStart,
+		&s.CurrentPeriodEnd,
+		&s.FailedAttempts,
+		&nextRetryAt,
+		&s.LastPaymentID,
+		&canceledAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&s.MerchantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.NextRetryAt = timePtr(nextRetryAt)
+	s.CanceledAt = timePtr(canceledAt)
+
+	return &s, nil
+}
+
+func (r *subscriptionRepository) Create(
+	ctx context.Context,
+	s *domain.Subscription,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "subscriptionRepository.Create")
+	defer span.End()
+
+	merchantID, err := merchantOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	s.MerchantID = merchantID
+	s.CreatedAt = now
+	s.UpdatedAt = now
+
+	query := `
+	INSERT INTO subscriptions (
+	public_id,
+	plan_id,
+	payer_id,
+	payment_method_token,
+	status,
+	cycle,
+	current_period_start,
+	current_period_end,
+	failed_attempts,
+	next_retry_at,
+	last_payment_id,
+	canceled_at,
+	created_at,
+	updated_at,
+	merchant_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		s.PublicID,
+		s.PlanID,
+		s.PayerID,
+		s.PaymentMethodToken,
+		s.Status,
+		s.Cycle,
+		s.CurrentPeriodStart.UTC(),
+		s.CurrentPeriodEnd.UTC(),
+		s.FailedAttempts,
+		nullTime(s.NextRetryAt),
+		s.LastPaymentID,
+		nullTime(s.CanceledAt),
+		s.CreatedAt,
+		s.UpdatedAt,
+		s.MerchantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	s.ID = int(id)
+
+	return nil
+}
+
+func (r *subscriptionRepository) Update(
+	ctx context.Context,
+	s *domain.Subscription,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "subscriptionRepository.Update")
+	defer span.End()
+
+	s.UpdatedAt = time.Now()
+
+	query := `
+	UPDATE subscriptions SET
+		payment_method_token = ?,
+		status = ?,
+		cycle = ?,
+		current_period_start = ?,
+		current_period_end = ?,
+		failed_attempts = ?,
+		next_retry_at = ?,
+		last_payment_id = ?,
+		canceled_at = ?,
+		updated_at = ?
+	WHERE id = ?
+	  AND (? = 0 OR merchant_id = ?)
+	`
+
+	scope := merchantScope(ctx)
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		s.PaymentMethodToken,
+		s.Status,
+		s.Cycle,
+		s.CurrentPeriodStart.UTC(),
+		s.CurrentPeriodEnd.UTC(),
+		s.FailedAttempts,
+		nullTime(s.NextRetryAt),
+		s.LastPaymentID,
+		nullTime(s.CanceledAt),
+		s.UpdatedAt,
+		s.ID,
+		scope, scope,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) FindByPublicID(
+	ctx context.Context,
+	publicID string,
+) (*domain.Subscription, error) {
+	ctx, span := observability.Tracer().Start(ctx, "subscriptionRepository.FindByPublicID")
+	defer span.End()
+
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions
+	WHERE public_id = ? AND (? = 0 OR merchant_id = ?)`
+
+	scope := merchantScope(ctx)
+	return scanSubscription(r.db.QueryRowContext(ctx, query, publicID, scope, scope))
+}
+
+func (r *subscriptionRepository) ListDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]*domain.Subscription, error) {
+	ctx, span := observability.Tracer().Start(ctx, "subscriptionRepository.ListDue")
+	defer span.End()
+
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscriptions
+	WHERE ((status IN (?, ?) AND current_period_end <= ?)
+	   OR (status = ? AND next_retry_at <= ?))
+	  AND (? = 0 OR merchant_id = ?)
+	ORDER BY current_period_end
+	LIMIT ?
+	`
+
+	now = now.UTC()
+	scope := merchantScope(ctx)
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		domain.SubscriptionStatusTrialing,
+		domain.SubscriptionStatusActive,
+		now,
+		domain.SubscriptionStatusPastDue,
+		now,
+		scope, scope,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*domain.Subscription, 0)
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
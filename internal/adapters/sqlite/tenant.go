@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+// Every merchant owned table has a merchant_id column. Queries filter on it
+// with
+//
+//	AND (? = 0 OR merchant_id = ?)
+//
+// bound to merchantScope twice, so requests only see their own merchant's
+// rows while workers and provider callbacks, which run without a tenant, see
+// all of them. Rows reached only through a scoped parent (virtual accounts,
+// QR codes, evidence, ...) carry no merchant_id of their own.
+
+// merchantScope returns the merchant reads in ctx are limited to, or 0 when
+// ctx has no tenant.
+func merchantScope(ctx context.Context) int {
+	t, _ := domain.TenantFromContext(ctx)
+	return t.MerchantID
+}
+
+// merchantOf returns the merchant new rows written in ctx belong to. Unlike
+// reads, writes always need one.
+func merchantOf(ctx context.Context) (int, error) {
+	t, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return 0, domain.ErrMerchantRequired
+	}
+	return t.MerchantID, nil
+}
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const threeDSColumns = `
+		id, payment_id, reference, status, challenge_url, return_url,
+		created_at, updated_at`
+
+type threeDSRepository struct {
+	db *sql.DB
+}
+
+func NewThreeDSRepository(db *sql.DB) ports.ThreeDSRepository {
+	return &threeDSRepository{db: db}
+}
+
+func scanThreeDS(row rowScanner) (*domain.ThreeDSAuthentication, error) {
+	var a domain.ThreeDSAuthentication
+	var challengeURL, returnURL sql.NullString
+
+	err := row.Scan(
+		&a.ID,
+		&a.PaymentID,
+		&a.Reference,
+		&a.Status,
+		&challengeURL,
+		&returnURL,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrThreeDSNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.ChallengeURL = challengeURL.String
+	a.ReturnURL = returnURL.String
+
+	return &a, nil
+}
+
+func (r *threeDSRepository) Create(
+	ctx context.Context,
+	a *domain.ThreeDSAuthentication,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "threeDSRepository.Create")
+	defer span.End()
+
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	query := `
+	INSERT INTO three_ds_authentications (
+	payment_id,
+	reference,
+	status,
+	challenge_url,
+	return_url,
+	created_at,
+	updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		a.PaymentID,
+		a.Reference,
+		a.Status,
+		a.ChallengeURL,
+		a.ReturnURL,
+		a.CreatedAt,
+		a.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = int(id)
+
+	return nil
+}
+
+func (r *threeDSRepository) FindByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) (*domain.ThreeDSAuthentication, error) {
+	ctx, span := observability.Tracer().Start(ctx, "threeDSRepository.FindByPaymentID")
+	defer span.End()
+
+	query := `SELECT ` + threeDSColumns + ` FROM three_ds_authentications WHERE payment_id = ?`
+
+	return scanThreeDS(conn(ctx, r.db).QueryRowContext(ctx, query, paymentID))
+}
+
+func (r *threeDSRepository) UpdateStatus(
+	ctx context.Context,
+	id int,
+	status domain.ThreeDSStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "threeDSRepository.UpdateStatus")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		`UPDATE three_ds_authentications SET status = ?, updated_at = ? WHERE id = ?`,
+		status,
+		time.Now(),
+		id,
+	)
+	return err
+}
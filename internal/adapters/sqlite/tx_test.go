@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+func TestTransactor_WithinTx(t *testing.T) {
+	observability.InitTracer("test")
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "payments.db") + "?_busy_timeout=5000"
+	db, err := New(dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	transactor := NewTransactor(db)
+	vaRepo := NewVirtualAccountRepository(db)
+	splitRepo := NewSplitRepository(db)
+	ctx := context.Background()
+
+	// store writes the records of a payment, failing afterwards with fail
+	store := func(paymentID string, fail error) error {
+		return transactor.WithinTx(ctx, func(ctx context.Context) error {
+			err := vaRepo.Create(ctx, &domain.VirtualAccount{
+				PaymentID:      paymentID,
+				BankCode:       "BCA",
+				AccountNumber:  "39358" + paymentID,
+				ExpectedAmount: 1000,
+				Status:         domain.VirtualAccountStatusOpen,
+				ExpiresAt:      time.Now().Add(time.Hour),
+			})
+			if err != nil {
+				return err
+			}
+			err = splitRepo.Create(ctx, []domain.PaymentSplit{
+				{PaymentID: paymentID, Recipient: "platform", Amount: 100},
+				{PaymentID: paymentID, Recipient: "seller", Amount: 900},
+			})
+			if err != nil {
+				return err
+			}
+			return fail
+		})
+	}
+
+	failure := errors.New("review insert failed")
+	if err := store("pay_rolled_back", failure); !errors.Is(err, failure) {
+		t.Fatalf("expected the failure to be returned, got %v", err)
+	}
+	if _, err := vaRepo.FindByPaymentID(ctx, "pay_rolled_back"); !errors.Is(err, domain.ErrVirtualAccountNotFound) {
+		t.Fatalf("expected the virtual account to be rolled back, got %v", err)
+	}
+	splits, err := splitRepo.ListByPaymentID(ctx, "pay_rolled_back")
+	if err != nil || len(splits) != 0 {
+		t.Fatalf("expected the splits to be rolled back, got %v %v", splits, err)
+	}
+
+	if err := store("pay_committed", nil); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if _, err := vaRepo.FindByPaymentID(ctx, "pay_committed"); err != nil {
+		t.Fatalf("expected the virtual account to be committed, got %v", err)
+	}
+	splits, err = splitRepo.ListByPaymentID(ctx, "pay_committed")
+	if err != nil || len(splits) != 2 {
+		t.Fatalf("expected 2 committed splits, got %v %v", splits, err)
+	}
+}
@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+)
+
+const virtualAccountColumns = `
+		id, payment_id, bank_code, account_number,
+		expected_amount, paid_amount, status, expires_at,
+		created_at, updated_at`
+
+type virtualAccountRepository struct {
+	db *sql.DB
+}
+
+func NewVirtualAccountRepository(db *sql.DB) ports.VirtualAccountRepository {
+	return &virtualAccountRepository{db: db}
+}
+
+func scanVirtualAccount(row rowScanner) (*domain.VirtualAccount, error) {
+	var va domain.VirtualAccount
+
+	err := row.Scan(
+		&va.ID,
+		&va.PaymentID,
+		&va.BankCode,
+		&va.AccountNumber,
+		&va.ExpectedAmount,
+		&va.PaidAmount,
+		&va.Status,
+		&va.ExpiresAt,
+		&va.CreatedAt,
+		&va.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrVirtualAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &va, nil
+}
+
+func (r *virtualAccountRepository) Create(
+	ctx context.Context,
+	va *domain.VirtualAccount,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "virtualAccountRepository.Create")
+	defer span.End()
+
+	now := time.Now()
+	va.CreatedAt = now
+	va.UpdatedAt = now
+
+	query := `
+	INSERT INTO virtual_accounts (
+	payment_id,
+	bank_code,
+	account_number,
+	expected_amount,
+	paid_amount,
+	status,
+	expires_at,
+	created_at,
+	updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		va.PaymentID,
+		va.BankCode,
+		va.AccountNumber,
+		va.ExpectedAmount,
+		va.PaidAmount,
+		va.Status,
+		va.ExpiresAt.UTC(),
+		va.CreatedAt,
+		va.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	va.ID = int(id)
+
+	return nil
+}
+
+func (r *virtualAccountRepository) FindByPaymentID(
+	ctx context.Context,
+	paymentID string,
+) (*domain.VirtualAccount, error) {
+	ctx, span := observability.Tracer().Start(ctx, "virtualAccountRepository.FindByPaymentID")
+	defer span.End()
+
+	query := `SELECT ` + virtualAccountColumns + ` FROM virtual_accounts WHERE payment_id = ?`
+
+	return scanVirtualAccount(conn(ctx, r.db).QueryRowContext(ctx, query, paymentID))
+}
+
+func (r *virtualAccountRepository) FindByAccountNumber(
+	ctx context.Context,
+	bankCode string,
+	accountNumber string,
+) (*domain.VirtualAccount, error) {
+	ctx, span := observability.Tracer().Start(ctx, "virtualAccountRepository.FindByAccountNumber")
+	defer span.End()
+
+	query := `
+	SELECT ` + virtualAccountColumns + `
+	FROM virtual_accounts
+	WHERE bank_code = ? AND account_number = ?
+	`
+
+	return scanVirtualAccount(conn(ctx, r.db).QueryRowContext(ctx, query, bankCode, accountNumber))
+}
+
+func (r *virtualAccountRepository) ApplyCredit(
+	ctx context.Context,
+	va *domain.VirtualAccount,
+	credit *domain.BankCredit,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "virtualAccountRepository.ApplyCredit")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO bank_credits (virtual_account_id, reference, amount, received_at)
+		VALUES (?, ?, ?, ?)`,
+		va.ID,
+		credit.Reference,
+		credit.Amount,
+		credit.ReceivedAt,
+	)
+	if isUniqueConstraintError(err) {
+		return domain.ErrDuplicateBankCredit
+	}
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	// the total is added to in place, so concurrent credits all count
+	expired := va.Status == domain.VirtualAccountStatusExpired
+	var status domain.VirtualAccountStatus
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE virtual_accounts SET paid_amount = paid_amount + ? WHERE id = ?
+		RETURNING expected_amount, paid_amount, status`,
+		credit.Amount,
+		va.ID,
+	).Scan(&va.ExpectedAmount, &va.PaidAmount, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrVirtualAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	va.Status = va.PaidStatus()
+	if expired || status == domain.VirtualAccountStatusExpired {
+		va.Status = domain.VirtualAccountStatusExpired
+	}
+	va.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE virtual_accounts SET status = ?, updated_at = ? WHERE id = ?`,
+		va.Status,
+		va.UpdatedAt,
+		va.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	credit.ID = int(id)
+	credit.VirtualAccountID = va.ID
+
+	return nil
+}
+
+func (r *virtualAccountRepository) UpdateStatus(
+	ctx context.Context,
+	id int,
+	status domain.VirtualAccountStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "virtualAccountRepository.UpdateStatus")
+	defer span.End()
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		`UPDATE virtual_accounts SET status = ?, updated_at = ? WHERE id = ?`,
+		status,
+		time.Now(),
+		id,
+	)
+	return err
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+func TestVirtualAccountRepository_ConcurrentCredits(t *testing.T) {
+	observability.InitTracer("test")
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "payments.db") + "?_busy_timeout=5000"
+	db, err := New(dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewVirtualAccountRepository(db)
+	ctx := context.Background()
+
+	va := &domain.VirtualAccount{
+		PaymentID:      "pay_va",
+		BankCode:       "BCA",
+		AccountNumber:  "3935800000000001",
+		ExpectedAmount: 1000,
+		Status:         domain.VirtualAccountStatusOpen,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+	if err := repo.Create(ctx, va); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// every credit starts from the same stale read of the account, like
+	// webhooks delivered at the same time
+	const credits = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, credits)
+	for i := range credits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stale := *va
+			errs <- repo.ApplyCredit(ctx, &stale, &domain.BankCredit{
+				Reference:  fmt.Sprintf("ref-%d", i),
+				Amount:     100,
+				ReceivedAt: time.Now(),
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("apply credit: %v", err)
+		}
+	}
+
+	got, err := repo.FindByPaymentID(ctx, "pay_va")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if got.PaidAmount != 1000 || got.Status != domain.VirtualAccountStatusPaid {
+		t.Fatalf("expected 1000 paid, got %d %s", got.PaidAmount, got.Status)
+	}
+}
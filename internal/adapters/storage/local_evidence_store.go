@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// LocalEvidenceStore keeps dispute evidence on the local disk, one
+// directory per dispute.
+type LocalEvidenceStore struct {
+	dir string
+}
+
+func NewLocalEvidenceStore(dir string) ports.EvidenceStore {
+	return &LocalEvidenceStore{dir: dir}
+}
+
+func (s *LocalEvidenceStore) Save(
+	ctx context.Context,
+	disputeID string,
+	fileName string,
+	r io.Reader,
+	maxSize int64,
+) (string, int64, error) {
+	_, span := observability.Tracer().Start(ctx, "LocalEvidenceStore.Save")
+	defer span.End()
+
+	dir := filepath.Join(s.dir, filepath.Base(disputeID))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", 0, err
+	}
+
+	// never trust the uploaded name as a path
+	name := uuid.NewString() + strings.ToLower(filepath.Ext(filepath.Base(fileName)))
+	path := filepath.Join(dir, name)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// read one byte past the limit to tell an exact fit from an oversize file
+	size, err := io.Copy(f, io.LimitReader(r, maxSize+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > maxSize {
+		err = domain.ErrInvalidEvidence
+	}
+	if err != nil {
+		return "", 0, errors.Join(err, os.Remove(path))
+	}
+
+	return path, size, nil
+}
@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"payment-service/internal/core/ports"
+)
+
+type aesGCMBox struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// NewAESGCMBox returns a SecretBox that seals data with AES-256-GCM. The
+// fingerprint key is derived from the same master key so a single secret
+// has to be managed.
+func NewAESGCMBox(key []byte) (ports.SecretBox, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("vault: key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("fingerprint"))
+
+	return &aesGCMBox{
+		aead:   aead,
+		macKey: mac.Sum(nil),
+	}, nil
+}
+
+func (b *aesGCMBox) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// nonce is stored in front of the ciphertext
+	return b.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (b *aesGCMBox) Open(ciphertext []byte) ([]byte, error) {
+	size := b.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("vault: ciphertext too short")
+	}
+	return b.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+func (b *aesGCMBox) Fingerprint(data []byte) string {
+	mac := hmac.New(sha256.New, b.macKey)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
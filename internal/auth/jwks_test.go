@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJWKS(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+	return path
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey := testRSAKey(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	rsaJWK := `{"kty":"RSA","kid":"rsa","alg":"RS256","use":"sig","n":"` + b64(rsaKey.N.Bytes()) +
+		`","e":"` + b64(big.NewInt(int64(rsaKey.E)).Bytes()) + `"}`
+	ecJWK := `{"kty":"EC","kid":"ec","crv":"P-256","x":"` + b64(ecKey.X.FillBytes(make([]byte, 32))) +
+		`","y":"` + b64(ecKey.Y.FillBytes(make([]byte, 32))) + `"}`
+	octJWK := `{"kty":"oct","kid":"oct","k":"` + b64([]byte("secret")) + `"}`
+	encJWK := `{"kty":"oct","kid":"enc","use":"enc","k":"` + b64([]byte("secret")) + `"}`
+
+	keys, err := LoadJWKS(writeJWKS(t, `{"keys":[`+strings.Join([]string{rsaJWK, ecJWK, octJWK, encJWK}, ",")+`]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("expected 3 signing keys, got %d", len(keys))
+	}
+	if pub, ok := keys["rsa"].Key.(*rsa.PublicKey); !ok || !pub.Equal(&rsaKey.PublicKey) || keys["rsa"].Alg != "RS256" {
+		t.Fatalf("unexpected rsa key %+v", keys["rsa"])
+	}
+	if pub, ok := keys["ec"].Key.(*ecdsa.PublicKey); !ok || !pub.Equal(&ecKey.PublicKey) {
+		t.Fatalf("unexpected ec key %+v", keys["ec"])
+	}
+	if secret, ok := keys["oct"].Key.([]byte); !ok || string(secret) != "secret" {
+		t.Fatalf("unexpected oct key %+v", keys["oct"])
+	}
+}
+
+func TestLoadJWKS_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not json", `keys`},
+		{"no signing keys", `{"keys":[{"kty":"oct","kid":"enc","use":"enc","k":"c2VjcmV0"}]}`},
+		{"duplicate kid", `{"keys":[{"kty":"oct","kid":"a","k":"c2VjcmV0"},{"kty":"oct","kid":"a","k":"c2VjcmV0"}]}`},
+		{"unsupported type", `{"keys":[{"kty":"OKP","kid":"a","crv":"Ed25519","x":"AAAA"}]}`},
+		{"empty oct key", `{"keys":[{"kty":"oct","kid":"a","k":""}]}`},
+		{"point off the curve", `{"keys":[{"kty":"EC","kid":"a","crv":"P-256","x":"` + b64(make([]byte, 32)) +
+			`","y":"` + b64(make([]byte, 32)) + `"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadJWKS(writeJWKS(t, tt.body)); err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"payment-service/internal/core/domain"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtAlgorithms are the algorithms service tokens may be signed with. "none"
+// and anything else is rejected before a key is looked up.
+var jwtAlgorithms = []string{
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"ES256", "ES384", "ES512",
+}
+
+// ServiceClaims are the claims of a token issued to an internal service.
+type ServiceClaims struct {
+	jwt.RegisteredClaims
+	// Scope is the space separated OAuth form, Scp the array form some
+	// issuers use instead.
+	Scope string   `json:"scope,omitempty"`
+	Scp   []string `json:"scp,omitempty"`
+	// MerchantID is the public id of the merchant the service acts for on
+	// the merchant API.
+	MerchantID string `json:"merchant_id,omitempty"`
+	Mode       string `json:"mode,omitempty"`
+}
+
+// Scopes returns the scopes granted by either claim.
+func (c *ServiceClaims) Scopes() []string {
+	scopes := strings.Fields(c.Scope)
+	return append(scopes, c.Scp...)
+}
+
+// JWTVerifier checks service tokens against the keys of a local JWKS file.
+type JWTVerifier struct {
+	parser *jwt.Parser
+
+	mu   sync.RWMutex
+	keys map[string]JWK
+
+	// reload, when set, is called for a kid the verifier does not know,
+	// at most once per reloadEvery
+	reload      func() (map[string]JWK, error)
+	reloadEvery time.Duration
+	reloadedAt  time.Time
+	now         func() time.Time
+}
+
+// NewJWTVerifier verifies tokens signed by keys. Issuer and audience are
+// only checked when set.
+func NewJWTVerifier(keys map[string]JWK, issuer, audience string) *JWTVerifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(jwtAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	return &JWTVerifier{
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+		now:    time.Now,
+	}
+}
+
+// WithReload picks up keys added to the set after startup: a token signed
+// with an unknown kid makes the verifier call load, at most once every
+// interval so such tokens cannot keep it reloading.
+func (v *JWTVerifier) WithReload(load func() (map[string]JWK, error), interval time.Duration) *JWTVerifier {
+	v.reload = load
+	v.reloadEvery = interval
+	return v
+}
+
+// Verify checks the token's signature and registered claims. Tokens must
+// name their subject.
+func (v *JWTVerifier) Verify(token string) (*ServiceClaims, error) {
+	var claims ServiceClaims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return &claims, nil
+}
+
+// key picks the verification key by kid. A set with a single key also
+// verifies tokens without a kid. The signing method checks the key type,
+// so an RSA public key can never be used as an HMAC secret.
+func (v *JWTVerifier) key(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	k, ok := v.lookup(kid)
+	if !ok && kid != "" {
+		reloaded, err := v.reloadKeys()
+		if err != nil {
+			return nil, fmt.Errorf("unknown key %q: %w", kid, err)
+		}
+		if reloaded {
+			k, ok = v.lookup(kid)
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	if k.Alg != "" && k.Alg != t.Method.Alg() {
+		return nil, fmt.Errorf("key %q is not for %s", kid, t.Method.Alg())
+	}
+	return k.Key, nil
+}
+
+func (v *JWTVerifier) lookup(kid string) (JWK, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	k, ok := v.keys[kid]
+	if !ok && kid == "" && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			k, ok = only, true
+		}
+	}
+	return k, ok
+}
+
+// reloadKeys replaces the keys with a fresh read of the set and reports
+// whether it did. A failed read keeps the current keys.
+func (v *JWTVerifier) reloadKeys() (bool, error) {
+	if v.reload == nil {
+		return false, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if !v.reloadedAt.IsZero() && now.Sub(v.reloadedAt) < v.reloadEvery {
+		return false, nil
+	}
+	v.reloadedAt = now
+
+	keys, err := v.reload()
+	if err != nil {
+		return false, fmt.Errorf("reload jwks: %w", err)
+	}
+	v.keys = keys
+	return true, nil
+}
+
+// LooksLikeJWT tells service tokens apart from API keys.
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Principal is who a verified token acts for.
+func (c *ServiceClaims) Principal() domain.Principal {
+	return domain.Principal{
+		Subject: c.Subject,
+		Scopes:  c.Scopes(),
+	}
+}
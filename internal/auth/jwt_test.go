@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return key
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func serviceClaims(mutate func(*ServiceClaims)) *ServiceClaims {
+	now := time.Now()
+	c := &ServiceClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "svc-billing",
+			Issuer:    "https://issuer.test",
+			Audience:  jwt.ClaimStrings{"payment-service"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Scope: "payments:read payments:write",
+	}
+	if mutate != nil {
+		mutate(c)
+	}
+	return c
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	rsaKey := testRSAKey(t)
+	otherKey := testRSAKey(t)
+	hmacSecret := []byte("0123456789abcdef0123456789abcdef")
+
+	keys := map[string]JWK{
+		"rsa":  {ID: "rsa", Alg: "RS256", Key: &rsaKey.PublicKey},
+		"open": {ID: "open", Key: &rsaKey.PublicKey},
+		"hmac": {ID: "hmac", Alg: "HS256", Key: hmacSecret},
+	}
+	v := NewJWTVerifier(keys, "https://issuer.test", "payment-service")
+
+	tests := []struct {
+		name  string
+		token func() string
+		ok    bool
+	}{
+		{
+			name:  "valid RS256",
+			token: func() string { return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(nil)) },
+			ok:    true,
+		},
+		{
+			name:  "valid HS256",
+			token: func() string { return signToken(t, jwt.SigningMethodHS256, "hmac", hmacSecret, serviceClaims(nil)) },
+			ok:    true,
+		},
+		{
+			name: "alg none",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, serviceClaims(nil))
+			},
+		},
+		{
+			name: "HS256 keyed with the RSA public key",
+			token: func() string {
+				// the classic confusion: the public modulus used as an HMAC secret
+				return signToken(t, jwt.SigningMethodHS256, "open", rsaKey.PublicKey.N.Bytes(), serviceClaims(nil))
+			},
+		},
+		{
+			name: "HS256 against a key pinned to RS256",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodHS256, "rsa", rsaKey.PublicKey.N.Bytes(), serviceClaims(nil))
+			},
+		},
+		{
+			name:  "RS384 against a key pinned to RS256",
+			token: func() string { return signToken(t, jwt.SigningMethodRS384, "rsa", rsaKey, serviceClaims(nil)) },
+		},
+		{
+			name:  "unknown kid",
+			token: func() string { return signToken(t, jwt.SigningMethodRS256, "gone", rsaKey, serviceClaims(nil)) },
+		},
+		{
+			name:  "kid of another key",
+			token: func() string { return signToken(t, jwt.SigningMethodRS256, "rsa", otherKey, serviceClaims(nil)) },
+		},
+		{
+			name:  "missing kid with several keys",
+			token: func() string { return signToken(t, jwt.SigningMethodRS256, "", rsaKey, serviceClaims(nil)) },
+		},
+		{
+			name: "expired",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				}))
+			},
+		},
+		{
+			name: "expired within leeway",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+				}))
+			},
+			ok: true,
+		},
+		{
+			name: "no expiry",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.ExpiresAt = nil
+				}))
+			},
+		},
+		{
+			name: "not yet valid",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Minute))
+				}))
+			},
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.Issuer = "https://evil.test"
+				}))
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.Audience = jwt.ClaimStrings{"other-service"}
+				}))
+			},
+		},
+		{
+			name: "no subject",
+			token: func() string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa", rsaKey, serviceClaims(func(c *ServiceClaims) {
+					c.Subject = ""
+				}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(tt.token())
+			if tt.ok && err != nil {
+				t.Fatalf("expected the token to verify, got %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatalf("expected the token to be rejected, got %+v", claims)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_SingleKeyWithoutKid(t *testing.T) {
+	rsaKey := testRSAKey(t)
+	v := NewJWTVerifier(map[string]JWK{"only": {ID: "only", Key: &rsaKey.PublicKey}}, "", "")
+
+	claims, err := v.Verify(signToken(t, jwt.SigningMethodRS256, "", rsaKey, serviceClaims(nil)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := claims.Scopes(); len(got) != 2 || got[0] != "payments:read" {
+		t.Fatalf("unexpected scopes %v", got)
+	}
+}
+
+func TestJWTVerifier_ReloadsOnUnknownKid(t *testing.T) {
+	oldKey := testRSAKey(t)
+	newKey := testRSAKey(t)
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	loads := 0
+	var loadErr error
+	v := NewJWTVerifier(map[string]JWK{"old": {ID: "old", Key: &oldKey.PublicKey}}, "", "").
+		WithReload(func() (map[string]JWK, error) {
+			loads++
+			if loadErr != nil {
+				return nil, loadErr
+			}
+			return map[string]JWK{
+				"old": {ID: "old", Key: &oldKey.PublicKey},
+				"new": {ID: "new", Key: &newKey.PublicKey},
+			}, nil
+		}, time.Minute)
+	v.now = func() time.Time { return now }
+
+	if _, err := v.Verify(signToken(t, jwt.SigningMethodRS256, "old", oldKey, serviceClaims(nil))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loads != 0 {
+		t.Fatalf("a known kid must not reload the set")
+	}
+
+	// a key added after startup is picked up
+	if _, err := v.Verify(signToken(t, jwt.SigningMethodRS256, "new", newKey, serviceClaims(nil))); err != nil {
+		t.Fatalf("expected the new key to be loaded, got %v", err)
+	}
+	if loads != 1 {
+		t.Fatalf("expected one reload, got %d", loads)
+	}
+
+	// unknown kids do not reload again within the interval
+	bogus := signToken(t, jwt.SigningMethodRS256, "bogus", newKey, serviceClaims(nil))
+	for range 3 {
+		if _, err := v.Verify(bogus); err == nil {
+			t.Fatalf("expected an unknown kid to be rejected")
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected reloads to be throttled, got %d", loads)
+	}
+
+	// a failed reload keeps the keys already loaded
+	now = now.Add(time.Minute)
+	loadErr = errors.New("file missing")
+	if _, err := v.Verify(bogus); err == nil {
+		t.Fatalf("expected an unknown kid to be rejected")
+	}
+	if loads != 2 {
+		t.Fatalf("expected a reload after the interval, got %d", loads)
+	}
+	if _, err := v.Verify(signToken(t, jwt.SigningMethodRS256, "new", newKey, serviceClaims(nil))); err != nil {
+		t.Fatalf("expected the loaded keys to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package auth authenticates callers independently of the transport, so the
+// HTTP and gRPC servers accept the same credentials in the same way.
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"payment-service/internal/core/domain"
+)
+
+// apiKeyAuthenticator resolves a merchant API key, like
+// usecase.AuthenticateAPIKeyUsecase.
+type apiKeyAuthenticator interface {
+	Execute(ctx context.Context, secret string) (domain.Tenant, error)
+}
+
+// serviceTokenAuthenticator resolves the merchant a service token acts
+// for, like usecase.AuthenticateServiceTokenUsecase.
+type serviceTokenAuthenticator interface {
+	Execute(ctx context.Context, merchantID string, mode domain.Mode) (domain.Tenant, error)
+}
+
+// MerchantAuthenticator authenticates callers of the merchant API:
+// merchants by their API key, internal services by a JWT bound to a merchant
+// through its merchant_id claim.
+type MerchantAuthenticator struct {
+	apiKeys       apiKeyAuthenticator
+	serviceTokens serviceTokenAuthenticator
+	// verifier is nil when JWTs are not accepted.
+	verifier *JWTVerifier
+}
+
+func NewMerchantAuthenticator(
+	apiKeys apiKeyAuthenticator,
+	serviceTokens serviceTokenAuthenticator,
+	verifier *JWTVerifier,
+) *MerchantAuthenticator {
+	return &MerchantAuthenticator{
+		apiKeys:       apiKeys,
+		serviceTokens: serviceTokens,
+		verifier:      verifier,
+	}
+}
+
+// Authenticate returns who the bearer token belongs to and the merchant it
+// acts for. Bad credentials fail with domain.ErrInvalidAPIKey or
+// domain.ErrInvalidToken, a token not bound to a merchant with
+// domain.ErrTokenMerchantRequired; any other error is an internal failure.
+func (a *MerchantAuthenticator) Authenticate(
+	ctx context.Context,
+	token string,
+) (domain.Principal, domain.Tenant, error) {
+	if a.verifier != nil && LooksLikeJWT(token) {
+		claims, err := a.verifier.Verify(token)
+		if err != nil {
+			return domain.Principal{}, domain.Tenant{}, domain.ErrInvalidToken
+		}
+		tenant, err := a.serviceTokens.Execute(ctx, claims.MerchantID, domain.Mode(claims.Mode))
+		if err != nil {
+			return domain.Principal{}, domain.Tenant{}, err
+		}
+		return claims.Principal(), tenant, nil
+	}
+
+	tenant, err := a.apiKeys.Execute(ctx, token)
+	if err != nil {
+		return domain.Principal{}, domain.Tenant{}, err
+	}
+	return domain.Principal{
+		Subject: fmt.Sprintf("api_key:%d", tenant.APIKeyID),
+		Scopes:  domain.MerchantScopes,
+	}, tenant, nil
+}
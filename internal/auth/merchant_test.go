@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"payment-service/internal/core/domain"
+)
+
+// fakeAPIKeys knows the secret "sk_live_1" of merchant 1.
+type fakeAPIKeys struct{}
+
+func (fakeAPIKeys) Execute(ctx context.Context, secret string) (domain.Tenant, error) {
+	if secret != "sk_live_1" {
+		return domain.Tenant{}, domain.ErrInvalidAPIKey
+	}
+	return domain.Tenant{MerchantID: 1, Mode: domain.ModeLive, APIKeyID: 7}, nil
+}
+
+// fakeServiceTokens knows merchant "mer_1".
+type fakeServiceTokens struct{}
+
+func (fakeServiceTokens) Execute(ctx context.Context, merchantID string, mode domain.Mode) (domain.Tenant, error) {
+	switch merchantID {
+	case "":
+		return domain.Tenant{}, domain.ErrTokenMerchantRequired
+	case "mer_1":
+		return domain.Tenant{MerchantID: 1, Mode: mode}, nil
+	default:
+		return domain.Tenant{}, domain.ErrInvalidToken
+	}
+}
+
+func TestMerchantAuthenticator(t *testing.T) {
+	key := testRSAKey(t)
+	verifier := NewJWTVerifier(map[string]JWK{"k1": {ID: "k1", Key: &key.PublicKey}}, "", "")
+	authn := NewMerchantAuthenticator(fakeAPIKeys{}, fakeServiceTokens{}, verifier)
+
+	serviceToken := func(merchantID string) string {
+		return signToken(t, jwt.SigningMethodRS256, "k1", key, serviceClaims(func(c *ServiceClaims) {
+			c.MerchantID = merchantID
+			c.Mode = string(domain.ModeTest)
+			c.Scope = domain.ScopePaymentsRead
+		}))
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		err      error
+		subject  string
+		scopes   []string
+		merchant int
+	}{
+		{"api key", "sk_live_1", nil, "api_key:7", domain.MerchantScopes, 1},
+		{"unknown api key", "sk_live_2", domain.ErrInvalidAPIKey, "", nil, 0},
+		{"no token", "", domain.ErrInvalidAPIKey, "", nil, 0},
+		{"service token", serviceToken("mer_1"), nil, "svc-billing", []string{domain.ScopePaymentsRead}, 1},
+		{"service token without merchant", serviceToken(""), domain.ErrTokenMerchantRequired, "", nil, 0},
+		{"service token of unknown merchant", serviceToken("mer_9"), domain.ErrInvalidToken, "", nil, 0},
+		{"forged service token", serviceToken("mer_1")[:20] + ".e30.c2ln", domain.ErrInvalidToken, "", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, tenant, err := authn.Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if principal.Subject != tt.subject || len(principal.Scopes) != len(tt.scopes) {
+				t.Fatalf("unexpected principal %+v", principal)
+			}
+			for _, scope := range tt.scopes {
+				if !principal.HasScope(scope) {
+					t.Fatalf("expected scope %s, got %v", scope, principal.Scopes)
+				}
+			}
+			if tenant.MerchantID != tt.merchant {
+				t.Fatalf("expected merchant %d, got %+v", tt.merchant, tenant)
+			}
+		})
+	}
+}
+
+func TestMerchantAuthenticator_WithoutVerifier(t *testing.T) {
+	key := testRSAKey(t)
+	authn := NewMerchantAuthenticator(fakeAPIKeys{}, fakeServiceTokens{}, nil)
+
+	// without a JWKS a JWT is just an unknown API key
+	token := signToken(t, jwt.SigningMethodRS256, "k1", key, serviceClaims(func(c *ServiceClaims) {
+		c.MerchantID = "mer_1"
+	}))
+	if _, _, err := authn.Authenticate(context.Background(), token); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+	}
+}
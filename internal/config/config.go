@@ -1,6 +1,13 @@
 package config
 
-import "os"
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type databaseConfig struct {
 	DSN string
@@ -9,11 +16,267 @@ type databaseConfig struct {
 type appConfig struct {
 	Port        string
 	ServiceName string
+	// Env is the deployment environment. Only "dev" turns on the insecure
+	// shortcuts meant for running the service locally.
+	Env string
+	// ValidateResponses checks every /v1 response against the OpenAPI
+	// document. Meant for test deployments, it buffers all responses.
+	ValidateResponses bool
+	// DrainDelay is how long the service keeps serving after a shutdown
+	// signal while reporting not ready, so load balancers stop routing to
+	// it first.
+	DrainDelay time.Duration
+	// ShutdownTimeout bounds the wait for in-flight requests and background
+	// workers once the service stops accepting requests.
+	ShutdownTimeout time.Duration
+	// TrustedProxies are the addresses or CIDRs of the proxies whose
+	// X-Forwarded-For is believed. With none the peer address is used.
+	TrustedProxies []string
+}
+
+type grpcConfig struct {
+	// Port is where the gRPC API for internal services listens.
+	Port string
+}
+
+type eventsConfig struct {
+	// Heartbeat is how often a comment is sent on idle event streams.
+	Heartbeat time.Duration
+	// MaxStreams caps the open event streams, MaxStreamsPerMerchant those
+	// of a single merchant.
+	MaxStreams            int
+	MaxStreamsPerMerchant int
+	// History is how many status changes of a payment are kept for clients
+	// resuming a stream, for Retention after the last one.
+	History   int
+	Retention time.Duration
+}
+
+type batchConfig struct {
+	// MaxItems caps the payments submitted in one batch or rows in one
+	// imported file.
+	MaxItems int
+	// MaxFileSize is the largest payment file accepted for import, in bytes.
+	MaxFileSize int
+	// Concurrency is how many payments of a batch are created at once.
+	Concurrency int
+	// Interval is how often submitted batches are picked up.
+	Interval time.Duration
+}
+
+type providerConfig struct {
+	// CircuitFailures is how many provider calls in a row may fail before
+	// calls are refused for CircuitCooldown.
+	CircuitFailures int
+	CircuitCooldown time.Duration
+}
+
+type healthConfig struct {
+	// CheckTimeout bounds each readiness check.
+	CheckTimeout time.Duration
+}
+
+type vaultConfig struct {
+	// EncryptionKey is the base64 encoded 32 byte AES key used to encrypt
+	// card data at rest.
+	EncryptionKey string
+}
+
+type billingConfig struct {
+	// Interval is how often the scheduler looks for due subscriptions.
+	Interval time.Duration
+	Provider string
+	// RetryAfter is the dunning schedule for failed subscription charges.
+	RetryAfter []time.Duration
+	// PendingRetryAfter is how long to wait before checking again on a
+	// charge held for review.
+	PendingRetryAfter time.Duration
+}
+
+type checkoutConfig struct {
+	// BaseURL is the public address payment link URLs are built from.
+	BaseURL  string
+	Provider string
+}
+
+type bankTransferConfig struct {
+	// VirtualAccountTTL is how long a virtual account accepts transfers.
+	VirtualAccountTTL time.Duration
+	// ExpiryInterval is how often unpaid payments are checked for expiry.
+	ExpiryInterval time.Duration
+	// WebhookToken authenticates bank callbacks, sent in X-Callback-Token.
+	// Required outside dev.
+	WebhookToken string
+}
+
+type ewalletConfig struct {
+	// SessionTTL is how long the payer has to approve in their wallet.
+	SessionTTL time.Duration
+	// WebhookToken authenticates wallet callbacks, sent in X-Callback-Token.
+	// Required outside dev.
+	WebhookToken string
+}
+
+type qrConfig struct {
+	// TTL is how long a dynamic QR can be paid.
+	TTL time.Duration
+	// WebhookToken authenticates QR network callbacks, sent in
+	// X-Callback-Token. Required outside dev.
+	WebhookToken string
+
+	MerchantID       string
+	MerchantName     string
+	MerchantCity     string
+	MerchantCountry  string
+	MerchantCategory string
+}
+
+type threeDSConfig struct {
+	// ChallengeThreshold is the amount from which the fake ACS asks the
+	// payer for an OTP.
+	ChallengeThreshold int
+	// ChallengeTTL is how long the payer has to complete the challenge.
+	ChallengeTTL time.Duration
+}
+
+type RiskConfig struct {
+	PayerVelocityWindow time.Duration
+	PayerVelocityMax    int
+	OrderVelocityWindow time.Duration
+	OrderVelocityMax    int
+	// AmountThresholds is the largest amount per payment method that does
+	// not count against the payment.
+	AmountThresholds map[string]int
+
+	BlockedPayers []int
+	BlockedIPs    []string
+	BlockedBINs   []string
+
+	// CountryCurrencies is the currency expected from payers in a country.
+	CountryCurrencies map[string]string
+
+	ReviewScore int
+	BlockScore  int
+}
+
+// SpendingLimit caps a payer's payments in a rolling window. Empty Method
+// or Currency match every payment and zero maximums are not enforced.
+type SpendingLimit struct {
+	Name      string        `json:"name"`
+	Method    string        `json:"method"`
+	Currency  string        `json:"currency"`
+	Window    time.Duration `json:"-"`
+	MaxCount  int           `json:"max_count"`
+	MaxAmount int           `json:"max_amount"`
+}
+
+// RateLimit is a token bucket for one route class: Burst requests at once,
+// refilled at Rate requests per second, counted per API key, IP or route.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+	By    string  `json:"by"`
+}
+
+type ReviewConfig struct {
+	// HoldAmount holds payments of at least this amount; zero disables it.
+	HoldAmount          int
+	HoldFirstTimePayers bool
+	// HoldMethods limits holds to these payment methods. Approving a held
+	// payment sends it to the provider, so by default only cards are held.
+	HoldMethods []string
+	// SLA is how long a payment waits for a decision before it expires.
+	SLA time.Duration
+}
+
+type disputeConfig struct {
+	// WebhookToken authenticates provider dispute events, sent in
+	// X-Callback-Token. Required outside dev.
+	WebhookToken string
+	// EvidenceDir is where uploaded evidence files are kept.
+	EvidenceDir string
+	// MaxEvidenceSize is the largest evidence file accepted, in bytes.
+	MaxEvidenceSize int
+	// EvidenceWindow is used when the provider sends no evidence due date.
+	EvidenceWindow time.Duration
+	// ChargebackFee is debited from the merchant for every lost dispute.
+	ChargebackFee int
+}
+
+type PayoutConfig struct {
+	// Schedule is daily, weekly or manual.
+	Schedule string
+	// Weekday is when weekly payouts are made.
+	Weekday time.Weekday
+	// Minimums is the smallest payout per currency.
+	Minimums map[string]int
+	// SettlementDelay is how long a successful payment stays pending.
+	SettlementDelay time.Duration
+	// Interval is how often due payouts are made and tracked.
+	Interval time.Duration
+	// Destination is the merchant bank account paid out to.
+	Destination string
+	// SettleAfter is how long the fake payout provider keeps a payout in
+	// transit.
+	SettleAfter time.Duration
+}
+
+type authConfig struct {
+	// AdminToken guards the admin API. It or JWKSFile is required outside
+	// dev.
+	AdminToken string
+	// KeyRotationGrace is how long a rotated API key keeps working.
+	KeyRotationGrace time.Duration
+	// SigningKeys maps key IDs to the HMAC secrets server-to-server callers
+	// sign requests with. Requests are only checked when it is set.
+	SigningKeys map[string]string
+	// SigningTolerance is how far a signed request's timestamp may be from
+	// the server clock.
+	SigningTolerance time.Duration
+	// JWKSFile is a local JWKS holding the keys internal services' tokens
+	// are verified with. Tokens are only accepted when it is set.
+	JWKSFile string
+	// JWKSReloadInterval is the least time between reads of JWKSFile made
+	// to find the key of a token with an unknown kid.
+	JWKSReloadInterval time.Duration
+	// JWTIssuer and JWTAudience are checked against tokens when set.
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// Dev reports whether the service runs locally, where missing secrets are
+// replaced by insecure defaults instead of refusing to start.
+func (c appConfig) Dev() bool {
+	return c.Env == "dev"
 }
 
 type Config struct {
-	Database databaseConfig
-	App      appConfig
+	Database     databaseConfig
+	App          appConfig
+	GRPC         grpcConfig
+	Events       eventsConfig
+	Batch        batchConfig
+	Provider     providerConfig
+	Health       healthConfig
+	Vault        vaultConfig
+	Billing      billingConfig
+	Checkout     checkoutConfig
+	BankTransfer bankTransferConfig
+	EWallet      ewalletConfig
+	QR           qrConfig
+	ThreeDS      threeDSConfig
+	Risk         RiskConfig
+	// SpendingLimits is read from SPENDING_LIMITS as a JSON array, e.g.
+	// [{"name":"card_10m","method":"credit_card","window":"10m","max_count":5}]
+	SpendingLimits []SpendingLimit
+	Review         ReviewConfig
+	Dispute        disputeConfig
+	Payout         PayoutConfig
+	Auth           authConfig
+	// RateLimits is read from RATE_LIMITS as a JSON object keyed by route
+	// class, e.g. {"payments_create":{"rate":5,"burst":10,"by":"api_key"}}.
+	// Classes left out keep their default; a zero rate disables a class.
+	RateLimits map[string]RateLimit
 }
 
 func LoadConfig() Config {
@@ -27,11 +290,308 @@ func LoadConfig() Config {
 		dsn = "file:payments.db"
 	}
 
+	baseURL := os.Getenv("PUBLIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:" + port
+	}
+
+	holdMethods := listEnv("REVIEW_HOLD_METHODS")
+	if len(holdMethods) == 0 {
+		holdMethods = []string{"credit_card"}
+	}
+
 	return Config{
 		Database: databaseConfig{DSN: dsn},
 		App: appConfig{
 			Port:        port,
 			ServiceName: "payment-service",
+			Env:         stringEnv("APP_ENV", "production"),
+
+			ValidateResponses: os.Getenv("OPENAPI_VALIDATE_RESPONSES") == "true",
+			DrainDelay:        durationEnv("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+			ShutdownTimeout:   durationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+			TrustedProxies:    listEnv("TRUSTED_PROXIES"),
+		},
+		GRPC: grpcConfig{
+			Port: stringEnv("GRPC_PORT", "50051"),
+		},
+		Events: eventsConfig{
+			Heartbeat:             durationEnv("EVENTS_HEARTBEAT", 15*time.Second),
+			MaxStreams:            intEnv("EVENTS_MAX_STREAMS", 1000),
+			MaxStreamsPerMerchant: intEnv("EVENTS_MAX_STREAMS_PER_MERCHANT", 50),
+			History:               intEnv("EVENTS_HISTORY", 32),
+			Retention:             durationEnv("EVENTS_RETENTION", 10*time.Minute),
+		},
+		Batch: batchConfig{
+			MaxItems:    intEnv("BATCH_MAX_ITEMS", 1000),
+			MaxFileSize: intEnv("BATCH_MAX_FILE_SIZE", 5<<20),
+			Concurrency: intEnv("BATCH_CONCURRENCY", 8),
+			Interval:    durationEnv("BATCH_INTERVAL", time.Second),
+		},
+		Provider: providerConfig{
+			CircuitFailures: intEnv("PROVIDER_CIRCUIT_FAILURES", 5),
+			CircuitCooldown: durationEnv("PROVIDER_CIRCUIT_COOLDOWN", 30*time.Second),
+		},
+		Health: healthConfig{
+			CheckTimeout: durationEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second),
 		},
+		Vault: vaultConfig{
+			EncryptionKey: os.Getenv("VAULT_ENCRYPTION_KEY"),
+		},
+		Billing: billingConfig{
+			Interval: durationEnv("BILLING_INTERVAL", time.Minute),
+			Provider: "fake",
+			RetryAfter: []time.Duration{
+				24 * time.Hour,
+				3 * 24 * time.Hour,
+				5 * 24 * time.Hour,
+			},
+			PendingRetryAfter: durationEnv("BILLING_PENDING_RETRY_AFTER", time.Hour),
+		},
+		Checkout: checkoutConfig{
+			BaseURL:  baseURL,
+			Provider: "fake",
+		},
+		BankTransfer: bankTransferConfig{
+			VirtualAccountTTL: durationEnv("VIRTUAL_ACCOUNT_TTL", 24*time.Hour),
+			ExpiryInterval:    durationEnv("EXPIRY_INTERVAL", time.Minute),
+			WebhookToken:      os.Getenv("BANK_WEBHOOK_TOKEN"),
+		},
+		EWallet: ewalletConfig{
+			SessionTTL:   durationEnv("EWALLET_SESSION_TTL", 15*time.Minute),
+			WebhookToken: os.Getenv("EWALLET_WEBHOOK_TOKEN"),
+		},
+		QR: qrConfig{
+			TTL:              durationEnv("QR_TTL", 15*time.Minute),
+			WebhookToken:     os.Getenv("QR_WEBHOOK_TOKEN"),
+			MerchantID:       stringEnv("QR_MERCHANT_ID", "ID1020000000001"),
+			MerchantName:     stringEnv("QR_MERCHANT_NAME", "Payment Service"),
+			MerchantCity:     stringEnv("QR_MERCHANT_CITY", "Jakarta"),
+			MerchantCountry:  stringEnv("QR_MERCHANT_COUNTRY", "ID"),
+			MerchantCategory: stringEnv("QR_MERCHANT_CATEGORY", "5999"),
+		},
+		ThreeDS: threeDSConfig{
+			ChallengeThreshold: intEnv("THREE_DS_CHALLENGE_THRESHOLD", 1000000),
+			ChallengeTTL:       durationEnv("THREE_DS_CHALLENGE_TTL", 10*time.Minute),
+		},
+		Risk: RiskConfig{
+			PayerVelocityWindow: durationEnv("RISK_PAYER_VELOCITY_WINDOW", 10*time.Minute),
+			PayerVelocityMax:    intEnv("RISK_PAYER_VELOCITY_MAX", 10),
+			OrderVelocityWindow: durationEnv("RISK_ORDER_VELOCITY_WINDOW", time.Hour),
+			OrderVelocityMax:    intEnv("RISK_ORDER_VELOCITY_MAX", 5),
+			AmountThresholds: intMapEnv("RISK_AMOUNT_THRESHOLDS", map[string]int{
+				"credit_card":   10000000,
+				"ewallet":       5000000,
+				"qr":            5000000,
+				"bank_transfer": 100000000,
+			}),
+			BlockedPayers: intListEnv("RISK_BLOCKED_PAYERS"),
+			BlockedIPs:    listEnv("RISK_BLOCKED_IPS"),
+			BlockedBINs:   listEnv("RISK_BLOCKED_BINS"),
+			CountryCurrencies: stringMapEnv("RISK_COUNTRY_CURRENCIES", map[string]string{
+				"ID": "IDR",
+				"SG": "SGD",
+				"MY": "MYR",
+				"TH": "THB",
+				"PH": "PHP",
+				"VN": "VND",
+			}),
+			ReviewScore: intEnv("RISK_REVIEW_SCORE", 50),
+			BlockScore:  intEnv("RISK_BLOCK_SCORE", 90),
+		},
+		SpendingLimits: spendingLimitsEnv("SPENDING_LIMITS", []SpendingLimit{
+			{Name: "payer_hourly", Window: time.Hour, MaxCount: 20},
+			{Name: "card_attempts", Method: "credit_card", Window: 10 * time.Minute, MaxCount: 5},
+			{Name: "idr_daily_amount", Currency: "IDR", Window: 24 * time.Hour, MaxAmount: 50000000},
+		}),
+		Review: ReviewConfig{
+			HoldAmount:          intEnv("REVIEW_HOLD_AMOUNT", 20000000),
+			HoldFirstTimePayers: os.Getenv("REVIEW_HOLD_FIRST_TIME_PAYERS") == "true",
+			HoldMethods:         holdMethods,
+			SLA:                 durationEnv("REVIEW_SLA", 24*time.Hour),
+		},
+		Dispute: disputeConfig{
+			WebhookToken:    os.Getenv("DISPUTE_WEBHOOK_TOKEN"),
+			EvidenceDir:     stringEnv("DISPUTE_EVIDENCE_DIR", "./data/evidence"),
+			MaxEvidenceSize: intEnv("DISPUTE_MAX_EVIDENCE_SIZE", 5<<20),
+			EvidenceWindow:  durationEnv("DISPUTE_EVIDENCE_WINDOW", 7*24*time.Hour),
+			ChargebackFee:   intEnv("DISPUTE_CHARGEBACK_FEE", 15000),
+		},
+		Payout: PayoutConfig{
+			Schedule: stringEnv("PAYOUT_SCHEDULE", "daily"),
+			Weekday:  weekdayEnv("PAYOUT_WEEKDAY", time.Monday),
+			Minimums: intMapEnv("PAYOUT_MINIMUMS", map[string]int{
+				"IDR": 100000,
+				"SGD": 10,
+				"MYR": 50,
+				"THB": 300,
+				"PHP": 500,
+				"VND": 250000,
+			}),
+			SettlementDelay: durationEnv("PAYOUT_SETTLEMENT_DELAY", 48*time.Hour),
+			Interval:        durationEnv("PAYOUT_INTERVAL", time.Minute),
+			Destination:     stringEnv("PAYOUT_DESTINATION", "BCA-1234567890"),
+			SettleAfter:     durationEnv("PAYOUT_SETTLE_AFTER", 30*time.Second),
+		},
+		Auth: authConfig{
+			AdminToken:         os.Getenv("ADMIN_API_KEY"),
+			KeyRotationGrace:   durationEnv("API_KEY_ROTATION_GRACE", 24*time.Hour),
+			SigningKeys:        stringMapEnv("SIGNING_KEYS", nil),
+			SigningTolerance:   durationEnv("SIGNING_TOLERANCE", 5*time.Minute),
+			JWKSFile:           os.Getenv("JWT_JWKS_FILE"),
+			JWKSReloadInterval: durationEnv("JWT_JWKS_RELOAD_INTERVAL", time.Minute),
+			JWTIssuer:          os.Getenv("JWT_ISSUER"),
+			JWTAudience:        os.Getenv("JWT_AUDIENCE"),
+		},
+		RateLimits: rateLimitsEnv("RATE_LIMITS", map[string]RateLimit{
+			"payments_create": {Rate: 10, Burst: 20, By: "api_key"},
+			"merchant":        {Rate: 50, Burst: 100, By: "api_key"},
+			"admin":           {Rate: 10, Burst: 20, By: "ip"},
+			"public":          {Rate: 5, Burst: 20, By: "ip"},
+			"webhooks":        {Rate: 100, Burst: 200, By: "route"},
+		}),
+	}
+}
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+func weekdayEnv(key string, fallback time.Weekday) time.Weekday {
+	v := strings.ToLower(os.Getenv(key))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if v == strings.ToLower(d.String()) {
+			return d
+		}
+	}
+	return fallback
+}
+
+func stringEnv(key string, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func intEnv(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+// listEnv reads a comma separated list, skipping empty entries.
+func listEnv(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func intListEnv(key string) []int {
+	var out []int
+	for _, v := range listEnv(key) {
+		if n, err := strconv.Atoi(v); err == nil {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// stringMapEnv reads comma separated key=value pairs such as
+// "ID=IDR,SG=SGD". The fallback is used when the variable is unset.
+func stringMapEnv(key string, fallback map[string]string) map[string]string {
+	pairs := listEnv(key)
+	if len(pairs) == 0 {
+		return fallback
+	}
+	out := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if ok {
+			out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return out
+}
+
+func intMapEnv(key string, fallback map[string]int) map[string]int {
+	pairs := stringMapEnv(key, nil)
+	if pairs == nil {
+		return fallback
+	}
+	out := make(map[string]int, len(pairs))
+	for k, v := range pairs {
+		if n, err := strconv.Atoi(v); err == nil {
+			out[k] = n
+		}
+	}
+	return out
+}
+
+func spendingLimitsEnv(key string, fallback []SpendingLimit) []SpendingLimit {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	var entries []struct {
+		SpendingLimit
+		Window string `json:"window"`
+	}
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		log.Printf("%s is not valid, using defaults: %v", key, err)
+		return fallback
+	}
+
+	limits := make([]SpendingLimit, 0, len(entries))
+	for _, e := range entries {
+		window, err := time.ParseDuration(e.Window)
+		if err != nil || window <= 0 {
+			log.Printf("%s: limit %q has an invalid window, skipping", key, e.Name)
+			continue
+		}
+		e.SpendingLimit.Window = window
+		limits = append(limits, e.SpendingLimit)
+	}
+	return limits
+}
+
+func rateLimitsEnv(key string, defaults map[string]RateLimit) map[string]RateLimit {
+	limits := make(map[string]RateLimit, len(defaults))
+	for class, limit := range defaults {
+		limits[class] = limit
+	}
+
+	raw := os.Getenv(key)
+	if raw == "" {
+		return limits
+	}
+
+	var overrides map[string]RateLimit
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		log.Printf("%s is not valid, using defaults: %v", key, err)
+		return limits
+	}
+	for class, limit := range overrides {
+		switch limit.By {
+		case "":
+			limit.By = "api_key"
+		case "api_key", "ip", "route":
+		default:
+			log.Printf("%s: class %q has an unknown key %q, skipping", key, class, limit.By)
+			continue
+		}
+		limits[class] = limit
 	}
+	return limits
 }
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDisputeNotFound        = errors.New("dispute not found")
+	ErrDisputeClosed          = errors.New("dispute is already closed")
+	ErrPaymentNotDisputable   = errors.New("only successful payments can be disputed")
+	ErrInvalidDisputeAmount   = errors.New("dispute amount must be between 1 and the payment amount")
+	ErrUnknownDisputeEvent    = errors.New("unknown dispute event")
+	ErrInvalidDisputeStatus   = errors.New("dispute cannot change to the requested status")
+	ErrEvidenceDeadlinePassed = errors.New("evidence due date has passed")
+	ErrEvidenceRequired       = errors.New("upload evidence before submitting")
+	ErrInvalidEvidence        = errors.New("evidence must be a pdf, png, jpeg or text file within the size limit")
+)
+
+type DisputeStatus string
+
+const (
+	// DisputeStatusNeedsResponse waits for the merchant to send evidence.
+	DisputeStatusNeedsResponse DisputeStatus = "needs_response"
+	DisputeStatusUnderReview   DisputeStatus = "under_review"
+	DisputeStatusWon           DisputeStatus = "won"
+	DisputeStatusLost          DisputeStatus = "lost"
+)
+
+func (s DisputeStatus) IsClosed() bool {
+	return s == DisputeStatusWon || s == DisputeStatusLost
+}
+
+// DisputeEvent is a notification about a dispute from the provider.
+type DisputeEvent string
+
+const (
+	DisputeEventOpened      DisputeEvent = "dispute.opened"
+	DisputeEventUnderReview DisputeEvent = "dispute.under_review"
+	DisputeEventWon         DisputeEvent = "dispute.won"
+	DisputeEventLost        DisputeEvent = "dispute.lost"
+)
+
+// Dispute is a chargeback raised by the cardholder's bank against a
+// successful payment.
+type Dispute struct {
+	ID       int
+	PublicID string
+
+	MerchantID int
+
+	PaymentID string
+	// Reference is the provider's id for the dispute.
+	Reference string
+
+	Reason   string
+	Amount   int
+	Currency string
+	Status   DisputeStatus
+
+	EvidenceDueBy time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+}
+
+func (d *Dispute) CanTransitionTo(next DisputeStatus) bool {
+	switch d.Status {
+	case DisputeStatusNeedsResponse:
+		return next == DisputeStatusUnderReview ||
+			next == DisputeStatusWon ||
+			next == DisputeStatusLost
+	case DisputeStatusUnderReview:
+		return next == DisputeStatusWon ||
+			next == DisputeStatusLost
+	default:
+		return false
+	}
+}
+
+// TransitionTo moves the dispute to next, recording when it closed.
+func (d *Dispute) TransitionTo(next DisputeStatus, now time.Time) error {
+	if !d.CanTransitionTo(next) {
+		if d.Status.IsClosed() {
+			return ErrDisputeClosed
+		}
+		return ErrInvalidDisputeStatus
+	}
+
+	d.Status = next
+	d.UpdatedAt = now
+	if next.IsClosed() {
+		d.ClosedAt = &now
+	}
+	return nil
+}
+
+// DisputeEvidence is a file the merchant uploaded to contest a dispute.
+type DisputeEvidence struct {
+	ID          int
+	DisputeID   int
+	FileName    string
+	ContentType string
+	Size        int64
+	// Path is where the file is kept by the evidence store.
+	Path      string
+	Note      string
+	CreatedAt time.Time
+}
+
+// EvidenceContentTypes are the file types accepted as evidence.
+var EvidenceContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"text/plain":      true,
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrEWalletAuthorizationNotFound = errors.New("e-wallet authorization not found")
+	ErrUnsupportedWallet            = errors.New("wallet is not supported")
+	ErrInvalidReturnURL             = errors.New("return url must be an absolute http(s) url")
+	ErrUnknownEWalletEvent          = errors.New("unknown e-wallet event")
+)
+
+const PaymentMethodEWallet = "ewallet"
+
+// NextActionType tells the client how the payer approves the payment.
+type NextActionType string
+
+const (
+	NextActionRedirect NextActionType = "redirect_to_url"
+	NextActionDeeplink NextActionType = "open_deeplink"
+	NextActionQR       NextActionType = "display_qr"
+)
+
+// EWallet channels describe where the payer started the payment.
+const (
+	EWalletChannelWeb    = "web"
+	EWalletChannelMobile = "mobile"
+	EWalletChannelQR     = "qr"
+)
+
+// NextAction is what the client must do before the payment can complete.
+// All three forms are filled in; Type is the one suited to the channel.
+type NextAction struct {
+	Type        NextActionType
+	RedirectURL string
+	Deeplink    string
+	QRString    string
+}
+
+// NextActionTypeForChannel picks the preferred action for a channel. Web
+// is the default.
+func NextActionTypeForChannel(channel string) NextActionType {
+	switch channel {
+	case EWalletChannelMobile:
+		return NextActionDeeplink
+	case EWalletChannelQR:
+		return NextActionQR
+	default:
+		return NextActionRedirect
+	}
+}
+
+// EWalletAuthorization is the approval request sent to the payer's wallet
+// for an e-wallet payment.
+type EWalletAuthorization struct {
+	ID int
+	// PaymentID is the public id of the payment being authorized.
+	PaymentID string
+	Wallet    string
+	Channel   string
+	// Reference is the wallet's id for the authorization.
+	Reference string
+	// ReturnURL is where the payer is sent back to after approving.
+	ReturnURL  string
+	NextAction NextAction
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// EWalletEvent is a status notification sent by the wallet.
+type EWalletEvent string
+
+const (
+	// EWalletEventAcknowledged means the payer opened the approval request.
+	EWalletEventAcknowledged EWalletEvent = "acknowledged"
+	EWalletEventApproved     EWalletEvent = "approved"
+	EWalletEventDeclined     EWalletEvent = "declined"
+)
+
+// ValidReturnURL reports whether raw can be used as a return url. An empty
+// value is allowed.
+func ValidReturnURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type LedgerEntryType string
+
+const (
+	// LedgerEntryChargeback takes back the disputed amount of a lost
+	// dispute.
+	LedgerEntryChargeback LedgerEntryType = "chargeback"
+	// LedgerEntryChargebackFee is what the provider charges for a lost
+	// dispute.
+	LedgerEntryChargebackFee LedgerEntryType = "chargeback_fee"
+)
+
+// LedgerEntry adjusts the money collected for a payment. Debits are
+// negative.
+type LedgerEntry struct {
+	ID        int
+	PaymentID string
+	Type      LedgerEntryType
+	Amount    int
+	Currency  string
+	// Reference is unique per entry so posting the same adjustment twice
+	// has no effect.
+	Reference   string
+	Description string
+	CreatedAt   time.Time
+}
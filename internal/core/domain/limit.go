@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrLimitExceeded = errors.New("limit_exceeded")
+
+// LimitExceededError names the spending limit a payment ran into.
+type LimitExceededError struct {
+	Limit string
+}
+
+func (e *LimitExceededError) Error() string {
+	return "limit_exceeded: " + e.Limit
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// SpendingLimit caps how many payments a payer makes, and for how much, in a
+// rolling window. An empty Method or Currency matches every payment; a zero
+// MaxCount or MaxAmount is not enforced.
+type SpendingLimit struct {
+	Name      string
+	Method    string
+	Currency  string
+	Window    time.Duration
+	MaxCount  int
+	MaxAmount int
+}
+
+func (l SpendingLimit) Applies(method, currency string) bool {
+	return (l.Method == "" || l.Method == method) &&
+		(l.Currency == "" || l.Currency == currency)
+}
+
+// SpendingUsage is what a payer spent within a limit's window, including the
+// payment being checked.
+type SpendingUsage struct {
+	Count  int
+	Amount int
+}
+
+func (l SpendingLimit) ExceededBy(u SpendingUsage) bool {
+	return (l.MaxCount > 0 && u.Count > l.MaxCount) ||
+		(l.MaxAmount > 0 && u.Amount > l.MaxAmount)
+}
+
+// SpendingAttempt is a payment counted against the limits. The idempotency
+// key makes a retried request count once.
+type SpendingAttempt struct {
+	PayerID        int
+	Method         string
+	Currency       string
+	Amount         int
+	IdempotencyKey string
+	At             time.Time
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPayerNotFound      = errors.New("payer not found")
+	ErrPayerNotActive     = errors.New("payer is not active")
+	ErrPayerAlreadyExists = errors.New("payer with this external reference already exists")
+)
+
+type PayerStatus string
+
+const (
+	PayerStatusActive   PayerStatus = "ACTIVE"
+	PayerStatusInactive PayerStatus = "INACTIVE"
+	PayerStatusBlocked  PayerStatus = "BLOCKED"
+)
+
+func (s PayerStatus) IsValid() bool {
+	switch s {
+	case PayerStatusActive,
+		PayerStatusInactive,
+		PayerStatusBlocked:
+		return true
+	default:
+		return false
+	}
+}
+
+type Payer struct {
+	ID         int
+	MerchantID int
+
+	Name              string
+	Email             string
+	Country           string
+	ExternalReference string
+
+	Status PayerStatus
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CanPay reports whether new payments may be created on behalf of the payer.
+func (p *Payer) CanPay() bool {
+	return p.Status == PayerStatusActive
+}
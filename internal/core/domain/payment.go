@@ -1,11 +1,42 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPaymentNotFound       = errors.New("payment not found")
+	ErrInvalidPaymentStatus  = errors.New("payment cannot change to the requested status")
+	ErrPaymentStatusConflict = errors.New("payment status was changed concurrently")
+	// ErrProviderUnavailable is returned without calling the provider while
+	// it keeps failing.
+	ErrProviderUnavailable = errors.New("payment provider is unavailable")
+	ErrPaymentDeclined     = errors.New("payment was declined by the provider")
+	ErrInvalidPayment      = errors.New("invalid payment")
+)
+
+// InvalidPaymentError says what is wrong with a payment request.
+type InvalidPaymentError struct {
+	Reason string
+}
+
+func (e *InvalidPaymentError) Error() string {
+	return e.Reason
+}
+
+func (e *InvalidPaymentError) Unwrap() error {
+	return ErrInvalidPayment
+}
 
 type Payment struct {
 	ID       int
 	PublicID string
 
+	MerchantID int
+	// Mode is test for payments made with a test API key.
+	Mode Mode
+
 	OrderID string
 	PayerID int
 
@@ -16,9 +47,44 @@ type Payment struct {
 	Provider string
 	Method   string
 
+	// PaymentMethodToken references the saved instrument used, if any.
+	PaymentMethodToken string
+
+	// BankCode, Wallet, Channel, ReturnURL and OffSession are the options
+	// the payment was requested with. A payment held for review is started
+	// with them once it is approved.
+	BankCode   string
+	Wallet     string
+	Channel    string
+	ReturnURL  string
+	OffSession bool
+
 	IdempotencyKey string
 
+	// CreatedBy is the subject of the API key or service token the payment
+	// was created with, empty for payments created by background jobs.
+	CreatedBy string
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	PaidAt    *time.Time
+	// ExpiresAt is set for asynchronous methods that wait for the payer.
+	ExpiresAt *time.Time
+
+	// ThreeDSStatus is the outcome of card authentication, empty when the
+	// payment was not authenticated.
+	ThreeDSStatus ThreeDSStatus
+	// LiabilityShift is set when a successful authentication moved fraud
+	// liability to the card issuer.
+	LiabilityShift bool
+
+	// RiskScore, RiskDecision and RiskRules record the risk assessment made
+	// before the payment was sent to the provider.
+	RiskScore    int
+	RiskDecision RiskDecision
+	RiskRules    []string
+
+	// Splits allocates a marketplace payment between recipients. It is
+	// empty for payments that are not split.
+	Splits []PaymentSplit
 }
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPaymentBatchNotFound   = errors.New("payment batch not found")
+	ErrEmptyPaymentBatch      = errors.New("payment batch has no items")
+	ErrPaymentBatchTooLarge   = errors.New("payment batch has too many items")
+	ErrDuplicateBatchItemKeys = errors.New("idempotency keys must be unique within a batch")
+	ErrInvalidPaymentFile     = errors.New("invalid payment file")
+	ErrPaymentFileTooLarge    = errors.New("payment file is too large")
+)
+
+// PaymentBatchSource is how a batch was submitted.
+type PaymentBatchSource string
+
+const (
+	PaymentBatchSourceAPI PaymentBatchSource = "api"
+	// PaymentBatchSourceCSV batches are imported from an uploaded file, one
+	// item per row.
+	PaymentBatchSourceCSV PaymentBatchSource = "csv"
+)
+
+type PaymentBatchStatus string
+
+const (
+	PaymentBatchStatusProcessing PaymentBatchStatus = "processing"
+	PaymentBatchStatusCompleted  PaymentBatchStatus = "completed"
+)
+
+type PaymentBatchItemStatus string
+
+const (
+	PaymentBatchItemPending PaymentBatchItemStatus = "pending"
+	PaymentBatchItemCreated PaymentBatchItemStatus = "created"
+	// PaymentBatchItemDuplicate means the item's idempotency key was used
+	// before; its payment is the one created then.
+	PaymentBatchItemDuplicate PaymentBatchItemStatus = "duplicate"
+	PaymentBatchItemFailed    PaymentBatchItemStatus = "failed"
+)
+
+// PaymentBatch is a set of payments submitted together and created in the
+// background.
+type PaymentBatch struct {
+	ID       int
+	PublicID string
+
+	MerchantID int
+	Mode       Mode
+	// CreatedBy is the subject the batch was submitted by; its payments are
+	// recorded as created by it too.
+	CreatedBy string
+	// ClientIP is the address the batch was submitted from. Its payments are
+	// risk-scored as made from it.
+	ClientIP string
+
+	Source PaymentBatchSource
+	// FileName is the uploaded file's name for imported batches.
+	FileName string
+
+	Status PaymentBatchStatus
+	Items  []PaymentBatchItem
+
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// Progress counts the batch's items by status.
+func (b *PaymentBatch) Progress() map[PaymentBatchItemStatus]int {
+	counts := map[PaymentBatchItemStatus]int{
+		PaymentBatchItemPending:   0,
+		PaymentBatchItemCreated:   0,
+		PaymentBatchItemDuplicate: 0,
+		PaymentBatchItemFailed:    0,
+	}
+	for _, item := range b.Items {
+		counts[item.Status]++
+	}
+	return counts
+}
+
+// PaymentBatchItem is one payment of a batch and what became of it.
+type PaymentBatchItem struct {
+	ID      int
+	BatchID int
+	// Position is the item's index in the submitted batch, or its row for
+	// imported batches, not counting the header.
+	Position int
+
+	OrderID            string
+	PayerID            int
+	Amount             int
+	Currency           string
+	Provider           string
+	Method             string
+	PaymentMethodToken string
+	BankCode           string
+	Wallet             string
+	Channel            string
+	ReturnURL          string
+	Splits             []SplitShare
+	IdempotencyKey     string
+
+	Status PaymentBatchItemStatus
+	// Attempts counts the runs that could not create the payment for a
+	// reason worth retrying.
+	Attempts int
+	// PaymentID and PaymentStatus are set for created and duplicate items.
+	PaymentID     string
+	PaymentStatus PaymentStatus
+	// ErrorCode and Error say why a failed item was not created.
+	ErrorCode string
+	Error     string
+}
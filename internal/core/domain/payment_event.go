@@ -0,0 +1,22 @@
+package domain
+
+// PaymentEvent is a payment as it was right after a status change.
+type PaymentEvent struct {
+	// ID orders the events of a payment. It is the payment's updated_at in
+	// nanoseconds, so it keeps increasing across restarts and clients can
+	// resume from the last event they saw.
+	ID int64
+	// From is the status the payment changed from, empty for a snapshot
+	// of the current state.
+	From    PaymentStatus
+	Payment Payment
+}
+
+// NewPaymentEvent records payment's current state.
+func NewPaymentEvent(payment *Payment, from PaymentStatus) PaymentEvent {
+	return PaymentEvent{
+		ID:      payment.UpdatedAt.UnixNano(),
+		From:    from,
+		Payment: *payment,
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPaymentLinkNotFound    = errors.New("payment link not found")
+	ErrPaymentLinkUnavailable = errors.New("payment link is no longer available")
+)
+
+type PaymentLinkStatus string
+
+const (
+	PaymentLinkStatusActive      PaymentLinkStatus = "ACTIVE"
+	PaymentLinkStatusCompleted   PaymentLinkStatus = "COMPLETED"
+	PaymentLinkStatusExpired     PaymentLinkStatus = "EXPIRED"
+	PaymentLinkStatusDeactivated PaymentLinkStatus = "DEACTIVATED"
+)
+
+type PaymentLink struct {
+	ID       int
+	PublicID string
+
+	MerchantID int
+
+	Amount      int
+	Currency    string
+	Description string
+
+	// SingleUse links stop accepting payments after the first one.
+	SingleUse  bool
+	UsageCount int
+
+	Status    PaymentLinkStatus
+	ExpiresAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// StatusAt reports the link status at now; an active link past its expiry is
+// reported as expired without having to be rewritten.
+func (l *PaymentLink) StatusAt(now time.Time) PaymentLinkStatus {
+	if l.Status == PaymentLinkStatusActive &&
+		l.ExpiresAt != nil &&
+		!now.Before(*l.ExpiresAt) {
+		return PaymentLinkStatusExpired
+	}
+	return l.Status
+}
+
+func (l *PaymentLink) IsOpen(now time.Time) bool {
+	return l.StatusAt(now) == PaymentLinkStatusActive
+}
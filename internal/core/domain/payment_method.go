@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPaymentMethodNotFound = errors.New("payment method not found")
+	ErrPaymentMethodInactive = errors.New("payment method is not active")
+	ErrPaymentMethodNotOwned = errors.New("payment method does not belong to payer")
+	ErrInvalidCardNumber     = errors.New("card number is invalid")
+	ErrCardExpired           = errors.New("card is expired")
+)
+
+const PaymentMethodTypeCard = "credit_card"
+
+type PaymentMethodStatus string
+
+const (
+	PaymentMethodStatusActive   PaymentMethodStatus = "ACTIVE"
+	PaymentMethodStatusDetached PaymentMethodStatus = "DETACHED"
+)
+
+type CardBrand string
+
+const (
+	CardBrandVisa       CardBrand = "visa"
+	CardBrandMastercard CardBrand = "mastercard"
+	CardBrandAmex       CardBrand = "amex"
+	CardBrandJCB        CardBrand = "jcb"
+	CardBrandUnknown    CardBrand = "unknown"
+)
+
+// PaymentMethod is a saved payment instrument. Only non-sensitive card data
+// is kept in clear; the full PAN lives in EncryptedPAN and never leaves the
+// vault.
+type PaymentMethod struct {
+	ID    int
+	Token string
+
+	PayerID int
+	Type    string
+
+	Brand      CardBrand
+	Last4      string
+	BIN        string
+	ExpMonth   int
+	ExpYear    int
+	HolderName string
+
+	EncryptedPAN []byte
+	Fingerprint  string
+
+	Status PaymentMethodStatus
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m *PaymentMethod) IsUsable() bool {
+	return m.Status == PaymentMethodStatusActive
+}
+
+// NormalizePAN strips the separators people usually type into card numbers.
+func NormalizePAN(pan string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(pan)
+}
+
+// LuhnValid reports whether pan is a plausible card number: 12-19 digits
+// with a valid Luhn check digit.
+func LuhnValid(pan string) bool {
+	if len(pan) < 12 || len(pan) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(pan) - 1; i >= 0; i-- {
+		c := pan[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// DetectCardBrand derives the card network from the leading digits.
+func DetectCardBrand(pan string) CardBrand {
+	switch {
+	case strings.HasPrefix(pan, "4"):
+		return CardBrandVisa
+	case strings.HasPrefix(pan, "34"), strings.HasPrefix(pan, "37"):
+		return CardBrandAmex
+	case strings.HasPrefix(pan, "35"):
+		return CardBrandJCB
+	}
+
+	if len(pan) >= 2 && pan[0] == '5' && pan[1] >= '1' && pan[1] <= '5' {
+		return CardBrandMastercard
+	}
+	if len(pan) >= 4 && pan[:4] >= "2221" && pan[:4] <= "2720" {
+		return CardBrandMastercard
+	}
+	return CardBrandUnknown
+}
+
+// CardExpired reports whether a card expiring at the end of month/year is no
+// longer valid at now.
+func CardExpired(month, year int, now time.Time) bool {
+	if month < 1 || month > 12 {
+		return true
+	}
+	// first instant after the expiry month
+	end := time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.Before(end)
+}
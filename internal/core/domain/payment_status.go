@@ -1,19 +1,33 @@
 package domain
 
+import "time"
+
 type PaymentStatus string
 
 const (
 	PaymentStatusPending    PaymentStatus = "PENDING"
 	PaymentStatusProcessing PaymentStatus = "PROCESSING"
-	PaymentStatusSuccess    PaymentStatus = "SUCCESS"
-	PaymentStatusFailed     PaymentStatus = "FAILED"
-	PaymentStatusExpired    PaymentStatus = "EXPIRED"
+	// PaymentStatusRequiresAction waits for the payer to complete a card
+	// authentication challenge.
+	PaymentStatusRequiresAction PaymentStatus = "REQUIRES_ACTION"
+	// PaymentStatusReview holds a payment flagged by the risk engine until
+	// someone approves or rejects it.
+	PaymentStatusReview PaymentStatus = "REVIEW"
+	// PaymentStatusOnHold holds a payment matching the manual review
+	// criteria until someone approves or rejects it.
+	PaymentStatusOnHold  PaymentStatus = "ON_HOLD"
+	PaymentStatusSuccess PaymentStatus = "SUCCESS"
+	PaymentStatusFailed  PaymentStatus = "FAILED"
+	PaymentStatusExpired PaymentStatus = "EXPIRED"
 )
 
 func (s PaymentStatus) IsValid() bool {
 	switch s {
 	case PaymentStatusPending,
 		PaymentStatusProcessing,
+		PaymentStatusRequiresAction,
+		PaymentStatusReview,
+		PaymentStatusOnHold,
 		PaymentStatusSuccess,
 		PaymentStatusFailed,
 		PaymentStatusExpired:
@@ -29,6 +43,11 @@ func (s PaymentStatus) IsFinal() bool {
 		s == PaymentStatusExpired
 }
 
+// AwaitingReview reports whether the payment is in the manual review queue.
+func (s PaymentStatus) AwaitingReview() bool {
+	return s == PaymentStatusReview || s == PaymentStatusOnHold
+}
+
 func (p *Payment) CanTransitionTo(next PaymentStatus) bool {
 	if p.Status.IsFinal() {
 		return false
@@ -37,6 +56,18 @@ func (p *Payment) CanTransitionTo(next PaymentStatus) bool {
 	switch p.Status {
 	case PaymentStatusPending:
 		return next == PaymentStatusProcessing ||
+			next == PaymentStatusRequiresAction ||
+			next == PaymentStatusFailed ||
+			next == PaymentStatusExpired
+
+	case PaymentStatusRequiresAction:
+		return next == PaymentStatusProcessing ||
+			next == PaymentStatusFailed ||
+			next == PaymentStatusExpired
+
+	case PaymentStatusReview, PaymentStatusOnHold:
+		// an approved payment is started as if it was just created
+		return next == PaymentStatusPending ||
 			next == PaymentStatusFailed ||
 			next == PaymentStatusExpired
 
@@ -48,3 +79,25 @@ func (p *Payment) CanTransitionTo(next PaymentStatus) bool {
 		return false
 	}
 }
+
+// TransitionTo moves the payment to next, recording when it was paid.
+func (p *Payment) TransitionTo(next PaymentStatus, now time.Time) error {
+	if !p.CanTransitionTo(next) {
+		return ErrInvalidPaymentStatus
+	}
+
+	p.Status = next
+	p.UpdatedAt = now
+	if next == PaymentStatusSuccess {
+		p.PaidAt = &now
+	}
+	return nil
+}
+
+// IsExpiredAt reports whether a payment still waiting for the payer has run
+// past its expiry.
+func (p *Payment) IsExpiredAt(now time.Time) bool {
+	return !p.Status.IsFinal() &&
+		p.ExpiresAt != nil &&
+		!now.Before(*p.ExpiresAt)
+}
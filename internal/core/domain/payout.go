@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPayoutNotFound       = errors.New("payout not found")
+	ErrInsufficientBalance  = errors.New("payout exceeds the available balance")
+	ErrPayoutBelowMinimum   = errors.New("payout is below the minimum amount")
+	ErrInvalidPayoutAmount  = errors.New("payout amount must be positive")
+	ErrPayoutStatusConflict = errors.New("payout status was changed concurrently")
+)
+
+type PayoutStatus string
+
+const (
+	// PayoutStatusPending is reserved against the balance but not yet sent
+	// to the payout provider.
+	PayoutStatusPending   PayoutStatus = "pending"
+	PayoutStatusInTransit PayoutStatus = "in_transit"
+	PayoutStatusPaid      PayoutStatus = "paid"
+	// PayoutStatusFailed returns the amount to the available balance.
+	PayoutStatusFailed PayoutStatus = "failed"
+)
+
+func (s PayoutStatus) IsFinal() bool {
+	return s == PayoutStatusPaid || s == PayoutStatusFailed
+}
+
+// PayoutTrigger records whether a payout was requested or made by the
+// schedule.
+type PayoutTrigger string
+
+const (
+	PayoutTriggerManual    PayoutTrigger = "manual"
+	PayoutTriggerScheduled PayoutTrigger = "scheduled"
+)
+
+// Payout moves collected money to the merchant's bank account.
+type Payout struct {
+	ID       int
+	PublicID string
+
+	MerchantID int
+
+	Amount   int
+	Currency string
+	Status   PayoutStatus
+	Trigger  PayoutTrigger
+	// Destination is the merchant account the payout is sent to.
+	Destination string
+
+	// Reference is the payout provider's id, set once sent.
+	Reference     string
+	FailureReason string
+
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	SentAt      *time.Time
+	CompletedAt *time.Time
+}
+
+func (p *Payout) CanTransitionTo(next PayoutStatus) bool {
+	switch p.Status {
+	case PayoutStatusPending:
+		return next == PayoutStatusInTransit || next == PayoutStatusFailed
+	case PayoutStatusInTransit:
+		return next == PayoutStatusPaid || next == PayoutStatusFailed
+	default:
+		return false
+	}
+}
+
+// TransitionTo moves the payout to next, recording when it was sent or
+// completed.
+func (p *Payout) TransitionTo(next PayoutStatus, now time.Time) error {
+	if !p.CanTransitionTo(next) {
+		return ErrPayoutStatusConflict
+	}
+
+	p.Status = next
+	p.UpdatedAt = now
+	if next == PayoutStatusInTransit {
+		p.SentAt = &now
+	}
+	if next.IsFinal() {
+		p.CompletedAt = &now
+	}
+	return nil
+}
+
+// Balance is the merchant's money in one currency.
+type Balance struct {
+	Currency string
+	// Available can be paid out: settled payments net of ledger
+	// adjustments and payouts that did not fail. A large chargeback can
+	// make it negative.
+	Available int
+	// Pending is collected but still within the settlement delay.
+	Pending int
+	// InTransit is reserved by payouts that are not completed yet.
+	InTransit int
+}
+
+type PayoutSchedule string
+
+const (
+	PayoutScheduleDaily  PayoutSchedule = "daily"
+	PayoutScheduleWeekly PayoutSchedule = "weekly"
+	// PayoutScheduleManual only pays out on request.
+	PayoutScheduleManual PayoutSchedule = "manual"
+)
+
+// PayoutPolicy decides when and how much is paid out.
+type PayoutPolicy struct {
+	Schedule PayoutSchedule
+	// Weekday is when weekly payouts are made.
+	Weekday time.Weekday
+	// Minimums is the smallest payout per currency. Currencies missing
+	// from the map have no minimum.
+	Minimums map[string]int
+	// SettlementDelay is how long a successful payment stays pending
+	// before it can be paid out.
+	SettlementDelay time.Duration
+}
+
+// Due reports whether a scheduled payout should be made at now, given when
+// the last one was made. A zero last means there was none.
+func (p PayoutPolicy) Due(last, now time.Time) bool {
+	start, ok := p.periodStart(now)
+	if !ok {
+		return false
+	}
+	return last.Before(start)
+}
+
+// periodStart is the beginning of the schedule period containing now, in
+// UTC.
+func (p PayoutPolicy) periodStart(now time.Time) (time.Time, bool) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch p.Schedule {
+	case PayoutScheduleDaily:
+		return day, true
+	case PayoutScheduleWeekly:
+		back := (int(now.Weekday()) - int(p.Weekday) + 7) % 7
+		return day.AddDate(0, 0, -back), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// CheckAmount validates a payout amount against the minimum for currency.
+func (p PayoutPolicy) CheckAmount(amount int, currency string) error {
+	if amount <= 0 {
+		return ErrInvalidPayoutAmount
+	}
+	if amount < p.Minimums[currency] {
+		return ErrPayoutBelowMinimum
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var (
+	ErrInvalidToken          = errors.New("invalid token")
+	ErrTokenMerchantRequired = errors.New("token is not bound to a merchant")
+)
+
+// Scopes a caller can be granted. No route asks for ScopeRefundsWrite until
+// refunds are exposed over the API.
+const (
+	ScopePaymentsRead  = "payments:read"
+	ScopePaymentsWrite = "payments:write"
+	ScopeRefundsWrite  = "refunds:write"
+	ScopeAdmin         = "admin"
+)
+
+// MerchantScopes are what a merchant's own API key may do.
+var MerchantScopes = []string{ScopePaymentsRead, ScopePaymentsWrite, ScopeRefundsWrite}
+
+// Principal is who a request is made by: an API key, an internal service
+// holding a JWT or the admin token.
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope reports whether p was granted scope.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal records who ctx acts on behalf of.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns who ctx acts on behalf of. Background workers
+// and provider callbacks run without one.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok && p.Subject != ""
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrQRCodeNotFound      = errors.New("qr code not found")
+	ErrUnsupportedCurrency = errors.New("currency is not supported for qr payments")
+	ErrQRAmountMismatch    = errors.New("paid amount does not match the qr amount")
+	ErrQRCodeExpired       = errors.New("qr code has expired")
+)
+
+const PaymentMethodQR = "qr"
+
+// QRCode is the dynamic merchant-presented QR issued for a single payment.
+type QRCode struct {
+	ID int
+	// PaymentID is the public id of the payment the code was issued for.
+	PaymentID string
+	// Reference is the bill number encoded in the payload; the scanning
+	// app reports it back when the payer pays.
+	Reference string
+	// Payload is the EMVCo string the QR image encodes.
+	Payload   string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPaymentNotInReview   = errors.New("payment is not awaiting review")
+	ErrReviewerRequired     = errors.New("reviewer is required")
+	ErrReviewReasonRequired = errors.New("a reason is required to reject a payment")
+	ErrReviewExpired        = errors.New("review deadline has passed")
+)
+
+// ReviewAction is an entry in a payment's review audit trail.
+type ReviewAction string
+
+const (
+	ReviewActionHeld     ReviewAction = "HELD"
+	ReviewActionApproved ReviewAction = "APPROVED"
+	ReviewActionRejected ReviewAction = "REJECTED"
+	// ReviewActionApprovalFailed is recorded instead of ReviewActionApproved
+	// when the approved payment was refused once it was started.
+	ReviewActionApprovalFailed ReviewAction = "APPROVAL_FAILED"
+	// ReviewActionExpired is recorded when nobody decided within the SLA.
+	ReviewActionExpired ReviewAction = "EXPIRED"
+)
+
+// SystemReviewer is recorded for actions taken automatically.
+const SystemReviewer = "system"
+
+type PaymentReview struct {
+	ID        int
+	PaymentID string
+	Action    ReviewAction
+	Reason    string
+	Reviewer  string
+	CreatedAt time.Time
+}
+
+// Reasons a payment is put on hold.
+const (
+	HoldReasonHighAmount     = "high_amount"
+	HoldReasonFirstTimePayer = "first_time_payer"
+)
+
+// HoldPolicy decides which payments wait for a manual decision before they
+// are sent to the provider.
+type HoldPolicy struct {
+	// MinAmount holds payments of at least this amount; zero disables it.
+	MinAmount int
+	// FirstTimePayers holds a payer's first payment.
+	FirstTimePayers bool
+	// Methods limits holds to these methods; empty means every method.
+	Methods map[string]bool
+}
+
+// HoldReasons returns why payment should be held, or nothing when it can go
+// ahead. previousPayments is how many payments the payer made before.
+func (p HoldPolicy) HoldReasons(payment *Payment, previousPayments int) []string {
+	if len(p.Methods) > 0 && !p.Methods[payment.Method] {
+		return nil
+	}
+
+	var reasons []string
+	if p.MinAmount > 0 && payment.Amount >= p.MinAmount {
+		reasons = append(reasons, HoldReasonHighAmount)
+	}
+	if p.FirstTimePayers && previousPayments == 0 {
+		reasons = append(reasons, HoldReasonFirstTimePayer)
+	}
+	return reasons
+}
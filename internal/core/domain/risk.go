@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrPaymentBlocked = errors.New("payment was blocked by risk rules")
+
+// RiskDecision is what the risk engine wants done with a payment before it
+// reaches the provider.
+type RiskDecision string
+
+const (
+	RiskDecisionAllow  RiskDecision = "ALLOW"
+	RiskDecisionReview RiskDecision = "REVIEW"
+	RiskDecisionBlock  RiskDecision = "BLOCK"
+)
+
+// Names of the risk rules, recorded on the payment when they trigger.
+const (
+	RiskRulePayerVelocity           = "payer_velocity"
+	RiskRuleOrderVelocity           = "order_velocity"
+	RiskRuleAmountThreshold         = "amount_threshold"
+	RiskRuleBlockedPayer            = "blocked_payer"
+	RiskRuleBlockedIP               = "blocked_ip"
+	RiskRuleBlockedBIN              = "blocked_bin"
+	RiskRuleCurrencyCountryMismatch = "currency_country_mismatch"
+)
+
+// VelocityRule triggers once Max payments were already made within Window.
+// A zero Max disables it.
+type VelocityRule struct {
+	Window time.Duration
+	Max    int
+	Score  int
+}
+
+// RiskPolicy holds the configured rules. Each triggered rule adds its score;
+// the total decides whether the payment is allowed, reviewed or blocked.
+type RiskPolicy struct {
+	PayerVelocity VelocityRule
+	OrderVelocity VelocityRule
+
+	// AmountThresholds is the largest amount allowed per method without
+	// adding AmountScore.
+	AmountThresholds map[string]int
+	AmountScore      int
+
+	BlockedPayers  map[int]bool
+	BlockedIPs     map[string]bool
+	BlockedBINs    map[string]bool
+	BlocklistScore int
+
+	// CountryCurrencies maps a payer country to the currency expected from
+	// it. Countries missing from the map are not checked.
+	CountryCurrencies map[string]string
+	MismatchScore     int
+
+	ReviewScore int
+	BlockScore  int
+}
+
+// RiskSignals is what is known about a payment attempt when it is scored.
+type RiskSignals struct {
+	PayerID      int
+	PayerCountry string
+	Method       string
+	Amount       int
+	Currency     string
+	IP           string
+	// BIN is only known for payments on saved cards.
+	BIN string
+
+	// PayerPayments and OrderPayments count payments already made within
+	// the velocity windows.
+	PayerPayments int
+	OrderPayments int
+}
+
+type RiskAssessment struct {
+	Score    int
+	Decision RiskDecision
+	// Rules lists the triggered rules in evaluation order.
+	Rules []string
+}
+
+// Evaluate scores s against the policy.
+func (p RiskPolicy) Evaluate(s RiskSignals) RiskAssessment {
+	var a RiskAssessment
+	trigger := func(rule string, score int) {
+		a.Rules = append(a.Rules, rule)
+		a.Score += score
+	}
+
+	if p.PayerVelocity.Max > 0 && s.PayerPayments >= p.PayerVelocity.Max {
+		trigger(RiskRulePayerVelocity, p.PayerVelocity.Score)
+	}
+	if p.OrderVelocity.Max > 0 && s.OrderPayments >= p.OrderVelocity.Max {
+		trigger(RiskRuleOrderVelocity, p.OrderVelocity.Score)
+	}
+	if limit, ok := p.AmountThresholds[s.Method]; ok && s.Amount > limit {
+		trigger(RiskRuleAmountThreshold, p.AmountScore)
+	}
+	if p.BlockedPayers[s.PayerID] {
+		trigger(RiskRuleBlockedPayer, p.BlocklistScore)
+	}
+	if s.IP != "" && p.BlockedIPs[s.IP] {
+		trigger(RiskRuleBlockedIP, p.BlocklistScore)
+	}
+	if s.BIN != "" && p.BlockedBINs[s.BIN] {
+		trigger(RiskRuleBlockedBIN, p.BlocklistScore)
+	}
+	if want, ok := p.CountryCurrencies[s.PayerCountry]; ok && want != s.Currency {
+		trigger(RiskRuleCurrencyCountryMismatch, p.MismatchScore)
+	}
+
+	switch {
+	case p.BlockScore > 0 && a.Score >= p.BlockScore:
+		a.Decision = RiskDecisionBlock
+	case p.ReviewScore > 0 && a.Score >= p.ReviewScore:
+		a.Decision = RiskDecisionReview
+	default:
+		a.Decision = RiskDecisionAllow
+	}
+	return a
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var ErrInvalidSplits = errors.New("invalid splits")
+
+// SplitShare is how a marketplace asks for part of a payment to go to one
+// recipient: either a fixed Amount or a Percentage of the payment, with up
+// to two decimals.
+type SplitShare struct {
+	Recipient  string
+	Amount     int
+	Percentage float64
+	// FeeBearer marks the recipient whose share carries the fees for the
+	// payment. At most one recipient bears fees; with none the platform
+	// does.
+	FeeBearer bool
+}
+
+// PaymentSplit is the part of a payment allocated to one recipient.
+type PaymentSplit struct {
+	ID        int
+	PaymentID string
+	Recipient string
+	Amount    int
+	// PercentageBps is the requested percentage in basis points, zero for
+	// fixed amount shares.
+	PercentageBps int
+	FeeBearer     bool
+}
+
+// Percentage is the requested percentage, zero for fixed amount shares.
+func (s PaymentSplit) Percentage() float64 {
+	return float64(s.PercentageBps) / 100
+}
+
+// AllocateSplits turns shares into amounts that add up to total exactly.
+// Fixed amounts and percentages of total may be mixed as long as they
+// cover total. Percentage shares are rounded down and the units lost to
+// rounding go one each to the shares that lost the most, earlier shares
+// first on a tie.
+func AllocateSplits(total int, shares []SplitShare) ([]PaymentSplit, error) {
+	splits := make([]PaymentSplit, 0, len(shares))
+	seen := make(map[string]bool, len(shares))
+	feeBearers := 0
+	fixed, bps := 0, 0
+
+	for i, share := range shares {
+		switch {
+		case share.Recipient == "":
+			return nil, fmt.Errorf("%w: split %d has no recipient", ErrInvalidSplits, i)
+		case seen[share.Recipient]:
+			return nil, fmt.Errorf("%w: recipient %s appears twice", ErrInvalidSplits, share.Recipient)
+		case (share.Amount > 0) == (share.Percentage > 0):
+			return nil, fmt.Errorf("%w: split for %s needs either an amount or a percentage", ErrInvalidSplits, share.Recipient)
+		case share.Amount < 0 || share.Percentage < 0:
+			return nil, fmt.Errorf("%w: split for %s is negative", ErrInvalidSplits, share.Recipient)
+		}
+		seen[share.Recipient] = true
+		if share.FeeBearer {
+			feeBearers++
+		}
+
+		split := PaymentSplit{
+			Recipient: share.Recipient,
+			Amount:    share.Amount,
+			FeeBearer: share.FeeBearer,
+		}
+		if share.Percentage > 0 {
+			scaled := share.Percentage * 100
+			split.PercentageBps = int(math.Round(scaled))
+			if math.Abs(scaled-float64(split.PercentageBps)) > 1e-6 {
+				return nil, fmt.Errorf("%w: percentage for %s has more than two decimals", ErrInvalidSplits, share.Recipient)
+			}
+			bps += split.PercentageBps
+		}
+		fixed += share.Amount
+		splits = append(splits, split)
+	}
+
+	if feeBearers > 1 {
+		return nil, fmt.Errorf("%w: only one recipient can bear the fees", ErrInvalidSplits)
+	}
+	// compared in basis points so rounding cannot hide a shortfall
+	if fixed*10000+total*bps != total*10000 {
+		return nil, fmt.Errorf("%w: splits must add up to the payment amount", ErrInvalidSplits)
+	}
+
+	allocated := fixed
+	var rounded []int
+	for i := range splits {
+		if splits[i].PercentageBps > 0 {
+			splits[i].Amount = total * splits[i].PercentageBps / 10000
+			allocated += splits[i].Amount
+			rounded = append(rounded, i)
+		}
+	}
+	lost := func(i int) int { return total * splits[i].PercentageBps % 10000 }
+	sort.SliceStable(rounded, func(a, b int) bool {
+		return lost(rounded[a]) > lost(rounded[b])
+	})
+	for _, i := range rounded[:total-allocated] {
+		splits[i].Amount++
+	}
+
+	return splits, nil
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrPlanNotFound              = errors.New("plan not found")
+	ErrPlanInactive              = errors.New("plan is not active")
+	ErrSubscriptionNotFound      = errors.New("subscription not found")
+	ErrInvalidSubscriptionChange = errors.New("subscription cannot change to the requested status")
+)
+
+type PlanInterval string
+
+const (
+	PlanIntervalDay   PlanInterval = "day"
+	PlanIntervalWeek  PlanInterval = "week"
+	PlanIntervalMonth PlanInterval = "month"
+	PlanIntervalYear  PlanInterval = "year"
+)
+
+func (i PlanInterval) IsValid() bool {
+	switch i {
+	case PlanIntervalDay,
+		PlanIntervalWeek,
+		PlanIntervalMonth,
+		PlanIntervalYear:
+		return true
+	default:
+		return false
+	}
+}
+
+type Plan struct {
+	ID       int
+	PublicID string
+
+	Name     string
+	Amount   int
+	Currency string
+
+	Interval      PlanInterval
+	IntervalCount int
+	TrialDays     int
+
+	Active bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NextPeriodEnd returns the end of a billing period starting at start.
+func (p *Plan) NextPeriodEnd(start time.Time) time.Time {
+	n := p.IntervalCount
+	if n <= 0 {
+		n = 1
+	}
+
+	switch p.Interval {
+	case PlanIntervalDay:
+		return start.AddDate(0, 0, n)
+	case PlanIntervalWeek:
+		return start.AddDate(0, 0, 7*n)
+	case PlanIntervalYear:
+		return start.AddDate(n, 0, 0)
+	default:
+		return start.AddDate(0, n, 0)
+	}
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrialing SubscriptionStatus = "TRIALING"
+	SubscriptionStatusActive   SubscriptionStatus = "ACTIVE"
+	SubscriptionStatusPastDue  SubscriptionStatus = "PAST_DUE"
+	SubscriptionStatusPaused   SubscriptionStatus = "PAUSED"
+	SubscriptionStatusCanceled SubscriptionStatus = "CANCELED"
+)
+
+type Subscription struct {
+	ID       int
+	PublicID string
+
+	MerchantID int
+
+	PlanID             int
+	PayerID            int
+	PaymentMethodToken string
+
+	Status SubscriptionStatus
+
+	// Cycle is the number of billing cycles charged successfully so far.
+	Cycle              int
+	CurrentPeriodStart time.Time
+	CurrentPeriodEnd   time.Time
+
+	// dunning state for the cycle currently being charged
+	FailedAttempts int
+	NextRetryAt    *time.Time
+
+	LastPaymentID string
+
+	CanceledAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsDue reports whether the subscription should be charged at now.
+func (s *Subscription) IsDue(now time.Time) bool {
+	switch s.Status {
+	case SubscriptionStatusTrialing, SubscriptionStatusActive:
+		return !now.Before(s.CurrentPeriodEnd)
+	case SubscriptionStatusPastDue:
+		return s.NextRetryAt != nil && !now.Before(*s.NextRetryAt)
+	default:
+		return false
+	}
+}
+
+// BillingOrderID identifies the upcoming cycle on the payments charged for
+// it.
+func (s *Subscription) BillingOrderID() string {
+	return s.PublicID + ":cycle:" + strconv.Itoa(s.Cycle+1)
+}
+
+// BillingKey is the idempotency key of the charge for the upcoming cycle, so
+// rechecks of the same attempt can never double charge the payer. Each
+// dunning retry after a failed charge is a new attempt with its own key.
+func (s *Subscription) BillingKey() string {
+	if s.FailedAttempts == 0 {
+		return s.BillingOrderID()
+	}
+	return s.BillingOrderID() + ":attempt:" + strconv.Itoa(s.FailedAttempts+1)
+}
+
+// MarkPaid starts the next period after a successful charge.
+func (s *Subscription) MarkPaid(plan *Plan, paymentID string) {
+	s.Cycle++
+	s.CurrentPeriodStart = s.CurrentPeriodEnd
+	s.CurrentPeriodEnd = plan.NextPeriodEnd(s.CurrentPeriodStart)
+	s.Status = SubscriptionStatusActive
+	s.FailedAttempts = 0
+	s.NextRetryAt = nil
+	s.LastPaymentID = paymentID
+}
+
+// MarkFailed records a failed charge. retryAfter lists the wait before each
+// dunning retry; once it is exhausted the subscription is canceled.
+func (s *Subscription) MarkFailed(now time.Time, retryAfter []time.Duration) {
+	s.FailedAttempts++
+	if s.FailedAttempts > len(retryAfter) {
+		_ = s.Cancel(now)
+		return
+	}
+
+	next := now.Add(retryAfter[s.FailedAttempts-1])
+	s.Status = SubscriptionStatusPastDue
+	s.NextRetryAt = &next
+}
+
+// MarkPending records a charge that is neither paid nor failed yet, such as
+// one held for review. The cycle is checked again at retryAt without using
+// up a dunning retry.
+func (s *Subscription) MarkPending(retryAt time.Time) {
+	s.Status = SubscriptionStatusPastDue
+	s.NextRetryAt = &retryAt
+}
+
+func (s *Subscription) Pause() error {
+	switch s.Status {
+	case SubscriptionStatusTrialing,
+		SubscriptionStatusActive,
+		SubscriptionStatusPastDue:
+		s.Status = SubscriptionStatusPaused
+		return nil
+	default:
+		return ErrInvalidSubscriptionChange
+	}
+}
+
+// Resume reactivates a paused subscription. A period that ended while paused
+// is billed right away.
+func (s *Subscription) Resume(now time.Time) error {
+	if s.Status != SubscriptionStatusPaused {
+		return ErrInvalidSubscriptionChange
+	}
+
+	s.Status = SubscriptionStatusActive
+	s.FailedAttempts = 0
+	s.NextRetryAt = nil
+	if s.CurrentPeriodEnd.Before(now) {
+		s.CurrentPeriodEnd = now
+	}
+	return nil
+}
+
+func (s *Subscription) Cancel(now time.Time) error {
+	if s.Status == SubscriptionStatusCanceled {
+		return ErrInvalidSubscriptionChange
+	}
+
+	s.Status = SubscriptionStatusCanceled
+	s.NextRetryAt = nil
+	s.CanceledAt = &now
+	return nil
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrThreeDSNotFound          = errors.New("card authentication not found")
+	ErrThreeDSPending           = errors.New("card authentication challenge is not completed yet")
+	ErrCardAuthenticationFailed = errors.New("card authentication failed")
+)
+
+// ThreeDSStatus is the outcome of a 3-D Secure authentication.
+type ThreeDSStatus string
+
+const (
+	ThreeDSStatusChallengeRequired ThreeDSStatus = "CHALLENGE_REQUIRED"
+	// ThreeDSStatusAuthenticated means the cardholder was verified.
+	ThreeDSStatusAuthenticated ThreeDSStatus = "AUTHENTICATED"
+	// ThreeDSStatusAttempted means the issuer could not authenticate but
+	// still accepts liability.
+	ThreeDSStatusAttempted ThreeDSStatus = "ATTEMPTED"
+	ThreeDSStatusFailed    ThreeDSStatus = "FAILED"
+)
+
+// IsFinal reports whether the authentication has an outcome.
+func (s ThreeDSStatus) IsFinal() bool {
+	return s == ThreeDSStatusAuthenticated ||
+		s == ThreeDSStatusAttempted ||
+		s == ThreeDSStatusFailed
+}
+
+// LiabilityShift reports whether the outcome moves fraud liability to the
+// issuer.
+func (s ThreeDSStatus) LiabilityShift() bool {
+	return s == ThreeDSStatusAuthenticated || s == ThreeDSStatusAttempted
+}
+
+// ThreeDSAuthentication is the card authentication run for a payment.
+type ThreeDSAuthentication struct {
+	ID int
+	// PaymentID is the public id of the authenticated payment.
+	PaymentID string
+	// Reference is the authentication server's id for the transaction.
+	Reference string
+	Status    ThreeDSStatus
+	// ChallengeURL is where the payer completes the challenge.
+	ChallengeURL string
+	// ReturnURL is where the payer is sent after the challenge.
+	ReturnURL string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ApplyThreeDS records an authentication outcome on the payment.
+func (p *Payment) ApplyThreeDS(status ThreeDSStatus) {
+	p.ThreeDSStatus = status
+	p.LiabilityShift = status.LiabilityShift()
+}
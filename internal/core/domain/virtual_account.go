@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrVirtualAccountNotFound = errors.New("virtual account not found")
+	ErrUnsupportedBank        = errors.New("bank code is not supported")
+	ErrDuplicateBankCredit    = errors.New("bank credit was already processed")
+)
+
+const PaymentMethodBankTransfer = "bank_transfer"
+
+type VirtualAccountStatus string
+
+const (
+	VirtualAccountStatusOpen          VirtualAccountStatus = "OPEN"
+	VirtualAccountStatusPartiallyPaid VirtualAccountStatus = "PARTIALLY_PAID"
+	VirtualAccountStatusPaid          VirtualAccountStatus = "PAID"
+	VirtualAccountStatusOverpaid      VirtualAccountStatus = "OVERPAID"
+	VirtualAccountStatusExpired       VirtualAccountStatus = "EXPIRED"
+)
+
+// VirtualAccount is the account number a payer transfers to for a single
+// bank transfer payment.
+type VirtualAccount struct {
+	ID int
+	// PaymentID is the public id of the payment the account was issued for.
+	PaymentID string
+
+	BankCode      string
+	AccountNumber string
+
+	ExpectedAmount int
+	PaidAmount     int
+
+	Status    VirtualAccountStatus
+	ExpiresAt time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BankCredit is an incoming transfer reported by the bank.
+type BankCredit struct {
+	ID               int
+	VirtualAccountID int
+	Reference        string
+	Amount           int
+	ReceivedAt       time.Time
+}
+
+// Outstanding is the amount still to be paid; it is negative on overpayment.
+func (va *VirtualAccount) Outstanding() int {
+	return va.ExpectedAmount - va.PaidAmount
+}
+
+// PaidStatus is the status the account has for what was paid into it so
+// far.
+func (va *VirtualAccount) PaidStatus() VirtualAccountStatus {
+	switch {
+	case va.PaidAmount == va.ExpectedAmount:
+		return VirtualAccountStatusPaid
+	case va.PaidAmount > va.ExpectedAmount:
+		return VirtualAccountStatusOverpaid
+	case va.PaidAmount > 0:
+		return VirtualAccountStatusPartiallyPaid
+	default:
+		return VirtualAccountStatusOpen
+	}
+}
@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"payment-service/internal/core/domain"
+)
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *domain.Dispute) error
+	FindByPublicID(ctx context.Context, publicID string) (*domain.Dispute, error)
+	FindByReference(ctx context.Context, reference string) (*domain.Dispute, error)
+	ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.Dispute, error)
+	// UpdateStatus persists dispute's status provided the stored status is
+	// still from. Otherwise ErrInvalidDisputeStatus is returned.
+	UpdateStatus(ctx context.Context, dispute *domain.Dispute, from domain.DisputeStatus) error
+
+	AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error
+	ListEvidence(ctx context.Context, disputeID int) ([]*domain.DisputeEvidence, error)
+}
+
+// EvidenceStore keeps uploaded evidence files.
+type EvidenceStore interface {
+	// Save writes at most maxSize bytes of r and returns where the file was
+	// stored and its size. Larger files are rejected with
+	// domain.ErrInvalidEvidence.
+	Save(
+		ctx context.Context,
+		disputeID string,
+		fileName string,
+		r io.Reader,
+		maxSize int64,
+	) (path string, size int64, err error)
+}
+
+type LedgerRepository interface {
+	// Post stores entries in one transaction. Entries whose reference was
+	// already posted are skipped.
+	Post(ctx context.Context, entries []*domain.LedgerEntry) error
+	ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.LedgerEntry, error)
+}
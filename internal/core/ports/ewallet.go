@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type EWalletProvider interface {
+	// AuthorizeEWallet asks the payer's wallet to approve payment. It fills
+	// in the wallet reference and the next action on auth.
+	AuthorizeEWallet(
+		ctx context.Context,
+		payment *domain.Payment,
+		auth *domain.EWalletAuthorization,
+	) error
+}
+
+type EWalletAuthorizationRepository interface {
+	Create(ctx context.Context, auth *domain.EWalletAuthorization) error
+	FindByPaymentID(
+		ctx context.Context,
+		paymentID string,
+	) (*domain.EWalletAuthorization, error)
+}
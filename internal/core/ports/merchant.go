@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"time"
+)
+
+type MerchantRepository interface {
+	Create(ctx context.Context, merchant *domain.Merchant) error
+	FindByID(ctx context.Context, id int) (*domain.Merchant, error)
+	FindByPublicID(ctx context.Context, publicID string) (*domain.Merchant, error)
+	ListActive(ctx context.Context) ([]*domain.Merchant, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	FindByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	FindByID(ctx context.Context, id int) (*domain.APIKey, error)
+	ListByMerchantID(ctx context.Context, merchantID int) ([]*domain.APIKey, error)
+	// Expire makes the key stop working at at, unless it already expires
+	// sooner.
+	Expire(ctx context.Context, id int, at time.Time) error
+	Revoke(ctx context.Context, id int, at time.Time) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type PayerRepository interface {
+	Create(ctx context.Context, payer *domain.Payer) error
+	Update(ctx context.Context, payer *domain.Payer) error
+	FindByID(ctx context.Context, id int) (*domain.Payer, error)
+	FindByEmail(ctx context.Context, email string) (*domain.Payer, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Payer, error)
+}
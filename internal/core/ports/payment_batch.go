@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"time"
+)
+
+type PaymentBatchRepository interface {
+	// Create stores the batch with its items.
+	Create(ctx context.Context, batch *domain.PaymentBatch) error
+	// FindByPublicID returns the batch with its items.
+	FindByPublicID(ctx context.Context, publicID string) (*domain.PaymentBatch, error)
+	// ListProcessing returns batches with items left to create, oldest
+	// first, with only those items.
+	ListProcessing(ctx context.Context, limit int) ([]*domain.PaymentBatch, error)
+	// UpdateItem records the outcome of an item.
+	UpdateItem(ctx context.Context, item *domain.PaymentBatchItem) error
+	// Complete marks the batch completed once no item is pending.
+	Complete(ctx context.Context, batch *domain.PaymentBatch, now time.Time) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import "payment-service/internal/core/domain"
+
+// PaymentEvents fans payment status changes out to whoever is watching the
+// payment.
+type PaymentEvents interface {
+	Publish(event domain.PaymentEvent)
+	// Subscribe returns the recent events of a payment newer than afterID
+	// and a channel receiving the ones published from then on. The channel
+	// is closed when the subscriber falls behind; cancel must be called once
+	// the subscriber is done.
+	Subscribe(
+		paymentID string,
+		afterID int64,
+	) (past []domain.PaymentEvent, live <-chan domain.PaymentEvent, cancel func())
+}
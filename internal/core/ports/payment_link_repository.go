@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"time"
+)
+
+type PaymentLinkRepository interface {
+	Create(ctx context.Context, link *domain.PaymentLink) error
+	FindByPublicID(ctx context.Context, publicID string) (*domain.PaymentLink, error)
+	UpdateStatus(
+		ctx context.Context,
+		publicID string,
+		status domain.PaymentLinkStatus,
+	) error
+	// Claim atomically records one use of an open link, completing single-use
+	// links. It returns ErrPaymentLinkUnavailable if the link cannot be used.
+	Claim(ctx context.Context, publicID string, now time.Time) error
+	// Release undoes a Claim whose payment could not be created.
+	Release(ctx context.Context, publicID string) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type PaymentMethodRepository interface {
+	Create(ctx context.Context, method *domain.PaymentMethod) error
+	FindByToken(ctx context.Context, token string) (*domain.PaymentMethod, error)
+	FindByFingerprint(
+		ctx context.Context,
+		payerID int,
+		fingerprint string,
+	) (*domain.PaymentMethod, error)
+	ListByPayerID(ctx context.Context, payerID int) ([]*domain.PaymentMethod, error)
+	UpdateStatus(
+		ctx context.Context,
+		token string,
+		status domain.PaymentMethodStatus,
+	) error
+}
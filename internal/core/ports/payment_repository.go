@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"payment-service/internal/core/domain"
+	"time"
 )
 
 type PaymentRepository interface {
@@ -15,4 +16,30 @@ type PaymentRepository interface {
 		ctx context.Context,
 		publicID string,
 	) (*domain.Payment, error)
+	ListByPayerID(
+		ctx context.Context,
+		payerID int,
+		limit, offset int,
+	) ([]*domain.Payment, error)
+	// UpdateStatus persists payment's status, paid_at, expires_at and
+	// authentication result, provided the stored status is still from.
+	// Otherwise ErrPaymentStatusConflict is returned.
+	UpdateStatus(
+		ctx context.Context,
+		payment *domain.Payment,
+		from domain.PaymentStatus,
+	) error
+	// ListExpired returns payments still waiting for the payer whose
+	// expires_at has passed.
+	ListExpired(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]*domain.Payment, error)
+	// ListAwaitingReview returns payments in REVIEW or ON_HOLD, oldest
+	// first.
+	ListAwaitingReview(
+		ctx context.Context,
+		limit, offset int,
+	) ([]*domain.Payment, error)
 }
@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"time"
+)
+
+type PayoutRepository interface {
+	// CreateWithinBalance stores payout unless it would take the available
+	// balance of its currency below zero, in which case
+	// domain.ErrInsufficientBalance is returned. Payments paid after
+	// settledBefore do not count as available.
+	CreateWithinBalance(ctx context.Context, payout *domain.Payout, settledBefore time.Time) error
+	FindByPublicID(ctx context.Context, publicID string) (*domain.Payout, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Payout, error)
+	ListByStatus(ctx context.Context, status domain.PayoutStatus, limit int) ([]*domain.Payout, error)
+	// LastScheduled returns the most recent scheduled payout in currency,
+	// or domain.ErrPayoutNotFound.
+	LastScheduled(ctx context.Context, currency string) (*domain.Payout, error)
+	// UpdateStatus persists payout's status provided the stored status is
+	// still from. Otherwise domain.ErrPayoutStatusConflict is returned.
+	UpdateStatus(ctx context.Context, payout *domain.Payout, from domain.PayoutStatus) error
+}
+
+type BalanceRepository interface {
+	// Balances returns a balance per currency. Payments paid after
+	// settledBefore are pending.
+	Balances(ctx context.Context, settledBefore time.Time) ([]domain.Balance, error)
+}
+
+// PayoutResult is what the payout provider reports for a sent payout.
+type PayoutResult struct {
+	// Status is in_transit until the bank confirms, then paid or failed.
+	Status        domain.PayoutStatus
+	FailureReason string
+}
+
+type PayoutProvider interface {
+	// Send hands the payout to the provider and returns its reference.
+	Send(ctx context.Context, payout *domain.Payout) (reference string, err error)
+	Status(ctx context.Context, reference string) (PayoutResult, error)
+}
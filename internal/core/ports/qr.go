@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type QRProvider interface {
+	// IssueQR builds the QR payload for payment. The returned code is not
+	// yet stored.
+	IssueQR(ctx context.Context, payment *domain.Payment) (*domain.QRCode, error)
+}
+
+type QRCodeRepository interface {
+	Create(ctx context.Context, qr *domain.QRCode) error
+	FindByPaymentID(ctx context.Context, paymentID string) (*domain.QRCode, error)
+	FindByReference(ctx context.Context, reference string) (*domain.QRCode, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+// ReviewRepository keeps the audit trail of manual review decisions.
+type ReviewRepository interface {
+	Create(ctx context.Context, review *domain.PaymentReview) error
+	// ListByPaymentID returns the trail oldest first.
+	ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.PaymentReview, error)
+}
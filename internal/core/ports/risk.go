@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// RiskHistory answers the velocity questions the risk rules ask about past
+// payments.
+type RiskHistory interface {
+	CountPayerPayments(ctx context.Context, payerID int, since time.Time) (int, error)
+	CountOrderPayments(ctx context.Context, orderID string, since time.Time) (int, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+// SecretBox encrypts sensitive data at rest.
+type SecretBox interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+	// Fingerprint returns a stable keyed digest of data, usable to detect
+	// duplicates without decrypting anything.
+	Fingerprint(data []byte) string
+}
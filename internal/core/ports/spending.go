@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type SpendingCounter interface {
+	// Record counts attempt against the payer's usage and checks limits in
+	// the same transaction, so concurrent payments cannot both slip under a
+	// limit. When one is exceeded nothing is recorded and a
+	// *domain.LimitExceededError is returned.
+	Record(
+		ctx context.Context,
+		attempt domain.SpendingAttempt,
+		limits []domain.SpendingLimit,
+	) error
+}
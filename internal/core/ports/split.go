@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type SplitRepository interface {
+	// Create stores the allocation of one payment.
+	Create(ctx context.Context, splits []domain.PaymentSplit) error
+	ListByPaymentID(ctx context.Context, paymentID string) ([]domain.PaymentSplit, error)
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"time"
+)
+
+type PlanRepository interface {
+	Create(ctx context.Context, plan *domain.Plan) error
+	FindByID(ctx context.Context, id int) (*domain.Plan, error)
+	FindByPublicID(ctx context.Context, publicID string) (*domain.Plan, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Plan, error)
+}
+
+type SubscriptionRepository interface {
+	Create(ctx context.Context, subscription *domain.Subscription) error
+	Update(ctx context.Context, subscription *domain.Subscription) error
+	FindByPublicID(
+		ctx context.Context,
+		publicID string,
+	) (*domain.Subscription, error)
+	// ListDue returns subscriptions whose next charge or dunning retry is due.
+	ListDue(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+	) ([]*domain.Subscription, error)
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type CardAuthenticator interface {
+	// Authenticate starts 3-D Secure for a card payment. A frictionless
+	// outcome is returned final; otherwise the status is
+	// CHALLENGE_REQUIRED and ChallengeURL is set.
+	Authenticate(
+		ctx context.Context,
+		payment *domain.Payment,
+		card *domain.PaymentMethod,
+	) (*domain.ThreeDSAuthentication, error)
+	// Result returns the current outcome of a challenge.
+	Result(ctx context.Context, reference string) (domain.ThreeDSStatus, error)
+}
+
+type ThreeDSRepository interface {
+	Create(ctx context.Context, auth *domain.ThreeDSAuthentication) error
+	FindByPaymentID(
+		ctx context.Context,
+		paymentID string,
+	) (*domain.ThreeDSAuthentication, error)
+	UpdateStatus(ctx context.Context, id int, status domain.ThreeDSStatus) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// Transactor makes repository writes atomic.
+type Transactor interface {
+	// WithinTx runs fn in a transaction: the writes of the repositories fn
+	// calls with the ctx it is given are committed together when fn returns
+	// nil and all discarded otherwise.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
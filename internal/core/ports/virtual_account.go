@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+type VirtualAccountProvider interface {
+	// IssueVirtualAccount returns the account number the payer should
+	// transfer to. The same bank and payment always yield the same number.
+	IssueVirtualAccount(
+		ctx context.Context,
+		bankCode string,
+		paymentID string,
+	) (string, error)
+}
+
+type VirtualAccountRepository interface {
+	Create(ctx context.Context, va *domain.VirtualAccount) error
+	FindByPaymentID(ctx context.Context, paymentID string) (*domain.VirtualAccount, error)
+	FindByAccountNumber(
+		ctx context.Context,
+		bankCode string,
+		accountNumber string,
+	) (*domain.VirtualAccount, error)
+	// ApplyCredit stores the credit and adds it to the account's paid
+	// amount together, then refreshes va from the stored account. An account
+	// that is or is passed in as EXPIRED stays expired. A credit whose
+	// reference was seen before returns ErrDuplicateBankCredit.
+	ApplyCredit(
+		ctx context.Context,
+		va *domain.VirtualAccount,
+		credit *domain.BankCredit,
+	) error
+	UpdateStatus(
+		ctx context.Context,
+		id int,
+		status domain.VirtualAccountStatus,
+	) error
+}
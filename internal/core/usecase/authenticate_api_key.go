@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// AuthenticateAPIKeyUsecase resolves an API key secret to the merchant it
+// acts for.
+type AuthenticateAPIKeyUsecase struct {
+	apiKeyRepo   ports.APIKeyRepository
+	merchantRepo ports.MerchantRepository
+	now          func() time.Time
+}
+
+func NewAuthenticateAPIKeyUsecase(
+	apiKeyRepo ports.APIKeyRepository,
+	merchantRepo ports.MerchantRepository,
+) *AuthenticateAPIKeyUsecase {
+	return &AuthenticateAPIKeyUsecase{
+		apiKeyRepo:   apiKeyRepo,
+		merchantRepo: merchantRepo,
+		now:          time.Now,
+	}
+}
+
+func (uc *AuthenticateAPIKeyUsecase) Execute(
+	ctx context.Context,
+	secret string,
+) (domain.Tenant, error) {
+	ctx, span := observability.Tracer().Start(ctx, "AuthenticateAPIKeyUseCase.Execute")
+	defer span.End()
+
+	tenant, err := uc.authenticate(ctx, secret)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return domain.Tenant{}, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("merchant.id", tenant.MerchantID),
+		attribute.String("api_key.mode", string(tenant.Mode)),
+	)
+	return tenant, nil
+}
+
+// authenticate reports every rejected key as ErrInvalidAPIKey so callers
+// cannot tell an unknown key from an expired one or a disabled merchant.
+func (uc *AuthenticateAPIKeyUsecase) authenticate(
+	ctx context.Context,
+	secret string,
+) (domain.Tenant, error) {
+	if secret == "" {
+		return domain.Tenant{}, domain.ErrInvalidAPIKey
+	}
+
+	key, err := uc.apiKeyRepo.FindByHash(ctx, domain.HashAPIKey(secret))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return domain.Tenant{}, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return domain.Tenant{}, err
+	}
+	if !key.ActiveAt(uc.now()) {
+		return domain.Tenant{}, domain.ErrInvalidAPIKey
+	}
+
+	merchant, err := uc.merchantRepo.FindByID(ctx, key.MerchantID)
+	if errors.Is(err, domain.ErrMerchantNotFound) {
+		return domain.Tenant{}, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return domain.Tenant{}, err
+	}
+	if merchant.Status != domain.MerchantStatusActive {
+		return domain.Tenant{}, domain.ErrInvalidAPIKey
+	}
+
+	return domain.Tenant{
+		MerchantID: merchant.ID,
+		Mode:       key.Mode,
+		APIKeyID:   key.ID,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// AuthenticateServiceTokenUsecase resolves the merchant an internal
+// service's token is bound to. The token itself is verified by the HTTP
+// layer; this only checks that the merchant it names may be acted for.
+type AuthenticateServiceTokenUsecase struct {
+	merchantRepo ports.MerchantRepository
+}
+
+func NewAuthenticateServiceTokenUsecase(
+	merchantRepo ports.MerchantRepository,
+) *AuthenticateServiceTokenUsecase {
+	return &AuthenticateServiceTokenUsecase{
+		merchantRepo: merchantRepo,
+	}
+}
+
+func (uc *AuthenticateServiceTokenUsecase) Execute(
+	ctx context.Context,
+	merchantID string,
+	mode domain.Mode,
+) (domain.Tenant, error) {
+	ctx, span := observability.Tracer().Start(ctx, "AuthenticateServiceTokenUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("merchant.public_id", merchantID),
+		attribute.String("api_key.mode", string(mode)),
+	)
+
+	tenant, err := uc.authenticate(ctx, merchantID, mode)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return domain.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+// authenticate reports unknown and disabled merchants alike as
+// ErrInvalidToken.
+func (uc *AuthenticateServiceTokenUsecase) authenticate(
+	ctx context.Context,
+	merchantID string,
+	mode domain.Mode,
+) (domain.Tenant, error) {
+	if merchantID == "" {
+		return domain.Tenant{}, domain.ErrTokenMerchantRequired
+	}
+	if mode == "" {
+		mode = domain.ModeLive
+	}
+	if !mode.Valid() {
+		return domain.Tenant{}, domain.ErrInvalidToken
+	}
+
+	merchant, err := uc.merchantRepo.FindByPublicID(ctx, merchantID)
+	if errors.Is(err, domain.ErrMerchantNotFound) {
+		return domain.Tenant{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.Tenant{}, err
+	}
+	if merchant.Status != domain.MerchantStatusActive {
+		return domain.Tenant{}, domain.ErrInvalidToken
+	}
+
+	return domain.Tenant{
+		MerchantID: merchant.ID,
+		Mode:       mode,
+	}, nil
+}
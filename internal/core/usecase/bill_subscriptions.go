@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// paymentCreator is the part of CreatePaymentUsecase used to charge
+// subscriptions.
+type paymentCreator interface {
+	Execute(ctx context.Context, input CreatePaymentInput) (*CreatePaymentOutput, error)
+}
+
+type BillingPolicy struct {
+	// Provider is recorded on the payments created for subscriptions.
+	Provider string
+	// RetryAfter is the wait before each dunning retry of a failed cycle.
+	// When all retries failed the subscription is canceled.
+	RetryAfter []time.Duration
+	// PendingRetryAfter is the wait before checking again on a charge that
+	// is held for review or otherwise not final.
+	PendingRetryAfter time.Duration
+	// BatchSize caps how many subscriptions are charged per run.
+	BatchSize int
+}
+
+type BillSubscriptionsOutput struct {
+	Charged  int
+	Pending  int
+	Failed   int
+	Canceled int
+}
+
+type BillSubscriptionsUsecase struct {
+	subscriptionRepo ports.SubscriptionRepository
+	planRepo         ports.PlanRepository
+	createPayment    paymentCreator
+	policy           BillingPolicy
+}
+
+func NewBillSubscriptionsUsecase(
+	subscriptionRepo ports.SubscriptionRepository,
+	planRepo ports.PlanRepository,
+	createPayment paymentCreator,
+	policy BillingPolicy,
+) *BillSubscriptionsUsecase {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 100
+	}
+	if policy.PendingRetryAfter <= 0 {
+		policy.PendingRetryAfter = time.Hour
+	}
+	return &BillSubscriptionsUsecase{
+		subscriptionRepo: subscriptionRepo,
+		planRepo:         planRepo,
+		createPayment:    createPayment,
+		policy:           policy,
+	}
+}
+
+// Execute charges every subscription that is due at now.
+func (uc *BillSubscriptionsUsecase) Execute(
+	ctx context.Context,
+	now time.Time,
+) (*BillSubscriptionsOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "BillSubscriptionsUseCase.Execute")
+	defer span.End()
+
+	due, err := uc.subscriptionRepo.ListDue(ctx, now, uc.policy.BatchSize)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	var out BillSubscriptionsOutput
+	var errs []error
+	for _, s := range due {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := uc.bill(ctx, s, now, &out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("billing.due", len(due)),
+		attribute.Int("billing.charged", out.Charged),
+		attribute.Int("billing.pending", out.Pending),
+		attribute.Int("billing.failed", out.Failed),
+	)
+
+	return &out, errors.Join(errs...)
+}
+
+func (uc *BillSubscriptionsUsecase) bill(
+	ctx context.Context,
+	s *domain.Subscription,
+	now time.Time,
+	out *BillSubscriptionsOutput,
+) error {
+	ctx = actingFor(ctx, s.MerchantID)
+
+	plan, err := uc.planRepo.FindByID(ctx, s.PlanID)
+	if err != nil {
+		return err
+	}
+
+	payment, err := uc.createPayment.Execute(ctx, CreatePaymentInput{
+		OrderID:            s.BillingOrderID(),
+		PayerID:            s.PayerID,
+		Amount:             plan.Amount,
+		Currency:           plan.Currency,
+		Provider:           uc.policy.Provider,
+		IdempotencyKey:     s.BillingKey(),
+		PaymentMethodToken: s.PaymentMethodToken,
+		OffSession:         true,
+	})
+
+	// only a settled charge starts the next period; a held charge is looked
+	// at again through the same billing key until it settles or fails
+	switch {
+	case err != nil && !chargeRefused(err):
+		// the charge got no answer, so it used up no retry; the
+		// subscription stays due and is charged again on the next run
+		observability.SubscriptionCharges.WithLabelValues("error").Inc()
+		return err
+	case err == nil && payment.Status == domain.PaymentStatusSuccess:
+		s.MarkPaid(plan, payment.PaymentID)
+		out.Charged++
+		observability.SubscriptionCharges.WithLabelValues("success").Inc()
+	case err == nil && !payment.Status.IsFinal():
+		s.MarkPending(now.Add(uc.policy.PendingRetryAfter))
+		out.Pending++
+		observability.SubscriptionCharges.WithLabelValues("pending").Inc()
+	default:
+		s.MarkFailed(now, uc.policy.RetryAfter)
+		out.Failed++
+		observability.SubscriptionCharges.WithLabelValues("failed").Inc()
+		if s.Status == domain.SubscriptionStatusCanceled {
+			out.Canceled++
+			observability.SubscriptionCharges.WithLabelValues("canceled").Inc()
+		}
+	}
+
+	return uc.subscriptionRepo.Update(ctx, s)
+}
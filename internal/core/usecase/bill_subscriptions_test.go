@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// mockPlanRepo implements ports.PlanRepository
+type mockPlanRepo struct {
+	plans map[int]*domain.Plan
+}
+
+func newMockPlanRepo(plans ...*domain.Plan) *mockPlanRepo {
+	m := &mockPlanRepo{plans: map[int]*domain.Plan{}}
+	for _, p := range plans {
+		m.plans[p.ID] = p
+	}
+	return m
+}
+
+func (m *mockPlanRepo) Create(ctx context.Context, plan *domain.Plan) error {
+	plan.ID = len(m.plans) + 1
+	m.plans[plan.ID] = plan
+	return nil
+}
+
+func (m *mockPlanRepo) FindByID(ctx context.Context, id int) (*domain.Plan, error) {
+	p, ok := m.plans[id]
+	if !ok {
+		return nil, domain.ErrPlanNotFound
+	}
+	return p, nil
+}
+
+func (m *mockPlanRepo) FindByPublicID(ctx context.Context, publicID string) (*domain.Plan, error) {
+	for _, p := range m.plans {
+		if p.PublicID == publicID {
+			return p, nil
+		}
+	}
+	return nil, domain.ErrPlanNotFound
+}
+
+func (m *mockPlanRepo) List(ctx context.Context, limit, offset int) ([]*domain.Plan, error) {
+	return nil, errors.New("not implemented")
+}
+
+// mockSubscriptionRepo implements ports.SubscriptionRepository
+type mockSubscriptionRepo struct {
+	subscriptions []*domain.Subscription
+	updated       []*domain.Subscription
+}
+
+func (m *mockSubscriptionRepo) Create(ctx context.Context, s *domain.Subscription) error {
+	s.ID = len(m.subscriptions) + 1
+	m.subscriptions = append(m.subscriptions, s)
+	return nil
+}
+
+func (m *mockSubscriptionRepo) Update(ctx context.Context, s *domain.Subscription) error {
+	cp := *s
+	m.updated = append(m.updated, &cp)
+	return nil
+}
+
+func (m *mockSubscriptionRepo) FindByPublicID(ctx context.Context, publicID string) (*domain.Subscription, error) {
+	for _, s := range m.subscriptions {
+		if s.PublicID == publicID {
+			return s, nil
+		}
+	}
+	return nil, domain.ErrSubscriptionNotFound
+}
+
+func (m *mockSubscriptionRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error) {
+	var due []*domain.Subscription
+	for _, s := range m.subscriptions {
+		if s.IsDue(now) {
+			due = append(due, s)
+		}
+	}
+	return due, nil
+}
+
+// fakePaymentCreator records charge attempts. Charges settle unless status
+// is set.
+type fakePaymentCreator struct {
+	err    error
+	status domain.PaymentStatus
+	inputs []CreatePaymentInput
+}
+
+func (f *fakePaymentCreator) Execute(ctx context.Context, input CreatePaymentInput) (*CreatePaymentOutput, error) {
+	f.inputs = append(f.inputs, input)
+	if f.err != nil {
+		return nil, f.err
+	}
+	status := f.status
+	if status == "" {
+		status = domain.PaymentStatusSuccess
+	}
+	return &CreatePaymentOutput{PaymentID: "pay_" + input.IdempotencyKey, Status: status}, nil
+}
+
+func monthlyPlan() *domain.Plan {
+	return &domain.Plan{
+		ID:            1,
+		PublicID:      "plan_1",
+		Amount:        5000,
+		Currency:      "IDR",
+		Interval:      domain.PlanIntervalMonth,
+		IntervalCount: 1,
+		Active:        true,
+	}
+}
+
+func TestBillSubscriptions_ChargesDueSubscription(t *testing.T) {
+	observability.InitTracer("test")
+
+	now := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+	sub := &domain.Subscription{
+		PublicID:           "sub_1",
+		PlanID:             1,
+		PayerID:            1,
+		PaymentMethodToken: "pm_1",
+		Status:             domain.SubscriptionStatusActive,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now,
+	}
+	subs := &mockSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}
+	creator := &fakePaymentCreator{}
+
+	uc := NewBillSubscriptionsUsecase(subs, newMockPlanRepo(monthlyPlan()), creator, BillingPolicy{Provider: "fake"})
+
+	out, err := uc.Execute(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Charged != 1 {
+		t.Fatalf("expected 1 charge, got %d", out.Charged)
+	}
+	if got := creator.inputs[0].IdempotencyKey; got != "sub_1:cycle:1" {
+		t.Fatalf("expected deterministic idempotency key, got %s", got)
+	}
+	if creator.inputs[0].Amount != 5000 || creator.inputs[0].PaymentMethodToken != "pm_1" || !creator.inputs[0].OffSession {
+		t.Fatalf("unexpected charge input: %+v", creator.inputs[0])
+	}
+	if sub.Cycle != 1 || !sub.CurrentPeriodEnd.Equal(now.AddDate(0, 1, 0)) {
+		t.Fatalf("expected period to advance, got cycle=%d end=%s", sub.Cycle, sub.CurrentPeriodEnd)
+	}
+
+	// nothing is due until the new period ends
+	out, _ = uc.Execute(context.Background(), now.Add(time.Hour))
+	if out.Charged != 0 {
+		t.Fatalf("expected no charge before period end, got %d", out.Charged)
+	}
+}
+
+func TestBillSubscriptions_DunningThenCancel(t *testing.T) {
+	observability.InitTracer("test")
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := &domain.Subscription{
+		PublicID:         "sub_2",
+		PlanID:           1,
+		PayerID:          1,
+		Status:           domain.SubscriptionStatusActive,
+		CurrentPeriodEnd: now,
+	}
+	subs := &mockSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}
+	creator := &fakePaymentCreator{err: fmt.Errorf("%w: insufficient funds", domain.ErrPaymentDeclined)}
+	policy := BillingPolicy{
+		Provider:   "fake",
+		RetryAfter: []time.Duration{time.Hour, 2 * time.Hour},
+	}
+
+	uc := NewBillSubscriptionsUsecase(subs, newMockPlanRepo(monthlyPlan()), creator, policy)
+
+	_, _ = uc.Execute(context.Background(), now)
+	if sub.Status != domain.SubscriptionStatusPastDue || sub.NextRetryAt == nil {
+		t.Fatalf("expected PAST_DUE with retry scheduled, got %s", sub.Status)
+	}
+	if !sub.NextRetryAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected retry after 1h, got %s", sub.NextRetryAt)
+	}
+
+	// not yet due for retry
+	_, _ = uc.Execute(context.Background(), now.Add(30*time.Minute))
+	if len(creator.inputs) != 1 {
+		t.Fatalf("expected no retry before NextRetryAt, got %d attempts", len(creator.inputs))
+	}
+
+	now = now.Add(time.Hour)
+	_, _ = uc.Execute(context.Background(), now)
+	now = now.Add(2 * time.Hour)
+	out, _ := uc.Execute(context.Background(), now)
+
+	if sub.Status != domain.SubscriptionStatusCanceled {
+		t.Fatalf("expected CANCELED after exhausting retries, got %s", sub.Status)
+	}
+	if out.Canceled != 1 {
+		t.Fatalf("expected canceled count 1, got %d", out.Canceled)
+	}
+	wantKeys := []string{"sub_2:cycle:1", "sub_2:cycle:1:attempt:2", "sub_2:cycle:1:attempt:3"}
+	if len(creator.inputs) != len(wantKeys) {
+		t.Fatalf("expected %d attempts, got %d", len(wantKeys), len(creator.inputs))
+	}
+	for i, in := range creator.inputs {
+		if in.IdempotencyKey != wantKeys[i] {
+			t.Fatalf("attempt %d: expected key %s, got %s", i+1, wantKeys[i], in.IdempotencyKey)
+		}
+		if in.OrderID != "sub_2:cycle:1" {
+			t.Fatalf("attempt %d: expected the cycle's order id, got %s", i+1, in.OrderID)
+		}
+	}
+}
+
+func TestBillSubscriptions_TransientErrorUsesNoRetry(t *testing.T) {
+	observability.InitTracer("test")
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := &domain.Subscription{
+		PublicID:         "sub_5",
+		PlanID:           1,
+		PayerID:          1,
+		Status:           domain.SubscriptionStatusActive,
+		CurrentPeriodEnd: now,
+	}
+	subs := &mockSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}
+	creator := &fakePaymentCreator{err: errors.New("database is locked")}
+	policy := BillingPolicy{Provider: "fake", RetryAfter: []time.Duration{time.Hour}}
+	uc := NewBillSubscriptionsUsecase(subs, newMockPlanRepo(monthlyPlan()), creator, policy)
+
+	out, err := uc.Execute(context.Background(), now)
+	if err == nil {
+		t.Fatalf("expected the error to be reported")
+	}
+	if out.Failed != 0 || sub.FailedAttempts != 0 || sub.Status != domain.SubscriptionStatusActive || len(subs.updated) != 0 {
+		t.Fatalf("expected the subscription to be left due, got %+v %+v", out, sub)
+	}
+
+	// the next run charges the same attempt again
+	creator.err = nil
+	out, _ = uc.Execute(context.Background(), now.Add(time.Minute))
+	if out.Charged != 1 || creator.inputs[1].IdempotencyKey != creator.inputs[0].IdempotencyKey {
+		t.Fatalf("expected the cycle to be charged under the same key, got %+v", out)
+	}
+}
+
+func TestBillSubscriptions_OnlySettledChargesArePaid(t *testing.T) {
+	observability.InitTracer("test")
+
+	for _, status := range []domain.PaymentStatus{
+		domain.PaymentStatusPending,
+		domain.PaymentStatusRequiresAction,
+		domain.PaymentStatusOnHold,
+		domain.PaymentStatusReview,
+	} {
+		t.Run(string(status), func(t *testing.T) {
+			now := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+			sub := &domain.Subscription{
+				PublicID:         "sub_3",
+				PlanID:           1,
+				PayerID:          1,
+				Status:           domain.SubscriptionStatusActive,
+				CurrentPeriodEnd: now,
+			}
+			subs := &mockSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}
+			creator := &fakePaymentCreator{status: status}
+			policy := BillingPolicy{
+				Provider:          "fake",
+				RetryAfter:        []time.Duration{time.Hour},
+				PendingRetryAfter: 30 * time.Minute,
+			}
+			uc := NewBillSubscriptionsUsecase(subs, newMockPlanRepo(monthlyPlan()), creator, policy)
+
+			out, err := uc.Execute(context.Background(), now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Charged != 0 || out.Pending != 1 || sub.Cycle != 0 {
+				t.Fatalf("expected the charge to stay pending, got %+v cycle=%d", out, sub.Cycle)
+			}
+			if sub.FailedAttempts != 0 || sub.NextRetryAt == nil || !sub.NextRetryAt.Equal(now.Add(30*time.Minute)) {
+				t.Fatalf("expected a recheck without using a retry, got %+v", sub)
+			}
+
+			// the recheck replays the same cycle and picks up the settled charge
+			creator.status = domain.PaymentStatusSuccess
+			now = now.Add(30 * time.Minute)
+			out, _ = uc.Execute(context.Background(), now)
+			if out.Charged != 1 || sub.Cycle != 1 || sub.Status != domain.SubscriptionStatusActive {
+				t.Fatalf("expected the cycle to be paid, got %+v %+v", out, sub)
+			}
+			if creator.inputs[1].IdempotencyKey != creator.inputs[0].IdempotencyKey {
+				t.Fatalf("recheck must reuse the cycle key")
+			}
+		})
+	}
+
+	t.Run(string(domain.PaymentStatusFailed), func(t *testing.T) {
+		now := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+		sub := &domain.Subscription{
+			PublicID:         "sub_4",
+			PlanID:           1,
+			PayerID:          1,
+			Status:           domain.SubscriptionStatusActive,
+			CurrentPeriodEnd: now,
+		}
+		subs := &mockSubscriptionRepo{subscriptions: []*domain.Subscription{sub}}
+		creator := &fakePaymentCreator{status: domain.PaymentStatusFailed}
+		policy := BillingPolicy{Provider: "fake", RetryAfter: []time.Duration{time.Hour}}
+		uc := NewBillSubscriptionsUsecase(subs, newMockPlanRepo(monthlyPlan()), creator, policy)
+
+		out, _ := uc.Execute(context.Background(), now)
+		if out.Failed != 1 || sub.FailedAttempts != 1 || sub.Status != domain.SubscriptionStatusPastDue {
+			t.Fatalf("expected a rejected charge to enter dunning, got %+v %+v", out, sub)
+		}
+
+		// the retry is a new charge rather than a replay of the failed one
+		creator.status = domain.PaymentStatusSuccess
+		out, _ = uc.Execute(context.Background(), now.Add(time.Hour))
+		if got := creator.inputs[1].IdempotencyKey; got != "sub_4:cycle:1:attempt:2" {
+			t.Fatalf("expected the retry to use a new key, got %s", got)
+		}
+		if out.Charged != 1 || sub.Cycle != 1 || sub.FailedAttempts != 0 {
+			t.Fatalf("expected the retry to pay the cycle, got %+v %+v", out, sub)
+		}
+	})
+}
+
+func TestCreateSubscription_Trial(t *testing.T) {
+	observability.InitTracer("test")
+
+	plan := monthlyPlan()
+	plan.TrialDays = 14
+	pm := &domain.PaymentMethod{Token: "pm_1", PayerID: 1, Status: domain.PaymentMethodStatusActive}
+	subs := &mockSubscriptionRepo{}
+
+	uc := NewCreateSubscriptionUsecase(newMockPlanRepo(plan), subs, activePayers(), newMockPaymentMethodRepo(pm))
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	s, err := uc.Execute(context.Background(), CreateSubscriptionInput{
+		PlanID:             "plan_1",
+		PayerID:            1,
+		PaymentMethodToken: "pm_1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Status != domain.SubscriptionStatusTrialing {
+		t.Fatalf("expected TRIALING, got %s", s.Status)
+	}
+	if !s.CurrentPeriodEnd.Equal(now.AddDate(0, 0, 14)) {
+		t.Fatalf("expected first charge after trial, got %s", s.CurrentPeriodEnd)
+	}
+
+	// instrument of another payer is rejected
+	_, err = uc.Execute(context.Background(), CreateSubscriptionInput{
+		PlanID:             "plan_1",
+		PayerID:            2,
+		PaymentMethodToken: "pm_1",
+	})
+	if !errors.Is(err, domain.ErrPaymentMethodNotOwned) {
+		t.Fatalf("expected ErrPaymentMethodNotOwned, got %v", err)
+	}
+}
+
+func TestSubscription_PauseResume(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	s := &domain.Subscription{
+		Status:           domain.SubscriptionStatusActive,
+		CurrentPeriodEnd: now.Add(-48 * time.Hour),
+	}
+
+	if err := s.Pause(); err != nil {
+		t.Fatalf("unexpected pause error: %v", err)
+	}
+	if s.IsDue(now) {
+		t.Fatalf("paused subscription must not be due")
+	}
+	if err := s.Resume(now); err != nil {
+		t.Fatalf("unexpected resume error: %v", err)
+	}
+	if !s.IsDue(now) {
+		t.Fatalf("resumed subscription with elapsed period should be due")
+	}
+	if err := s.Cancel(now); err != nil {
+		t.Fatalf("unexpected cancel error: %v", err)
+	}
+	if err := s.Resume(now); !errors.Is(err, domain.ErrInvalidSubscriptionChange) {
+		t.Fatalf("expected ErrInvalidSubscriptionChange, got %v", err)
+	}
+}
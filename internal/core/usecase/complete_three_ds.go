@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CompleteThreeDSOutput struct {
+	Payment *domain.Payment
+	// ReturnURL is empty when the merchant did not give one.
+	ReturnURL string
+}
+
+// CompleteThreeDSUsecase picks up a card payment after the payer finished
+// the authentication challenge and resumes its authorization.
+type CompleteThreeDSUsecase struct {
+	threeDSRepo     ports.ThreeDSRepository
+	paymentRepo     ports.PaymentRepository
+	authenticator   ports.CardAuthenticator
+	paymentProvider ports.PaymentProvider
+	now             func() time.Time
+}
+
+func NewCompleteThreeDSUsecase(
+	threeDSRepo ports.ThreeDSRepository,
+	paymentRepo ports.PaymentRepository,
+	authenticator ports.CardAuthenticator,
+	paymentProvider ports.PaymentProvider,
+) *CompleteThreeDSUsecase {
+	return &CompleteThreeDSUsecase{
+		threeDSRepo:     threeDSRepo,
+		paymentRepo:     paymentRepo,
+		authenticator:   authenticator,
+		paymentProvider: paymentProvider,
+		now:             time.Now,
+	}
+}
+
+// Execute is safe to repeat, also concurrently: a payment that already left
+// REQUIRES_ACTION is returned as it is.
+func (uc *CompleteThreeDSUsecase) Execute(
+	ctx context.Context,
+	paymentID string,
+) (*CompleteThreeDSOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CompleteThreeDSUseCase.Execute")
+	defer span.End()
+
+	out, err := uc.complete(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("payment.id", paymentID),
+		attribute.String("payment.status", string(out.Payment.Status)),
+		attribute.Bool("three_ds.liability_shift", out.Payment.LiabilityShift),
+	)
+	return out, nil
+}
+
+func (uc *CompleteThreeDSUsecase) complete(
+	ctx context.Context,
+	paymentID string,
+) (*CompleteThreeDSOutput, error) {
+	auth, err := uc.threeDSRepo.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &CompleteThreeDSOutput{
+		Payment:   payment,
+		ReturnURL: auth.ReturnURL,
+	}
+	if payment.Status != domain.PaymentStatusRequiresAction {
+		return out, nil
+	}
+
+	now := uc.now()
+	if payment.IsExpiredAt(now) {
+		if err := payment.TransitionTo(domain.PaymentStatusExpired, now); err != nil {
+			return nil, err
+		}
+		return out, uc.paymentRepo.UpdateStatus(ctx, payment, domain.PaymentStatusRequiresAction)
+	}
+
+	result, err := uc.authenticator.Result(ctx, auth.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsFinal() {
+		return nil, domain.ErrThreeDSPending
+	}
+
+	payment.ApplyThreeDS(result)
+	next := domain.PaymentStatusFailed
+	if result.LiabilityShift() {
+		next = domain.PaymentStatusProcessing
+	}
+	if err := payment.TransitionTo(next, now); err != nil {
+		return nil, err
+	}
+
+	// the conditional update claims the challenge before the provider is
+	// called, so a payer redirected twice is only charged once
+	err = uc.paymentRepo.UpdateStatus(ctx, payment, domain.PaymentStatusRequiresAction)
+	if errors.Is(err, domain.ErrPaymentStatusConflict) {
+		out.Payment, err = uc.paymentRepo.FindbyPublicID(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if next == domain.PaymentStatusProcessing {
+		// resume the authorization that was held for the challenge
+		if err := uc.paymentProvider.Process(ctx, payment.Method); err != nil {
+			if err := payment.TransitionTo(domain.PaymentStatusFailed, now); err != nil {
+				return nil, err
+			}
+			if err := uc.paymentRepo.UpdateStatus(ctx, payment, domain.PaymentStatusProcessing); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := uc.threeDSRepo.UpdateStatus(ctx, auth.ID, result); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
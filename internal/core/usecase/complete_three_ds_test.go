@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type mockThreeDSRepo struct {
+	auths map[string]*domain.ThreeDSAuthentication
+}
+
+func newMockThreeDSRepo(auths ...*domain.ThreeDSAuthentication) *mockThreeDSRepo {
+	r := &mockThreeDSRepo{auths: map[string]*domain.ThreeDSAuthentication{}}
+	for _, a := range auths {
+		r.auths[a.PaymentID] = a
+	}
+	return r
+}
+
+func (r *mockThreeDSRepo) Create(ctx context.Context, auth *domain.ThreeDSAuthentication) error {
+	cp := *auth
+	cp.ID = len(r.auths) + 1
+	r.auths[auth.PaymentID] = &cp
+	return nil
+}
+
+func (r *mockThreeDSRepo) FindByPaymentID(ctx context.Context, paymentID string) (*domain.ThreeDSAuthentication, error) {
+	a, ok := r.auths[paymentID]
+	if !ok {
+		return nil, domain.ErrThreeDSNotFound
+	}
+	return a, nil
+}
+
+func (r *mockThreeDSRepo) UpdateStatus(ctx context.Context, id int, status domain.ThreeDSStatus) error {
+	for _, a := range r.auths {
+		if a.ID == id {
+			a.Status = status
+			return nil
+		}
+	}
+	return domain.ErrThreeDSNotFound
+}
+
+// fakeAuthenticator answers Authenticate with outcome and Result with result.
+type fakeAuthenticator struct {
+	outcome domain.ThreeDSStatus
+	result  domain.ThreeDSStatus
+}
+
+func (a *fakeAuthenticator) Authenticate(ctx context.Context, p *domain.Payment, card *domain.PaymentMethod) (*domain.ThreeDSAuthentication, error) {
+	auth := &domain.ThreeDSAuthentication{
+		PaymentID: p.PublicID,
+		Reference: "3ds_ref",
+		Status:    a.outcome,
+	}
+	if a.outcome == domain.ThreeDSStatusChallengeRequired {
+		auth.ChallengeURL = "http://acs.test/sim/3ds/3ds_ref"
+	}
+	return auth, nil
+}
+
+func (a *fakeAuthenticator) Result(ctx context.Context, reference string) (domain.ThreeDSStatus, error) {
+	return a.result, nil
+}
+
+func savedCard() *mockPaymentMethodRepo {
+	return newMockPaymentMethodRepo(&domain.PaymentMethod{
+		Token:    "pm_card",
+		PayerID:  1,
+		Type:     domain.PaymentMethodTypeCard,
+		Last4:    "4242",
+		ExpMonth: 12,
+		ExpYear:  time.Now().Year() + 2,
+		Status:   domain.PaymentMethodStatusActive,
+	})
+}
+
+func cardInput(key string) CreatePaymentInput {
+	return CreatePaymentInput{
+		OrderID:            "order_3ds",
+		PayerID:            1,
+		Amount:             2000000,
+		Currency:           "IDR",
+		Provider:           "fake",
+		PaymentMethodToken: "pm_card",
+		ReturnURL:          "https://merchant.test/done",
+		IdempotencyKey:     key,
+	}
+}
+
+func challengedPayment(expiresAt time.Time) (*domain.Payment, *domain.ThreeDSAuthentication) {
+	p := &domain.Payment{
+		PublicID:      "pay_3ds",
+		Amount:        2000000,
+		Currency:      "IDR",
+		Method:        domain.PaymentMethodTypeCard,
+		Status:        domain.PaymentStatusRequiresAction,
+		ThreeDSStatus: domain.ThreeDSStatusChallengeRequired,
+		ExpiresAt:     &expiresAt,
+	}
+	auth := &domain.ThreeDSAuthentication{
+		ID:        1,
+		PaymentID: p.PublicID,
+		Reference: "3ds_ref",
+		Status:    domain.ThreeDSStatusChallengeRequired,
+		ReturnURL: "https://merchant.test/done",
+	}
+	return p, auth
+}
+
+func TestCreatePayment_FrictionlessCardShiftsLiability(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	provider := &mockPaymentProvider{}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), savedCard(), provider).
+		WithThreeDS(&fakeAuthenticator{outcome: domain.ThreeDSStatusAuthenticated}, newMockThreeDSRepo(), 10*time.Minute)
+
+	out, err := uc.Execute(context.Background(), cardInput("idem-3ds-frictionless"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.NextAction != nil || out.Status != domain.PaymentStatusPending {
+		t.Fatalf("expected no challenge, got %+v", out)
+	}
+	if provider.calledWith != domain.PaymentMethodTypeCard {
+		t.Fatalf("expected the payment to be processed")
+	}
+	stored := payments.payments[out.PaymentID]
+	if stored.ThreeDSStatus != domain.ThreeDSStatusAuthenticated || !stored.LiabilityShift {
+		t.Fatalf("expected liability shift to be stored, got %+v", stored)
+	}
+}
+
+func TestCreatePayment_ChallengeRequiresAction(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	provider := &mockPaymentProvider{}
+	threeDS := newMockThreeDSRepo()
+	uc := NewCreatePaymentUsecase(payments, activePayers(), savedCard(), provider).
+		WithThreeDS(&fakeAuthenticator{outcome: domain.ThreeDSStatusChallengeRequired}, threeDS, 10*time.Minute)
+
+	out, err := uc.Execute(context.Background(), cardInput("idem-3ds-challenge"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusRequiresAction || out.ExpiresAt == nil {
+		t.Fatalf("expected REQUIRES_ACTION with an expiry, got %+v", out)
+	}
+	if out.NextAction == nil || out.NextAction.RedirectURL != "http://acs.test/sim/3ds/3ds_ref" {
+		t.Fatalf("expected redirect to the challenge, got %+v", out.NextAction)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("payment must not be processed before the challenge")
+	}
+	auth, err := threeDS.FindByPaymentID(context.Background(), out.PaymentID)
+	if err != nil || auth.ReturnURL != "https://merchant.test/done" {
+		t.Fatalf("expected challenge to be stored with the return url, got %+v %v", auth, err)
+	}
+}
+
+func TestCreatePayment_OffSessionSkipsChallenge(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	provider := &mockPaymentProvider{}
+	threeDS := newMockThreeDSRepo()
+	uc := NewCreatePaymentUsecase(payments, activePayers(), savedCard(), provider).
+		WithThreeDS(&fakeAuthenticator{outcome: domain.ThreeDSStatusChallengeRequired}, threeDS, 10*time.Minute)
+
+	input := cardInput("idem-3ds-off-session")
+	input.OffSession = true
+	out, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusSuccess || out.NextAction != nil {
+		t.Fatalf("expected the renewal to settle without a challenge, got %+v", out)
+	}
+	if provider.calledWith != domain.PaymentMethodTypeCard || len(threeDS.auths) != 0 {
+		t.Fatalf("expected the card to be charged without authentication")
+	}
+	if stored := payments.payments[out.PaymentID]; stored.PaidAt == nil {
+		t.Fatalf("expected paid_at to be recorded, got %+v", stored)
+	}
+}
+
+func TestCreatePayment_FailedAuthenticationRejected(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	uc := NewCreatePaymentUsecase(payments, activePayers(), savedCard(), &mockPaymentProvider{}).
+		WithThreeDS(&fakeAuthenticator{outcome: domain.ThreeDSStatusFailed}, newMockThreeDSRepo(), 10*time.Minute)
+
+	_, err := uc.Execute(context.Background(), cardInput("idem-3ds-failed"))
+	if !errors.Is(err, domain.ErrCardAuthenticationFailed) {
+		t.Fatalf("expected ErrCardAuthenticationFailed, got %v", err)
+	}
+	if len(payments.payments) != 0 {
+		t.Fatalf("expected no payment to be stored")
+	}
+}
+
+func TestCompleteThreeDS_AuthenticatedResumesPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, auth := challengedPayment(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	threeDS := newMockThreeDSRepo(auth)
+	provider := &mockPaymentProvider{}
+	uc := NewCompleteThreeDSUsecase(threeDS, payments,
+		&fakeAuthenticator{result: domain.ThreeDSStatusAuthenticated}, provider)
+
+	out, err := uc.Execute(context.Background(), "pay_3ds")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Payment.Status != domain.PaymentStatusProcessing || !out.Payment.LiabilityShift {
+		t.Fatalf("expected PROCESSING with liability shift, got %+v", out.Payment)
+	}
+	if out.ReturnURL != "https://merchant.test/done" {
+		t.Fatalf("expected return url, got %q", out.ReturnURL)
+	}
+	if provider.calledWith != domain.PaymentMethodTypeCard {
+		t.Fatalf("expected authorization to resume")
+	}
+	if auth.Status != domain.ThreeDSStatusAuthenticated {
+		t.Fatalf("expected authentication to be updated, got %s", auth.Status)
+	}
+
+	// the issuer may redirect the payer more than once
+	provider.calledWith = ""
+	out, err = uc.Execute(context.Background(), "pay_3ds")
+	if err != nil || out.Payment.Status != domain.PaymentStatusProcessing {
+		t.Fatalf("expected repeat to be a no-op, got %+v %v", out, err)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("payment must only be processed once")
+	}
+}
+
+// racingProvider runs during once, while the first charge is in flight.
+type racingProvider struct {
+	calls  int
+	during func()
+}
+
+func (p *racingProvider) Process(ctx context.Context, method string) error {
+	p.calls++
+	if during := p.during; during != nil {
+		p.during = nil
+		during()
+	}
+	return nil
+}
+
+func TestCompleteThreeDS_ConcurrentCompletesChargeOnce(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, auth := challengedPayment(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	provider := &racingProvider{}
+	uc := NewCompleteThreeDSUsecase(newMockThreeDSRepo(auth), payments,
+		&fakeAuthenticator{result: domain.ThreeDSStatusAuthenticated}, provider)
+
+	var second *CompleteThreeDSOutput
+	var secondErr error
+	provider.during = func() {
+		second, secondErr = uc.Execute(context.Background(), "pay_3ds")
+	}
+
+	out, err := uc.Execute(context.Background(), "pay_3ds")
+	if err != nil || secondErr != nil {
+		t.Fatalf("unexpected errors: %v, %v", err, secondErr)
+	}
+	if provider.calls != 1 {
+		t.Fatalf("expected the card to be charged once, got %d", provider.calls)
+	}
+	if out.Payment.Status != domain.PaymentStatusProcessing || second.Payment.Status != domain.PaymentStatusProcessing {
+		t.Fatalf("expected both to report PROCESSING, got %s and %s", out.Payment.Status, second.Payment.Status)
+	}
+}
+
+func TestCompleteThreeDS_DeclinedAfterChallengeFailsPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, auth := challengedPayment(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewCompleteThreeDSUsecase(newMockThreeDSRepo(auth), payments,
+		&fakeAuthenticator{result: domain.ThreeDSStatusAuthenticated}, &mockPaymentProvider{err: errors.New("declined")})
+
+	out, err := uc.Execute(context.Background(), "pay_3ds")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Payment.Status != domain.PaymentStatusFailed || payments.payments["pay_3ds"].Status != domain.PaymentStatusFailed {
+		t.Fatalf("expected FAILED to be stored, got %s", payments.payments["pay_3ds"].Status)
+	}
+}
+
+func TestCompleteThreeDS_FailedChallengeFailsPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, auth := challengedPayment(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	provider := &mockPaymentProvider{}
+	uc := NewCompleteThreeDSUsecase(newMockThreeDSRepo(auth), payments,
+		&fakeAuthenticator{result: domain.ThreeDSStatusFailed}, provider)
+
+	out, err := uc.Execute(context.Background(), "pay_3ds")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Payment.Status != domain.PaymentStatusFailed || out.Payment.LiabilityShift {
+		t.Fatalf("expected FAILED without liability shift, got %+v", out.Payment)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("failed authentication must not be processed")
+	}
+}
+
+func TestCompleteThreeDS_PendingChallenge(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, auth := challengedPayment(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewCompleteThreeDSUsecase(newMockThreeDSRepo(auth), payments,
+		&fakeAuthenticator{result: domain.ThreeDSStatusChallengeRequired}, &mockPaymentProvider{})
+
+	_, err := uc.Execute(context.Background(), "pay_3ds")
+	if !errors.Is(err, domain.ErrThreeDSPending) {
+		t.Fatalf("expected ErrThreeDSPending, got %v", err)
+	}
+	if payments.payments["pay_3ds"].Status != domain.PaymentStatusRequiresAction {
+		t.Fatalf("payment must keep waiting for the challenge")
+	}
+}
+
+func TestCompleteThreeDS_ExpiredChallenge(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, auth := challengedPayment(time.Now().Add(-time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	provider := &mockPaymentProvider{}
+	uc := NewCompleteThreeDSUsecase(newMockThreeDSRepo(auth), payments,
+		&fakeAuthenticator{result: domain.ThreeDSStatusAuthenticated}, provider)
+
+	out, err := uc.Execute(context.Background(), "pay_3ds")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Payment.Status != domain.PaymentStatusExpired {
+		t.Fatalf("expected EXPIRED, got %s", out.Payment.Status)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("expired challenge must not be processed")
+	}
+}
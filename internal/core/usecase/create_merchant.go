@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CreateMerchantInput struct {
+	Name              string
+	PayoutDestination string
+}
+
+// IssuedAPIKey carries a new key's secret, which is returned once and never
+// stored.
+type IssuedAPIKey struct {
+	Key    *domain.APIKey
+	Secret string
+}
+
+type CreateMerchantOutput struct {
+	Merchant *domain.Merchant
+	Keys     []IssuedAPIKey
+}
+
+// CreateMerchantUsecase onboards a merchant with one live and one test key.
+type CreateMerchantUsecase struct {
+	merchantRepo ports.MerchantRepository
+	apiKeyRepo   ports.APIKeyRepository
+}
+
+func NewCreateMerchantUsecase(
+	merchantRepo ports.MerchantRepository,
+	apiKeyRepo ports.APIKeyRepository,
+) *CreateMerchantUsecase {
+	return &CreateMerchantUsecase{
+		merchantRepo: merchantRepo,
+		apiKeyRepo:   apiKeyRepo,
+	}
+}
+
+func (uc *CreateMerchantUsecase) Execute(
+	ctx context.Context,
+	input CreateMerchantInput,
+) (*CreateMerchantOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreateMerchantUseCase.Execute")
+	defer span.End()
+
+	out, err := uc.create(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return out, nil
+}
+
+func (uc *CreateMerchantUsecase) create(
+	ctx context.Context,
+	input CreateMerchantInput,
+) (*CreateMerchantOutput, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, domain.ErrMerchantNameRequired
+	}
+
+	merchant := &domain.Merchant{
+		PublicID:          "mer_" + uuid.NewString(),
+		Name:              name,
+		Status:            domain.MerchantStatusActive,
+		PayoutDestination: strings.TrimSpace(input.PayoutDestination),
+	}
+	if err := uc.merchantRepo.Create(ctx, merchant); err != nil {
+		return nil, err
+	}
+
+	out := &CreateMerchantOutput{Merchant: merchant}
+	for _, mode := range []domain.Mode{domain.ModeLive, domain.ModeTest} {
+		issued, err := issueAPIKey(ctx, uc.apiKeyRepo, merchant.ID, mode)
+		if err != nil {
+			return nil, err
+		}
+		out.Keys = append(out.Keys, *issued)
+	}
+	return out, nil
+}
+
+func issueAPIKey(
+	ctx context.Context,
+	apiKeyRepo ports.APIKeyRepository,
+	merchantID int,
+	mode domain.Mode,
+) (*IssuedAPIKey, error) {
+	key, secret, err := domain.NewAPIKey(merchantID, mode)
+	if err != nil {
+		return nil, err
+	}
+	if err := apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &IssuedAPIKey{Key: key, Secret: secret}, nil
+}
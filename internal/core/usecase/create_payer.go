@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type PayerInput struct {
+	Name              string
+	Email             string
+	Country           string
+	ExternalReference string
+}
+
+type CreatePayerUsecase struct {
+	payerRepo ports.PayerRepository
+}
+
+func NewCreatePayerUsecase(
+	payerRepo ports.PayerRepository,
+) *CreatePayerUsecase {
+	return &CreatePayerUsecase{
+		payerRepo: payerRepo,
+	}
+}
+
+func isValidCountryCode(country string) bool {
+	if len(country) != 2 {
+		return false
+	}
+	for _, r := range country {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizePayerInput(input PayerInput) PayerInput {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Email = strings.TrimSpace(input.Email)
+	input.Country = strings.ToUpper(strings.TrimSpace(input.Country))
+	input.ExternalReference = strings.TrimSpace(input.ExternalReference)
+	return input
+}
+
+func isValidPayerInput(input PayerInput) (bool, error) {
+	if input.Name == "" {
+		return false, errors.New("name is required")
+	}
+	if _, err := mail.ParseAddress(input.Email); err != nil {
+		return false, errors.New("email is invalid")
+	}
+	if !isValidCountryCode(input.Country) {
+		return false, errors.New("country must be an ISO 3166-1 alpha-2 code")
+	}
+	return true, nil
+}
+
+func (uc *CreatePayerUsecase) Execute(
+	ctx context.Context,
+	input PayerInput,
+) (*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreatePayerUseCase.Execute")
+	defer span.End()
+
+	input = normalizePayerInput(input)
+	if valid, err := isValidPayerInput(input); !valid {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	payer := &domain.Payer{
+		Name:              input.Name,
+		Email:             input.Email,
+		Country:           input.Country,
+		ExternalReference: input.ExternalReference,
+		Status:            domain.PayerStatusActive,
+	}
+
+	if err := uc.payerRepo.Create(ctx, payer); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return payer, nil
+}
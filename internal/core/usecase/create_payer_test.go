@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+func TestCreatePayer_Success(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := newMockPayerRepo()
+	uc := NewCreatePayerUsecase(repo)
+
+	payer, err := uc.Execute(context.Background(), PayerInput{
+		Name:              " Jane Doe ",
+		Email:             "jane@example.com",
+		Country:           "id",
+		ExternalReference: "crm-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payer.ID == 0 {
+		t.Fatalf("expected payer id to be assigned")
+	}
+	if payer.Name != "Jane Doe" {
+		t.Fatalf("expected trimmed name, got %q", payer.Name)
+	}
+	if payer.Country != "ID" {
+		t.Fatalf("expected upper-cased country, got %q", payer.Country)
+	}
+	if payer.Status != domain.PayerStatusActive {
+		t.Fatalf("expected ACTIVE status, got %s", payer.Status)
+	}
+}
+
+func TestCreatePayer_InvalidInput(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewCreatePayerUsecase(newMockPayerRepo())
+
+	cases := map[string]PayerInput{
+		"missing name":  {Email: "a@b.co", Country: "ID"},
+		"invalid email": {Name: "A", Email: "nope", Country: "ID"},
+		"bad country":   {Name: "A", Email: "a@b.co", Country: "IDN"},
+	}
+	for name, input := range cases {
+		if _, err := uc.Execute(context.Background(), input); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestCreatePayer_DuplicateExternalReference(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := newMockPayerRepo()
+	repo.createErr = domain.ErrPayerAlreadyExists
+	uc := NewCreatePayerUsecase(repo)
+
+	_, err := uc.Execute(context.Background(), PayerInput{
+		Name:              "A",
+		Email:             "a@b.co",
+		Country:           "ID",
+		ExternalReference: "dup",
+	})
+	if !errors.Is(err, domain.ErrPayerAlreadyExists) {
+		t.Fatalf("expected ErrPayerAlreadyExists, got %v", err)
+	}
+}
+
+func TestDeletePayer_Deactivates(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := newMockPayerRepo(&domain.Payer{ID: 3, Status: domain.PayerStatusActive})
+	uc := NewDeletePayerUsecase(repo)
+
+	if err := uc.Execute(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updated == nil || repo.updated.Status != domain.PayerStatusInactive {
+		t.Fatalf("expected payer to be marked INACTIVE, got %+v", repo.updated)
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"payment-service/internal/core/domain"
 	"payment-service/internal/core/ports"
 	"payment-service/internal/observability"
@@ -21,47 +22,269 @@ type CreatePaymentInput struct {
 	Provider       string
 	Method         string
 	IdempotencyKey string
+
+	// PaymentMethodToken charges a vaulted instrument; when set the method is
+	// taken from the saved instrument and Method may be left empty.
+	PaymentMethodToken string
+
+	// BankCode selects the issuing bank for bank_transfer payments.
+	BankCode string
+
+	// Wallet and Channel are used by ewallet payments. An empty wallet lets
+	// the provider pick its default.
+	Wallet  string
+	Channel string
+	// ReturnURL is where the payer is sent after approving an ewallet
+	// payment or completing a card challenge.
+	ReturnURL string
+
+	// ClientIP is the payer's address, checked against the risk blocklist.
+	ClientIP string
+
+	// OffSession marks a merchant-initiated charge of a saved card, such as
+	// a subscription renewal. No payer is present to complete a challenge,
+	// so it skips 3-D Secure and settles once the provider accepts it.
+	OffSession bool
+
+	// Splits divides a marketplace payment between recipients and must
+	// cover the whole amount.
+	Splits []domain.SplitShare
 }
 
 type CreatePaymentOutput struct {
 	PaymentID string
 	Status    domain.PaymentStatus
+	ExpiresAt *time.Time
+
+	// VirtualAccount carries the transfer instructions for bank_transfer
+	// payments and is nil for every other method.
+	VirtualAccount *domain.VirtualAccount
+	// NextAction tells the payer how to approve an ewallet payment or
+	// carries the payload to show for a qr payment.
+	NextAction *domain.NextAction
+	// QRCode is set for qr payments.
+	QRCode *domain.QRCode
+	// Replayed is set when the idempotency key was used before and this is
+	// the payment created then.
+	Replayed bool
 }
 
 type CreatePaymentUsecase struct {
-	paymentRepo     ports.PaymentRepository
-	paymentProvider ports.PaymentProvider
+	paymentRepo       ports.PaymentRepository
+	payerRepo         ports.PayerRepository
+	paymentMethodRepo ports.PaymentMethodRepository
+	paymentProvider   ports.PaymentProvider
+
+	vaProvider ports.VirtualAccountProvider
+	vaRepo     ports.VirtualAccountRepository
+	vaTTL      time.Duration
+
+	walletProvider ports.EWalletProvider
+	walletRepo     ports.EWalletAuthorizationRepository
+	walletTTL      time.Duration
+
+	qrProvider ports.QRProvider
+	qrRepo     ports.QRCodeRepository
+
+	authenticator ports.CardAuthenticator
+	threeDSRepo   ports.ThreeDSRepository
+	challengeTTL  time.Duration
+
+	riskPolicy  domain.RiskPolicy
+	riskHistory ports.RiskHistory
+
+	spendingCounter ports.SpendingCounter
+	spendingLimits  []domain.SpendingLimit
+
+	holdPolicy  domain.HoldPolicy
+	holdHistory ports.RiskHistory
+	reviewRepo  ports.ReviewRepository
+	reviewSLA   time.Duration
+
+	splitRepo ports.SplitRepository
+
+	// transactor is nil when the payment and its records are stored
+	// without a transaction.
+	transactor ports.Transactor
 }
 
 func NewCreatePaymentUsecase(
 	paymentRepo ports.PaymentRepository,
+	payerRepo ports.PayerRepository,
+	paymentMethodRepo ports.PaymentMethodRepository,
 	paymentProvider ports.PaymentProvider,
 ) *CreatePaymentUsecase {
 	return &CreatePaymentUsecase{
-		paymentRepo:     paymentRepo,
-		paymentProvider: paymentProvider,
+		paymentRepo:       paymentRepo,
+		payerRepo:         payerRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		paymentProvider:   paymentProvider,
 	}
 }
 
+// WithBankTransfer enables the bank_transfer method: such payments skip the
+// synchronous provider call and are settled later by a bank credit.
+func (uc *CreatePaymentUsecase) WithBankTransfer(
+	vaProvider ports.VirtualAccountProvider,
+	vaRepo ports.VirtualAccountRepository,
+	ttl time.Duration,
+) *CreatePaymentUsecase {
+	uc.vaProvider = vaProvider
+	uc.vaRepo = vaRepo
+	uc.vaTTL = ttl
+	return uc
+}
+
+// WithEWallet enables the ewallet method: such payments wait for the payer
+// to approve them in their wallet.
+func (uc *CreatePaymentUsecase) WithEWallet(
+	walletProvider ports.EWalletProvider,
+	walletRepo ports.EWalletAuthorizationRepository,
+	ttl time.Duration,
+) *CreatePaymentUsecase {
+	uc.walletProvider = walletProvider
+	uc.walletRepo = walletRepo
+	uc.walletTTL = ttl
+	return uc
+}
+
+// WithQR enables the qr method: such payments show a dynamic QR that the
+// payer scans with their banking app.
+func (uc *CreatePaymentUsecase) WithQR(
+	qrProvider ports.QRProvider,
+	qrRepo ports.QRCodeRepository,
+) *CreatePaymentUsecase {
+	uc.qrProvider = qrProvider
+	uc.qrRepo = qrRepo
+	return uc
+}
+
+// WithThreeDS authenticates payments on saved cards before they are
+// processed. Payments that need a challenge wait in REQUIRES_ACTION.
+func (uc *CreatePaymentUsecase) WithThreeDS(
+	authenticator ports.CardAuthenticator,
+	threeDSRepo ports.ThreeDSRepository,
+	challengeTTL time.Duration,
+) *CreatePaymentUsecase {
+	uc.authenticator = authenticator
+	uc.threeDSRepo = threeDSRepo
+	uc.challengeTTL = challengeTTL
+	return uc
+}
+
+// WithRisk scores every payment against policy before the provider is
+// called. Payments that score for review wait in REVIEW and blocked ones are
+// stored as FAILED.
+func (uc *CreatePaymentUsecase) WithRisk(
+	policy domain.RiskPolicy,
+	history ports.RiskHistory,
+) *CreatePaymentUsecase {
+	uc.riskPolicy = policy
+	uc.riskHistory = history
+	return uc
+}
+
+// WithSpendingLimits rejects payments that would take a payer over one of
+// limits.
+func (uc *CreatePaymentUsecase) WithSpendingLimits(
+	counter ports.SpendingCounter,
+	limits []domain.SpendingLimit,
+) *CreatePaymentUsecase {
+	uc.spendingCounter = counter
+	uc.spendingLimits = limits
+	return uc
+}
+
+// WithReviews puts payments matching policy ON_HOLD for a manual decision
+// and records why held payments, including those the risk engine sent to
+// REVIEW, entered the queue. Held payments expire after sla.
+func (uc *CreatePaymentUsecase) WithReviews(
+	policy domain.HoldPolicy,
+	history ports.RiskHistory,
+	reviewRepo ports.ReviewRepository,
+	sla time.Duration,
+) *CreatePaymentUsecase {
+	uc.holdPolicy = policy
+	uc.holdHistory = history
+	uc.reviewRepo = reviewRepo
+	uc.reviewSLA = sla
+	return uc
+}
+
+// WithSplits accepts split payments and stores how they are allocated
+// between recipients.
+func (uc *CreatePaymentUsecase) WithSplits(
+	splitRepo ports.SplitRepository,
+) *CreatePaymentUsecase {
+	uc.splitRepo = splitRepo
+	return uc
+}
+
+// WithTransactor stores a payment and the records issued for it in one
+// transaction, so a failed insert does not leave a payment behind that an
+// idempotent retry would replay without them.
+func (uc *CreatePaymentUsecase) WithTransactor(
+	transactor ports.Transactor,
+) *CreatePaymentUsecase {
+	uc.transactor = transactor
+	return uc
+}
+
 func isValidPaymentInput(input CreatePaymentInput) (bool, error) {
+	if input.PayerID <= 0 {
+		return false, &domain.InvalidPaymentError{Reason: "payer id is required"}
+	}
 	if input.Amount <= 0 {
-		return false, errors.New("amount must be greater than zero")
+		return false, &domain.InvalidPaymentError{Reason: "amount must be greater than zero"}
 	}
 	if input.Currency == "" {
-		return false, errors.New("currency is required")
+		return false, &domain.InvalidPaymentError{Reason: "currency is required"}
 	}
-	if input.Method == "" {
-		return false, errors.New("payment method is required")
+	if input.Method == "" && input.PaymentMethodToken == "" {
+		return false, &domain.InvalidPaymentError{Reason: "payment method is required"}
 	}
 	if input.Provider == "" {
-		return false, errors.New("payment provider is required")
+		return false, &domain.InvalidPaymentError{Reason: "payment provider is required"}
 	}
 	if input.IdempotencyKey == "" {
-		return false, errors.New("idempotency key is required")
+		return false, &domain.InvalidPaymentError{Reason: "idempotency key is required"}
+	}
+	if input.Method == domain.PaymentMethodBankTransfer && input.BankCode == "" {
+		return false, &domain.InvalidPaymentError{Reason: "bank code is required for bank transfers"}
+	}
+	if !domain.ValidReturnURL(input.ReturnURL) {
+		return false, domain.ErrInvalidReturnURL
 	}
 	return true, nil
 }
 
+// resolvePaymentMethod loads the saved instrument referenced by token and
+// checks that the payer may charge it.
+func (uc *CreatePaymentUsecase) resolvePaymentMethod(
+	ctx context.Context,
+	payerID int,
+	token string,
+	method string,
+) (*domain.PaymentMethod, error) {
+	pm, err := uc.paymentMethodRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if pm.PayerID != payerID {
+		return nil, domain.ErrPaymentMethodNotOwned
+	}
+	if !pm.IsUsable() {
+		return nil, domain.ErrPaymentMethodInactive
+	}
+	if domain.CardExpired(pm.ExpMonth, pm.ExpYear, time.Now()) {
+		return nil, domain.ErrCardExpired
+	}
+	if method != "" && method != pm.Type {
+		return nil, &domain.InvalidPaymentError{Reason: "method does not match the saved payment method"}
+	}
+	return pm, nil
+}
+
 func isUniqueConstraintError(err error) bool {
 	return strings.Contains(err.Error(), "UNIQUE constraint failed")
 }
@@ -80,12 +303,49 @@ func (uc *CreatePaymentUsecase) Execute(
 		return nil, err
 	}
 
-	if err := uc.paymentProvider.Process(ctx, input.Method); err != nil {
+	// --- allocate splits ---
+	var splits []domain.PaymentSplit
+	if len(input.Splits) > 0 {
+		var err error
+		splits, err = uc.allocateSplits(input)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	// --- payer must exist and be allowed to pay ---
+	payer, err := uc.payerRepo.FindByID(ctx, input.PayerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if !payer.CanPay() {
+		err := domain.ErrPayerNotActive
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
+	// --- resolve saved instrument ---
+	var pm *domain.PaymentMethod
+	if input.PaymentMethodToken != "" {
+		pm, err = uc.resolvePaymentMethod(
+			ctx,
+			payer.ID,
+			input.PaymentMethodToken,
+			input.Method,
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		input.Method = pm.Type
+	}
+
 	// --- create domain object ---
 	now := time.Now()
 
@@ -101,32 +361,631 @@ func (uc *CreatePaymentUsecase) Execute(
 		Status:         domain.PaymentStatusPending,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+
+		PaymentMethodToken: input.PaymentMethodToken,
+		BankCode:           strings.ToUpper(strings.TrimSpace(input.BankCode)),
+		Wallet:             strings.ToUpper(strings.TrimSpace(input.Wallet)),
+		Channel:            input.Channel,
+		ReturnURL:          input.ReturnURL,
+		OffSession:         input.OffSession,
+	}
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		payment.CreatedBy = principal.Subject
+	}
+	for i := range splits {
+		splits[i].PaymentID = payment.PublicID
+	}
+	payment.Splits = splits
+
+	// --- enforce spending limits ---
+	if uc.spendingCounter != nil {
+		if err := uc.recordSpending(ctx, payment); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	// --- score the attempt before anything reaches the provider ---
+	if uc.riskHistory != nil {
+		if err := uc.assessRisk(ctx, payment, payer, pm, input.ClientIP); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	// --- hold for manual review ---
+	var holdReason string
+	if uc.reviewRepo != nil {
+		holdReason, err = uc.holdForReview(ctx, payment)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	// --- start the payment with the provider ---
+	// held for review or blocked by the risk engine otherwise
+	started := &startedPayment{}
+	if payment.Status == domain.PaymentStatusPending {
+		started, err = uc.start(ctx, payment, pm, now)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
 	}
 
 	// --- persist ---
-	var paymentOutput CreatePaymentOutput
-	err := uc.paymentRepo.Create(ctx, payment)
+	err = uc.persist(ctx, func(ctx context.Context) error {
+		if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+			return err
+		}
+		if len(payment.Splits) > 0 {
+			if err := uc.splitRepo.Create(ctx, payment.Splits); err != nil {
+				return err
+			}
+		}
+		if err := uc.createRecords(ctx, started); err != nil {
+			return err
+		}
+		if holdReason == "" {
+			return nil
+		}
+		return uc.reviewRepo.Create(ctx, &domain.PaymentReview{
+			PaymentID: payment.PublicID,
+			Action:    domain.ReviewActionHeld,
+			Reason:    holdReason,
+			Reviewer:  domain.SystemReviewer,
+		})
+	})
 	if err != nil {
 		// --- handle idempotency key conflict ---
 		if isUniqueConstraintError(err) {
-			existingPayment, findErr := uc.paymentRepo.FindByIdempotencyKey(
-				ctx,
-				input.IdempotencyKey,
-			)
-			if findErr != nil {
-				return nil, findErr
+			return uc.existingOutput(ctx, input.IdempotencyKey)
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if payment.RiskDecision == domain.RiskDecisionBlock {
+		err := domain.ErrPaymentBlocked
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	// --- return lightweight response ---
+	output := &CreatePaymentOutput{
+		PaymentID:      payment.PublicID,
+		Status:         payment.Status,
+		ExpiresAt:      payment.ExpiresAt,
+		VirtualAccount: started.va,
+	}
+	if started.auth != nil {
+		output.NextAction = &started.auth.NextAction
+	}
+	if started.qr != nil {
+		output.QRCode = started.qr
+		output.NextAction = qrNextAction(started.qr)
+	}
+	if started.threeDS != nil && payment.Status == domain.PaymentStatusRequiresAction {
+		output.NextAction = challengeNextAction(started.threeDS)
+	}
+	return output, nil
+}
+
+// Resume starts a payment a reviewer approved from the from status, the
+// way Execute starts a payment that is not held, and stores it together
+// with what the provider issued for it. When the provider or the payer's
+// instrument refuses it the payment is stored FAILED and the refusal is
+// returned; any other error leaves the stored payment as it was.
+func (uc *CreatePaymentUsecase) Resume(
+	ctx context.Context,
+	payment *domain.Payment,
+	from domain.PaymentStatus,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "CreatePaymentUseCase.Resume")
+	defer span.End()
+
+	err := uc.resume(ctx, payment, from)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (uc *CreatePaymentUsecase) resume(
+	ctx context.Context,
+	payment *domain.Payment,
+	from domain.PaymentStatus,
+) error {
+	now := time.Now()
+	if err := payment.TransitionTo(domain.PaymentStatusPending, now); err != nil {
+		return err
+	}
+	// the review deadline no longer applies
+	payment.ExpiresAt = nil
+
+	started, err := uc.restart(ctx, payment, now)
+	if err != nil {
+		if !chargeRefused(err) {
+			return err
+		}
+		if err := payment.TransitionTo(domain.PaymentStatusFailed, now); err != nil {
+			return err
+		}
+		return errors.Join(err, uc.paymentRepo.UpdateStatus(ctx, payment, from))
+	}
+
+	return uc.persist(ctx, func(ctx context.Context) error {
+		if err := uc.paymentRepo.UpdateStatus(ctx, payment, from); err != nil {
+			return err
+		}
+		return uc.createRecords(ctx, started)
+	})
+}
+
+// restart starts a stored payment, checking again that its saved
+// instrument can still be charged.
+func (uc *CreatePaymentUsecase) restart(
+	ctx context.Context,
+	payment *domain.Payment,
+	now time.Time,
+) (*startedPayment, error) {
+	var pm *domain.PaymentMethod
+	if payment.PaymentMethodToken != "" {
+		var err error
+		pm, err = uc.resolvePaymentMethod(
+			ctx,
+			payment.PayerID,
+			payment.PaymentMethodToken,
+			payment.Method,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uc.start(ctx, payment, pm, now)
+}
+
+// startedPayment is what the provider issued when a payment was started.
+// Only the record of the payment's method is set.
+type startedPayment struct {
+	va      *domain.VirtualAccount
+	auth    *domain.EWalletAuthorization
+	qr      *domain.QRCode
+	threeDS *domain.ThreeDSAuthentication
+}
+
+// start sends a PENDING payment to the provider the way its method needs:
+// bank transfers get a virtual account, ewallet payments an approval
+// request and qr payments their code, while cards are authenticated and
+// charged. pm is the saved instrument, if any.
+func (uc *CreatePaymentUsecase) start(
+	ctx context.Context,
+	payment *domain.Payment,
+	pm *domain.PaymentMethod,
+	now time.Time,
+) (*startedPayment, error) {
+	started := &startedPayment{}
+	var err error
+	switch payment.Method {
+	case domain.PaymentMethodBankTransfer:
+		started.va, err = uc.issueVirtualAccount(ctx, payment, now)
+	case domain.PaymentMethodEWallet:
+		started.auth, err = uc.authorizeEWallet(ctx, payment, now)
+	case domain.PaymentMethodQR:
+		started.qr, err = uc.issueQR(ctx, payment)
+	default:
+		if pm != nil && uc.authenticator != nil && !payment.OffSession {
+			started.threeDS, err = uc.authenticateCard(ctx, payment, pm, now)
+		}
+		// a challenged payment is processed once the payer completes it
+		if err == nil && payment.Status == domain.PaymentStatusPending {
+			err = uc.charge(ctx, payment.Method)
+		}
+		if err == nil && payment.OffSession {
+			err = payment.TransitionTo(domain.PaymentStatusProcessing, now)
+			if err == nil {
+				err = payment.TransitionTo(domain.PaymentStatusSuccess, now)
 			}
-			paymentOutput = CreatePaymentOutput{
-				PaymentID: existingPayment.PublicID,
-				Status:    existingPayment.Status,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+// charge has the provider process the payment. Anything but the provider
+// being out of reach is the charge being declined.
+func (uc *CreatePaymentUsecase) charge(ctx context.Context, method string) error {
+	err := uc.paymentProvider.Process(ctx, method)
+	if err == nil ||
+		errors.Is(err, domain.ErrProviderUnavailable) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domain.ErrPaymentDeclined, err)
+}
+
+// chargeRefused reports whether err is the payment being turned down, as
+// opposed to the charge not getting through.
+func chargeRefused(err error) bool {
+	for _, refusal := range []error{
+		domain.ErrPaymentDeclined,
+		domain.ErrPaymentBlocked,
+		domain.ErrCardExpired,
+		domain.ErrCardAuthenticationFailed,
+		domain.ErrPaymentMethodNotFound,
+		domain.ErrPaymentMethodInactive,
+		domain.ErrPaymentMethodNotOwned,
+		domain.ErrPayerNotFound,
+		domain.ErrPayerNotActive,
+		domain.ErrLimitExceeded,
+		domain.ErrInvalidPayment,
+		domain.ErrUnsupportedBank,
+		domain.ErrUnsupportedWallet,
+		domain.ErrUnsupportedCurrency,
+	} {
+		if errors.Is(err, refusal) {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *CreatePaymentUsecase) allocateSplits(
+	input CreatePaymentInput,
+) ([]domain.PaymentSplit, error) {
+	if uc.splitRepo == nil {
+		return nil, errors.New("split payments are not enabled")
+	}
+	return domain.AllocateSplits(input.Amount, input.Splits)
+}
+
+// recordSpending counts the payment against the limits that apply to it.
+// Attempts count whether or not the provider accepts them, which is what
+// stops card testing.
+func (uc *CreatePaymentUsecase) recordSpending(
+	ctx context.Context,
+	payment *domain.Payment,
+) error {
+	var limits []domain.SpendingLimit
+	for _, limit := range uc.spendingLimits {
+		if limit.Applies(payment.Method, payment.Currency) {
+			limits = append(limits, limit)
+		}
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	err := uc.spendingCounter.Record(ctx, domain.SpendingAttempt{
+		PayerID:        payment.PayerID,
+		Method:         payment.Method,
+		Currency:       payment.Currency,
+		Amount:         payment.Amount,
+		IdempotencyKey: payment.IdempotencyKey,
+		At:             payment.CreatedAt,
+	}, limits)
+
+	var limitErr *domain.LimitExceededError
+	if errors.As(err, &limitErr) {
+		observability.LimitRejections.WithLabelValues(limitErr.Limit).Inc()
+	}
+	return err
+}
+
+// assessRisk scores the payment and records the outcome on it. A payment
+// sent to review waits in REVIEW; a blocked one is stored as FAILED.
+func (uc *CreatePaymentUsecase) assessRisk(
+	ctx context.Context,
+	payment *domain.Payment,
+	payer *domain.Payer,
+	pm *domain.PaymentMethod,
+	clientIP string,
+) error {
+	signals := domain.RiskSignals{
+		PayerID:      payer.ID,
+		PayerCountry: payer.Country,
+		Method:       payment.Method,
+		Amount:       payment.Amount,
+		Currency:     payment.Currency,
+		IP:           clientIP,
+	}
+	if pm != nil {
+		signals.BIN = pm.BIN
+	}
+
+	var err error
+	if rule := uc.riskPolicy.PayerVelocity; rule.Max > 0 {
+		since := payment.CreatedAt.Add(-rule.Window)
+		signals.PayerPayments, err = uc.riskHistory.CountPayerPayments(ctx, payer.ID, since)
+		if err != nil {
+			return err
+		}
+	}
+	if rule := uc.riskPolicy.OrderVelocity; rule.Max > 0 {
+		since := payment.CreatedAt.Add(-rule.Window)
+		signals.OrderPayments, err = uc.riskHistory.CountOrderPayments(ctx, payment.OrderID, since)
+		if err != nil {
+			return err
+		}
+	}
+
+	assessment := uc.riskPolicy.Evaluate(signals)
+	payment.RiskScore = assessment.Score
+	payment.RiskDecision = assessment.Decision
+	payment.RiskRules = assessment.Rules
+	observability.RiskDecisions.WithLabelValues(string(assessment.Decision)).Inc()
+
+	switch assessment.Decision {
+	case domain.RiskDecisionReview:
+		payment.Status = domain.PaymentStatusReview
+	case domain.RiskDecisionBlock:
+		payment.Status = domain.PaymentStatusFailed
+	}
+	return nil
+}
+
+// holdForReview puts the payment ON_HOLD when it matches the hold policy
+// and gives held payments their review deadline. It returns why the payment
+// is waiting, or nothing when it is not.
+func (uc *CreatePaymentUsecase) holdForReview(
+	ctx context.Context,
+	payment *domain.Payment,
+) (string, error) {
+	var reason string
+	switch payment.Status {
+	case domain.PaymentStatusPending:
+		previous := 0
+		if uc.holdPolicy.FirstTimePayers {
+			var err error
+			previous, err = uc.holdHistory.CountPayerPayments(ctx, payment.PayerID, time.Time{})
+			if err != nil {
+				return "", err
 			}
-			return &paymentOutput, nil
 		}
+		reasons := uc.holdPolicy.HoldReasons(payment, previous)
+		if len(reasons) == 0 {
+			return "", nil
+		}
+		payment.Status = domain.PaymentStatusOnHold
+		reason = strings.Join(reasons, ",")
+	case domain.PaymentStatusReview:
+		reason = "risk: " + strings.Join(payment.RiskRules, ",")
+	default:
+		return "", nil
 	}
 
-	// --- return lightweight response ---
-	return &CreatePaymentOutput{
-		PaymentID: payment.PublicID,
-		Status:    payment.Status,
+	if uc.reviewSLA > 0 {
+		deadline := payment.CreatedAt.Add(uc.reviewSLA)
+		payment.ExpiresAt = &deadline
+	}
+	return reason, nil
+}
+
+// issueVirtualAccount gets the account the payer transfers into. The
+// payment expires when the account does.
+func (uc *CreatePaymentUsecase) issueVirtualAccount(
+	ctx context.Context,
+	payment *domain.Payment,
+	now time.Time,
+) (*domain.VirtualAccount, error) {
+	if uc.vaProvider == nil {
+		return nil, errors.New("bank transfers are not enabled")
+	}
+
+	number, err := uc.vaProvider.IssueVirtualAccount(ctx, payment.BankCode, payment.PublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(uc.vaTTL)
+	payment.ExpiresAt = &expiresAt
+
+	return &domain.VirtualAccount{
+		PaymentID:      payment.PublicID,
+		BankCode:       payment.BankCode,
+		AccountNumber:  number,
+		ExpectedAmount: payment.Amount,
+		Status:         domain.VirtualAccountStatusOpen,
+		ExpiresAt:      expiresAt,
 	}, nil
 }
+
+// authorizeEWallet sends the approval request to the payer's wallet. The
+// payment stays PENDING until the wallet calls back.
+func (uc *CreatePaymentUsecase) authorizeEWallet(
+	ctx context.Context,
+	payment *domain.Payment,
+	now time.Time,
+) (*domain.EWalletAuthorization, error) {
+	if uc.walletProvider == nil {
+		return nil, errors.New("e-wallet payments are not enabled")
+	}
+
+	auth := &domain.EWalletAuthorization{
+		PaymentID: payment.PublicID,
+		Wallet:    payment.Wallet,
+		Channel:   payment.Channel,
+		ReturnURL: payment.ReturnURL,
+	}
+	if auth.Channel == "" {
+		auth.Channel = domain.EWalletChannelWeb
+	}
+	if err := uc.walletProvider.AuthorizeEWallet(ctx, payment, auth); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(uc.walletTTL)
+	payment.ExpiresAt = &expiresAt
+
+	return auth, nil
+}
+
+// issueQR builds the dynamic QR for payment. The payment expires with the
+// code.
+func (uc *CreatePaymentUsecase) issueQR(
+	ctx context.Context,
+	payment *domain.Payment,
+) (*domain.QRCode, error) {
+	if uc.qrProvider == nil {
+		return nil, errors.New("qr payments are not enabled")
+	}
+
+	qr, err := uc.qrProvider.IssueQR(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	payment.ExpiresAt = &qr.ExpiresAt
+
+	return qr, nil
+}
+
+func qrNextAction(qr *domain.QRCode) *domain.NextAction {
+	return &domain.NextAction{
+		Type:     domain.NextActionQR,
+		QRString: qr.Payload,
+	}
+}
+
+// authenticateCard runs 3-D Secure for a saved card. When the issuer asks
+// for a challenge the payment moves to REQUIRES_ACTION and expires if the
+// payer does not complete it in time.
+func (uc *CreatePaymentUsecase) authenticateCard(
+	ctx context.Context,
+	payment *domain.Payment,
+	pm *domain.PaymentMethod,
+	now time.Time,
+) (*domain.ThreeDSAuthentication, error) {
+	auth, err := uc.authenticator.Authenticate(ctx, payment, pm)
+	if err != nil {
+		return nil, err
+	}
+	auth.PaymentID = payment.PublicID
+	auth.ReturnURL = payment.ReturnURL
+
+	switch auth.Status {
+	case domain.ThreeDSStatusChallengeRequired:
+		if err := payment.TransitionTo(domain.PaymentStatusRequiresAction, now); err != nil {
+			return nil, err
+		}
+		payment.ThreeDSStatus = auth.Status
+		expiresAt := now.Add(uc.challengeTTL)
+		payment.ExpiresAt = &expiresAt
+	case domain.ThreeDSStatusFailed:
+		return nil, domain.ErrCardAuthenticationFailed
+	default:
+		payment.ApplyThreeDS(auth.Status)
+	}
+
+	return auth, nil
+}
+
+func challengeNextAction(auth *domain.ThreeDSAuthentication) *domain.NextAction {
+	return &domain.NextAction{
+		Type:        domain.NextActionRedirect,
+		RedirectURL: auth.ChallengeURL,
+	}
+}
+
+// persist runs store in a transaction when the usecase has a transactor.
+func (uc *CreatePaymentUsecase) persist(
+	ctx context.Context,
+	store func(ctx context.Context) error,
+) error {
+	if uc.transactor == nil {
+		return store(ctx)
+	}
+	return uc.transactor.WithinTx(ctx, store)
+}
+
+// createRecords stores what the provider issued for the payment, stopping at
+// the first failure.
+func (uc *CreatePaymentUsecase) createRecords(
+	ctx context.Context,
+	started *startedPayment,
+) error {
+	if started.va != nil {
+		if err := uc.vaRepo.Create(ctx, started.va); err != nil {
+			return err
+		}
+	}
+	if started.auth != nil {
+		if err := uc.walletRepo.Create(ctx, started.auth); err != nil {
+			return err
+		}
+	}
+	if started.qr != nil {
+		if err := uc.qrRepo.Create(ctx, started.qr); err != nil {
+			return err
+		}
+	}
+	if started.threeDS != nil {
+		return uc.threeDSRepo.Create(ctx, started.threeDS)
+	}
+	return nil
+}
+
+// existingOutput replays the response of the payment already stored under
+// idempotencyKey, including whatever the payer still has to act on.
+func (uc *CreatePaymentUsecase) existingOutput(
+	ctx context.Context,
+	idempotencyKey string,
+) (*CreatePaymentOutput, error) {
+	existingPayment, err := uc.paymentRepo.FindByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if existingPayment.RiskDecision == domain.RiskDecisionBlock {
+		return nil, domain.ErrPaymentBlocked
+	}
+	output := &CreatePaymentOutput{
+		PaymentID: existingPayment.PublicID,
+		Status:    existingPayment.Status,
+		ExpiresAt: existingPayment.ExpiresAt,
+		Replayed:  true,
+	}
+	if existingPayment.Method == domain.PaymentMethodBankTransfer && uc.vaRepo != nil {
+		va, err := uc.vaRepo.FindByPaymentID(ctx, existingPayment.PublicID)
+		if err != nil && !errors.Is(err, domain.ErrVirtualAccountNotFound) {
+			return nil, err
+		}
+		output.VirtualAccount = va
+	}
+	if existingPayment.Method == domain.PaymentMethodEWallet && uc.walletRepo != nil {
+		auth, err := uc.walletRepo.FindByPaymentID(ctx, existingPayment.PublicID)
+		if err != nil && !errors.Is(err, domain.ErrEWalletAuthorizationNotFound) {
+			return nil, err
+		}
+		if auth != nil && !existingPayment.Status.IsFinal() {
+			output.NextAction = &auth.NextAction
+		}
+	}
+	if existingPayment.Method == domain.PaymentMethodQR && uc.qrRepo != nil {
+		qr, err := uc.qrRepo.FindByPaymentID(ctx, existingPayment.PublicID)
+		if err != nil && !errors.Is(err, domain.ErrQRCodeNotFound) {
+			return nil, err
+		}
+		if qr != nil {
+			output.QRCode = qr
+			output.NextAction = qrNextAction(qr)
+		}
+	}
+	if existingPayment.Status == domain.PaymentStatusRequiresAction && uc.threeDSRepo != nil {
+		auth, err := uc.threeDSRepo.FindByPaymentID(ctx, existingPayment.PublicID)
+		if err != nil {
+			return nil, err
+		}
+		output.NextAction = challengeNextAction(auth)
+	}
+	return output, nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CreatePaymentBatchInput struct {
+	Items []CreatePaymentInput
+	// ClientIP is the address the batch is submitted from.
+	ClientIP string
+}
+
+// CreatePaymentBatchUsecase accepts a batch of payments. They are created
+// in the background by ProcessPaymentBatchesUsecase.
+type CreatePaymentBatchUsecase struct {
+	batchRepo ports.PaymentBatchRepository
+	maxItems  int
+}
+
+func NewCreatePaymentBatchUsecase(
+	batchRepo ports.PaymentBatchRepository,
+	maxItems int,
+) *CreatePaymentBatchUsecase {
+	return &CreatePaymentBatchUsecase{
+		batchRepo: batchRepo,
+		maxItems:  maxItems,
+	}
+}
+
+// Execute stores the batch with every item pending. Items are checked
+// one by one when they are processed, so a bad item fails on its own
+// instead of rejecting the whole batch.
+func (uc *CreatePaymentBatchUsecase) Execute(
+	ctx context.Context,
+	input CreatePaymentBatchInput,
+) (*domain.PaymentBatch, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreatePaymentBatchUseCase.Execute")
+	defer span.End()
+
+	items := input.Items
+
+	span.SetAttributes(attribute.Int("batch.items", len(items)))
+
+	if err := uc.validate(items); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	batch := newPaymentBatch(ctx, domain.PaymentBatchSourceAPI, input.ClientIP)
+	for i, input := range items {
+		batch.Items = append(batch.Items, newPaymentBatchItem(i, input))
+	}
+
+	if err := uc.batchRepo.Create(ctx, batch); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return batch, nil
+}
+
+// validate rejects batches that cannot be processed at all. Two items with
+// the same idempotency key would make the second a replay of the first,
+// which is almost certainly a mistake by the caller.
+func (uc *CreatePaymentBatchUsecase) validate(items []CreatePaymentInput) error {
+	if len(items) == 0 {
+		return domain.ErrEmptyPaymentBatch
+	}
+	if uc.maxItems > 0 && len(items) > uc.maxItems {
+		return domain.ErrPaymentBatchTooLarge
+	}
+
+	keys := make(map[string]struct{}, len(items))
+	for _, input := range items {
+		if input.IdempotencyKey == "" {
+			continue
+		}
+		if _, ok := keys[input.IdempotencyKey]; ok {
+			return domain.ErrDuplicateBatchItemKeys
+		}
+		keys[input.IdempotencyKey] = struct{}{}
+	}
+	return nil
+}
+
+// newPaymentBatch starts a batch submitted by the caller in ctx from
+// clientIP.
+func newPaymentBatch(
+	ctx context.Context,
+	source domain.PaymentBatchSource,
+	clientIP string,
+) *domain.PaymentBatch {
+	batch := &domain.PaymentBatch{
+		PublicID: "batch_" + uuid.NewString(),
+		Source:   source,
+		ClientIP: clientIP,
+		Status:   domain.PaymentBatchStatusProcessing,
+	}
+	if tenant, ok := domain.TenantFromContext(ctx); ok {
+		batch.Mode = tenant.Mode
+	}
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		batch.CreatedBy = principal.Subject
+	}
+	return batch
+}
+
+func newPaymentBatchItem(position int, input CreatePaymentInput) domain.PaymentBatchItem {
+	return domain.PaymentBatchItem{
+		Position:           position,
+		OrderID:            input.OrderID,
+		PayerID:            input.PayerID,
+		Amount:             input.Amount,
+		Currency:           input.Currency,
+		Provider:           input.Provider,
+		Method:             input.Method,
+		PaymentMethodToken: input.PaymentMethodToken,
+		BankCode:           input.BankCode,
+		Wallet:             input.Wallet,
+		Channel:            input.Channel,
+		ReturnURL:          input.ReturnURL,
+		Splits:             input.Splits,
+		IdempotencyKey:     input.IdempotencyKey,
+		Status:             domain.PaymentBatchItemPending,
+	}
+}
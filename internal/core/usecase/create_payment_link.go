@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CreatePaymentLinkInput struct {
+	Amount      int
+	Currency    string
+	Description string
+	SingleUse   bool
+	ExpiresAt   *time.Time
+}
+
+type CreatePaymentLinkUsecase struct {
+	paymentLinkRepo ports.PaymentLinkRepository
+	now             func() time.Time
+}
+
+func NewCreatePaymentLinkUsecase(
+	paymentLinkRepo ports.PaymentLinkRepository,
+) *CreatePaymentLinkUsecase {
+	return &CreatePaymentLinkUsecase{
+		paymentLinkRepo: paymentLinkRepo,
+		now:             time.Now,
+	}
+}
+
+func (uc *CreatePaymentLinkUsecase) validate(input CreatePaymentLinkInput) error {
+	if input.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	if input.Currency == "" {
+		return errors.New("currency is required")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(uc.now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+func (uc *CreatePaymentLinkUsecase) Execute(
+	ctx context.Context,
+	input CreatePaymentLinkInput,
+) (*domain.PaymentLink, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreatePaymentLinkUseCase.Execute")
+	defer span.End()
+
+	input.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	input.Description = strings.TrimSpace(input.Description)
+
+	if err := uc.validate(input); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	link := &domain.PaymentLink{
+		PublicID:    "plink_" + uuid.NewString(),
+		Amount:      input.Amount,
+		Currency:    input.Currency,
+		Description: input.Description,
+		SingleUse:   input.SingleUse,
+		Status:      domain.PaymentLinkStatusActive,
+		ExpiresAt:   input.ExpiresAt,
+	}
+
+	if err := uc.paymentLinkRepo.Create(ctx, link); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return link, nil
+}
@@ -45,6 +45,86 @@ func (m *mockPaymentRepo) FindbyPublicID(ctx context.Context, publicID string) (
     return nil, errors.New("not implemented")
 }
 
+func (m *mockPaymentRepo) ListByPayerID(ctx context.Context, payerID int, limit, offset int) ([]*domain.Payment, error) {
+    return nil, errors.New("not implemented")
+}
+
+func (m *mockPaymentRepo) UpdateStatus(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus) error {
+    return errors.New("not implemented")
+}
+
+func (m *mockPaymentRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Payment, error) {
+    return nil, errors.New("not implemented")
+}
+
+func (m *mockPaymentRepo) ListAwaitingReview(ctx context.Context, limit, offset int) ([]*domain.Payment, error) {
+    return nil, errors.New("not implemented")
+}
+
+// mockPayerRepo implements ports.PayerRepository
+type mockPayerRepo struct {
+    payers    map[int]*domain.Payer
+    createErr error
+    updated   *domain.Payer
+}
+
+func newMockPayerRepo(payers ...*domain.Payer) *mockPayerRepo {
+    m := &mockPayerRepo{payers: map[int]*domain.Payer{}}
+    for _, p := range payers {
+        m.payers[p.ID] = p
+    }
+    return m
+}
+
+// activePayers returns a repo where every id used by the tests below is an active payer
+func activePayers() *mockPayerRepo {
+    return newMockPayerRepo(
+        &domain.Payer{ID: 1, Status: domain.PayerStatusActive},
+        &domain.Payer{ID: 2, Status: domain.PayerStatusActive},
+        &domain.Payer{ID: 5, Status: domain.PayerStatusActive},
+        &domain.Payer{ID: 42, Status: domain.PayerStatusActive},
+    )
+}
+
+func (m *mockPayerRepo) Create(ctx context.Context, payer *domain.Payer) error {
+    if m.createErr != nil {
+        return m.createErr
+    }
+    payer.ID = len(m.payers) + 1
+    m.payers[payer.ID] = payer
+    return nil
+}
+
+func (m *mockPayerRepo) Update(ctx context.Context, payer *domain.Payer) error {
+    p := *payer
+    m.updated = &p
+    m.payers[payer.ID] = &p
+    return nil
+}
+
+func (m *mockPayerRepo) FindByID(ctx context.Context, id int) (*domain.Payer, error) {
+    p, ok := m.payers[id]
+    if !ok {
+        return nil, domain.ErrPayerNotFound
+    }
+    cp := *p
+    return &cp, nil
+}
+
+func (m *mockPayerRepo) FindByEmail(ctx context.Context, email string) (*domain.Payer, error) {
+    for _, p := range m.payers {
+        if p.Email == email {
+            cp := *p
+            return &cp, nil
+        }
+    }
+    return nil, domain.ErrPayerNotFound
+}
+
+func (m *mockPayerRepo) List(ctx context.Context, limit, offset int) ([]*domain.Payer, error) {
+    return nil, errors.New("not implemented")
+}
+
 func TestExecute_Success(t *testing.T) {
     observability.InitTracer("test")
 
@@ -53,7 +133,7 @@ func TestExecute_Success(t *testing.T) {
     repo := &mockPaymentRepo{}
     provider := &mockPaymentProvider{}
 
-    uc := NewCreatePaymentUsecase(repo, provider)
+    uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(), provider)
 
     input := CreatePaymentInput{
         OrderID:        "order_123",
@@ -95,7 +175,7 @@ func TestExecute_InvalidInput(t *testing.T) {
     repo := &mockPaymentRepo{}
     provider := &mockPaymentProvider{}
 
-    uc := NewCreatePaymentUsecase(repo, provider)
+    uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(), provider)
 
     input := CreatePaymentInput{
         OrderID:        "",
@@ -121,7 +201,7 @@ func TestExecute_ProviderError(t *testing.T) {
     repo := &mockPaymentRepo{}
     provider := &mockPaymentProvider{err: errors.New("provider failed")}
 
-    uc := NewCreatePaymentUsecase(repo, provider)
+    uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(), provider)
 
     input := CreatePaymentInput{
         OrderID:        "order_1",
@@ -137,6 +217,17 @@ func TestExecute_ProviderError(t *testing.T) {
     if err == nil {
         t.Fatalf("expected provider error, got nil and output %v", out)
     }
+    if !errors.Is(err, domain.ErrPaymentDeclined) {
+        t.Fatalf("expected the charge to be declined, got %v", err)
+    }
+
+    // an unreachable provider did not decline anything
+    provider.err = domain.ErrProviderUnavailable
+    input.IdempotencyKey = "idem-3"
+    _, err = uc.Execute(ctx, input)
+    if !errors.Is(err, domain.ErrProviderUnavailable) || errors.Is(err, domain.ErrPaymentDeclined) {
+        t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+    }
 }
 
 func TestExecute_IdempotencyConflict(t *testing.T) {
@@ -156,7 +247,7 @@ func TestExecute_IdempotencyConflict(t *testing.T) {
     }
     provider := &mockPaymentProvider{}
 
-    uc := NewCreatePaymentUsecase(repo, provider)
+    uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(), provider)
 
     input := CreatePaymentInput{
         OrderID:        "order_x",
@@ -191,7 +282,7 @@ func TestCreatePayment_TimestampsSet(t *testing.T) {
     repo := &mockPaymentRepo{}
     provider := &mockPaymentProvider{}
 
-    uc := NewCreatePaymentUsecase(repo, provider)
+    uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(), provider)
 
     input := CreatePaymentInput{
         OrderID:        "o",
@@ -218,3 +309,90 @@ func TestCreatePayment_TimestampsSet(t *testing.T) {
         t.Fatalf("UpdatedAt not set")
     }
 }
+
+func TestCreatePayment_RecordsSubject(t *testing.T) {
+    observability.InitTracer("test")
+
+    ctx := domain.WithPrincipal(context.Background(), domain.Principal{
+        Subject: "svc-checkout",
+        Scopes:  []string{domain.ScopePaymentsWrite},
+    })
+    repo := &mockPaymentRepo{}
+
+    uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{})
+
+    _, err := uc.Execute(ctx, CreatePaymentInput{
+        OrderID:        "o",
+        PayerID:        2,
+        Amount:         1,
+        Currency:       "IDR",
+        Provider:       "FAKE",
+        Method:         "QR",
+        IdempotencyKey: "idem-subject",
+    })
+    if err != nil {
+        t.Fatalf("unexpected err: %v", err)
+    }
+    if repo.createdPayment.CreatedBy != "svc-checkout" {
+        t.Fatalf("expected created_by svc-checkout, got %q", repo.createdPayment.CreatedBy)
+    }
+}
+
+func TestExecute_UnknownPayer(t *testing.T) {
+    observability.InitTracer("test")
+
+    ctx := context.Background()
+
+    repo := &mockPaymentRepo{}
+    provider := &mockPaymentProvider{}
+
+    uc := NewCreatePaymentUsecase(repo, newMockPayerRepo(), newMockPaymentMethodRepo(), provider)
+
+    input := CreatePaymentInput{
+        OrderID:        "order_1",
+        PayerID:        99,
+        Amount:         10,
+        Currency:       "USD",
+        Provider:       "FAKE",
+        Method:         "CARD",
+        IdempotencyKey: "idem-5",
+    }
+
+    _, err := uc.Execute(ctx, input)
+    if !errors.Is(err, domain.ErrPayerNotFound) {
+        t.Fatalf("expected ErrPayerNotFound, got %v", err)
+    }
+    if provider.calledWith != "" {
+        t.Fatalf("provider must not be called for unknown payer")
+    }
+    if repo.createdPayment != nil {
+        t.Fatalf("payment must not be persisted for unknown payer")
+    }
+}
+
+func TestExecute_InactivePayer(t *testing.T) {
+    observability.InitTracer("test")
+
+    ctx := context.Background()
+
+    repo := &mockPaymentRepo{}
+    provider := &mockPaymentProvider{}
+    payers := newMockPayerRepo(&domain.Payer{ID: 7, Status: domain.PayerStatusBlocked})
+
+    uc := NewCreatePaymentUsecase(repo, payers, newMockPaymentMethodRepo(), provider)
+
+    input := CreatePaymentInput{
+        OrderID:        "order_1",
+        PayerID:        7,
+        Amount:         10,
+        Currency:       "USD",
+        Provider:       "FAKE",
+        Method:         "CARD",
+        IdempotencyKey: "idem-6",
+    }
+
+    _, err := uc.Execute(ctx, input)
+    if !errors.Is(err, domain.ErrPayerNotActive) {
+        t.Fatalf("expected ErrPayerNotActive, got %v", err)
+    }
+}
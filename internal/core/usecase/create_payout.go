@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CreatePayoutInput struct {
+	Currency string
+	// Amount defaults to the whole available balance.
+	Amount int
+}
+
+// CreatePayoutUsecase reserves a payout requested by the merchant. It is
+// sent to the payout provider by the payout scheduler.
+type CreatePayoutUsecase struct {
+	payoutRepo   ports.PayoutRepository
+	balanceRepo  ports.BalanceRepository
+	merchantRepo ports.MerchantRepository
+	policy       domain.PayoutPolicy
+	destination  string
+	now          func() time.Time
+}
+
+func NewCreatePayoutUsecase(
+	payoutRepo ports.PayoutRepository,
+	balanceRepo ports.BalanceRepository,
+	merchantRepo ports.MerchantRepository,
+	policy domain.PayoutPolicy,
+	destination string,
+) *CreatePayoutUsecase {
+	return &CreatePayoutUsecase{
+		payoutRepo:   payoutRepo,
+		balanceRepo:  balanceRepo,
+		merchantRepo: merchantRepo,
+		policy:       policy,
+		destination:  destination,
+		now:          time.Now,
+	}
+}
+
+func (uc *CreatePayoutUsecase) Execute(
+	ctx context.Context,
+	input CreatePayoutInput,
+) (*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreatePayoutUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("payout.currency", input.Currency),
+		attribute.Int("payout.amount", input.Amount),
+	)
+
+	payout, err := uc.create(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payout, nil
+}
+
+func (uc *CreatePayoutUsecase) create(
+	ctx context.Context,
+	input CreatePayoutInput,
+) (*domain.Payout, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMerchantRequired
+	}
+	merchant, err := uc.merchantRepo.FindByID(ctx, tenant.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	settledBefore := uc.now().Add(-uc.policy.SettlementDelay)
+
+	amount := input.Amount
+	if amount == 0 {
+		balances, err := uc.balanceRepo.Balances(ctx, settledBefore)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range balances {
+			if b.Currency == input.Currency {
+				amount = b.Available
+			}
+		}
+		if amount <= 0 {
+			return nil, domain.ErrInsufficientBalance
+		}
+	}
+
+	if err := uc.policy.CheckAmount(amount, input.Currency); err != nil {
+		return nil, err
+	}
+
+	destination := payoutDestination(merchant, uc.destination)
+	payout := newPayout(amount, input.Currency, domain.PayoutTriggerManual, destination)
+	if err := uc.payoutRepo.CreateWithinBalance(ctx, payout, settledBefore); err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// payoutDestination is where merchant is paid, falling back to the
+// configured account for merchants that have not set one.
+func payoutDestination(merchant *domain.Merchant, fallback string) string {
+	if merchant.PayoutDestination != "" {
+		return merchant.PayoutDestination
+	}
+	return fallback
+}
+
+func newPayout(
+	amount int,
+	currency string,
+	trigger domain.PayoutTrigger,
+	destination string,
+) *domain.Payout {
+	return &domain.Payout{
+		PublicID:    "po_" + uuid.NewString(),
+		Amount:      amount,
+		Currency:    currency,
+		Status:      domain.PayoutStatusPending,
+		Trigger:     trigger,
+		Destination: destination,
+	}
+}
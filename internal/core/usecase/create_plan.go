@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CreatePlanInput struct {
+	Name          string
+	Amount        int
+	Currency      string
+	Interval      domain.PlanInterval
+	IntervalCount int
+	TrialDays     int
+}
+
+type CreatePlanUsecase struct {
+	planRepo ports.PlanRepository
+}
+
+func NewCreatePlanUsecase(planRepo ports.PlanRepository) *CreatePlanUsecase {
+	return &CreatePlanUsecase{
+		planRepo: planRepo,
+	}
+}
+
+func isValidPlanInput(input CreatePlanInput) (bool, error) {
+	if input.Name == "" {
+		return false, errors.New("name is required")
+	}
+	if input.Amount <= 0 {
+		return false, errors.New("amount must be greater than zero")
+	}
+	if input.Currency == "" {
+		return false, errors.New("currency is required")
+	}
+	if !input.Interval.IsValid() {
+		return false, errors.New("interval must be one of day, week, month, year")
+	}
+	if input.IntervalCount < 0 {
+		return false, errors.New("interval_count must not be negative")
+	}
+	if input.TrialDays < 0 {
+		return false, errors.New("trial_days must not be negative")
+	}
+	return true, nil
+}
+
+func (uc *CreatePlanUsecase) Execute(
+	ctx context.Context,
+	input CreatePlanInput,
+) (*domain.Plan, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreatePlanUseCase.Execute")
+	defer span.End()
+
+	input.Name = strings.TrimSpace(input.Name)
+	input.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+	if input.IntervalCount == 0 {
+		input.IntervalCount = 1
+	}
+
+	if valid, err := isValidPlanInput(input); !valid {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	plan := &domain.Plan{
+		PublicID:      "plan_" + uuid.NewString(),
+		Name:          input.Name,
+		Amount:        input.Amount,
+		Currency:      input.Currency,
+		Interval:      input.Interval,
+		IntervalCount: input.IntervalCount,
+		TrialDays:     input.TrialDays,
+		Active:        true,
+	}
+
+	if err := uc.planRepo.Create(ctx, plan); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type CreateSubscriptionInput struct {
+	PlanID             string
+	PayerID            int
+	PaymentMethodToken string
+}
+
+type CreateSubscriptionUsecase struct {
+	planRepo          ports.PlanRepository
+	subscriptionRepo  ports.SubscriptionRepository
+	payerRepo         ports.PayerRepository
+	paymentMethodRepo ports.PaymentMethodRepository
+	now               func() time.Time
+}
+
+func NewCreateSubscriptionUsecase(
+	planRepo ports.PlanRepository,
+	subscriptionRepo ports.SubscriptionRepository,
+	payerRepo ports.PayerRepository,
+	paymentMethodRepo ports.PaymentMethodRepository,
+) *CreateSubscriptionUsecase {
+	return &CreateSubscriptionUsecase{
+		planRepo:          planRepo,
+		subscriptionRepo:  subscriptionRepo,
+		payerRepo:         payerRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		now:               time.Now,
+	}
+}
+
+func (uc *CreateSubscriptionUsecase) validate(
+	ctx context.Context,
+	input CreateSubscriptionInput,
+) (*domain.Plan, error) {
+	if input.PlanID == "" {
+		return nil, errors.New("plan id is required")
+	}
+	if input.PaymentMethodToken == "" {
+		return nil, errors.New("payment method is required")
+	}
+
+	plan, err := uc.planRepo.FindByPublicID(ctx, input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.Active {
+		return nil, domain.ErrPlanInactive
+	}
+
+	payer, err := uc.payerRepo.FindByID(ctx, input.PayerID)
+	if err != nil {
+		return nil, err
+	}
+	if !payer.CanPay() {
+		return nil, domain.ErrPayerNotActive
+	}
+
+	pm, err := uc.paymentMethodRepo.FindByToken(ctx, input.PaymentMethodToken)
+	if err != nil {
+		return nil, err
+	}
+	if pm.PayerID != payer.ID {
+		return nil, domain.ErrPaymentMethodNotOwned
+	}
+	if !pm.IsUsable() {
+		return nil, domain.ErrPaymentMethodInactive
+	}
+
+	return plan, nil
+}
+
+// Execute starts a subscription. Without a trial the first cycle is due
+// immediately and is charged by the billing scheduler on its next run.
+func (uc *CreateSubscriptionUsecase) Execute(
+	ctx context.Context,
+	input CreateSubscriptionInput,
+) (*domain.Subscription, error) {
+	ctx, span := observability.Tracer().Start(ctx, "CreateSubscriptionUseCase.Execute")
+	defer span.End()
+
+	plan, err := uc.validate(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	now := uc.now()
+	subscription := &domain.Subscription{
+		PublicID:           "sub_" + uuid.NewString(),
+		PlanID:             plan.ID,
+		PayerID:            input.PayerID,
+		PaymentMethodToken: input.PaymentMethodToken,
+		Status:             domain.SubscriptionStatusActive,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now,
+	}
+	if plan.TrialDays > 0 {
+		subscription.Status = domain.SubscriptionStatusTrialing
+		subscription.CurrentPeriodEnd = now.AddDate(0, 0, plan.TrialDays)
+	}
+
+	if err := uc.subscriptionRepo.Create(ctx, subscription); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type DeactivatePaymentLinkUsecase struct {
+	paymentLinkRepo ports.PaymentLinkRepository
+}
+
+func NewDeactivatePaymentLinkUsecase(
+	paymentLinkRepo ports.PaymentLinkRepository,
+) *DeactivatePaymentLinkUsecase {
+	return &DeactivatePaymentLinkUsecase{
+		paymentLinkRepo: paymentLinkRepo,
+	}
+}
+
+func (uc *DeactivatePaymentLinkUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.PaymentLink, error) {
+	ctx, span := observability.Tracer().Start(ctx, "DeactivatePaymentLinkUseCase.Execute")
+	defer span.End()
+
+	err := uc.paymentLinkRepo.UpdateStatus(
+		ctx,
+		publicID,
+		domain.PaymentLinkStatusDeactivated,
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	link, err := uc.paymentLinkRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return link, nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type DecideReviewInput struct {
+	PaymentID string
+	Approve   bool
+	// Reason is optional when approving.
+	Reason   string
+	Reviewer string
+}
+
+// paymentResumer is the part of CreatePaymentUsecase used to start approved
+// payments.
+type paymentResumer interface {
+	Resume(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus) error
+}
+
+// DecideReviewUsecase records a reviewer's decision on a payment waiting in
+// REVIEW or ON_HOLD. Approved payments are started as if they had not been
+// held, rejected ones fail.
+type DecideReviewUsecase struct {
+	paymentRepo ports.PaymentRepository
+	reviewRepo  ports.ReviewRepository
+	payments    paymentResumer
+	now         func() time.Time
+}
+
+func NewDecideReviewUsecase(
+	paymentRepo ports.PaymentRepository,
+	reviewRepo ports.ReviewRepository,
+	payments paymentResumer,
+) *DecideReviewUsecase {
+	return &DecideReviewUsecase{
+		paymentRepo: paymentRepo,
+		reviewRepo:  reviewRepo,
+		payments:    payments,
+		now:         time.Now,
+	}
+}
+
+func (uc *DecideReviewUsecase) Execute(
+	ctx context.Context,
+	input DecideReviewInput,
+) (*ReviewOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "DecideReviewUseCase.Execute")
+	defer span.End()
+
+	out, err := uc.decide(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("payment.id", input.PaymentID),
+		attribute.String("payment.status", string(out.Payment.Status)),
+		attribute.Bool("review.approved", input.Approve),
+	)
+	return out, nil
+}
+
+func (uc *DecideReviewUsecase) decide(
+	ctx context.Context,
+	input DecideReviewInput,
+) (*ReviewOutput, error) {
+	input.Reviewer = strings.TrimSpace(input.Reviewer)
+	input.Reason = strings.TrimSpace(input.Reason)
+	if input.Reviewer == "" {
+		return nil, domain.ErrReviewerRequired
+	}
+	if !input.Approve && input.Reason == "" {
+		return nil, domain.ErrReviewReasonRequired
+	}
+
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, input.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !payment.Status.AwaitingReview() {
+		return nil, domain.ErrPaymentNotInReview
+	}
+
+	// the expiry worker may not have caught up with the SLA yet
+	now := uc.now()
+	if payment.IsExpiredAt(now) {
+		return nil, domain.ErrReviewExpired
+	}
+
+	from := payment.Status
+	review := &domain.PaymentReview{
+		PaymentID: payment.PublicID,
+		Action:    domain.ReviewActionRejected,
+		Reason:    input.Reason,
+		Reviewer:  input.Reviewer,
+	}
+	if input.Approve {
+		review.Action = domain.ReviewActionApproved
+		switch err := uc.payments.Resume(ctx, payment, from); {
+		case err == nil:
+		case payment.Status == domain.PaymentStatusFailed:
+			// the payment was refused once it was started
+			review.Action = domain.ReviewActionApprovalFailed
+			review.Reason = err.Error()
+		default:
+			return nil, err
+		}
+	} else {
+		if err := payment.TransitionTo(domain.PaymentStatusFailed, now); err != nil {
+			return nil, err
+		}
+		if err := uc.paymentRepo.UpdateStatus(ctx, payment, from); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+
+	history, err := uc.reviewRepo.ListByPaymentID(ctx, payment.PublicID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewOutput{Payment: payment, History: history}, nil
+}
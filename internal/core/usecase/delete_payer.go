@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// DeletePayerUsecase deactivates a payer. Rows are never removed so that the
+// payer's payment history keeps pointing at a valid profile.
+type DeletePayerUsecase struct {
+	payerRepo ports.PayerRepository
+}
+
+func NewDeletePayerUsecase(
+	payerRepo ports.PayerRepository,
+) *DeletePayerUsecase {
+	return &DeletePayerUsecase{
+		payerRepo: payerRepo,
+	}
+}
+
+func (uc *DeletePayerUsecase) Execute(
+	ctx context.Context,
+	id int,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "DeletePayerUseCase.Execute")
+	defer span.End()
+
+	payer, err := uc.payerRepo.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	if payer.Status == domain.PayerStatusInactive {
+		return nil
+	}
+
+	payer.Status = domain.PayerStatusInactive
+	if err := uc.payerRepo.Update(ctx, payer); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
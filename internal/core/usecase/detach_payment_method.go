@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// DetachPaymentMethodUsecase makes a saved instrument unusable for new
+// payments. The vault row is kept so past payments can still reference it.
+type DetachPaymentMethodUsecase struct {
+	paymentMethodRepo ports.PaymentMethodRepository
+}
+
+func NewDetachPaymentMethodUsecase(
+	paymentMethodRepo ports.PaymentMethodRepository,
+) *DetachPaymentMethodUsecase {
+	return &DetachPaymentMethodUsecase{
+		paymentMethodRepo: paymentMethodRepo,
+	}
+}
+
+func (uc *DetachPaymentMethodUsecase) Execute(
+	ctx context.Context,
+	token string,
+) error {
+	ctx, span := observability.Tracer().Start(ctx, "DetachPaymentMethodUseCase.Execute")
+	defer span.End()
+
+	err := uc.paymentMethodRepo.UpdateStatus(
+		ctx,
+		token,
+		domain.PaymentMethodStatusDetached,
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
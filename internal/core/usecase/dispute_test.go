@@ -0,0 +1,362 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type mockDisputeRepo struct {
+	disputes []*domain.Dispute
+	evidence []*domain.DisputeEvidence
+}
+
+func (r *mockDisputeRepo) Create(ctx context.Context, d *domain.Dispute) error {
+	d.ID = len(r.disputes) + 1
+	d.CreatedAt = time.Now()
+	d.UpdatedAt = d.CreatedAt
+	cp := *d
+	r.disputes = append(r.disputes, &cp)
+	return nil
+}
+
+func (r *mockDisputeRepo) find(match func(*domain.Dispute) bool) (*domain.Dispute, error) {
+	for _, d := range r.disputes {
+		if match(d) {
+			cp := *d
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrDisputeNotFound
+}
+
+func (r *mockDisputeRepo) FindByPublicID(ctx context.Context, publicID string) (*domain.Dispute, error) {
+	return r.find(func(d *domain.Dispute) bool { return d.PublicID == publicID })
+}
+
+func (r *mockDisputeRepo) FindByReference(ctx context.Context, reference string) (*domain.Dispute, error) {
+	return r.find(func(d *domain.Dispute) bool { return d.Reference == reference })
+}
+
+func (r *mockDisputeRepo) ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.Dispute, error) {
+	var out []*domain.Dispute
+	for _, d := range r.disputes {
+		if d.PaymentID == paymentID {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (r *mockDisputeRepo) UpdateStatus(ctx context.Context, d *domain.Dispute, from domain.DisputeStatus) error {
+	for i, stored := range r.disputes {
+		if stored.ID == d.ID {
+			if stored.Status != from {
+				return domain.ErrInvalidDisputeStatus
+			}
+			cp := *d
+			r.disputes[i] = &cp
+			return nil
+		}
+	}
+	return domain.ErrDisputeNotFound
+}
+
+func (r *mockDisputeRepo) AddEvidence(ctx context.Context, ev *domain.DisputeEvidence) error {
+	ev.ID = len(r.evidence) + 1
+	r.evidence = append(r.evidence, ev)
+	return nil
+}
+
+func (r *mockDisputeRepo) ListEvidence(ctx context.Context, disputeID int) ([]*domain.DisputeEvidence, error) {
+	var out []*domain.DisputeEvidence
+	for _, ev := range r.evidence {
+		if ev.DisputeID == disputeID {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+type mockLedgerRepo struct {
+	entries []*domain.LedgerEntry
+}
+
+func (r *mockLedgerRepo) Post(ctx context.Context, entries []*domain.LedgerEntry) error {
+	for _, e := range entries {
+		posted := false
+		for _, existing := range r.entries {
+			if existing.Reference == e.Reference {
+				posted = true
+			}
+		}
+		if !posted {
+			r.entries = append(r.entries, e)
+		}
+	}
+	return nil
+}
+
+func (r *mockLedgerRepo) ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.LedgerEntry, error) {
+	var out []*domain.LedgerEntry
+	for _, e := range r.entries {
+		if e.PaymentID == paymentID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+type memoryEvidenceStore struct {
+	files map[string]string
+}
+
+func (s *memoryEvidenceStore) Save(
+	ctx context.Context,
+	disputeID string,
+	fileName string,
+	r io.Reader,
+	maxSize int64,
+) (string, int64, error) {
+	b, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return "", 0, err
+	}
+	if int64(len(b)) > maxSize {
+		return "", 0, domain.ErrInvalidEvidence
+	}
+	path := disputeID + "/" + fileName
+	s.files[path] = string(b)
+	return path, int64(len(b)), nil
+}
+
+func settledPayment() *domain.Payment {
+	return &domain.Payment{
+		PublicID: "pay_settled",
+		Amount:   150000,
+		Currency: "IDR",
+		Method:   domain.PaymentMethodTypeCard,
+		Status:   domain.PaymentStatusSuccess,
+	}
+}
+
+func openDispute(t *testing.T, uc *HandleDisputeEventUsecase) *domain.Dispute {
+	t.Helper()
+	d, err := uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event:     domain.DisputeEventOpened,
+		Reference: "dsp_1",
+		PaymentID: "pay_settled",
+		Reason:    "fraudulent",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return d
+}
+
+func TestHandleDisputeEvent_OpenIsIdempotent(t *testing.T) {
+	observability.InitTracer("test")
+
+	disputes := &mockDisputeRepo{}
+	uc := NewHandleDisputeEventUsecase(
+		disputes, newStatefulPaymentRepo(settledPayment()), &mockLedgerRepo{}, 7*24*time.Hour, 0,
+	)
+
+	first := openDispute(t, uc)
+	if first.Status != domain.DisputeStatusNeedsResponse || first.Amount != 150000 {
+		t.Fatalf("expected a full amount dispute awaiting response, got %+v", first)
+	}
+	if first.EvidenceDueBy.Before(time.Now().Add(6 * 24 * time.Hour)) {
+		t.Fatalf("expected the default evidence window, got %v", first.EvidenceDueBy)
+	}
+
+	again := openDispute(t, uc)
+	if again.PublicID != first.PublicID || len(disputes.disputes) != 1 {
+		t.Fatalf("redelivered event must not open a second dispute")
+	}
+}
+
+func TestHandleDisputeEvent_RejectsInvalidOpen(t *testing.T) {
+	observability.InitTracer("test")
+
+	pending := settledPayment()
+	pending.PublicID = "pay_pending"
+	pending.Status = domain.PaymentStatusPending
+
+	uc := NewHandleDisputeEventUsecase(
+		&mockDisputeRepo{}, newStatefulPaymentRepo(settledPayment(), pending), &mockLedgerRepo{}, time.Hour, 0,
+	)
+
+	_, err := uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event: domain.DisputeEventOpened, Reference: "dsp_a", PaymentID: "pay_pending",
+	})
+	if !errors.Is(err, domain.ErrPaymentNotDisputable) {
+		t.Fatalf("expected ErrPaymentNotDisputable, got %v", err)
+	}
+
+	_, err = uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event: domain.DisputeEventOpened, Reference: "dsp_b", PaymentID: "pay_settled", Amount: 150001,
+	})
+	if !errors.Is(err, domain.ErrInvalidDisputeAmount) {
+		t.Fatalf("expected ErrInvalidDisputeAmount, got %v", err)
+	}
+
+	_, err = uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event: "dispute.reopened", Reference: "dsp_b",
+	})
+	if !errors.Is(err, domain.ErrUnknownDisputeEvent) {
+		t.Fatalf("expected ErrUnknownDisputeEvent, got %v", err)
+	}
+}
+
+func TestHandleDisputeEvent_LostPostsChargebackOnce(t *testing.T) {
+	observability.InitTracer("test")
+
+	ledger := &mockLedgerRepo{}
+	uc := NewHandleDisputeEventUsecase(
+		&mockDisputeRepo{}, newStatefulPaymentRepo(settledPayment()), ledger, time.Hour, 15000,
+	)
+	openDispute(t, uc)
+
+	for i := 0; i < 2; i++ {
+		d, err := uc.Execute(context.Background(), HandleDisputeEventInput{
+			Event: domain.DisputeEventLost, Reference: "dsp_1",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Status != domain.DisputeStatusLost || d.ClosedAt == nil {
+			t.Fatalf("expected a closed lost dispute, got %+v", d)
+		}
+	}
+
+	if len(ledger.entries) != 2 {
+		t.Fatalf("expected chargeback and fee posted once, got %d entries", len(ledger.entries))
+	}
+	if ledger.entries[0].Type != domain.LedgerEntryChargeback || ledger.entries[0].Amount != -150000 {
+		t.Fatalf("unexpected chargeback entry: %+v", ledger.entries[0])
+	}
+	if ledger.entries[1].Type != domain.LedgerEntryChargebackFee || ledger.entries[1].Amount != -15000 {
+		t.Fatalf("unexpected fee entry: %+v", ledger.entries[1])
+	}
+
+	_, err := uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event: domain.DisputeEventWon, Reference: "dsp_1",
+	})
+	if !errors.Is(err, domain.ErrDisputeClosed) {
+		t.Fatalf("expected ErrDisputeClosed, got %v", err)
+	}
+}
+
+func TestHandleDisputeEvent_WonPostsNothing(t *testing.T) {
+	observability.InitTracer("test")
+
+	ledger := &mockLedgerRepo{}
+	uc := NewHandleDisputeEventUsecase(
+		&mockDisputeRepo{}, newStatefulPaymentRepo(settledPayment()), ledger, time.Hour, 15000,
+	)
+	openDispute(t, uc)
+
+	d, err := uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event: domain.DisputeEventWon, Reference: "dsp_1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Status != domain.DisputeStatusWon {
+		t.Fatalf("expected won, got %s", d.Status)
+	}
+
+	_, err = uc.Execute(context.Background(), HandleDisputeEventInput{
+		Event: domain.DisputeEventLost, Reference: "dsp_1",
+	})
+	if !errors.Is(err, domain.ErrDisputeClosed) {
+		t.Fatalf("expected ErrDisputeClosed, got %v", err)
+	}
+	if len(ledger.entries) != 0 {
+		t.Fatalf("a won dispute must not be charged back")
+	}
+}
+
+func TestDisputeEvidence_UploadAndSubmit(t *testing.T) {
+	observability.InitTracer("test")
+
+	disputes := &mockDisputeRepo{}
+	events := NewHandleDisputeEventUsecase(
+		disputes, newStatefulPaymentRepo(settledPayment()), &mockLedgerRepo{}, time.Hour, 0,
+	)
+	d := openDispute(t, events)
+
+	store := &memoryEvidenceStore{files: map[string]string{}}
+	upload := NewUploadDisputeEvidenceUsecase(disputes, store, 16)
+	submit := NewSubmitDisputeEvidenceUsecase(disputes)
+
+	if _, err := submit.Execute(context.Background(), d.PublicID); !errors.Is(err, domain.ErrEvidenceRequired) {
+		t.Fatalf("expected ErrEvidenceRequired, got %v", err)
+	}
+
+	_, err := upload.Execute(context.Background(), UploadDisputeEvidenceInput{
+		DisputeID: d.PublicID, FileName: "run.sh", ContentType: "application/x-sh", File: strings.NewReader("x"),
+	})
+	if !errors.Is(err, domain.ErrInvalidEvidence) {
+		t.Fatalf("expected ErrInvalidEvidence for content type, got %v", err)
+	}
+
+	_, err = upload.Execute(context.Background(), UploadDisputeEvidenceInput{
+		DisputeID: d.PublicID, FileName: "big.txt", ContentType: "text/plain", File: strings.NewReader(strings.Repeat("x", 17)),
+	})
+	if !errors.Is(err, domain.ErrInvalidEvidence) {
+		t.Fatalf("expected ErrInvalidEvidence for size, got %v", err)
+	}
+
+	ev, err := upload.Execute(context.Background(), UploadDisputeEvidenceInput{
+		DisputeID: d.PublicID, FileName: "receipt.txt", ContentType: "text/plain", Note: "delivered", File: strings.NewReader("signed receipt"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev.Size != 14 || store.files[ev.Path] != "signed receipt" {
+		t.Fatalf("evidence not stored: %+v", ev)
+	}
+
+	submitted, err := submit.Execute(context.Background(), d.PublicID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if submitted.Status != domain.DisputeStatusUnderReview {
+		t.Fatalf("expected under_review, got %s", submitted.Status)
+	}
+
+	_, err = upload.Execute(context.Background(), UploadDisputeEvidenceInput{
+		DisputeID: d.PublicID, FileName: "late.txt", ContentType: "text/plain", File: strings.NewReader("x"),
+	})
+	if !errors.Is(err, domain.ErrInvalidDisputeStatus) {
+		t.Fatalf("expected ErrInvalidDisputeStatus after submitting, got %v", err)
+	}
+}
+
+func TestDisputeEvidence_RejectedAfterDeadline(t *testing.T) {
+	observability.InitTracer("test")
+
+	disputes := &mockDisputeRepo{}
+	events := NewHandleDisputeEventUsecase(
+		disputes, newStatefulPaymentRepo(settledPayment()), &mockLedgerRepo{}, time.Hour, 0,
+	)
+	d := openDispute(t, events)
+
+	upload := NewUploadDisputeEvidenceUsecase(disputes, &memoryEvidenceStore{files: map[string]string{}}, 1024)
+	upload.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	_, err := upload.Execute(context.Background(), UploadDisputeEvidenceInput{
+		DisputeID: d.PublicID, FileName: "receipt.pdf", ContentType: "application/pdf", File: strings.NewReader("%PDF"),
+	})
+	if !errors.Is(err, domain.ErrEvidenceDeadlinePassed) {
+		t.Fatalf("expected ErrEvidenceDeadlinePassed, got %v", err)
+	}
+}
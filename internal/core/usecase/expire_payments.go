@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ExpirePaymentsUsecase struct {
+	paymentRepo ports.PaymentRepository
+	vaRepo      ports.VirtualAccountRepository
+	reviewRepo  ports.ReviewRepository
+	batchSize   int
+}
+
+func NewExpirePaymentsUsecase(
+	paymentRepo ports.PaymentRepository,
+	vaRepo ports.VirtualAccountRepository,
+	batchSize int,
+) *ExpirePaymentsUsecase {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &ExpirePaymentsUsecase{
+		paymentRepo: paymentRepo,
+		vaRepo:      vaRepo,
+		batchSize:   batchSize,
+	}
+}
+
+// WithReviews records in the review audit trail when a held payment
+// expires because nobody decided on it in time.
+func (uc *ExpirePaymentsUsecase) WithReviews(
+	reviewRepo ports.ReviewRepository,
+) *ExpirePaymentsUsecase {
+	uc.reviewRepo = reviewRepo
+	return uc
+}
+
+// Execute expires pending payments whose deadline passed before now and
+// closes their virtual accounts. It returns how many payments were expired.
+func (uc *ExpirePaymentsUsecase) Execute(
+	ctx context.Context,
+	now time.Time,
+) (int, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ExpirePaymentsUseCase.Execute")
+	defer span.End()
+
+	expired, err := uc.paymentRepo.ListExpired(ctx, now, uc.batchSize)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, err
+	}
+
+	count := 0
+	var errs []error
+	for _, p := range expired {
+		if err := uc.expire(ctx, p, now); err != nil {
+			// A payment settled concurrently is simply skipped.
+			if !errors.Is(err, domain.ErrPaymentStatusConflict) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		count++
+	}
+
+	span.SetAttributes(attribute.Int("payments.expired", count))
+
+	if err := errors.Join(errs...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return count, err
+	}
+	return count, nil
+}
+
+func (uc *ExpirePaymentsUsecase) expire(
+	ctx context.Context,
+	payment *domain.Payment,
+	now time.Time,
+) error {
+	from := payment.Status
+	if err := payment.TransitionTo(domain.PaymentStatusExpired, now); err != nil {
+		return err
+	}
+	if err := uc.paymentRepo.UpdateStatus(ctx, payment, from); err != nil {
+		return err
+	}
+
+	if from.AwaitingReview() && uc.reviewRepo != nil {
+		return uc.reviewRepo.Create(ctx, &domain.PaymentReview{
+			PaymentID: payment.PublicID,
+			Action:    domain.ReviewActionExpired,
+			Reason:    "review SLA elapsed",
+			Reviewer:  domain.SystemReviewer,
+		})
+	}
+
+	if payment.Method != domain.PaymentMethodBankTransfer {
+		return nil
+	}
+	va, err := uc.vaRepo.FindByPaymentID(ctx, payment.PublicID)
+	if errors.Is(err, domain.ErrVirtualAccountNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if va.Status == domain.VirtualAccountStatusPaid ||
+		va.Status == domain.VirtualAccountStatusOverpaid {
+		return nil
+	}
+	return uc.vaRepo.UpdateStatus(ctx, va.ID, domain.VirtualAccountStatusExpired)
+}
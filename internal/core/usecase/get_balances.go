@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetBalancesUsecase struct {
+	balanceRepo     ports.BalanceRepository
+	settlementDelay time.Duration
+	now             func() time.Time
+}
+
+func NewGetBalancesUsecase(
+	balanceRepo ports.BalanceRepository,
+	settlementDelay time.Duration,
+) *GetBalancesUsecase {
+	return &GetBalancesUsecase{
+		balanceRepo:     balanceRepo,
+		settlementDelay: settlementDelay,
+		now:             time.Now,
+	}
+}
+
+// Execute returns the pending and available balance per currency.
+func (uc *GetBalancesUsecase) Execute(ctx context.Context) ([]domain.Balance, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetBalancesUseCase.Execute")
+	defer span.End()
+
+	balances, err := uc.balanceRepo.Balances(ctx, uc.now().Add(-uc.settlementDelay))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return balances, nil
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type DisputeOutput struct {
+	Dispute  *domain.Dispute
+	Evidence []*domain.DisputeEvidence
+	// Adjustments are the ledger entries posted for the dispute.
+	Adjustments []*domain.LedgerEntry
+}
+
+type GetDisputeUsecase struct {
+	disputeRepo ports.DisputeRepository
+	ledgerRepo  ports.LedgerRepository
+}
+
+func NewGetDisputeUsecase(
+	disputeRepo ports.DisputeRepository,
+	ledgerRepo ports.LedgerRepository,
+) *GetDisputeUsecase {
+	return &GetDisputeUsecase{
+		disputeRepo: disputeRepo,
+		ledgerRepo:  ledgerRepo,
+	}
+}
+
+func (uc *GetDisputeUsecase) Execute(
+	ctx context.Context,
+	disputeID string,
+) (*DisputeOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetDisputeUseCase.Execute")
+	defer span.End()
+
+	out, err := uc.get(ctx, disputeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return out, nil
+}
+
+func (uc *GetDisputeUsecase) get(
+	ctx context.Context,
+	disputeID string,
+) (*DisputeOutput, error) {
+	dispute, err := uc.disputeRepo.FindByPublicID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, err := uc.disputeRepo.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.ledgerRepo.ListByPaymentID(ctx, dispute.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	adjustments := make([]*domain.LedgerEntry, 0)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Reference, dispute.PublicID+":") {
+			adjustments = append(adjustments, e)
+		}
+	}
+
+	return &DisputeOutput{
+		Dispute:     dispute,
+		Evidence:    evidence,
+		Adjustments: adjustments,
+	}, nil
+}
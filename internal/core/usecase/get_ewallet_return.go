@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type EWalletReturnOutput struct {
+	Payment *domain.Payment
+	// ReturnURL is empty when the merchant did not give one.
+	ReturnURL string
+}
+
+// GetEWalletReturnUsecase resolves where a payer coming back from their
+// wallet should be sent.
+type GetEWalletReturnUsecase struct {
+	walletRepo  ports.EWalletAuthorizationRepository
+	paymentRepo ports.PaymentRepository
+}
+
+func NewGetEWalletReturnUsecase(
+	walletRepo ports.EWalletAuthorizationRepository,
+	paymentRepo ports.PaymentRepository,
+) *GetEWalletReturnUsecase {
+	return &GetEWalletReturnUsecase{
+		walletRepo:  walletRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+func (uc *GetEWalletReturnUsecase) Execute(
+	ctx context.Context,
+	paymentID string,
+) (*EWalletReturnOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetEWalletReturnUseCase.Execute")
+	defer span.End()
+
+	auth, err := uc.walletRepo.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &EWalletReturnOutput{
+		Payment:   payment,
+		ReturnURL: auth.ReturnURL,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPayerUsecase struct {
+	payerRepo ports.PayerRepository
+}
+
+func NewGetPayerUsecase(
+	payerRepo ports.PayerRepository,
+) *GetPayerUsecase {
+	return &GetPayerUsecase{
+		payerRepo: payerRepo,
+	}
+}
+
+func (uc *GetPayerUsecase) Execute(
+	ctx context.Context,
+	id int,
+) (*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPayerUseCase.Execute")
+	defer span.End()
+
+	payer, err := uc.payerRepo.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payer, nil
+}
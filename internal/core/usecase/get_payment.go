@@ -11,6 +11,7 @@ import (
 
 type GetPaymentUsecase struct {
 	paymentRepo ports.PaymentRepository
+	splitRepo   ports.SplitRepository
 }
 
 func NewGetPaymentUsecase(
@@ -21,6 +22,14 @@ func NewGetPaymentUsecase(
 	}
 }
 
+// WithSplits loads how split payments are allocated between recipients.
+func (uc *GetPaymentUsecase) WithSplits(
+	splitRepo ports.SplitRepository,
+) *GetPaymentUsecase {
+	uc.splitRepo = splitRepo
+	return uc
+}
+
 func (uc *GetPaymentUsecase) Execute(
 	ctx context.Context,
 	publicID string,
@@ -37,5 +46,14 @@ func (uc *GetPaymentUsecase) Execute(
 		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+
+	if uc.splitRepo != nil {
+		payment.Splits, err = uc.splitRepo.ListByPaymentID(ctx, payment.PublicID)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+	}
 	return payment, nil
 }
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPaymentBatchUsecase struct {
+	batchRepo ports.PaymentBatchRepository
+}
+
+func NewGetPaymentBatchUsecase(
+	batchRepo ports.PaymentBatchRepository,
+) *GetPaymentBatchUsecase {
+	return &GetPaymentBatchUsecase{
+		batchRepo: batchRepo,
+	}
+}
+
+func (uc *GetPaymentBatchUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.PaymentBatch, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPaymentBatchUseCase.Execute")
+	defer span.End()
+
+	batch, err := uc.batchRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return batch, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPaymentLinkUsecase struct {
+	paymentLinkRepo ports.PaymentLinkRepository
+}
+
+func NewGetPaymentLinkUsecase(
+	paymentLinkRepo ports.PaymentLinkRepository,
+) *GetPaymentLinkUsecase {
+	return &GetPaymentLinkUsecase{
+		paymentLinkRepo: paymentLinkRepo,
+	}
+}
+
+func (uc *GetPaymentLinkUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.PaymentLink, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPaymentLinkUseCase.Execute")
+	defer span.End()
+
+	link, err := uc.paymentLinkRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return link, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPaymentMethodUsecase struct {
+	paymentMethodRepo ports.PaymentMethodRepository
+}
+
+func NewGetPaymentMethodUsecase(
+	paymentMethodRepo ports.PaymentMethodRepository,
+) *GetPaymentMethodUsecase {
+	return &GetPaymentMethodUsecase{
+		paymentMethodRepo: paymentMethodRepo,
+	}
+}
+
+func (uc *GetPaymentMethodUsecase) Execute(
+	ctx context.Context,
+	token string,
+) (*domain.PaymentMethod, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPaymentMethodUseCase.Execute")
+	defer span.End()
+
+	method, err := uc.paymentMethodRepo.FindByToken(ctx, token)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return method, nil
+}
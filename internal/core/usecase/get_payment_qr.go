@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPaymentQROutput struct {
+	Payment *domain.Payment
+	QRCode  *domain.QRCode
+}
+
+type GetPaymentQRUsecase struct {
+	qrRepo      ports.QRCodeRepository
+	paymentRepo ports.PaymentRepository
+}
+
+func NewGetPaymentQRUsecase(
+	qrRepo ports.QRCodeRepository,
+	paymentRepo ports.PaymentRepository,
+) *GetPaymentQRUsecase {
+	return &GetPaymentQRUsecase{
+		qrRepo:      qrRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+func (uc *GetPaymentQRUsecase) Execute(
+	ctx context.Context,
+	paymentID string,
+) (*GetPaymentQROutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPaymentQRUseCase.Execute")
+	defer span.End()
+
+	qr, err := uc.qrRepo.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &GetPaymentQROutput{
+		Payment: payment,
+		QRCode:  qr,
+	}, nil
+}
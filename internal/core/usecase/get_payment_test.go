@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"payment-service/internal/core/domain"
 	"payment-service/internal/observability"
@@ -27,6 +28,22 @@ func (m *mockGetPaymentRepo) FindbyPublicID(ctx context.Context, publicID string
     return m.returned, m.err
 }
 
+func (m *mockGetPaymentRepo) ListByPayerID(ctx context.Context, payerID int, limit, offset int) ([]*domain.Payment, error) {
+    return nil, errors.New("not implemented")
+}
+
+func (m *mockGetPaymentRepo) UpdateStatus(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus) error {
+    return errors.New("not implemented")
+}
+
+func (m *mockGetPaymentRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Payment, error) {
+    return nil, errors.New("not implemented")
+}
+
+func (m *mockGetPaymentRepo) ListAwaitingReview(ctx context.Context, limit, offset int) ([]*domain.Payment, error) {
+    return nil, errors.New("not implemented")
+}
+
 func TestGetPayment_Success(t *testing.T) {
     observability.InitTracer("test")
 
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPayoutUsecase struct {
+	payoutRepo ports.PayoutRepository
+}
+
+func NewGetPayoutUsecase(payoutRepo ports.PayoutRepository) *GetPayoutUsecase {
+	return &GetPayoutUsecase{payoutRepo: payoutRepo}
+}
+
+func (uc *GetPayoutUsecase) Execute(
+	ctx context.Context,
+	payoutID string,
+) (*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPayoutUseCase.Execute")
+	defer span.End()
+
+	payout, err := uc.payoutRepo.FindByPublicID(ctx, payoutID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payout, nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetPlanUsecase struct {
+	planRepo ports.PlanRepository
+}
+
+func NewGetPlanUsecase(planRepo ports.PlanRepository) *GetPlanUsecase {
+	return &GetPlanUsecase{
+		planRepo: planRepo,
+	}
+}
+
+func (uc *GetPlanUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.Plan, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetPlanUseCase.Execute")
+	defer span.End()
+
+	plan, err := uc.planRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return plan, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ReviewOutput struct {
+	Payment *domain.Payment
+	// History is the review audit trail, oldest first.
+	History []*domain.PaymentReview
+}
+
+// GetReviewUsecase shows a payment together with its review audit trail.
+type GetReviewUsecase struct {
+	paymentRepo ports.PaymentRepository
+	reviewRepo  ports.ReviewRepository
+}
+
+func NewGetReviewUsecase(
+	paymentRepo ports.PaymentRepository,
+	reviewRepo ports.ReviewRepository,
+) *GetReviewUsecase {
+	return &GetReviewUsecase{
+		paymentRepo: paymentRepo,
+		reviewRepo:  reviewRepo,
+	}
+}
+
+func (uc *GetReviewUsecase) Execute(
+	ctx context.Context,
+	paymentID string,
+) (*ReviewOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetReviewUseCase.Execute")
+	defer span.End()
+
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	history, err := uc.reviewRepo.ListByPaymentID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return &ReviewOutput{Payment: payment, History: history}, nil
+}
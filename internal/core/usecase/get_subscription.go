@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type GetSubscriptionUsecase struct {
+	subscriptionRepo ports.SubscriptionRepository
+}
+
+func NewGetSubscriptionUsecase(
+	subscriptionRepo ports.SubscriptionRepository,
+) *GetSubscriptionUsecase {
+	return &GetSubscriptionUsecase{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+func (uc *GetSubscriptionUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.Subscription, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetSubscriptionUseCase.Execute")
+	defer span.End()
+
+	subscription, err := uc.subscriptionRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return subscription, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// GetVirtualAccountUsecase returns the account a bank transfer payment is
+// paid into, for showing the transfer instructions again.
+type GetVirtualAccountUsecase struct {
+	vaRepo ports.VirtualAccountRepository
+}
+
+func NewGetVirtualAccountUsecase(
+	vaRepo ports.VirtualAccountRepository,
+) *GetVirtualAccountUsecase {
+	return &GetVirtualAccountUsecase{vaRepo: vaRepo}
+}
+
+func (uc *GetVirtualAccountUsecase) Execute(
+	ctx context.Context,
+	paymentID string,
+) (*domain.VirtualAccount, error) {
+	ctx, span := observability.Tracer().Start(ctx, "GetVirtualAccountUseCase.Execute")
+	defer span.End()
+
+	va, err := uc.vaRepo.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return va, nil
+}
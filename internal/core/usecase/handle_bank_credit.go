@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type HandleBankCreditInput struct {
+	BankCode      string
+	AccountNumber string
+	Amount        int
+	// Reference is the bank's unique id for the transfer and makes
+	// redelivered notifications harmless.
+	Reference  string
+	ReceivedAt time.Time
+}
+
+type HandleBankCreditOutput struct {
+	PaymentID      string
+	PaymentStatus  domain.PaymentStatus
+	VirtualAccount *domain.VirtualAccount
+	// Duplicate is set when the credit had already been processed.
+	Duplicate bool
+	// Late is set when the credit arrived after the payment expired.
+	Late bool
+}
+
+type HandleBankCreditUsecase struct {
+	vaRepo      ports.VirtualAccountRepository
+	paymentRepo ports.PaymentRepository
+	now         func() time.Time
+}
+
+func NewHandleBankCreditUsecase(
+	vaRepo ports.VirtualAccountRepository,
+	paymentRepo ports.PaymentRepository,
+) *HandleBankCreditUsecase {
+	return &HandleBankCreditUsecase{
+		vaRepo:      vaRepo,
+		paymentRepo: paymentRepo,
+		now:         time.Now,
+	}
+}
+
+func isValidBankCreditInput(input HandleBankCreditInput) error {
+	if input.BankCode == "" || input.AccountNumber == "" {
+		return errors.New("bank code and account number are required")
+	}
+	if input.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	if input.Reference == "" {
+		return errors.New("reference is required")
+	}
+	return nil
+}
+
+// Execute records an incoming transfer against its virtual account and
+// settles the payment once the expected amount has been received.
+func (uc *HandleBankCreditUsecase) Execute(
+	ctx context.Context,
+	input HandleBankCreditInput,
+) (*HandleBankCreditOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "HandleBankCreditUseCase.Execute")
+	defer span.End()
+
+	out, err := uc.handle(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("payment.id", out.PaymentID),
+		attribute.String("virtual_account.status", string(out.VirtualAccount.Status)),
+		attribute.Bool("bank_credit.duplicate", out.Duplicate),
+	)
+	return out, nil
+}
+
+func (uc *HandleBankCreditUsecase) handle(
+	ctx context.Context,
+	input HandleBankCreditInput,
+) (*HandleBankCreditOutput, error) {
+	if err := isValidBankCreditInput(input); err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	if input.ReceivedAt.IsZero() {
+		input.ReceivedAt = now
+	}
+
+	va, err := uc.vaRepo.FindByAccountNumber(ctx, input.BankCode, input.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, va.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &HandleBankCreditOutput{
+		PaymentID:      payment.PublicID,
+		VirtualAccount: va,
+	}
+
+	// --- a transfer after expiry is recorded but never settles ---
+	if payment.IsExpiredAt(input.ReceivedAt) {
+		if err := uc.expire(ctx, payment, now); err != nil {
+			return nil, err
+		}
+		out.Late = true
+	}
+
+	credit := &domain.BankCredit{
+		VirtualAccountID: va.ID,
+		Reference:        input.Reference,
+		Amount:           input.Amount,
+		ReceivedAt:       input.ReceivedAt,
+	}
+	if out.Late {
+		va.Status = domain.VirtualAccountStatusExpired
+	}
+
+	err = uc.vaRepo.ApplyCredit(ctx, va, credit)
+	if errors.Is(err, domain.ErrDuplicateBankCredit) {
+		current, findErr := uc.vaRepo.FindByPaymentID(ctx, va.PaymentID)
+		if findErr != nil {
+			return nil, findErr
+		}
+		// A redelivery finishes settlement that failed after the credit
+		// was stored.
+		if current.Outstanding() <= 0 && !out.Late &&
+			payment.Status == domain.PaymentStatusPending {
+			if err := uc.settle(ctx, payment, now); err != nil {
+				return nil, err
+			}
+		}
+		out.VirtualAccount = current
+		out.PaymentStatus = payment.Status
+		out.Duplicate = true
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// only the credit that completes the amount settles, not those that
+	// arrive concurrently or after it
+	paid := va.Outstanding() <= 0 && va.Outstanding()+credit.Amount > 0
+	if paid && !out.Late && payment.Status == domain.PaymentStatusPending {
+		if err := uc.settle(ctx, payment, now); err != nil {
+			return nil, err
+		}
+	}
+
+	out.PaymentStatus = payment.Status
+	return out, nil
+}
+
+// settle moves a pending payment through PROCESSING to SUCCESS.
+func (uc *HandleBankCreditUsecase) settle(
+	ctx context.Context,
+	payment *domain.Payment,
+	now time.Time,
+) error {
+	if err := payment.TransitionTo(domain.PaymentStatusProcessing, now); err != nil {
+		return err
+	}
+	if err := payment.TransitionTo(domain.PaymentStatusSuccess, now); err != nil {
+		return err
+	}
+	return uc.paymentRepo.UpdateStatus(ctx, payment, domain.PaymentStatusPending)
+}
+
+func (uc *HandleBankCreditUsecase) expire(
+	ctx context.Context,
+	payment *domain.Payment,
+	now time.Time,
+) error {
+	if payment.Status == domain.PaymentStatusExpired {
+		return nil
+	}
+	from := payment.Status
+	if err := payment.TransitionTo(domain.PaymentStatusExpired, now); err != nil {
+		return err
+	}
+	return uc.paymentRepo.UpdateStatus(ctx, payment, from)
+}
@@ -0,0 +1,363 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// statefulPaymentRepo keeps payments by public id so status updates can be
+// asserted.
+type statefulPaymentRepo struct {
+	mockPaymentRepo
+	payments map[string]*domain.Payment
+}
+
+func newStatefulPaymentRepo(payments ...*domain.Payment) *statefulPaymentRepo {
+	r := &statefulPaymentRepo{payments: map[string]*domain.Payment{}}
+	for _, p := range payments {
+		r.payments[p.PublicID] = p
+	}
+	return r
+}
+
+func (r *statefulPaymentRepo) Create(ctx context.Context, p *domain.Payment) error {
+	cp := *p
+	r.payments[p.PublicID] = &cp
+	return nil
+}
+
+func (r *statefulPaymentRepo) FindbyPublicID(ctx context.Context, publicID string) (*domain.Payment, error) {
+	p, ok := r.payments[publicID]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *statefulPaymentRepo) UpdateStatus(ctx context.Context, p *domain.Payment, from domain.PaymentStatus) error {
+	stored, ok := r.payments[p.PublicID]
+	if !ok || stored.Status != from {
+		return domain.ErrPaymentStatusConflict
+	}
+	cp := *p
+	r.payments[p.PublicID] = &cp
+	return nil
+}
+
+func (r *statefulPaymentRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Payment, error) {
+	var out []*domain.Payment
+	for _, p := range r.payments {
+		if (p.Status == domain.PaymentStatusPending ||
+			p.Status == domain.PaymentStatusRequiresAction ||
+			p.Status.AwaitingReview()) && p.IsExpiredAt(now) {
+			cp := *p
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+type mockVirtualAccountRepo struct {
+	accounts   map[string]*domain.VirtualAccount
+	references map[string]bool
+}
+
+func newMockVirtualAccountRepo(accounts ...*domain.VirtualAccount) *mockVirtualAccountRepo {
+	r := &mockVirtualAccountRepo{
+		accounts:   map[string]*domain.VirtualAccount{},
+		references: map[string]bool{},
+	}
+	for i, va := range accounts {
+		va.ID = i + 1
+		r.accounts[va.PaymentID] = va
+	}
+	return r
+}
+
+func (r *mockVirtualAccountRepo) Create(ctx context.Context, va *domain.VirtualAccount) error {
+	va.ID = len(r.accounts) + 1
+	cp := *va
+	r.accounts[va.PaymentID] = &cp
+	return nil
+}
+
+func (r *mockVirtualAccountRepo) FindByPaymentID(ctx context.Context, paymentID string) (*domain.VirtualAccount, error) {
+	va, ok := r.accounts[paymentID]
+	if !ok {
+		return nil, domain.ErrVirtualAccountNotFound
+	}
+	cp := *va
+	return &cp, nil
+}
+
+func (r *mockVirtualAccountRepo) FindByAccountNumber(ctx context.Context, bankCode, accountNumber string) (*domain.VirtualAccount, error) {
+	for _, va := range r.accounts {
+		if va.BankCode == bankCode && va.AccountNumber == accountNumber {
+			cp := *va
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrVirtualAccountNotFound
+}
+
+func (r *mockVirtualAccountRepo) ApplyCredit(ctx context.Context, va *domain.VirtualAccount, credit *domain.BankCredit) error {
+	if r.references[credit.Reference] {
+		return domain.ErrDuplicateBankCredit
+	}
+	r.references[credit.Reference] = true
+	stored := r.accounts[va.PaymentID]
+	stored.PaidAmount += credit.Amount
+	if va.Status != domain.VirtualAccountStatusExpired && stored.Status != domain.VirtualAccountStatusExpired {
+		stored.Status = stored.PaidStatus()
+	} else {
+		stored.Status = domain.VirtualAccountStatusExpired
+	}
+	*va = *stored
+	return nil
+}
+
+func (r *mockVirtualAccountRepo) UpdateStatus(ctx context.Context, id int, status domain.VirtualAccountStatus) error {
+	for _, va := range r.accounts {
+		if va.ID == id {
+			va.Status = status
+			return nil
+		}
+	}
+	return domain.ErrVirtualAccountNotFound
+}
+
+type fakeVAProvider struct{}
+
+func (fakeVAProvider) IssueVirtualAccount(ctx context.Context, bankCode, paymentID string) (string, error) {
+	if bankCode != "BCA" {
+		return "", domain.ErrUnsupportedBank
+	}
+	return "3935800000000001", nil
+}
+
+func pendingTransfer(expiresAt time.Time) (*domain.Payment, *domain.VirtualAccount) {
+	p := &domain.Payment{
+		PublicID:  "pay_va",
+		Amount:    10000,
+		Method:    domain.PaymentMethodBankTransfer,
+		Status:    domain.PaymentStatusPending,
+		ExpiresAt: &expiresAt,
+	}
+	va := &domain.VirtualAccount{
+		PaymentID:      p.PublicID,
+		BankCode:       "BCA",
+		AccountNumber:  "3935800000000001",
+		ExpectedAmount: 10000,
+		Status:         domain.VirtualAccountStatusOpen,
+		ExpiresAt:      expiresAt,
+	}
+	return p, va
+}
+
+func credit(amount int, ref string) HandleBankCreditInput {
+	return HandleBankCreditInput{
+		BankCode:      "BCA",
+		AccountNumber: "3935800000000001",
+		Amount:        amount,
+		Reference:     ref,
+	}
+}
+
+func TestCreatePayment_BankTransferIssuesVirtualAccount(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	accounts := newMockVirtualAccountRepo()
+	provider := &mockPaymentProvider{}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider).
+		WithBankTransfer(fakeVAProvider{}, accounts, time.Hour)
+
+	out, err := uc.Execute(context.Background(), CreatePaymentInput{
+		OrderID:        "order_va",
+		PayerID:        1,
+		Amount:         10000,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         domain.PaymentMethodBankTransfer,
+		BankCode:       "BCA",
+		IdempotencyKey: "idem-va",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("bank transfers must not call the provider, got %q", provider.calledWith)
+	}
+	if out.VirtualAccount == nil || out.VirtualAccount.AccountNumber != "3935800000000001" {
+		t.Fatalf("expected virtual account in output, got %+v", out.VirtualAccount)
+	}
+	if out.ExpiresAt == nil || payments.payments[out.PaymentID].ExpiresAt == nil {
+		t.Fatalf("expected payment to carry an expiry")
+	}
+	if _, err := accounts.FindByPaymentID(context.Background(), out.PaymentID); err != nil {
+		t.Fatalf("expected virtual account to be stored: %v", err)
+	}
+}
+
+func TestCreatePayment_BankTransferValidation(t *testing.T) {
+	observability.InitTracer("test")
+
+	input := CreatePaymentInput{
+		OrderID:        "order_va",
+		PayerID:        1,
+		Amount:         10000,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         domain.PaymentMethodBankTransfer,
+		IdempotencyKey: "idem-va",
+	}
+
+	uc := NewCreatePaymentUsecase(newStatefulPaymentRepo(), activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{})
+	if _, err := uc.Execute(context.Background(), input); err == nil {
+		t.Fatalf("expected error without bank code")
+	}
+
+	input.BankCode = "BCA"
+	if _, err := uc.Execute(context.Background(), input); err == nil {
+		t.Fatalf("expected error when bank transfers are not enabled")
+	}
+
+	input.BankCode = "XYZ"
+	uc.WithBankTransfer(fakeVAProvider{}, newMockVirtualAccountRepo(), time.Hour)
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, domain.ErrUnsupportedBank) {
+		t.Fatalf("expected ErrUnsupportedBank, got %v", err)
+	}
+}
+
+func TestHandleBankCredit_ExactAmountSettlesPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, va := pendingTransfer(time.Now().Add(time.Hour))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleBankCreditUsecase(newMockVirtualAccountRepo(va), payments)
+
+	out, err := uc.Execute(context.Background(), credit(10000, "ref-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.PaymentStatus != domain.PaymentStatusSuccess {
+		t.Fatalf("expected SUCCESS, got %s", out.PaymentStatus)
+	}
+	if out.VirtualAccount.Status != domain.VirtualAccountStatusPaid {
+		t.Fatalf("expected PAID, got %s", out.VirtualAccount.Status)
+	}
+	if stored := payments.payments["pay_va"]; stored.Status != domain.PaymentStatusSuccess || stored.PaidAt == nil {
+		t.Fatalf("expected stored payment to be paid, got %+v", stored)
+	}
+}
+
+func TestHandleBankCredit_PartialThenOverpaid(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, va := pendingTransfer(time.Now().Add(time.Hour))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleBankCreditUsecase(newMockVirtualAccountRepo(va), payments)
+
+	out, err := uc.Execute(context.Background(), credit(4000, "ref-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.PaymentStatus != domain.PaymentStatusPending {
+		t.Fatalf("expected payment to stay PENDING, got %s", out.PaymentStatus)
+	}
+	if out.VirtualAccount.Status != domain.VirtualAccountStatusPartiallyPaid || out.VirtualAccount.Outstanding() != 6000 {
+		t.Fatalf("unexpected account after partial credit: %+v", out.VirtualAccount)
+	}
+
+	out, err = uc.Execute(context.Background(), credit(7000, "ref-2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.PaymentStatus != domain.PaymentStatusSuccess {
+		t.Fatalf("expected SUCCESS, got %s", out.PaymentStatus)
+	}
+	if out.VirtualAccount.Status != domain.VirtualAccountStatusOverpaid || out.VirtualAccount.Outstanding() != -1000 {
+		t.Fatalf("unexpected account after overpayment: %+v", out.VirtualAccount)
+	}
+}
+
+func TestHandleBankCredit_DuplicateReferenceIsIgnored(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, va := pendingTransfer(time.Now().Add(time.Hour))
+	accounts := newMockVirtualAccountRepo(va)
+	uc := NewHandleBankCreditUsecase(accounts, newStatefulPaymentRepo(p))
+
+	if _, err := uc.Execute(context.Background(), credit(4000, "ref-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out, err := uc.Execute(context.Background(), credit(4000, "ref-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Duplicate {
+		t.Fatalf("expected duplicate flag")
+	}
+	if out.VirtualAccount.PaidAmount != 4000 {
+		t.Fatalf("duplicate credit must not be counted twice, paid %d", out.VirtualAccount.PaidAmount)
+	}
+}
+
+func TestHandleBankCredit_LateCreditDoesNotSettle(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, va := pendingTransfer(time.Now().Add(-time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleBankCreditUsecase(newMockVirtualAccountRepo(va), payments)
+
+	out, err := uc.Execute(context.Background(), credit(10000, "ref-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Late || out.PaymentStatus != domain.PaymentStatusExpired {
+		t.Fatalf("expected late credit on expired payment, got %+v", out)
+	}
+	if out.VirtualAccount.Status != domain.VirtualAccountStatusExpired {
+		t.Fatalf("expected account to stay EXPIRED, got %s", out.VirtualAccount.Status)
+	}
+}
+
+func TestHandleBankCredit_UnknownAccount(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewHandleBankCreditUsecase(newMockVirtualAccountRepo(), newStatefulPaymentRepo())
+
+	_, err := uc.Execute(context.Background(), credit(10000, "ref-1"))
+	if !errors.Is(err, domain.ErrVirtualAccountNotFound) {
+		t.Fatalf("expected ErrVirtualAccountNotFound, got %v", err)
+	}
+}
+
+func TestExpirePayments_ExpiresPendingTransfers(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, va := pendingTransfer(time.Now().Add(-time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	accounts := newMockVirtualAccountRepo(va)
+	uc := NewExpirePaymentsUsecase(payments, accounts, 10)
+
+	n, err := uc.Execute(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 expired payment, got %d", n)
+	}
+	if payments.payments["pay_va"].Status != domain.PaymentStatusExpired {
+		t.Fatalf("expected payment EXPIRED, got %s", payments.payments["pay_va"].Status)
+	}
+	if accounts.accounts["pay_va"].Status != domain.VirtualAccountStatusExpired {
+		t.Fatalf("expected account EXPIRED, got %s", accounts.accounts["pay_va"].Status)
+	}
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type HandleDisputeEventInput struct {
+	Event domain.DisputeEvent
+	// Reference is the provider's id for the dispute.
+	Reference string
+
+	// The fields below are only read from dispute.opened.
+	PaymentID string
+	Reason    string
+	// Amount defaults to the full payment amount.
+	Amount int
+	// EvidenceDueBy defaults to the configured evidence window.
+	EvidenceDueBy time.Time
+}
+
+type HandleDisputeEventUsecase struct {
+	disputeRepo ports.DisputeRepository
+	paymentRepo ports.PaymentRepository
+	ledgerRepo  ports.LedgerRepository
+
+	// evidenceWindow is how long the merchant has to respond when the
+	// provider does not send a due date.
+	evidenceWindow time.Duration
+	// chargebackFee is posted in the payment currency when a dispute is
+	// lost. Zero posts no fee.
+	chargebackFee int
+
+	now func() time.Time
+}
+
+func NewHandleDisputeEventUsecase(
+	disputeRepo ports.DisputeRepository,
+	paymentRepo ports.PaymentRepository,
+	ledgerRepo ports.LedgerRepository,
+	evidenceWindow time.Duration,
+	chargebackFee int,
+) *HandleDisputeEventUsecase {
+	return &HandleDisputeEventUsecase{
+		disputeRepo:    disputeRepo,
+		paymentRepo:    paymentRepo,
+		ledgerRepo:     ledgerRepo,
+		evidenceWindow: evidenceWindow,
+		chargebackFee:  chargebackFee,
+		now:            time.Now,
+	}
+}
+
+var disputeEventTargets = map[domain.DisputeEvent]domain.DisputeStatus{
+	domain.DisputeEventUnderReview: domain.DisputeStatusUnderReview,
+	domain.DisputeEventWon:         domain.DisputeStatusWon,
+	domain.DisputeEventLost:        domain.DisputeStatusLost,
+}
+
+// Execute applies a dispute notification from the provider. Events that
+// were already applied are accepted without changes.
+func (uc *HandleDisputeEventUsecase) Execute(
+	ctx context.Context,
+	input HandleDisputeEventInput,
+) (*domain.Dispute, error) {
+	ctx, span := observability.Tracer().Start(ctx, "HandleDisputeEventUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("dispute.reference", input.Reference),
+		attribute.String("dispute.event", string(input.Event)),
+	)
+
+	var (
+		dispute *domain.Dispute
+		err     error
+	)
+	if input.Event == domain.DisputeEventOpened {
+		dispute, err = uc.open(ctx, input)
+	} else {
+		dispute, err = uc.handle(ctx, input)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (uc *HandleDisputeEventUsecase) open(
+	ctx context.Context,
+	input HandleDisputeEventInput,
+) (*domain.Dispute, error) {
+	existing, err := uc.disputeRepo.FindByReference(ctx, input.Reference)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, domain.ErrDisputeNotFound) {
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, input.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != domain.PaymentStatusSuccess {
+		return nil, domain.ErrPaymentNotDisputable
+	}
+	ctx = actingFor(ctx, payment.MerchantID)
+
+	amount := input.Amount
+	if amount == 0 {
+		amount = payment.Amount
+	}
+	if amount < 0 || amount > payment.Amount {
+		return nil, domain.ErrInvalidDisputeAmount
+	}
+
+	dueBy := input.EvidenceDueBy
+	if dueBy.IsZero() {
+		dueBy = uc.now().Add(uc.evidenceWindow)
+	}
+
+	dispute := &domain.Dispute{
+		PublicID:      "dp_" + uuid.NewString(),
+		PaymentID:     payment.PublicID,
+		Reference:     input.Reference,
+		Reason:        input.Reason,
+		Amount:        amount,
+		Currency:      payment.Currency,
+		Status:        domain.DisputeStatusNeedsResponse,
+		EvidenceDueBy: dueBy,
+	}
+	if err := uc.disputeRepo.Create(ctx, dispute); err != nil {
+		// a concurrent delivery of the same event may have won the race
+		if existing, findErr := uc.disputeRepo.FindByReference(ctx, input.Reference); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (uc *HandleDisputeEventUsecase) handle(
+	ctx context.Context,
+	input HandleDisputeEventInput,
+) (*domain.Dispute, error) {
+	next, ok := disputeEventTargets[input.Event]
+	if !ok {
+		return nil, domain.ErrUnknownDisputeEvent
+	}
+
+	dispute, err := uc.disputeRepo.FindByReference(ctx, input.Reference)
+	if err != nil {
+		return nil, err
+	}
+	ctx = actingFor(ctx, dispute.MerchantID)
+
+	from := dispute.Status
+	if from != next {
+		if err := dispute.TransitionTo(next, uc.now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// the ledger is posted before the status so a failed update is retried
+	// by the provider without double charging
+	if next == domain.DisputeStatusLost {
+		if err := uc.ledgerRepo.Post(ctx, uc.chargebackEntries(dispute)); err != nil {
+			return nil, err
+		}
+	}
+
+	if from == next {
+		return dispute, nil
+	}
+	if err := uc.disputeRepo.UpdateStatus(ctx, dispute, from); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (uc *HandleDisputeEventUsecase) chargebackEntries(dispute *domain.Dispute) []*domain.LedgerEntry {
+	entries := []*domain.LedgerEntry{{
+		PaymentID:   dispute.PaymentID,
+		Type:        domain.LedgerEntryChargeback,
+		Amount:      -dispute.Amount,
+		Currency:    dispute.Currency,
+		Reference:   dispute.PublicID + ":chargeback",
+		Description: "lost dispute " + dispute.Reference,
+	}}
+	if uc.chargebackFee > 0 {
+		entries = append(entries, &domain.LedgerEntry{
+			PaymentID:   dispute.PaymentID,
+			Type:        domain.LedgerEntryChargebackFee,
+			Amount:      -uc.chargebackFee,
+			Currency:    dispute.Currency,
+			Reference:   dispute.PublicID + ":chargeback_fee",
+			Description: "chargeback fee for dispute " + dispute.Reference,
+		})
+	}
+	return entries
+}
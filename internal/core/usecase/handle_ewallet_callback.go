@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type HandleEWalletCallbackInput struct {
+	PaymentID string
+	// Reference must match the wallet reference issued at authorization.
+	Reference string
+	Event     domain.EWalletEvent
+}
+
+type HandleEWalletCallbackUsecase struct {
+	walletRepo  ports.EWalletAuthorizationRepository
+	paymentRepo ports.PaymentRepository
+	now         func() time.Time
+}
+
+func NewHandleEWalletCallbackUsecase(
+	walletRepo ports.EWalletAuthorizationRepository,
+	paymentRepo ports.PaymentRepository,
+) *HandleEWalletCallbackUsecase {
+	return &HandleEWalletCallbackUsecase{
+		walletRepo:  walletRepo,
+		paymentRepo: paymentRepo,
+		now:         time.Now,
+	}
+}
+
+// ewalletEventTargets lists the statuses a payment passes through for each
+// wallet event, in order.
+var ewalletEventTargets = map[domain.EWalletEvent][]domain.PaymentStatus{
+	domain.EWalletEventAcknowledged: {
+		domain.PaymentStatusProcessing,
+	},
+	domain.EWalletEventApproved: {
+		domain.PaymentStatusProcessing,
+		domain.PaymentStatusSuccess,
+	},
+	domain.EWalletEventDeclined: {
+		domain.PaymentStatusFailed,
+	},
+}
+
+// Execute applies a wallet notification to its payment. Notifications
+// that were already applied are accepted without changes.
+func (uc *HandleEWalletCallbackUsecase) Execute(
+	ctx context.Context,
+	input HandleEWalletCallbackInput,
+) (*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "HandleEWalletCallbackUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("payment.id", input.PaymentID),
+		attribute.String("ewallet.event", string(input.Event)),
+	)
+
+	payment, err := uc.handle(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (uc *HandleEWalletCallbackUsecase) handle(
+	ctx context.Context,
+	input HandleEWalletCallbackInput,
+) (*domain.Payment, error) {
+	targets, ok := ewalletEventTargets[input.Event]
+	if !ok {
+		return nil, domain.ErrUnknownEWalletEvent
+	}
+
+	auth, err := uc.walletRepo.FindByPaymentID(ctx, input.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Reference != auth.Reference {
+		return nil, domain.ErrEWalletAuthorizationNotFound
+	}
+
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, input.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := payment.Status
+	now := uc.now()
+	for _, next := range targets {
+		if payment.Status == next || reached(payment.Status, next) {
+			continue
+		}
+		if err := payment.TransitionTo(next, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if payment.Status == from {
+		return payment, nil
+	}
+	if err := uc.paymentRepo.UpdateStatus(ctx, payment, from); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// reached reports whether a payment in status current has already gone past
+// next, so a late acknowledgement after approval is not an error.
+func reached(current, next domain.PaymentStatus) bool {
+	return next == domain.PaymentStatusProcessing &&
+		current == domain.PaymentStatusSuccess
+}
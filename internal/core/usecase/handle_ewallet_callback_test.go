@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type mockEWalletRepo struct {
+	auths map[string]*domain.EWalletAuthorization
+}
+
+func newMockEWalletRepo(auths ...*domain.EWalletAuthorization) *mockEWalletRepo {
+	r := &mockEWalletRepo{auths: map[string]*domain.EWalletAuthorization{}}
+	for _, a := range auths {
+		r.auths[a.PaymentID] = a
+	}
+	return r
+}
+
+func (r *mockEWalletRepo) Create(ctx context.Context, a *domain.EWalletAuthorization) error {
+	cp := *a
+	r.auths[a.PaymentID] = &cp
+	return nil
+}
+
+func (r *mockEWalletRepo) FindByPaymentID(ctx context.Context, paymentID string) (*domain.EWalletAuthorization, error) {
+	a, ok := r.auths[paymentID]
+	if !ok {
+		return nil, domain.ErrEWalletAuthorizationNotFound
+	}
+	cp := *a
+	return &cp, nil
+}
+
+type fakeEWalletProvider struct{}
+
+func (fakeEWalletProvider) AuthorizeEWallet(ctx context.Context, p *domain.Payment, a *domain.EWalletAuthorization) error {
+	if a.Wallet == "" {
+		a.Wallet = "FAKEPAY"
+	}
+	if a.Wallet != "FAKEPAY" {
+		return domain.ErrUnsupportedWallet
+	}
+	a.Reference = "ewr_1"
+	a.NextAction = domain.NextAction{
+		Type:        domain.NextActionTypeForChannel(a.Channel),
+		RedirectURL: "http://wallet.test/approve/ewr_1",
+		Deeplink:    "fakepay://pay?reference=ewr_1",
+		QRString:    "http://wallet.test/approve/ewr_1",
+	}
+	return nil
+}
+
+func pendingEWallet() (*domain.Payment, *domain.EWalletAuthorization) {
+	p := &domain.Payment{
+		PublicID: "pay_ew",
+		Amount:   5000,
+		Method:   domain.PaymentMethodEWallet,
+		Status:   domain.PaymentStatusPending,
+	}
+	a := &domain.EWalletAuthorization{
+		PaymentID: p.PublicID,
+		Wallet:    "FAKEPAY",
+		Reference: "ewr_1",
+		ReturnURL: "https://shop.test/done",
+	}
+	return p, a
+}
+
+func ewalletEvent(event domain.EWalletEvent) HandleEWalletCallbackInput {
+	return HandleEWalletCallbackInput{
+		PaymentID: "pay_ew",
+		Reference: "ewr_1",
+		Event:     event,
+	}
+}
+
+func TestCreatePayment_EWalletReturnsNextAction(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	wallets := newMockEWalletRepo()
+	provider := &mockPaymentProvider{}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider).
+		WithEWallet(fakeEWalletProvider{}, wallets, 15*time.Minute)
+
+	out, err := uc.Execute(context.Background(), CreatePaymentInput{
+		OrderID:        "order_ew",
+		PayerID:        1,
+		Amount:         5000,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         domain.PaymentMethodEWallet,
+		Channel:        domain.EWalletChannelMobile,
+		ReturnURL:      "https://shop.test/done",
+		IdempotencyKey: "idem-ew",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("e-wallet payments must not be processed synchronously, got %q", provider.calledWith)
+	}
+	if out.Status != domain.PaymentStatusPending {
+		t.Fatalf("expected PENDING, got %s", out.Status)
+	}
+	if out.NextAction == nil || out.NextAction.Type != domain.NextActionDeeplink {
+		t.Fatalf("expected deeplink next action, got %+v", out.NextAction)
+	}
+	stored, err := wallets.FindByPaymentID(context.Background(), out.PaymentID)
+	if err != nil {
+		t.Fatalf("expected authorization to be stored: %v", err)
+	}
+	if stored.ReturnURL != "https://shop.test/done" || stored.Reference != "ewr_1" {
+		t.Fatalf("unexpected stored authorization: %+v", stored)
+	}
+	if out.ExpiresAt == nil {
+		t.Fatalf("expected e-wallet payment to expire")
+	}
+}
+
+func TestCreatePayment_EWalletRejectsRelativeReturnURL(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewCreatePaymentUsecase(newStatefulPaymentRepo(), activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithEWallet(fakeEWalletProvider{}, newMockEWalletRepo(), time.Minute)
+
+	_, err := uc.Execute(context.Background(), CreatePaymentInput{
+		OrderID:        "order_ew",
+		PayerID:        1,
+		Amount:         5000,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         domain.PaymentMethodEWallet,
+		ReturnURL:      "/done",
+		IdempotencyKey: "idem-ew",
+	})
+	if !errors.Is(err, domain.ErrInvalidReturnURL) {
+		t.Fatalf("expected ErrInvalidReturnURL, got %v", err)
+	}
+}
+
+func TestHandleEWalletCallback_AcknowledgeThenApprove(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, a := pendingEWallet()
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleEWalletCallbackUsecase(newMockEWalletRepo(a), payments)
+
+	got, err := uc.Execute(context.Background(), ewalletEvent(domain.EWalletEventAcknowledged))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != domain.PaymentStatusProcessing {
+		t.Fatalf("expected PROCESSING, got %s", got.Status)
+	}
+
+	got, err = uc.Execute(context.Background(), ewalletEvent(domain.EWalletEventApproved))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != domain.PaymentStatusSuccess || got.PaidAt == nil {
+		t.Fatalf("expected paid SUCCESS, got %+v", got)
+	}
+	if payments.payments["pay_ew"].Status != domain.PaymentStatusSuccess {
+		t.Fatalf("expected stored payment SUCCESS, got %s", payments.payments["pay_ew"].Status)
+	}
+
+	// redelivered and late notifications are harmless
+	for _, e := range []domain.EWalletEvent{domain.EWalletEventApproved, domain.EWalletEventAcknowledged} {
+		got, err = uc.Execute(context.Background(), ewalletEvent(e))
+		if err != nil {
+			t.Fatalf("%s after approval: unexpected error: %v", e, err)
+		}
+		if got.Status != domain.PaymentStatusSuccess {
+			t.Fatalf("%s after approval changed status to %s", e, got.Status)
+		}
+	}
+}
+
+func TestHandleEWalletCallback_ApproveWithoutAcknowledge(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, a := pendingEWallet()
+	uc := NewHandleEWalletCallbackUsecase(newMockEWalletRepo(a), newStatefulPaymentRepo(p))
+
+	got, err := uc.Execute(context.Background(), ewalletEvent(domain.EWalletEventApproved))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != domain.PaymentStatusSuccess {
+		t.Fatalf("expected SUCCESS, got %s", got.Status)
+	}
+}
+
+func TestHandleEWalletCallback_DeclineAfterApprovalConflicts(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, a := pendingEWallet()
+	uc := NewHandleEWalletCallbackUsecase(newMockEWalletRepo(a), newStatefulPaymentRepo(p))
+
+	if _, err := uc.Execute(context.Background(), ewalletEvent(domain.EWalletEventApproved)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := uc.Execute(context.Background(), ewalletEvent(domain.EWalletEventDeclined))
+	if !errors.Is(err, domain.ErrInvalidPaymentStatus) {
+		t.Fatalf("expected ErrInvalidPaymentStatus, got %v", err)
+	}
+}
+
+func TestHandleEWalletCallback_Rejects(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, a := pendingEWallet()
+	uc := NewHandleEWalletCallbackUsecase(newMockEWalletRepo(a), newStatefulPaymentRepo(p))
+
+	input := ewalletEvent(domain.EWalletEventApproved)
+	input.Reference = "ewr_other"
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, domain.ErrEWalletAuthorizationNotFound) {
+		t.Fatalf("expected ErrEWalletAuthorizationNotFound for wrong reference, got %v", err)
+	}
+
+	if _, err := uc.Execute(context.Background(), ewalletEvent("refunded")); !errors.Is(err, domain.ErrUnknownEWalletEvent) {
+		t.Fatalf("expected ErrUnknownEWalletEvent, got %v", err)
+	}
+}
+
+func TestGetEWalletReturn(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, a := pendingEWallet()
+	uc := NewGetEWalletReturnUsecase(newMockEWalletRepo(a), newStatefulPaymentRepo(p))
+
+	out, err := uc.Execute(context.Background(), "pay_ew")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.ReturnURL != "https://shop.test/done" || out.Payment.PublicID != "pay_ew" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+
+	if _, err := uc.Execute(context.Background(), "pay_missing"); !errors.Is(err, domain.ErrEWalletAuthorizationNotFound) {
+		t.Fatalf("expected ErrEWalletAuthorizationNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type HandleQRPaymentInput struct {
+	// Reference is the bill number read from the scanned payload.
+	Reference string
+	Amount    int
+	// IssuerReference is the payer's bank id for the transfer.
+	IssuerReference string
+}
+
+type HandleQRPaymentUsecase struct {
+	qrRepo      ports.QRCodeRepository
+	paymentRepo ports.PaymentRepository
+	now         func() time.Time
+}
+
+func NewHandleQRPaymentUsecase(
+	qrRepo ports.QRCodeRepository,
+	paymentRepo ports.PaymentRepository,
+) *HandleQRPaymentUsecase {
+	return &HandleQRPaymentUsecase{
+		qrRepo:      qrRepo,
+		paymentRepo: paymentRepo,
+		now:         time.Now,
+	}
+}
+
+// Execute completes the payment behind a scanned QR. A repeated
+// notification for a paid code returns the payment unchanged.
+func (uc *HandleQRPaymentUsecase) Execute(
+	ctx context.Context,
+	input HandleQRPaymentInput,
+) (*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "HandleQRPaymentUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("qr.reference", input.Reference),
+		attribute.String("qr.issuer_reference", input.IssuerReference),
+	)
+
+	payment, err := uc.handle(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (uc *HandleQRPaymentUsecase) handle(
+	ctx context.Context,
+	input HandleQRPaymentInput,
+) (*domain.Payment, error) {
+	if input.Reference == "" {
+		return nil, errors.New("reference is required")
+	}
+
+	qr, err := uc.qrRepo.FindByReference(ctx, input.Reference)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, qr.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status == domain.PaymentStatusSuccess {
+		return payment, nil
+	}
+	// dynamic codes are for the exact amount
+	if input.Amount != payment.Amount {
+		return nil, domain.ErrQRAmountMismatch
+	}
+
+	now := uc.now()
+	from := payment.Status
+	if payment.IsExpiredAt(now) {
+		if err := payment.TransitionTo(domain.PaymentStatusExpired, now); err != nil {
+			return nil, err
+		}
+		if err := uc.paymentRepo.UpdateStatus(ctx, payment, from); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrQRCodeExpired
+	}
+
+	if payment.Status == domain.PaymentStatusPending {
+		if err := payment.TransitionTo(domain.PaymentStatusProcessing, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := payment.TransitionTo(domain.PaymentStatusSuccess, now); err != nil {
+		return nil, err
+	}
+	if err := uc.paymentRepo.UpdateStatus(ctx, payment, from); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type mockQRCodeRepo struct {
+	codes map[string]*domain.QRCode
+}
+
+func newMockQRCodeRepo(codes ...*domain.QRCode) *mockQRCodeRepo {
+	r := &mockQRCodeRepo{codes: map[string]*domain.QRCode{}}
+	for _, qr := range codes {
+		r.codes[qr.PaymentID] = qr
+	}
+	return r
+}
+
+func (r *mockQRCodeRepo) Create(ctx context.Context, qr *domain.QRCode) error {
+	cp := *qr
+	r.codes[qr.PaymentID] = &cp
+	return nil
+}
+
+func (r *mockQRCodeRepo) FindByPaymentID(ctx context.Context, paymentID string) (*domain.QRCode, error) {
+	qr, ok := r.codes[paymentID]
+	if !ok {
+		return nil, domain.ErrQRCodeNotFound
+	}
+	return qr, nil
+}
+
+func (r *mockQRCodeRepo) FindByReference(ctx context.Context, reference string) (*domain.QRCode, error) {
+	for _, qr := range r.codes {
+		if qr.Reference == reference {
+			return qr, nil
+		}
+	}
+	return nil, domain.ErrQRCodeNotFound
+}
+
+type fakeQRProvider struct{}
+
+func (fakeQRProvider) IssueQR(ctx context.Context, p *domain.Payment) (*domain.QRCode, error) {
+	if p.Currency != "IDR" {
+		return nil, domain.ErrUnsupportedCurrency
+	}
+	return &domain.QRCode{
+		PaymentID: p.PublicID,
+		Reference: "REF1",
+		Payload:   "000201010212...6304ABCD",
+		ExpiresAt: p.CreatedAt.Add(15 * time.Minute),
+	}, nil
+}
+
+func pendingQR(expiresAt time.Time) (*domain.Payment, *domain.QRCode) {
+	p := &domain.Payment{
+		PublicID:  "pay_qr",
+		Amount:    15000,
+		Currency:  "IDR",
+		Method:    domain.PaymentMethodQR,
+		Status:    domain.PaymentStatusPending,
+		ExpiresAt: &expiresAt,
+	}
+	qr := &domain.QRCode{
+		PaymentID: p.PublicID,
+		Reference: "REF1",
+		Payload:   "000201010212...6304ABCD",
+		ExpiresAt: expiresAt,
+	}
+	return p, qr
+}
+
+func TestCreatePayment_QRIssuesCode(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	codes := newMockQRCodeRepo()
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithQR(fakeQRProvider{}, codes)
+
+	input := CreatePaymentInput{
+		OrderID:        "order_qr",
+		PayerID:        1,
+		Amount:         15000,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         domain.PaymentMethodQR,
+		IdempotencyKey: "idem-qr",
+	}
+	out, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.QRCode == nil || out.NextAction == nil || out.NextAction.Type != domain.NextActionQR {
+		t.Fatalf("expected qr next action, got %+v", out)
+	}
+	if out.NextAction.QRString != out.QRCode.Payload {
+		t.Fatalf("next action must carry the payload")
+	}
+	if _, err := codes.FindByPaymentID(context.Background(), out.PaymentID); err != nil {
+		t.Fatalf("expected qr code to be stored: %v", err)
+	}
+	if out.ExpiresAt == nil || !out.ExpiresAt.Equal(out.QRCode.ExpiresAt) {
+		t.Fatalf("expected payment to expire with the qr code")
+	}
+
+	input.Currency = "EUR"
+	input.IdempotencyKey = "idem-qr-eur"
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, domain.ErrUnsupportedCurrency) {
+		t.Fatalf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+}
+
+func TestHandleQRPayment_ScanCompletesPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, qr := pendingQR(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleQRPaymentUsecase(newMockQRCodeRepo(qr), payments)
+
+	input := HandleQRPaymentInput{Reference: "REF1", Amount: 15000, IssuerReference: "iss_1"}
+	got, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != domain.PaymentStatusSuccess || got.PaidAt == nil {
+		t.Fatalf("expected paid SUCCESS, got %+v", got)
+	}
+	if payments.payments["pay_qr"].Status != domain.PaymentStatusSuccess {
+		t.Fatalf("expected stored payment SUCCESS")
+	}
+
+	// a redelivered notification is accepted
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error on redelivery: %v", err)
+	}
+}
+
+func TestHandleQRPayment_AmountMismatch(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, qr := pendingQR(time.Now().Add(time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleQRPaymentUsecase(newMockQRCodeRepo(qr), payments)
+
+	_, err := uc.Execute(context.Background(), HandleQRPaymentInput{Reference: "REF1", Amount: 14000})
+	if !errors.Is(err, domain.ErrQRAmountMismatch) {
+		t.Fatalf("expected ErrQRAmountMismatch, got %v", err)
+	}
+	if payments.payments["pay_qr"].Status != domain.PaymentStatusPending {
+		t.Fatalf("payment must stay PENDING")
+	}
+}
+
+func TestHandleQRPayment_ExpiredCode(t *testing.T) {
+	observability.InitTracer("test")
+
+	p, qr := pendingQR(time.Now().Add(-time.Minute))
+	payments := newStatefulPaymentRepo(p)
+	uc := NewHandleQRPaymentUsecase(newMockQRCodeRepo(qr), payments)
+
+	_, err := uc.Execute(context.Background(), HandleQRPaymentInput{Reference: "REF1", Amount: 15000})
+	if !errors.Is(err, domain.ErrQRCodeExpired) {
+		t.Fatalf("expected ErrQRCodeExpired, got %v", err)
+	}
+	if payments.payments["pay_qr"].Status != domain.PaymentStatusExpired {
+		t.Fatalf("expected payment EXPIRED, got %s", payments.payments["pay_qr"].Status)
+	}
+}
+
+func TestHandleQRPayment_UnknownReference(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewHandleQRPaymentUsecase(newMockQRCodeRepo(), newStatefulPaymentRepo())
+
+	_, err := uc.Execute(context.Background(), HandleQRPaymentInput{Reference: "NOPE", Amount: 1})
+	if !errors.Is(err, domain.ErrQRCodeNotFound) {
+		t.Fatalf("expected ErrQRCodeNotFound, got %v", err)
+	}
+}
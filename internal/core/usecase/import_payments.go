@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Columns of an imported payment file. The header row names them, in any
+// order; optional columns may be left out.
+var (
+	requiredPaymentFileColumns = []string{
+		"order_id", "payer_id", "amount", "currency", "provider", "idempotency_key",
+	}
+	optionalPaymentFileColumns = []string{
+		"method", "payment_method", "bank_code", "wallet", "channel", "return_url",
+	}
+)
+
+// utf8BOM is written at the start of CSV files saved by spreadsheet tools.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type ImportPaymentsInput struct {
+	FileName string
+	File     io.Reader
+	// ClientIP is the address the file is uploaded from.
+	ClientIP string
+}
+
+// ImportPaymentsUsecase turns an uploaded CSV file into a payment batch,
+// one item per row. Its payments are created in the background by
+// ProcessPaymentBatchesUsecase like those of any batch.
+type ImportPaymentsUsecase struct {
+	batchRepo   ports.PaymentBatchRepository
+	maxRows     int
+	maxFileSize int64
+}
+
+func NewImportPaymentsUsecase(
+	batchRepo ports.PaymentBatchRepository,
+	maxRows int,
+	maxFileSize int64,
+) *ImportPaymentsUsecase {
+	return &ImportPaymentsUsecase{
+		batchRepo:   batchRepo,
+		maxRows:     maxRows,
+		maxFileSize: maxFileSize,
+	}
+}
+
+// Execute validates the file row by row. A file that cannot be read as a
+// whole is rejected; a bad row is stored as a failed item so it shows up
+// in the result with the rest.
+func (uc *ImportPaymentsUsecase) Execute(
+	ctx context.Context,
+	input ImportPaymentsInput,
+) (*domain.PaymentBatch, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ImportPaymentsUseCase.Execute")
+	defer span.End()
+
+	batch, err := uc.parse(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("batch.items", len(batch.Items)),
+		attribute.Int("batch.invalid_rows", batch.Progress()[domain.PaymentBatchItemFailed]),
+	)
+
+	if err := uc.batchRepo.Create(ctx, batch); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (uc *ImportPaymentsUsecase) parse(
+	ctx context.Context,
+	input ImportPaymentsInput,
+) (*domain.PaymentBatch, error) {
+	data, err := io.ReadAll(io.LimitReader(input.File, uc.maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > uc.maxFileSize {
+		return nil, domain.ErrPaymentFileTooLarge
+	}
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.ErrEmptyPaymentBatch
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPaymentFile, err)
+	}
+	columns, err := paymentFileColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := newPaymentBatch(ctx, domain.PaymentBatchSourceCSV, input.ClientIP)
+	batch.FileName = input.FileName
+
+	// row each idempotency key was first used on, counted from 1 after the
+	// header
+	keys := make(map[string]int)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// the rest of the file cannot be split into rows reliably
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPaymentFile, err)
+		}
+		if uc.maxRows > 0 && len(batch.Items) == uc.maxRows {
+			return nil, domain.ErrPaymentBatchTooLarge
+		}
+
+		rowNumber := len(batch.Items) + 1
+		row, rowErr := paymentFileRow(columns, record)
+		if rowErr == nil && row.IdempotencyKey != "" {
+			if first, ok := keys[row.IdempotencyKey]; ok {
+				rowErr = fmt.Errorf("idempotency_key was already used on row %d", first)
+			} else {
+				keys[row.IdempotencyKey] = rowNumber
+			}
+		}
+
+		item := newPaymentBatchItem(len(batch.Items), row)
+		if rowErr != nil {
+			item.Status = domain.PaymentBatchItemFailed
+			item.ErrorCode = "invalid_row"
+			item.Error = rowErr.Error()
+		}
+		batch.Items = append(batch.Items, item)
+	}
+	if len(batch.Items) == 0 {
+		return nil, domain.ErrEmptyPaymentBatch
+	}
+	return batch, nil
+}
+
+// paymentFileColumns maps each known column to its index in header.
+func paymentFileColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(requiredPaymentFileColumns, name) &&
+			!slices.Contains(optionalPaymentFileColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrInvalidPaymentFile, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidPaymentFile, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredPaymentFileColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", domain.ErrInvalidPaymentFile, name)
+		}
+	}
+	if _, ok := columns["method"]; !ok {
+		if _, ok := columns["payment_method"]; !ok {
+			return nil, fmt.Errorf("%w: either a method or a payment_method column is required", domain.ErrInvalidPaymentFile)
+		}
+	}
+	return columns, nil
+}
+
+// paymentFileRow reads the payment in record. What could be read is
+// returned along with the error so the row still identifies itself.
+func paymentFileRow(columns map[string]int, record []string) (CreatePaymentInput, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	input := CreatePaymentInput{
+		OrderID:        field("order_id"),
+		Currency:       field("currency"),
+		Provider:       field("provider"),
+		Method:         field("method"),
+		IdempotencyKey: field("idempotency_key"),
+
+		PaymentMethodToken: field("payment_method"),
+		BankCode:           field("bank_code"),
+		Wallet:             field("wallet"),
+		Channel:            field("channel"),
+		ReturnURL:          field("return_url"),
+	}
+	if len(record) != len(columns) {
+		return input, fmt.Errorf("expected %d fields, got %d", len(columns), len(record))
+	}
+
+	var err error
+	if input.PayerID, err = strconv.Atoi(field("payer_id")); err != nil {
+		return input, errors.New("payer_id must be a number")
+	}
+	if input.Amount, err = strconv.Atoi(field("amount")); err != nil {
+		return input, errors.New("amount must be a whole number")
+	}
+	if input.OrderID == "" {
+		return input, errors.New("order_id is required")
+	}
+	if input.Channel != "" && !slices.Contains([]string{"web", "mobile", "qr"}, input.Channel) {
+		return input, errors.New("channel must be web, mobile or qr")
+	}
+	if valid, err := isValidPaymentInput(input); !valid {
+		return input, err
+	}
+	return input, nil
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+func TestImportPayments_ValidatesRows(t *testing.T) {
+	observability.InitTracer("test")
+
+	file := "\ufeffOrder_ID,payer_id,amount,currency,provider,method,bank_code,idempotency_key\n" +
+		"o1,1,1000,IDR,fake,credit_card,,k1\n" +
+		"o2,x,1000,IDR,fake,credit_card,,k2\n" +
+		"o3,1,1000,IDR,fake,bank_transfer,,k3\n" +
+		"o4,1,1000,IDR,fake,credit_card,,k1\n" +
+		"o5,1,1000,IDR,fake\n" +
+		"\"o6, north\",2,2500,IDR,fake,bank_transfer,BCA,k6\n"
+
+	repo := &mockPaymentBatchRepo{}
+	batch, err := NewImportPaymentsUsecase(repo, 10, 1<<20).Execute(context.Background(), ImportPaymentsInput{
+		FileName: "charges.csv",
+		File:     strings.NewReader(file),
+		ClientIP: "203.0.113.9",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if batch.Source != domain.PaymentBatchSourceCSV || batch.FileName != "charges.csv" || batch.ClientIP != "203.0.113.9" {
+		t.Fatalf("unexpected batch: %+v", batch)
+	}
+
+	want := []struct {
+		status domain.PaymentBatchItemStatus
+		error  string
+	}{
+		{domain.PaymentBatchItemPending, ""},
+		{domain.PaymentBatchItemFailed, "payer_id must be a number"},
+		{domain.PaymentBatchItemFailed, "bank code is required for bank transfers"},
+		{domain.PaymentBatchItemFailed, "idempotency_key was already used on row 1"},
+		{domain.PaymentBatchItemFailed, "expected 8 fields, got 5"},
+		{domain.PaymentBatchItemPending, ""},
+	}
+	if len(batch.Items) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(batch.Items))
+	}
+	for i, w := range want {
+		item := batch.Items[i]
+		if item.Position != i || item.Status != w.status || item.Error != w.error {
+			t.Errorf("row %d: got %d/%s/%q, want %s/%q", i+1, item.Position, item.Status, item.Error, w.status, w.error)
+		}
+		if item.Status == domain.PaymentBatchItemFailed && item.ErrorCode != "invalid_row" {
+			t.Errorf("row %d: expected invalid_row, got %q", i+1, item.ErrorCode)
+		}
+	}
+	if last := batch.Items[5]; last.OrderID != "o6, north" || last.Amount != 2500 || last.BankCode != "BCA" {
+		t.Errorf("unexpected last row: %+v", last)
+	}
+}
+
+func TestImportPayments_RejectsFile(t *testing.T) {
+	observability.InitTracer("test")
+
+	header := "order_id,payer_id,amount,currency,provider,method,idempotency_key\n"
+	row := "o1,1,1000,IDR,fake,credit_card,k1\n"
+	cases := []struct {
+		name string
+		file string
+		want error
+	}{
+		{"empty", "", domain.ErrEmptyPaymentBatch},
+		{"header only", header, domain.ErrEmptyPaymentBatch},
+		{"missing column", "order_id,payer_id,amount,currency,provider,method\n" + row, domain.ErrInvalidPaymentFile},
+		{"unknown column", "order_id,payer_id,amount,currency,provider,method,idempotency_key,notes\n" + row, domain.ErrInvalidPaymentFile},
+		{"no method column", "order_id,payer_id,amount,currency,provider,idempotency_key\n" + row, domain.ErrInvalidPaymentFile},
+		{"broken quoting", header + "\"o1,1,1000\n", domain.ErrInvalidPaymentFile},
+		{"too many rows", header + row + row + row, domain.ErrPaymentBatchTooLarge},
+		{"too large", header + strings.Repeat(row, 20), domain.ErrPaymentFileTooLarge},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := NewImportPaymentsUsecase(&mockPaymentBatchRepo{}, 2, 512)
+			_, err := uc.Execute(context.Background(), ImportPaymentsInput{File: strings.NewReader(tc.file)})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
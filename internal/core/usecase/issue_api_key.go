@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// IssueAPIKeyUsecase gives a merchant an additional key. It is used by
+// admins, for example to give merchants created before API keys existed
+// their first one.
+type IssueAPIKeyUsecase struct {
+	merchantRepo ports.MerchantRepository
+	apiKeyRepo   ports.APIKeyRepository
+}
+
+func NewIssueAPIKeyUsecase(
+	merchantRepo ports.MerchantRepository,
+	apiKeyRepo ports.APIKeyRepository,
+) *IssueAPIKeyUsecase {
+	return &IssueAPIKeyUsecase{
+		merchantRepo: merchantRepo,
+		apiKeyRepo:   apiKeyRepo,
+	}
+}
+
+func (uc *IssueAPIKeyUsecase) Execute(
+	ctx context.Context,
+	merchantID string,
+	mode domain.Mode,
+) (*IssuedAPIKey, error) {
+	ctx, span := observability.Tracer().Start(ctx, "IssueAPIKeyUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("merchant.id", merchantID),
+		attribute.String("api_key.mode", string(mode)),
+	)
+
+	issued, err := uc.issue(ctx, merchantID, mode)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return issued, nil
+}
+
+func (uc *IssueAPIKeyUsecase) issue(
+	ctx context.Context,
+	merchantID string,
+	mode domain.Mode,
+) (*IssuedAPIKey, error) {
+	if !mode.Valid() {
+		return nil, domain.ErrInvalidAPIKeyMode
+	}
+
+	merchant, err := uc.merchantRepo.FindByPublicID(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	return issueAPIKey(ctx, uc.apiKeyRepo, merchant.ID, mode)
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ListAPIKeysUsecase lists the calling merchant's keys, including expired
+// and revoked ones.
+type ListAPIKeysUsecase struct {
+	apiKeyRepo ports.APIKeyRepository
+}
+
+func NewListAPIKeysUsecase(apiKeyRepo ports.APIKeyRepository) *ListAPIKeysUsecase {
+	return &ListAPIKeysUsecase{apiKeyRepo: apiKeyRepo}
+}
+
+func (uc *ListAPIKeysUsecase) Execute(ctx context.Context) ([]*domain.APIKey, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListAPIKeysUseCase.Execute")
+	defer span.End()
+
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		err := domain.ErrMerchantRequired
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	keys, err := uc.apiKeyRepo.ListByMerchantID(ctx, tenant.MerchantID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return keys, nil
+}
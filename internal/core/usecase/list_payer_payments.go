@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ListPayerPaymentsUsecase struct {
+	payerRepo   ports.PayerRepository
+	paymentRepo ports.PaymentRepository
+}
+
+func NewListPayerPaymentsUsecase(
+	payerRepo ports.PayerRepository,
+	paymentRepo ports.PaymentRepository,
+) *ListPayerPaymentsUsecase {
+	return &ListPayerPaymentsUsecase{
+		payerRepo:   payerRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+func (uc *ListPayerPaymentsUsecase) Execute(
+	ctx context.Context,
+	payerID int,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListPayerPaymentsUseCase.Execute")
+	defer span.End()
+
+	// make sure an unknown payer is reported as such instead of an empty list
+	if _, err := uc.payerRepo.FindByID(ctx, payerID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	limit, offset = normalizePage(limit, offset)
+
+	payments, err := uc.paymentRepo.ListByPayerID(ctx, payerID, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payments, nil
+}
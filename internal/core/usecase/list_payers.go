@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ListPayersUsecase struct {
+	payerRepo ports.PayerRepository
+}
+
+func NewListPayersUsecase(
+	payerRepo ports.PayerRepository,
+) *ListPayersUsecase {
+	return &ListPayersUsecase{
+		payerRepo: payerRepo,
+	}
+}
+
+func (uc *ListPayersUsecase) Execute(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListPayersUseCase.Execute")
+	defer span.End()
+
+	limit, offset = normalizePage(limit, offset)
+
+	payers, err := uc.payerRepo.List(ctx, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payers, nil
+}
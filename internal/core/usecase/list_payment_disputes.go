@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ListPaymentDisputesUsecase struct {
+	paymentRepo ports.PaymentRepository
+	disputeRepo ports.DisputeRepository
+}
+
+func NewListPaymentDisputesUsecase(
+	paymentRepo ports.PaymentRepository,
+	disputeRepo ports.DisputeRepository,
+) *ListPaymentDisputesUsecase {
+	return &ListPaymentDisputesUsecase{
+		paymentRepo: paymentRepo,
+		disputeRepo: disputeRepo,
+	}
+}
+
+func (uc *ListPaymentDisputesUsecase) Execute(
+	ctx context.Context,
+	paymentID string,
+) ([]*domain.Dispute, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListPaymentDisputesUseCase.Execute")
+	defer span.End()
+
+	if _, err := uc.paymentRepo.FindbyPublicID(ctx, paymentID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	disputes, err := uc.disputeRepo.ListByPaymentID(ctx, paymentID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return disputes, nil
+}
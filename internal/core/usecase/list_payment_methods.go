@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ListPaymentMethodsUsecase struct {
+	payerRepo         ports.PayerRepository
+	paymentMethodRepo ports.PaymentMethodRepository
+}
+
+func NewListPaymentMethodsUsecase(
+	payerRepo ports.PayerRepository,
+	paymentMethodRepo ports.PaymentMethodRepository,
+) *ListPaymentMethodsUsecase {
+	return &ListPaymentMethodsUsecase{
+		payerRepo:         payerRepo,
+		paymentMethodRepo: paymentMethodRepo,
+	}
+}
+
+func (uc *ListPaymentMethodsUsecase) Execute(
+	ctx context.Context,
+	payerID int,
+) ([]*domain.PaymentMethod, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListPaymentMethodsUseCase.Execute")
+	defer span.End()
+
+	if _, err := uc.payerRepo.FindByID(ctx, payerID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	methods, err := uc.paymentMethodRepo.ListByPayerID(ctx, payerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return methods, nil
+}
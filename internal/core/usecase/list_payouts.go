@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ListPayoutsUsecase struct {
+	payoutRepo ports.PayoutRepository
+}
+
+func NewListPayoutsUsecase(payoutRepo ports.PayoutRepository) *ListPayoutsUsecase {
+	return &ListPayoutsUsecase{payoutRepo: payoutRepo}
+}
+
+// Execute lists payouts, newest first.
+func (uc *ListPayoutsUsecase) Execute(
+	ctx context.Context,
+	limit int,
+	offset int,
+) ([]*domain.Payout, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListPayoutsUseCase.Execute")
+	defer span.End()
+
+	limit, offset = normalizePage(limit, offset)
+
+	payouts, err := uc.payoutRepo.List(ctx, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payouts, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ListPlansUsecase struct {
+	planRepo ports.PlanRepository
+}
+
+func NewListPlansUsecase(planRepo ports.PlanRepository) *ListPlansUsecase {
+	return &ListPlansUsecase{
+		planRepo: planRepo,
+	}
+}
+
+func (uc *ListPlansUsecase) Execute(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Plan, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListPlansUseCase.Execute")
+	defer span.End()
+
+	limit, offset = normalizePage(limit, offset)
+
+	plans, err := uc.planRepo.List(ctx, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return plans, nil
+}
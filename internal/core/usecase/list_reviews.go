@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ListReviewsUsecase lists the manual review queue.
+type ListReviewsUsecase struct {
+	paymentRepo ports.PaymentRepository
+}
+
+func NewListReviewsUsecase(paymentRepo ports.PaymentRepository) *ListReviewsUsecase {
+	return &ListReviewsUsecase{paymentRepo: paymentRepo}
+}
+
+func (uc *ListReviewsUsecase) Execute(
+	ctx context.Context,
+	limit, offset int,
+) ([]*domain.Payment, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ListReviewsUseCase.Execute")
+	defer span.End()
+
+	limit, offset = normalizePage(limit, offset)
+
+	payments, err := uc.paymentRepo.ListAwaitingReview(ctx, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return payments, nil
+}
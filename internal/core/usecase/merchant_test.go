@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type memoryMerchants struct {
+	merchants []*domain.Merchant
+}
+
+func newMemoryMerchants(merchants ...*domain.Merchant) *memoryMerchants {
+	return &memoryMerchants{merchants: merchants}
+}
+
+func (m *memoryMerchants) Create(ctx context.Context, merchant *domain.Merchant) error {
+	merchant.ID = len(m.merchants) + 1
+	merchant.CreatedAt = time.Now()
+	m.merchants = append(m.merchants, merchant)
+	return nil
+}
+
+func (m *memoryMerchants) FindByID(ctx context.Context, id int) (*domain.Merchant, error) {
+	for _, merchant := range m.merchants {
+		if merchant.ID == id {
+			return merchant, nil
+		}
+	}
+	return nil, domain.ErrMerchantNotFound
+}
+
+func (m *memoryMerchants) FindByPublicID(ctx context.Context, publicID string) (*domain.Merchant, error) {
+	for _, merchant := range m.merchants {
+		if merchant.PublicID == publicID {
+			return merchant, nil
+		}
+	}
+	return nil, domain.ErrMerchantNotFound
+}
+
+func (m *memoryMerchants) ListActive(ctx context.Context) ([]*domain.Merchant, error) {
+	var out []*domain.Merchant
+	for _, merchant := range m.merchants {
+		if merchant.Status == domain.MerchantStatusActive {
+			out = append(out, merchant)
+		}
+	}
+	return out, nil
+}
+
+type memoryAPIKeys struct {
+	keys []*domain.APIKey
+}
+
+func (m *memoryAPIKeys) Create(ctx context.Context, key *domain.APIKey) error {
+	key.ID = len(m.keys) + 1
+	key.CreatedAt = time.Now()
+	cp := *key
+	m.keys = append(m.keys, &cp)
+	return nil
+}
+
+func (m *memoryAPIKeys) find(match func(*domain.APIKey) bool) (*domain.APIKey, error) {
+	for _, k := range m.keys {
+		if match(k) {
+			cp := *k
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+func (m *memoryAPIKeys) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return m.find(func(k *domain.APIKey) bool { return k.Hash == hash })
+}
+
+func (m *memoryAPIKeys) FindByID(ctx context.Context, id int) (*domain.APIKey, error) {
+	return m.find(func(k *domain.APIKey) bool { return k.ID == id })
+}
+
+func (m *memoryAPIKeys) ListByMerchantID(ctx context.Context, merchantID int) ([]*domain.APIKey, error) {
+	var out []*domain.APIKey
+	for _, k := range m.keys {
+		if k.MerchantID == merchantID {
+			cp := *k
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryAPIKeys) Expire(ctx context.Context, id int, at time.Time) error {
+	for _, k := range m.keys {
+		if k.ID == id {
+			if k.ExpiresAt == nil || k.ExpiresAt.After(at) {
+				k.ExpiresAt = &at
+			}
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (m *memoryAPIKeys) Revoke(ctx context.Context, id int, at time.Time) error {
+	for _, k := range m.keys {
+		if k.ID == id {
+			k.RevokedAt = &at
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func merchantCtx(merchantID int) context.Context {
+	return domain.WithTenant(context.Background(), domain.Tenant{MerchantID: merchantID, Mode: domain.ModeLive})
+}
+
+func TestCreateMerchant_IssuesLiveAndTestKeys(t *testing.T) {
+	observability.InitTracer("test")
+
+	merchants := newMemoryMerchants()
+	keys := &memoryAPIKeys{}
+	auth := NewAuthenticateAPIKeyUsecase(keys, merchants)
+
+	out, err := NewCreateMerchantUsecase(merchants, keys).Execute(context.Background(), CreateMerchantInput{
+		Name: " Toko Budi ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Merchant.Name != "Toko Budi" || !strings.HasPrefix(out.Merchant.PublicID, "mer_") {
+		t.Fatalf("unexpected merchant: %+v", out.Merchant)
+	}
+	if len(out.Keys) != 2 {
+		t.Fatalf("expected a live and a test key, got %d", len(out.Keys))
+	}
+
+	for _, issued := range out.Keys {
+		if !strings.HasPrefix(issued.Secret, "sk_"+string(issued.Key.Mode)+"_") {
+			t.Fatalf("unexpected secret %q for a %s key", issued.Secret, issued.Key.Mode)
+		}
+		for _, stored := range keys.keys {
+			if stored.Hash == issued.Secret {
+				t.Fatal("the secret must not be stored")
+			}
+		}
+
+		tenant, err := auth.Execute(context.Background(), issued.Secret)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tenant.MerchantID != out.Merchant.ID || tenant.Mode != issued.Key.Mode {
+			t.Fatalf("unexpected tenant %+v for a %s key", tenant, issued.Key.Mode)
+		}
+	}
+
+	if _, err := NewCreateMerchantUsecase(merchants, keys).Execute(context.Background(), CreateMerchantInput{}); !errors.Is(err, domain.ErrMerchantNameRequired) {
+		t.Fatalf("expected ErrMerchantNameRequired, got %v", err)
+	}
+}
+
+func TestAuthenticateAPIKey_RejectsUnusableKeys(t *testing.T) {
+	observability.InitTracer("test")
+
+	merchants := newMemoryMerchants(
+		&domain.Merchant{ID: 1, Status: domain.MerchantStatusActive},
+		&domain.Merchant{ID: 2, Status: domain.MerchantStatusDisabled},
+	)
+	keys := &memoryAPIKeys{}
+	auth := NewAuthenticateAPIKeyUsecase(keys, merchants)
+
+	revoked, _ := issueAPIKey(context.Background(), keys, 1, domain.ModeLive)
+	_ = keys.Revoke(context.Background(), revoked.Key.ID, time.Now())
+	disabled, _ := issueAPIKey(context.Background(), keys, 2, domain.ModeLive)
+
+	for name, secret := range map[string]string{
+		"missing":  "",
+		"unknown":  "sk_live_nope",
+		"revoked":  revoked.Secret,
+		"disabled": disabled.Secret,
+	} {
+		if _, err := auth.Execute(context.Background(), secret); !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Errorf("%s: expected ErrInvalidAPIKey, got %v", name, err)
+		}
+	}
+}
+
+func TestAuthenticateServiceToken_BindsToActiveMerchant(t *testing.T) {
+	observability.InitTracer("test")
+
+	merchants := newMemoryMerchants(
+		&domain.Merchant{ID: 1, PublicID: "mer_a", Status: domain.MerchantStatusActive},
+		&domain.Merchant{ID: 2, PublicID: "mer_b", Status: domain.MerchantStatusDisabled},
+	)
+	auth := NewAuthenticateServiceTokenUsecase(merchants)
+
+	tenant, err := auth.Execute(context.Background(), "mer_a", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant.MerchantID != 1 || tenant.Mode != domain.ModeLive {
+		t.Fatalf("expected live tenant of merchant 1, got %+v", tenant)
+	}
+
+	if _, err := auth.Execute(context.Background(), "", ""); !errors.Is(err, domain.ErrTokenMerchantRequired) {
+		t.Errorf("expected ErrTokenMerchantRequired, got %v", err)
+	}
+	for name, c := range map[string]struct {
+		merchant string
+		mode     domain.Mode
+	}{
+		"unknown":  {"mer_nope", domain.ModeLive},
+		"disabled": {"mer_b", domain.ModeLive},
+		"bad mode": {"mer_a", "staging"},
+	} {
+		if _, err := auth.Execute(context.Background(), c.merchant, c.mode); !errors.Is(err, domain.ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func TestRotateAPIKey_OldKeyWorksDuringGracePeriod(t *testing.T) {
+	observability.InitTracer("test")
+
+	merchants := newMemoryMerchants(&domain.Merchant{ID: 1, Status: domain.MerchantStatusActive})
+	keys := &memoryAPIKeys{}
+	old, _ := issueAPIKey(context.Background(), keys, 1, domain.ModeTest)
+
+	now := time.Now()
+	rotate := NewRotateAPIKeyUsecase(keys, time.Hour)
+	rotate.now = func() time.Time { return now }
+
+	issued, err := rotate.Execute(merchantCtx(1), old.Key.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issued.Key.Mode != domain.ModeTest || issued.Secret == old.Secret {
+		t.Fatalf("expected a new test key, got %+v", issued.Key)
+	}
+
+	auth := NewAuthenticateAPIKeyUsecase(keys, merchants)
+	auth.now = func() time.Time { return now.Add(30 * time.Minute) }
+	if _, err := auth.Execute(context.Background(), old.Secret); err != nil {
+		t.Fatalf("old key must work during the grace period, got %v", err)
+	}
+
+	auth.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := auth.Execute(context.Background(), old.Secret); !errors.Is(err, domain.ErrInvalidAPIKey) {
+		t.Fatalf("expected the old key to expire, got %v", err)
+	}
+	if _, err := auth.Execute(context.Background(), issued.Secret); err != nil {
+		t.Fatalf("unexpected error for the new key: %v", err)
+	}
+}
+
+func TestRotateAPIKey_OnlyOwnKeys(t *testing.T) {
+	observability.InitTracer("test")
+
+	keys := &memoryAPIKeys{}
+	other, _ := issueAPIKey(context.Background(), keys, 2, domain.ModeLive)
+
+	rotate := NewRotateAPIKeyUsecase(keys, time.Hour)
+	if _, err := rotate.Execute(merchantCtx(1), other.Key.ID); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Fatalf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+	if len(keys.keys) != 1 || keys.keys[0].ExpiresAt != nil {
+		t.Fatal("another merchant's key must be left alone")
+	}
+
+	list, err := NewListAPIKeysUsecase(keys).Execute(merchantCtx(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected no keys for merchant 1, got %d", len(list))
+	}
+}
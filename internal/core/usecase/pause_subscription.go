@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type PauseSubscriptionUsecase struct {
+	subscriptionRepo ports.SubscriptionRepository
+}
+
+func NewPauseSubscriptionUsecase(
+	subscriptionRepo ports.SubscriptionRepository,
+) *PauseSubscriptionUsecase {
+	return &PauseSubscriptionUsecase{
+		subscriptionRepo: subscriptionRepo,
+	}
+}
+
+func (uc *PauseSubscriptionUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.Subscription, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PauseSubscriptionUseCase.Execute")
+	defer span.End()
+
+	s, err := uc.subscriptionRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := s.Pause(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := uc.subscriptionRepo.Update(ctx, s); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return s, nil
+}
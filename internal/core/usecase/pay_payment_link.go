@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// cardTokenizer is the part of TokenizeCardUsecase used by hosted checkout.
+type cardTokenizer interface {
+	Execute(ctx context.Context, input TokenizeCardInput) (*domain.PaymentMethod, error)
+}
+
+type PayPaymentLinkInput struct {
+	LinkID string
+	Payer  PayerInput
+	Method string
+	// Card is only used when Method is credit_card.
+	Card TokenizeCardInput
+	// BankCode is the bank the payer transfers from when Method is
+	// bank_transfer.
+	BankCode string
+	// SaveCard is the payer's consent to keep Card for later payments.
+	// Without it the card is detached once the payment was attempted.
+	SaveCard bool
+	// ReturnURL is where the payer comes back to after approving in their
+	// wallet or completing a card challenge.
+	ReturnURL string
+	ClientIP  string
+	// IdempotencyKey is issued with the checkout form so a double submit
+	// does not pay twice.
+	IdempotencyKey string
+}
+
+// PayPaymentLinkUsecase pays a payment link from the hosted checkout page.
+// Checkout visitors are anonymous, so each payment gets a new guest payer.
+type PayPaymentLinkUsecase struct {
+	paymentLinkRepo   ports.PaymentLinkRepository
+	paymentRepo       ports.PaymentRepository
+	payerRepo         ports.PayerRepository
+	paymentMethodRepo ports.PaymentMethodRepository
+	tokenizeCard      cardTokenizer
+	createPayment     paymentCreator
+	provider          string
+	now               func() time.Time
+}
+
+func NewPayPaymentLinkUsecase(
+	paymentLinkRepo ports.PaymentLinkRepository,
+	paymentRepo ports.PaymentRepository,
+	payerRepo ports.PayerRepository,
+	paymentMethodRepo ports.PaymentMethodRepository,
+	tokenizeCard cardTokenizer,
+	createPayment paymentCreator,
+	provider string,
+) *PayPaymentLinkUsecase {
+	return &PayPaymentLinkUsecase{
+		paymentLinkRepo:   paymentLinkRepo,
+		paymentRepo:       paymentRepo,
+		payerRepo:         payerRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		tokenizeCard:      tokenizeCard,
+		createPayment:     createPayment,
+		provider:          provider,
+		now:               time.Now,
+	}
+}
+
+// guestPayer creates the payer for an anonymous checkout. The email is
+// typed by the visitor and proves nothing, so it is never used to find an
+// existing payer whose saved cards the visitor would then share.
+func (uc *PayPaymentLinkUsecase) guestPayer(
+	ctx context.Context,
+	input PayerInput,
+) (*domain.Payer, error) {
+	input = normalizePayerInput(input)
+	if valid, err := isValidPayerInput(input); !valid {
+		return nil, err
+	}
+
+	payer := &domain.Payer{
+		Name:    input.Name,
+		Email:   input.Email,
+		Country: input.Country,
+		Status:  domain.PayerStatusActive,
+	}
+	if err := uc.payerRepo.Create(ctx, payer); err != nil {
+		return nil, err
+	}
+	return payer, nil
+}
+
+func (uc *PayPaymentLinkUsecase) Execute(
+	ctx context.Context,
+	input PayPaymentLinkInput,
+) (*CreatePaymentOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PayPaymentLinkUseCase.Execute")
+	defer span.End()
+
+	output, err := uc.execute(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+func (uc *PayPaymentLinkUsecase) execute(
+	ctx context.Context,
+	input PayPaymentLinkInput,
+) (*CreatePaymentOutput, error) {
+	if input.Method == "" {
+		return nil, errors.New("payment method is required")
+	}
+	if input.IdempotencyKey == "" {
+		return nil, errors.New("idempotency key is required")
+	}
+
+	// a resubmitted form gets the payment it already created, even if that
+	// payment used up the link
+	existing, err := uc.paymentRepo.FindByIdempotencyKey(ctx, input.IdempotencyKey)
+	if err == nil && existing != nil && existing.OrderID == input.LinkID {
+		return &CreatePaymentOutput{
+			PaymentID: existing.PublicID,
+			Status:    existing.Status,
+		}, nil
+	}
+
+	link, err := uc.paymentLinkRepo.FindByPublicID(ctx, input.LinkID)
+	if err != nil {
+		return nil, err
+	}
+	if !link.IsOpen(uc.now()) {
+		return nil, domain.ErrPaymentLinkUnavailable
+	}
+	ctx = actingFor(ctx, link.MerchantID)
+
+	payer, err := uc.guestPayer(ctx, input.Payer)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentInput := CreatePaymentInput{
+		OrderID:        link.PublicID,
+		PayerID:        payer.ID,
+		Amount:         link.Amount,
+		Currency:       link.Currency,
+		Provider:       uc.provider,
+		Method:         input.Method,
+		IdempotencyKey: input.IdempotencyKey,
+		ClientIP:       input.ClientIP,
+	}
+
+	if input.Method == domain.PaymentMethodBankTransfer {
+		paymentInput.BankCode = input.BankCode
+	}
+
+	if input.Method == domain.PaymentMethodEWallet {
+		paymentInput.Channel = domain.EWalletChannelWeb
+		paymentInput.ReturnURL = input.ReturnURL
+	}
+
+	if input.Method == domain.PaymentMethodTypeCard {
+		card := input.Card
+		card.PayerID = payer.ID
+		pm, err := uc.tokenizeCard.Execute(ctx, card)
+		if err != nil {
+			return nil, err
+		}
+		paymentInput.PaymentMethodToken = pm.Token
+		paymentInput.ReturnURL = input.ReturnURL
+
+		if !input.SaveCard {
+			// the payment keeps working with a detached card, including a
+			// 3-D Secure challenge completed later
+			defer uc.detachCard(ctx, pm.Token)
+		}
+	}
+
+	if err := uc.paymentLinkRepo.Claim(ctx, link.PublicID, uc.now()); err != nil {
+		return nil, err
+	}
+
+	output, err := uc.createPayment.Execute(ctx, paymentInput)
+	if err != nil {
+		// give the use back so the payer can try again
+		if releaseErr := uc.paymentLinkRepo.Release(ctx, link.PublicID); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// detachCard drops a card the payer did not agree to save. A failure does
+// not undo the payment, so it is only recorded on the span.
+func (uc *PayPaymentLinkUsecase) detachCard(ctx context.Context, token string) {
+	err := uc.paymentMethodRepo.UpdateStatus(ctx, token, domain.PaymentMethodStatusDetached)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+}
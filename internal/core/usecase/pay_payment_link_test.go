@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// mockPaymentLinkRepo implements ports.PaymentLinkRepository
+type mockPaymentLinkRepo struct {
+	links    map[string]*domain.PaymentLink
+	released int
+}
+
+func newMockPaymentLinkRepo(links ...*domain.PaymentLink) *mockPaymentLinkRepo {
+	m := &mockPaymentLinkRepo{links: map[string]*domain.PaymentLink{}}
+	for _, l := range links {
+		m.links[l.PublicID] = l
+	}
+	return m
+}
+
+func (m *mockPaymentLinkRepo) Create(ctx context.Context, l *domain.PaymentLink) error {
+	m.links[l.PublicID] = l
+	return nil
+}
+
+func (m *mockPaymentLinkRepo) FindByPublicID(ctx context.Context, publicID string) (*domain.PaymentLink, error) {
+	l, ok := m.links[publicID]
+	if !ok {
+		return nil, domain.ErrPaymentLinkNotFound
+	}
+	return l, nil
+}
+
+func (m *mockPaymentLinkRepo) UpdateStatus(ctx context.Context, publicID string, status domain.PaymentLinkStatus) error {
+	l, ok := m.links[publicID]
+	if !ok {
+		return domain.ErrPaymentLinkNotFound
+	}
+	l.Status = status
+	return nil
+}
+
+func (m *mockPaymentLinkRepo) Claim(ctx context.Context, publicID string, now time.Time) error {
+	l, ok := m.links[publicID]
+	if !ok || !l.IsOpen(now) {
+		return domain.ErrPaymentLinkUnavailable
+	}
+	l.UsageCount++
+	if l.SingleUse {
+		l.Status = domain.PaymentLinkStatusCompleted
+	}
+	return nil
+}
+
+func (m *mockPaymentLinkRepo) Release(ctx context.Context, publicID string) error {
+	l := m.links[publicID]
+	l.UsageCount--
+	if l.SingleUse {
+		l.Status = domain.PaymentLinkStatusActive
+	}
+	m.released++
+	return nil
+}
+
+// fakeCardTokenizer returns a fixed saved card for any input
+type fakeCardTokenizer struct {
+	input TokenizeCardInput
+}
+
+func (f *fakeCardTokenizer) Execute(ctx context.Context, input TokenizeCardInput) (*domain.PaymentMethod, error) {
+	f.input = input
+	return &domain.PaymentMethod{Token: "pm_checkout", PayerID: input.PayerID}, nil
+}
+
+func checkoutInput(method string) PayPaymentLinkInput {
+	return PayPaymentLinkInput{
+		LinkID: "plink_1",
+		Payer: PayerInput{
+			Name:    "Jane",
+			Email:   "jane@example.com",
+			Country: "ID",
+		},
+		Method:         method,
+		IdempotencyKey: "checkout_1",
+	}
+}
+
+func TestPayPaymentLink_SingleUse(t *testing.T) {
+	observability.InitTracer("test")
+
+	link := &domain.PaymentLink{
+		PublicID:  "plink_1",
+		Amount:    2500,
+		Currency:  "IDR",
+		SingleUse: true,
+		Status:    domain.PaymentLinkStatusActive,
+	}
+	links := newMockPaymentLinkRepo(link)
+	payers := newMockPayerRepo()
+	creator := &fakePaymentCreator{}
+
+	uc := NewPayPaymentLinkUsecase(links, &mockPaymentRepo{}, payers, newMockPaymentMethodRepo(), &fakeCardTokenizer{}, creator, "fake")
+
+	if _, err := uc.Execute(context.Background(), checkoutInput("ewallet")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payers.payers) != 1 {
+		t.Fatalf("expected payer to be created on the fly")
+	}
+	got := creator.inputs[0]
+	if got.Amount != 2500 || got.Currency != "IDR" || got.OrderID != "plink_1" {
+		t.Fatalf("payment must use the link's amount and reference, got %+v", got)
+	}
+	if link.Status != domain.PaymentLinkStatusCompleted {
+		t.Fatalf("expected single-use link to be completed, got %s", link.Status)
+	}
+
+	second := checkoutInput("ewallet")
+	second.IdempotencyKey = "checkout_2"
+	if _, err := uc.Execute(context.Background(), second); !errors.Is(err, domain.ErrPaymentLinkUnavailable) {
+		t.Fatalf("expected ErrPaymentLinkUnavailable, got %v", err)
+	}
+}
+
+func TestPayPaymentLink_ReleasesOnFailure(t *testing.T) {
+	observability.InitTracer("test")
+
+	link := &domain.PaymentLink{
+		PublicID:  "plink_1",
+		Amount:    100,
+		Currency:  "IDR",
+		SingleUse: true,
+		Status:    domain.PaymentLinkStatusActive,
+	}
+	links := newMockPaymentLinkRepo(link)
+	creator := &fakePaymentCreator{err: errors.New("provider failure")}
+
+	uc := NewPayPaymentLinkUsecase(links, &mockPaymentRepo{}, newMockPayerRepo(), newMockPaymentMethodRepo(), &fakeCardTokenizer{}, creator, "fake")
+
+	if _, err := uc.Execute(context.Background(), checkoutInput("ewallet")); err == nil {
+		t.Fatalf("expected provider error")
+	}
+	if links.released != 1 || link.Status != domain.PaymentLinkStatusActive {
+		t.Fatalf("expected link to be released, status=%s released=%d", link.Status, links.released)
+	}
+}
+
+func TestPayPaymentLink_CardIsTokenized(t *testing.T) {
+	observability.InitTracer("test")
+
+	// the visitor types the email of a payer that already exists
+	existing := &domain.Payer{ID: 9, Email: "jane@example.com", Status: domain.PayerStatusActive}
+
+	for _, save := range []bool{false, true} {
+		link := &domain.PaymentLink{
+			PublicID: "plink_1",
+			Amount:   100,
+			Currency: "IDR",
+			Status:   domain.PaymentLinkStatusActive,
+		}
+		payers := newMockPayerRepo(existing)
+		methods := newMockPaymentMethodRepo(&domain.PaymentMethod{
+			Token:  "pm_checkout",
+			Status: domain.PaymentMethodStatusActive,
+		})
+		tokenizer := &fakeCardTokenizer{}
+		creator := &fakePaymentCreator{}
+
+		uc := NewPayPaymentLinkUsecase(newMockPaymentLinkRepo(link), &mockPaymentRepo{}, payers, methods, tokenizer, creator, "fake")
+
+		input := checkoutInput(domain.PaymentMethodTypeCard)
+		input.Card = TokenizeCardInput{Number: "4242424242424242", ExpMonth: 1, ExpYear: 2099, CVC: "123"}
+		input.SaveCard = save
+
+		if _, err := uc.Execute(context.Background(), input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tokenizer.input.PayerID == existing.ID {
+			t.Fatalf("expected card to be saved for a guest payer, not the existing one")
+		}
+		if payers.payers[tokenizer.input.PayerID] == nil {
+			t.Fatalf("expected a guest payer to be created")
+		}
+		if creator.inputs[0].PaymentMethodToken != "pm_checkout" {
+			t.Fatalf("expected payment to use the saved card token")
+		}
+
+		want := domain.PaymentMethodStatusDetached
+		if save {
+			want = domain.PaymentMethodStatusActive
+		}
+		if got := methods.methods["pm_checkout"].Status; got != want {
+			t.Errorf("save=%v: expected card to be %s, got %s", save, want, got)
+		}
+	}
+}
+
+func TestPayPaymentLink_BankTransfer(t *testing.T) {
+	observability.InitTracer("test")
+
+	link := &domain.PaymentLink{
+		PublicID: "plink_1",
+		Amount:   100,
+		Currency: "IDR",
+		Status:   domain.PaymentLinkStatusActive,
+	}
+	creator := &fakePaymentCreator{}
+
+	uc := NewPayPaymentLinkUsecase(newMockPaymentLinkRepo(link), &mockPaymentRepo{}, newMockPayerRepo(), newMockPaymentMethodRepo(), &fakeCardTokenizer{}, creator, "fake")
+
+	input := checkoutInput(domain.PaymentMethodBankTransfer)
+	input.BankCode = "BCA"
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := creator.inputs[0]; got.Method != domain.PaymentMethodBankTransfer || got.BankCode != "BCA" {
+		t.Fatalf("expected a BCA bank transfer, got %+v", got)
+	}
+}
+
+func TestPayPaymentLink_Expired(t *testing.T) {
+	observability.InitTracer("test")
+
+	past := time.Now().Add(-time.Minute)
+	link := &domain.PaymentLink{
+		PublicID:  "plink_1",
+		Amount:    100,
+		Currency:  "IDR",
+		Status:    domain.PaymentLinkStatusActive,
+		ExpiresAt: &past,
+	}
+
+	uc := NewPayPaymentLinkUsecase(newMockPaymentLinkRepo(link), &mockPaymentRepo{}, newMockPayerRepo(), newMockPaymentMethodRepo(), &fakeCardTokenizer{}, &fakePaymentCreator{}, "fake")
+
+	if _, err := uc.Execute(context.Background(), checkoutInput("ewallet")); !errors.Is(err, domain.ErrPaymentLinkUnavailable) {
+		t.Fatalf("expected ErrPaymentLinkUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+)
+
+// memoryPayouts keeps payouts in memory and checks them against a fixed
+// settled amount per currency.
+type memoryPayouts struct {
+	settled map[string]int
+	payouts []*domain.Payout
+}
+
+func (m *memoryPayouts) Balances(ctx context.Context, settledBefore time.Time) ([]domain.Balance, error) {
+	var out []domain.Balance
+	for currency, amount := range m.settled {
+		b := domain.Balance{Currency: currency, Available: amount}
+		for _, p := range m.payouts {
+			if p.Currency != currency || p.Status == domain.PayoutStatusFailed {
+				continue
+			}
+			b.Available -= p.Amount
+			if !p.Status.IsFinal() {
+				b.InTransit += p.Amount
+			}
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (m *memoryPayouts) CreateWithinBalance(ctx context.Context, p *domain.Payout, settledBefore time.Time) error {
+	balances, _ := m.Balances(ctx, settledBefore)
+	for _, b := range balances {
+		if b.Currency == p.Currency && b.Available >= p.Amount {
+			p.ID = len(m.payouts) + 1
+			p.CreatedAt = time.Now()
+			cp := *p
+			m.payouts = append(m.payouts, &cp)
+			return nil
+		}
+	}
+	return domain.ErrInsufficientBalance
+}
+
+func (m *memoryPayouts) FindByPublicID(ctx context.Context, publicID string) (*domain.Payout, error) {
+	for _, p := range m.payouts {
+		if p.PublicID == publicID {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrPayoutNotFound
+}
+
+func (m *memoryPayouts) List(ctx context.Context, limit, offset int) ([]*domain.Payout, error) {
+	return m.payouts, nil
+}
+
+func (m *memoryPayouts) ListByStatus(ctx context.Context, status domain.PayoutStatus, limit int) ([]*domain.Payout, error) {
+	var out []*domain.Payout
+	for _, p := range m.payouts {
+		if p.Status == status {
+			cp := *p
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryPayouts) LastScheduled(ctx context.Context, currency string) (*domain.Payout, error) {
+	for i := len(m.payouts) - 1; i >= 0; i-- {
+		p := m.payouts[i]
+		if p.Currency == currency && p.Trigger == domain.PayoutTriggerScheduled {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrPayoutNotFound
+}
+
+func (m *memoryPayouts) UpdateStatus(ctx context.Context, p *domain.Payout, from domain.PayoutStatus) error {
+	for i, stored := range m.payouts {
+		if stored.ID == p.ID {
+			if stored.Status != from {
+				return domain.ErrPayoutStatusConflict
+			}
+			cp := *p
+			m.payouts[i] = &cp
+			return nil
+		}
+	}
+	return domain.ErrPayoutNotFound
+}
+
+type fakePayoutProvider struct {
+	results map[string]ports.PayoutResult
+}
+
+func (p *fakePayoutProvider) Send(ctx context.Context, payout *domain.Payout) (string, error) {
+	return "tr_" + payout.PublicID, nil
+}
+
+func (p *fakePayoutProvider) Status(ctx context.Context, reference string) (ports.PayoutResult, error) {
+	if r, ok := p.results[reference]; ok {
+		return r, nil
+	}
+	return ports.PayoutResult{Status: domain.PayoutStatusInTransit}, nil
+}
+
+func testPayoutPolicy(schedule domain.PayoutSchedule) domain.PayoutPolicy {
+	return domain.PayoutPolicy{
+		Schedule: schedule,
+		Weekday:  time.Monday,
+		Minimums: map[string]int{"IDR": 100000, "SGD": 10},
+	}
+}
+
+func TestPayoutPolicy_Due(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule domain.PayoutSchedule
+		last     time.Time
+		want     bool
+	}{
+		{"daily first", domain.PayoutScheduleDaily, time.Time{}, true},
+		{"daily yesterday", domain.PayoutScheduleDaily, now.Add(-24 * time.Hour), true},
+		{"daily today", domain.PayoutScheduleDaily, now.Add(-time.Hour), false},
+		{"weekly last week", domain.PayoutScheduleWeekly, now.AddDate(0, 0, -3), true},
+		{"weekly this monday", domain.PayoutScheduleWeekly, now.AddDate(0, 0, -2), false},
+		{"manual", domain.PayoutScheduleManual, time.Time{}, false},
+	}
+	for _, tc := range cases {
+		if got := testPayoutPolicy(tc.schedule).Due(tc.last, now); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestCreatePayout_ChecksMinimumAndBalance(t *testing.T) {
+	observability.InitTracer("test")
+
+	store := &memoryPayouts{settled: map[string]int{"IDR": 500000}}
+	merchants := newMemoryMerchants(&domain.Merchant{ID: 1, Status: domain.MerchantStatusActive})
+	uc := NewCreatePayoutUsecase(store, store, merchants, testPayoutPolicy(domain.PayoutScheduleManual), "BCA-1")
+	ctx := merchantCtx(1)
+
+	if _, err := uc.Execute(context.Background(), CreatePayoutInput{Currency: "IDR", Amount: 200000}); !errors.Is(err, domain.ErrMerchantRequired) {
+		t.Fatalf("expected ErrMerchantRequired without a merchant, got %v", err)
+	}
+
+	_, err := uc.Execute(ctx, CreatePayoutInput{Currency: "IDR", Amount: 50000})
+	if !errors.Is(err, domain.ErrPayoutBelowMinimum) {
+		t.Fatalf("expected ErrPayoutBelowMinimum, got %v", err)
+	}
+
+	_, err = uc.Execute(ctx, CreatePayoutInput{Currency: "IDR", Amount: 600000})
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+
+	first, err := uc.Execute(ctx, CreatePayoutInput{Currency: "IDR", Amount: 200000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Status != domain.PayoutStatusPending || first.Trigger != domain.PayoutTriggerManual {
+		t.Fatalf("unexpected payout: %+v", first)
+	}
+
+	rest, err := uc.Execute(ctx, CreatePayoutInput{Currency: "IDR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rest.Amount != 300000 {
+		t.Fatalf("expected the remaining 300000 to be paid out, got %d", rest.Amount)
+	}
+
+	_, err = uc.Execute(ctx, CreatePayoutInput{Currency: "IDR"})
+	if !errors.Is(err, domain.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance on an empty balance, got %v", err)
+	}
+}
+
+func TestRunPayouts_SchedulesOncePerPeriod(t *testing.T) {
+	observability.InitTracer("test")
+
+	store := &memoryPayouts{settled: map[string]int{"IDR": 250000, "SGD": 5}}
+	provider := &fakePayoutProvider{results: map[string]ports.PayoutResult{}}
+	merchants := newMemoryMerchants(&domain.Merchant{ID: 1, Status: domain.MerchantStatusActive})
+	uc := NewRunPayoutsUsecase(store, store, merchants, provider, testPayoutPolicy(domain.PayoutScheduleDaily), "BCA-1", 10)
+
+	now := time.Now()
+	out, err := uc.Execute(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Scheduled != 1 || out.Sent != 1 {
+		t.Fatalf("expected one payout scheduled and sent, got %+v", out)
+	}
+	if len(store.payouts) != 1 || store.payouts[0].Amount != 250000 || store.payouts[0].Status != domain.PayoutStatusInTransit {
+		t.Fatalf("unexpected payouts: %+v", store.payouts)
+	}
+
+	store.settled["IDR"] = 400000
+	out, err = uc.Execute(context.Background(), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Scheduled != 0 {
+		t.Fatalf("expected no second payout in the same day, got %+v", out)
+	}
+}
+
+func TestRunPayouts_TracksOutcome(t *testing.T) {
+	observability.InitTracer("test")
+
+	store := &memoryPayouts{settled: map[string]int{"IDR": 1000000}}
+	provider := &fakePayoutProvider{results: map[string]ports.PayoutResult{}}
+	merchants := newMemoryMerchants(&domain.Merchant{ID: 1, Status: domain.MerchantStatusActive})
+	create := NewCreatePayoutUsecase(store, store, merchants, testPayoutPolicy(domain.PayoutScheduleManual), "BCA-1")
+	run := NewRunPayoutsUsecase(store, store, merchants, provider, testPayoutPolicy(domain.PayoutScheduleManual), "BCA-1", 10)
+
+	paid, _ := create.Execute(merchantCtx(1), CreatePayoutInput{Currency: "IDR", Amount: 300000})
+	bounced, _ := create.Execute(merchantCtx(1), CreatePayoutInput{Currency: "IDR", Amount: 200000})
+
+	if _, err := run.Execute(context.Background(), time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	provider.results["tr_"+paid.PublicID] = ports.PayoutResult{Status: domain.PayoutStatusPaid}
+	provider.results["tr_"+bounced.PublicID] = ports.PayoutResult{
+		Status:        domain.PayoutStatusFailed,
+		FailureReason: "account closed",
+	}
+
+	out, err := run.Execute(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Paid != 1 || out.Failed != 1 {
+		t.Fatalf("expected one paid and one failed, got %+v", out)
+	}
+
+	balances, _ := store.Balances(context.Background(), time.Now())
+	if balances[0].Available != 700000 || balances[0].InTransit != 0 {
+		t.Fatalf("failed payout must return to the available balance, got %+v", balances[0])
+	}
+
+	got, _ := store.FindByPublicID(context.Background(), bounced.PublicID)
+	if got.FailureReason != "account closed" || got.CompletedAt == nil {
+		t.Fatalf("unexpected failed payout: %+v", got)
+	}
+}
+
+func TestRunPayouts_PaysEachMerchantToItsDestination(t *testing.T) {
+	observability.InitTracer("test")
+
+	store := &memoryPayouts{settled: map[string]int{"IDR": 250000}}
+	provider := &fakePayoutProvider{results: map[string]ports.PayoutResult{}}
+	merchants := newMemoryMerchants(&domain.Merchant{
+		ID:                1,
+		Status:            domain.MerchantStatusActive,
+		PayoutDestination: "MANDIRI-77",
+	})
+	uc := NewRunPayoutsUsecase(store, store, merchants, provider, testPayoutPolicy(domain.PayoutScheduleDaily), "BCA-1", 10)
+
+	if _, err := uc.Execute(context.Background(), time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.payouts) != 1 || store.payouts[0].Destination != "MANDIRI-77" {
+		t.Fatalf("expected a payout to the merchant's account, got %+v", store.payouts)
+	}
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// maxBatchItemAttempts is how many runs an item is tried for before an
+// unexpected error fails it.
+const maxBatchItemAttempts = 3
+
+// batchErrorCodes are the error codes reported for items the payment could
+// not be created for.
+var batchErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{domain.ErrPayerNotFound, "payer_not_found"},
+	{domain.ErrPayerNotActive, "payer_not_active"},
+	{domain.ErrPaymentMethodNotFound, "payment_method_not_found"},
+	{domain.ErrPaymentMethodInactive, "payment_method_inactive"},
+	{domain.ErrPaymentMethodNotOwned, "payment_method_not_owned"},
+	{domain.ErrCardExpired, "card_expired"},
+	{domain.ErrCardAuthenticationFailed, "authentication_failed"},
+	{domain.ErrLimitExceeded, "limit_exceeded"},
+	{domain.ErrInvalidSplits, "invalid_splits"},
+	{domain.ErrPaymentBlocked, "payment_blocked"},
+	{domain.ErrInvalidReturnURL, "invalid_return_url"},
+	{domain.ErrUnsupportedBank, "unsupported_bank"},
+	{domain.ErrUnsupportedWallet, "unsupported_wallet"},
+	{domain.ErrUnsupportedCurrency, "unsupported_currency"},
+}
+
+// batchErrorCode returns the error code for err, or "" when err is not one
+// the payment would fail with again.
+func batchErrorCode(err error) string {
+	for _, c := range batchErrorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+type ProcessPaymentBatchesOutput struct {
+	Created   int
+	Duplicate int
+	Failed    int
+	// Retrying counts items left pending after an unexpected error.
+	Retrying  int
+	Completed int
+}
+
+// ProcessPaymentBatchesUsecase creates the payments of submitted batches. A
+// batch's items are created concurrently, at most concurrency at a time.
+type ProcessPaymentBatchesUsecase struct {
+	batchRepo     ports.PaymentBatchRepository
+	createPayment paymentCreator
+	concurrency   int
+	batchSize     int
+}
+
+func NewProcessPaymentBatchesUsecase(
+	batchRepo ports.PaymentBatchRepository,
+	createPayment paymentCreator,
+	concurrency int,
+) *ProcessPaymentBatchesUsecase {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &ProcessPaymentBatchesUsecase{
+		batchRepo:     batchRepo,
+		createPayment: createPayment,
+		concurrency:   concurrency,
+		batchSize:     10,
+	}
+}
+
+func (uc *ProcessPaymentBatchesUsecase) Execute(
+	ctx context.Context,
+	now time.Time,
+) (*ProcessPaymentBatchesOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ProcessPaymentBatchesUseCase.Execute")
+	defer span.End()
+
+	out := &ProcessPaymentBatchesOutput{}
+	batches, err := uc.batchRepo.ListProcessing(ctx, uc.batchSize)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return out, err
+	}
+
+	var errs []error
+	for _, batch := range batches {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := uc.process(ctx, batch, now, out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("batches.completed", out.Completed),
+		attribute.Int("batch_items.created", out.Created),
+		attribute.Int("batch_items.duplicate", out.Duplicate),
+		attribute.Int("batch_items.failed", out.Failed),
+		attribute.Int("batch_items.retrying", out.Retrying),
+	)
+
+	if err := errors.Join(errs...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return out, err
+	}
+	return out, nil
+}
+
+// process creates the batch's pending items and completes the batch once
+// none is left. Items are created on behalf of whoever submitted the batch.
+// When ctx is done no further item is started; the rest stay pending.
+func (uc *ProcessPaymentBatchesUsecase) process(
+	ctx context.Context,
+	batch *domain.PaymentBatch,
+	now time.Time,
+	out *ProcessPaymentBatchesOutput,
+) error {
+	ctx = domain.WithTenant(ctx, domain.Tenant{
+		MerchantID: batch.MerchantID,
+		Mode:       batch.Mode,
+	})
+	ctx = domain.WithPrincipal(ctx, domain.Principal{Subject: batch.CreatedBy})
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, uc.concurrency)
+
+launch:
+	for i := range batch.Items {
+		select {
+		case <-ctx.Done():
+			break launch
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(item *domain.PaymentBatchItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			uc.createItem(ctx, item, batch.ClientIP)
+			err := uc.batchRepo.UpdateItem(ctx, item)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			switch item.Status {
+			case domain.PaymentBatchItemCreated:
+				out.Created++
+			case domain.PaymentBatchItemDuplicate:
+				out.Duplicate++
+			case domain.PaymentBatchItemFailed:
+				out.Failed++
+			default:
+				out.Retrying++
+			}
+		}(&batch.Items[i])
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if err := uc.batchRepo.Complete(ctx, batch, now); err != nil {
+		return err
+	}
+	if batch.Status == domain.PaymentBatchStatusCompleted {
+		out.Completed++
+	}
+	return nil
+}
+
+// createItem creates the item's payment and records the outcome on item.
+// An unexpected error, such as the database being unavailable, leaves it
+// pending to be tried again; the idempotency key makes that safe.
+func (uc *ProcessPaymentBatchesUsecase) createItem(
+	ctx context.Context,
+	item *domain.PaymentBatchItem,
+	clientIP string,
+) {
+	input := CreatePaymentInput{
+		OrderID:            item.OrderID,
+		PayerID:            item.PayerID,
+		Amount:             item.Amount,
+		Currency:           item.Currency,
+		Provider:           item.Provider,
+		Method:             item.Method,
+		IdempotencyKey:     item.IdempotencyKey,
+		PaymentMethodToken: item.PaymentMethodToken,
+		BankCode:           item.BankCode,
+		Wallet:             item.Wallet,
+		Channel:            item.Channel,
+		ReturnURL:          item.ReturnURL,
+		ClientIP:           clientIP,
+		Splits:             item.Splits,
+	}
+	if valid, err := isValidPaymentInput(input); !valid {
+		code := batchErrorCode(err)
+		if code == "" {
+			code = "invalid_request"
+		}
+		item.Status = domain.PaymentBatchItemFailed
+		item.ErrorCode = code
+		item.Error = err.Error()
+		return
+	}
+
+	output, err := uc.createPayment.Execute(ctx, input)
+	if err != nil {
+		item.Error = err.Error()
+		if code := batchErrorCode(err); code != "" {
+			item.Status = domain.PaymentBatchItemFailed
+			item.ErrorCode = code
+			return
+		}
+		item.Attempts++
+		if item.Attempts >= maxBatchItemAttempts {
+			item.Status = domain.PaymentBatchItemFailed
+			item.ErrorCode = "processing_error"
+		}
+		return
+	}
+
+	item.Status = domain.PaymentBatchItemCreated
+	if output.Replayed {
+		item.Status = domain.PaymentBatchItemDuplicate
+	}
+	item.PaymentID = output.PaymentID
+	item.PaymentStatus = output.Status
+	item.ErrorCode = ""
+	item.Error = ""
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// mockPaymentBatchRepo keeps batches in memory
+type mockPaymentBatchRepo struct {
+	mu      sync.Mutex
+	batches []*domain.PaymentBatch
+}
+
+func (r *mockPaymentBatchRepo) Create(ctx context.Context, b *domain.PaymentBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b.ID = len(r.batches) + 1
+	for i := range b.Items {
+		b.Items[i].ID = i + 1
+		b.Items[i].BatchID = b.ID
+	}
+	r.batches = append(r.batches, b)
+	return nil
+}
+
+func (r *mockPaymentBatchRepo) FindByPublicID(ctx context.Context, publicID string) (*domain.PaymentBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.batches {
+		if b.PublicID == publicID {
+			cp := *b
+			cp.Items = append([]domain.PaymentBatchItem(nil), b.Items...)
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrPaymentBatchNotFound
+}
+
+func (r *mockPaymentBatchRepo) ListProcessing(ctx context.Context, limit int) ([]*domain.PaymentBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.PaymentBatch
+	for _, b := range r.batches {
+		if b.Status != domain.PaymentBatchStatusProcessing {
+			continue
+		}
+		cp := *b
+		cp.Items = nil
+		for _, item := range b.Items {
+			if item.Status == domain.PaymentBatchItemPending {
+				cp.Items = append(cp.Items, item)
+			}
+		}
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (r *mockPaymentBatchRepo) UpdateItem(ctx context.Context, item *domain.PaymentBatchItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.batches[item.BatchID-1]
+	b.Items[item.ID-1] = *item
+	return nil
+}
+
+func (r *mockPaymentBatchRepo) Complete(ctx context.Context, batch *domain.PaymentBatch, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.batches[batch.ID-1]
+	if b.Progress()[domain.PaymentBatchItemPending] > 0 {
+		return nil
+	}
+	b.Status = domain.PaymentBatchStatusCompleted
+	b.CompletedAt = &now
+	batch.Status = b.Status
+	return nil
+}
+
+// batchPaymentCreator answers by payer id and tracks concurrent calls
+type batchPaymentCreator struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	subjects    []string
+	clientIPs   []string
+	delay       time.Duration
+}
+
+func (c *batchPaymentCreator) Execute(ctx context.Context, input CreatePaymentInput) (*CreatePaymentOutput, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		c.subjects = append(c.subjects, p.Subject)
+	}
+	c.clientIPs = append(c.clientIPs, input.ClientIP)
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	switch input.PayerID {
+	case 404:
+		return nil, domain.ErrPayerNotFound
+	case 500:
+		return nil, errors.New("database is locked")
+	}
+	return &CreatePaymentOutput{
+		PaymentID: "pay_" + input.IdempotencyKey,
+		Status:    domain.PaymentStatusPending,
+		Replayed:  input.PayerID == 409,
+	}, nil
+}
+
+func batchInput(key string, payerID int) CreatePaymentInput {
+	return CreatePaymentInput{
+		OrderID:        "order_" + key,
+		PayerID:        payerID,
+		Amount:         1000,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         "credit_card",
+		IdempotencyKey: key,
+	}
+}
+
+func submitBatch(t *testing.T, repo *mockPaymentBatchRepo, items ...CreatePaymentInput) *domain.PaymentBatch {
+	t.Helper()
+	ctx := domain.WithTenant(context.Background(), domain.Tenant{MerchantID: 7, Mode: domain.ModeLive})
+	ctx = domain.WithPrincipal(ctx, domain.Principal{Subject: "payroll"})
+
+	batch, err := NewCreatePaymentBatchUsecase(repo, 100).Execute(ctx, CreatePaymentBatchInput{
+		Items:    items,
+		ClientIP: "203.0.113.9",
+	})
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	return batch
+}
+
+func TestProcessPaymentBatches_ItemResults(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := &mockPaymentBatchRepo{}
+	batch := submitBatch(t, repo,
+		batchInput("a", 1),
+		batchInput("b", 409),
+		batchInput("c", 404),
+		batchInput("d", 0),
+		batchInput("e", 500),
+	)
+	creator := &batchPaymentCreator{}
+	uc := NewProcessPaymentBatchesUsecase(repo, creator, 2)
+
+	out, err := uc.Execute(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Created != 1 || out.Duplicate != 1 || out.Failed != 2 || out.Retrying != 1 || out.Completed != 0 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+
+	got, _ := repo.FindByPublicID(context.Background(), batch.PublicID)
+	want := []struct {
+		status domain.PaymentBatchItemStatus
+		code   string
+	}{
+		{domain.PaymentBatchItemCreated, ""},
+		{domain.PaymentBatchItemDuplicate, ""},
+		{domain.PaymentBatchItemFailed, "payer_not_found"},
+		{domain.PaymentBatchItemFailed, "invalid_request"},
+		{domain.PaymentBatchItemPending, ""},
+	}
+	for i, w := range want {
+		item := got.Items[i]
+		if item.Status != w.status || item.ErrorCode != w.code {
+			t.Errorf("item %d: got %s/%q, want %s/%q", i, item.Status, item.ErrorCode, w.status, w.code)
+		}
+	}
+	if got.Items[1].PaymentID != "pay_b" {
+		t.Errorf("duplicate should carry the original payment, got %q", got.Items[1].PaymentID)
+	}
+	for _, s := range creator.subjects {
+		if s != "payroll" {
+			t.Errorf("payment created by %q, want the batch's submitter", s)
+		}
+	}
+	// the risk engine sees where the batch was submitted from
+	for _, ip := range creator.clientIPs {
+		if ip != "203.0.113.9" {
+			t.Errorf("payment created from %q, want the batch's client ip", ip)
+		}
+	}
+
+	// the unexpected error is retried until it fails the item
+	for range maxBatchItemAttempts - 1 {
+		if _, err := uc.Execute(context.Background(), time.Now()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	got, _ = repo.FindByPublicID(context.Background(), batch.PublicID)
+	if got.Status != domain.PaymentBatchStatusCompleted {
+		t.Fatalf("expected completed batch, got %s", got.Status)
+	}
+	if item := got.Items[4]; item.Status != domain.PaymentBatchItemFailed || item.ErrorCode != "processing_error" {
+		t.Fatalf("expected processing_error, got %s/%q", item.Status, item.ErrorCode)
+	}
+}
+
+func TestProcessPaymentBatches_BoundedConcurrency(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := &mockPaymentBatchRepo{}
+	var items []CreatePaymentInput
+	for i := range 20 {
+		items = append(items, batchInput(string(rune('a'+i)), 1))
+	}
+	submitBatch(t, repo, items...)
+
+	creator := &batchPaymentCreator{delay: 5 * time.Millisecond}
+	out, err := NewProcessPaymentBatchesUsecase(repo, creator, 3).Execute(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Created != 20 || out.Completed != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if creator.maxInFlight > 3 {
+		t.Fatalf("expected at most 3 payments at once, got %d", creator.maxInFlight)
+	}
+	if creator.maxInFlight < 2 {
+		t.Fatalf("expected payments to be created concurrently, got %d", creator.maxInFlight)
+	}
+}
+
+func TestCreatePaymentBatch_Rejects(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewCreatePaymentBatchUsecase(&mockPaymentBatchRepo{}, 2)
+	cases := []struct {
+		name  string
+		items []CreatePaymentInput
+		want  error
+	}{
+		{"empty", nil, domain.ErrEmptyPaymentBatch},
+		{"too large", []CreatePaymentInput{batchInput("a", 1), batchInput("b", 1), batchInput("c", 1)}, domain.ErrPaymentBatchTooLarge},
+		{"duplicate keys", []CreatePaymentInput{batchInput("a", 1), batchInput("a", 2)}, domain.ErrDuplicateBatchItemKeys},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := uc.Execute(context.Background(), CreatePaymentBatchInput{Items: tc.items}); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ResumeSubscriptionUsecase struct {
+	subscriptionRepo ports.SubscriptionRepository
+	now              func() time.Time
+}
+
+func NewResumeSubscriptionUsecase(
+	subscriptionRepo ports.SubscriptionRepository,
+) *ResumeSubscriptionUsecase {
+	return &ResumeSubscriptionUsecase{
+		subscriptionRepo: subscriptionRepo,
+		now:              time.Now,
+	}
+}
+
+func (uc *ResumeSubscriptionUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+) (*domain.Subscription, error) {
+	ctx, span := observability.Tracer().Start(ctx, "ResumeSubscriptionUseCase.Execute")
+	defer span.End()
+
+	s, err := uc.subscriptionRepo.FindByPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := s.Resume(uc.now()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := uc.subscriptionRepo.Update(ctx, s); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type mockReviewRepo struct {
+	reviews []*domain.PaymentReview
+}
+
+func (r *mockReviewRepo) Create(ctx context.Context, review *domain.PaymentReview) error {
+	review.ID = len(r.reviews) + 1
+	review.CreatedAt = time.Now()
+	r.reviews = append(r.reviews, review)
+	return nil
+}
+
+func (r *mockReviewRepo) ListByPaymentID(ctx context.Context, paymentID string) ([]*domain.PaymentReview, error) {
+	var out []*domain.PaymentReview
+	for _, review := range r.reviews {
+		if review.PaymentID == paymentID {
+			out = append(out, review)
+		}
+	}
+	return out, nil
+}
+
+func heldPayment(status domain.PaymentStatus, expiresAt time.Time) *domain.Payment {
+	return &domain.Payment{
+		PublicID:  "pay_held",
+		PayerID:   1,
+		Amount:    50000000,
+		Currency:  "IDR",
+		Method:    domain.PaymentMethodTypeCard,
+		Status:    status,
+		ExpiresAt: &expiresAt,
+	}
+}
+
+func cardPaymentInput(key string, amount int) CreatePaymentInput {
+	return CreatePaymentInput{
+		OrderID:        "order_" + key,
+		PayerID:        1,
+		Amount:         amount,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         domain.PaymentMethodTypeCard,
+		IdempotencyKey: key,
+	}
+}
+
+func TestCreatePayment_HoldsHighAmountForReview(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	reviews := &mockReviewRepo{}
+	provider := &mockPaymentProvider{}
+	policy := domain.HoldPolicy{
+		MinAmount: 20000000,
+		Methods:   map[string]bool{domain.PaymentMethodTypeCard: true},
+	}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider).
+		WithReviews(policy, fakeRiskHistory{payerPayments: 4}, reviews, time.Hour)
+
+	out, err := uc.Execute(context.Background(), cardPaymentInput("hold-1", 25000000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusOnHold || out.ExpiresAt == nil {
+		t.Fatalf("expected ON_HOLD with a review deadline, got %+v", out)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("held payment must not reach the provider")
+	}
+	if len(reviews.reviews) != 1 || reviews.reviews[0].Action != domain.ReviewActionHeld ||
+		reviews.reviews[0].Reason != domain.HoldReasonHighAmount {
+		t.Fatalf("expected a HELD audit entry, got %+v", reviews.reviews)
+	}
+
+	// below the threshold goes straight through
+	out, err = uc.Execute(context.Background(), cardPaymentInput("hold-2", 1000))
+	if err != nil || out.Status != domain.PaymentStatusPending || provider.calledWith == "" {
+		t.Fatalf("expected payment to be processed, got %+v %v", out, err)
+	}
+}
+
+func TestCreatePayment_HoldsFirstTimePayer(t *testing.T) {
+	observability.InitTracer("test")
+
+	reviews := &mockReviewRepo{}
+	uc := NewCreatePaymentUsecase(newStatefulPaymentRepo(), activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithReviews(domain.HoldPolicy{FirstTimePayers: true}, fakeRiskHistory{}, reviews, time.Hour)
+
+	out, err := uc.Execute(context.Background(), cardPaymentInput("first-1", 1000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusOnHold || reviews.reviews[0].Reason != domain.HoldReasonFirstTimePayer {
+		t.Fatalf("expected first payment to be held, got %+v", out)
+	}
+}
+
+func TestCreatePayment_RiskReviewEntersQueue(t *testing.T) {
+	observability.InitTracer("test")
+
+	reviews := &mockReviewRepo{}
+	uc := NewCreatePaymentUsecase(newStatefulPaymentRepo(), activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithRisk(testRiskPolicy(), fakeRiskHistory{payerPayments: 3, orderPayments: 2}).
+		WithReviews(domain.HoldPolicy{}, fakeRiskHistory{}, reviews, time.Hour)
+
+	out, err := uc.Execute(context.Background(), cardPaymentInput("risk-q-1", 1000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusReview || out.ExpiresAt == nil {
+		t.Fatalf("expected REVIEW with a deadline, got %+v", out)
+	}
+	if len(reviews.reviews) != 1 || reviews.reviews[0].Reason != "risk: payer_velocity,order_velocity" {
+		t.Fatalf("expected risk rules as hold reason, got %+v", reviews.reviews)
+	}
+}
+
+func TestDecideReview_ApproveSendsToProvider(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo(heldPayment(domain.PaymentStatusOnHold, time.Now().Add(time.Hour)))
+	reviews := &mockReviewRepo{}
+	provider := &mockPaymentProvider{}
+	uc := NewDecideReviewUsecase(payments, reviews, NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider))
+
+	out, err := uc.Execute(context.Background(), DecideReviewInput{
+		PaymentID: "pay_held",
+		Approve:   true,
+		Reviewer:  "alice@example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// charged the way an unheld card payment is
+	if out.Payment.Status != domain.PaymentStatusPending || provider.calledWith != domain.PaymentMethodTypeCard {
+		t.Fatalf("expected approved payment to be processed, got %s", out.Payment.Status)
+	}
+	if stored := payments.payments["pay_held"]; stored.Status != domain.PaymentStatusPending || stored.ExpiresAt != nil {
+		t.Fatalf("expected the payment to be stored without its review deadline, got %+v", stored)
+	}
+	if len(out.History) != 1 || out.History[0].Action != domain.ReviewActionApproved ||
+		out.History[0].Reviewer != "alice@example.com" || out.History[0].CreatedAt.IsZero() {
+		t.Fatalf("expected approval in the audit trail, got %+v", out.History)
+	}
+
+	// a decided payment is no longer in the queue
+	_, err = uc.Execute(context.Background(), DecideReviewInput{PaymentID: "pay_held", Reviewer: "bob", Reason: "late"})
+	if !errors.Is(err, domain.ErrPaymentNotInReview) {
+		t.Fatalf("expected ErrPaymentNotInReview, got %v", err)
+	}
+}
+
+func TestDecideReview_RejectNeedsReason(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo(heldPayment(domain.PaymentStatusReview, time.Now().Add(time.Hour)))
+	reviews := &mockReviewRepo{}
+	provider := &mockPaymentProvider{}
+	uc := NewDecideReviewUsecase(payments, reviews, NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider))
+
+	input := DecideReviewInput{PaymentID: "pay_held", Reviewer: "alice@example.com"}
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, domain.ErrReviewReasonRequired) {
+		t.Fatalf("expected ErrReviewReasonRequired, got %v", err)
+	}
+
+	input.Reason = "stolen card reported"
+	out, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Payment.Status != domain.PaymentStatusFailed || provider.calledWith != "" {
+		t.Fatalf("expected rejected payment to fail without the provider, got %s", out.Payment.Status)
+	}
+	if out.History[0].Action != domain.ReviewActionRejected || out.History[0].Reason != "stolen card reported" {
+		t.Fatalf("expected rejection in the audit trail, got %+v", out.History[0])
+	}
+}
+
+func TestDecideReview_ApproveStartsHeldMethod(t *testing.T) {
+	observability.InitTracer("test")
+
+	tests := []struct {
+		name   string
+		input  CreatePaymentInput
+		status domain.PaymentStatus
+	}{
+		{"bank_transfer", CreatePaymentInput{Method: domain.PaymentMethodBankTransfer, BankCode: "bca"}, domain.PaymentStatusPending},
+		{"ewallet", CreatePaymentInput{Method: domain.PaymentMethodEWallet, Channel: domain.EWalletChannelMobile}, domain.PaymentStatusPending},
+		{"qr", CreatePaymentInput{Method: domain.PaymentMethodQR}, domain.PaymentStatusPending},
+		{"saved card", CreatePaymentInput{PaymentMethodToken: "pm_card", ReturnURL: "https://merchant.test/done"}, domain.PaymentStatusRequiresAction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payments := newStatefulPaymentRepo()
+			reviews := &mockReviewRepo{}
+			vas := newMockVirtualAccountRepo()
+			wallets := newMockEWalletRepo()
+			codes := newMockQRCodeRepo()
+			threeDS := newMockThreeDSRepo()
+			provider := &mockPaymentProvider{}
+			create := NewCreatePaymentUsecase(payments, activePayers(), savedCard(), provider).
+				WithBankTransfer(fakeVAProvider{}, vas, 24*time.Hour).
+				WithEWallet(fakeEWalletProvider{}, wallets, 15*time.Minute).
+				WithQR(fakeQRProvider{}, codes).
+				WithThreeDS(&fakeAuthenticator{outcome: domain.ThreeDSStatusChallengeRequired}, threeDS, 10*time.Minute).
+				WithReviews(domain.HoldPolicy{MinAmount: 1000}, fakeRiskHistory{}, reviews, time.Hour)
+
+			input := tt.input
+			input.OrderID = "order_held"
+			input.PayerID = 1
+			input.Amount = 5000
+			input.Currency = "IDR"
+			input.Provider = "fake"
+			input.IdempotencyKey = "held-" + tt.name
+			held, err := create.Execute(context.Background(), input)
+			if err != nil || held.Status != domain.PaymentStatusOnHold {
+				t.Fatalf("expected the payment to be held, got %+v %v", held, err)
+			}
+
+			out, err := NewDecideReviewUsecase(payments, reviews, create).Execute(context.Background(), DecideReviewInput{
+				PaymentID: held.PaymentID,
+				Approve:   true,
+				Reviewer:  "alice",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Payment.Status != tt.status || payments.payments[held.PaymentID].Status != tt.status {
+				t.Fatalf("expected %s, got %s", tt.status, out.Payment.Status)
+			}
+			if out.History[len(out.History)-1].Action != domain.ReviewActionApproved {
+				t.Fatalf("expected the approval in the audit trail, got %+v", out.History)
+			}
+
+			var issued bool
+			switch tt.name {
+			case "bank_transfer":
+				va := vas.accounts[held.PaymentID]
+				issued = va != nil && va.BankCode == "BCA"
+			case "ewallet":
+				auth := wallets.auths[held.PaymentID]
+				issued = auth != nil && auth.Channel == domain.EWalletChannelMobile
+			case "qr":
+				issued = codes.codes[held.PaymentID] != nil
+			case "saved card":
+				auth := threeDS.auths[held.PaymentID]
+				issued = auth != nil && auth.ReturnURL == "https://merchant.test/done" && provider.calledWith == ""
+			}
+			if !issued {
+				t.Fatalf("expected the approved payment to be started like a new one")
+			}
+			if expiresAt := payments.payments[held.PaymentID].ExpiresAt; expiresAt == nil || !expiresAt.After(time.Now()) {
+				t.Fatalf("expected the payment to expire with what was issued, got %v", expiresAt)
+			}
+		})
+	}
+}
+
+func TestDecideReview_ApprovedPaymentRefused(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo(heldPayment(domain.PaymentStatusReview, time.Now().Add(time.Hour)))
+	reviews := &mockReviewRepo{}
+	provider := &mockPaymentProvider{err: domain.ErrProviderUnavailable}
+	uc := NewDecideReviewUsecase(payments, reviews, NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider))
+	input := DecideReviewInput{PaymentID: "pay_held", Approve: true, Reviewer: "alice"}
+
+	// the provider never answered, so the payment waits for another decision
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, domain.ErrProviderUnavailable) {
+		t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+	}
+	if payments.payments["pay_held"].Status != domain.PaymentStatusReview || len(reviews.reviews) != 0 {
+		t.Fatalf("expected the payment to stay in review, got %s", payments.payments["pay_held"].Status)
+	}
+
+	provider.err = errors.New("insufficient funds")
+	out, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Payment.Status != domain.PaymentStatusFailed || payments.payments["pay_held"].Status != domain.PaymentStatusFailed {
+		t.Fatalf("expected the declined payment to fail, got %s", out.Payment.Status)
+	}
+	if len(out.History) != 1 || out.History[0].Action != domain.ReviewActionApprovalFailed ||
+		!strings.Contains(out.History[0].Reason, "insufficient funds") || out.History[0].Reviewer != "alice" {
+		t.Fatalf("expected the refusal in the audit trail, got %+v", out.History)
+	}
+}
+
+func TestDecideReview_PastSLA(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo(heldPayment(domain.PaymentStatusOnHold, time.Now().Add(-time.Minute)))
+	uc := NewDecideReviewUsecase(payments, &mockReviewRepo{}, NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}))
+
+	_, err := uc.Execute(context.Background(), DecideReviewInput{PaymentID: "pay_held", Approve: true, Reviewer: "alice"})
+	if !errors.Is(err, domain.ErrReviewExpired) {
+		t.Fatalf("expected ErrReviewExpired, got %v", err)
+	}
+}
+
+func TestExpirePayments_ExpiresStaleReviews(t *testing.T) {
+	observability.InitTracer("test")
+
+	now := time.Now()
+	payments := newStatefulPaymentRepo(heldPayment(domain.PaymentStatusOnHold, now.Add(-time.Minute)))
+	reviews := &mockReviewRepo{}
+	uc := NewExpirePaymentsUsecase(payments, newMockVirtualAccountRepo(), 10).WithReviews(reviews)
+
+	n, err := uc.Execute(context.Background(), now)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one expired payment, got %d %v", n, err)
+	}
+	if payments.payments["pay_held"].Status != domain.PaymentStatusExpired {
+		t.Fatalf("expected EXPIRED")
+	}
+	if len(reviews.reviews) != 1 || reviews.reviews[0].Action != domain.ReviewActionExpired ||
+		reviews.reviews[0].Reviewer != domain.SystemReviewer {
+		t.Fatalf("expected an EXPIRED audit entry, got %+v", reviews.reviews)
+	}
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// RevokeAPIKeyUsecase disables a key immediately, for keys that leaked.
+type RevokeAPIKeyUsecase struct {
+	apiKeyRepo ports.APIKeyRepository
+	now        func() time.Time
+}
+
+func NewRevokeAPIKeyUsecase(apiKeyRepo ports.APIKeyRepository) *RevokeAPIKeyUsecase {
+	return &RevokeAPIKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		now:        time.Now,
+	}
+}
+
+func (uc *RevokeAPIKeyUsecase) Execute(ctx context.Context, keyID int) error {
+	ctx, span := observability.Tracer().Start(ctx, "RevokeAPIKeyUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("api_key.id", keyID))
+
+	if err := uc.apiKeyRepo.Revoke(ctx, keyID, uc.now()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
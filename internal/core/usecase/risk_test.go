@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type fakeRiskHistory struct {
+	payerPayments int
+	orderPayments int
+}
+
+func (h fakeRiskHistory) CountPayerPayments(ctx context.Context, payerID int, since time.Time) (int, error) {
+	return h.payerPayments, nil
+}
+
+func (h fakeRiskHistory) CountOrderPayments(ctx context.Context, orderID string, since time.Time) (int, error) {
+	return h.orderPayments, nil
+}
+
+func testRiskPolicy() domain.RiskPolicy {
+	return domain.RiskPolicy{
+		PayerVelocity:     domain.VelocityRule{Window: 10 * time.Minute, Max: 3, Score: 30},
+		OrderVelocity:     domain.VelocityRule{Window: time.Hour, Max: 2, Score: 30},
+		AmountThresholds:  map[string]int{"bank_transfer": 1000000},
+		AmountScore:       40,
+		BlockedIPs:        map[string]bool{"10.6.6.6": true},
+		BlocklistScore:    100,
+		CountryCurrencies: map[string]string{"ID": "IDR"},
+		MismatchScore:     20,
+		ReviewScore:       50,
+		BlockScore:        90,
+	}
+}
+
+func riskInput(key string, amount int) CreatePaymentInput {
+	return CreatePaymentInput{
+		OrderID:        "order_risk",
+		PayerID:        1,
+		Amount:         amount,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         "bank_transfer",
+		BankCode:       "BCA",
+		IdempotencyKey: key,
+		ClientIP:       "10.0.0.1",
+	}
+}
+
+func TestRiskPolicy_Evaluate(t *testing.T) {
+	policy := testRiskPolicy()
+
+	tests := []struct {
+		name     string
+		signals  domain.RiskSignals
+		decision domain.RiskDecision
+		rules    []string
+	}{
+		{
+			name:     "clean",
+			signals:  domain.RiskSignals{PayerCountry: "ID", Method: "bank_transfer", Amount: 1000, Currency: "IDR"},
+			decision: domain.RiskDecisionAllow,
+		},
+		{
+			name:     "currency mismatch alone is allowed",
+			signals:  domain.RiskSignals{PayerCountry: "ID", Method: "bank_transfer", Amount: 1000, Currency: "USD"},
+			decision: domain.RiskDecisionAllow,
+			rules:    []string{domain.RiskRuleCurrencyCountryMismatch},
+		},
+		{
+			name:     "large amount from a mismatched country is reviewed",
+			signals:  domain.RiskSignals{PayerCountry: "ID", Method: "bank_transfer", Amount: 2000000, Currency: "USD"},
+			decision: domain.RiskDecisionReview,
+			rules:    []string{domain.RiskRuleAmountThreshold, domain.RiskRuleCurrencyCountryMismatch},
+		},
+		{
+			name:     "velocity on payer and order is reviewed",
+			signals:  domain.RiskSignals{PayerCountry: "ID", Method: "bank_transfer", Amount: 1000, Currency: "IDR", PayerPayments: 3, OrderPayments: 2},
+			decision: domain.RiskDecisionReview,
+			rules:    []string{domain.RiskRulePayerVelocity, domain.RiskRuleOrderVelocity},
+		},
+		{
+			name:     "blocked ip",
+			signals:  domain.RiskSignals{PayerCountry: "ID", Method: "bank_transfer", Amount: 1000, Currency: "IDR", IP: "10.6.6.6"},
+			decision: domain.RiskDecisionBlock,
+			rules:    []string{domain.RiskRuleBlockedIP},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Evaluate(tt.signals)
+			if got.Decision != tt.decision {
+				t.Fatalf("expected %s, got %s (score %d)", tt.decision, got.Decision, got.Score)
+			}
+			if !reflect.DeepEqual(got.Rules, tt.rules) {
+				t.Fatalf("expected rules %v, got %v", tt.rules, got.Rules)
+			}
+		})
+	}
+}
+
+func TestCreatePayment_RiskAllowRecordsAssessment(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithBankTransfer(fakeVAProvider{}, newMockVirtualAccountRepo(), time.Hour).
+		WithRisk(testRiskPolicy(), fakeRiskHistory{})
+
+	out, err := uc.Execute(context.Background(), riskInput("idem-risk-allow", 50000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusPending || out.VirtualAccount == nil {
+		t.Fatalf("expected an issued transfer, got %+v", out)
+	}
+	if stored := payments.payments[out.PaymentID]; stored.RiskDecision != domain.RiskDecisionAllow {
+		t.Fatalf("expected ALLOW to be stored, got %q", stored.RiskDecision)
+	}
+}
+
+func TestCreatePayment_RiskReviewHoldsPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	accounts := newMockVirtualAccountRepo()
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithBankTransfer(fakeVAProvider{}, accounts, time.Hour).
+		WithRisk(testRiskPolicy(), fakeRiskHistory{payerPayments: 3, orderPayments: 2})
+
+	out, err := uc.Execute(context.Background(), riskInput("idem-risk-review", 50000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Status != domain.PaymentStatusReview || out.VirtualAccount != nil {
+		t.Fatalf("expected payment held without instructions, got %+v", out)
+	}
+	if len(accounts.accounts) != 0 {
+		t.Fatalf("no virtual account must be issued while in review")
+	}
+	stored := payments.payments[out.PaymentID]
+	if stored.RiskScore != 60 || len(stored.RiskRules) != 2 {
+		t.Fatalf("expected score and rules to be stored, got %d %v", stored.RiskScore, stored.RiskRules)
+	}
+}
+
+func TestCreatePayment_RiskBlockStoresFailedPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	provider := &mockPaymentProvider{}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider).
+		WithRisk(testRiskPolicy(), fakeRiskHistory{})
+
+	input := riskInput("idem-risk-block", 50000)
+	input.Method = "credit_card"
+	input.ClientIP = "10.6.6.6"
+	_, err := uc.Execute(context.Background(), input)
+	if !errors.Is(err, domain.ErrPaymentBlocked) {
+		t.Fatalf("expected ErrPaymentBlocked, got %v", err)
+	}
+	if provider.calledWith != "" {
+		t.Fatalf("blocked payment must not reach the provider")
+	}
+	if len(payments.payments) != 1 {
+		t.Fatalf("expected the blocked attempt to be stored")
+	}
+	for _, p := range payments.payments {
+		if p.Status != domain.PaymentStatusFailed || p.RiskDecision != domain.RiskDecisionBlock {
+			t.Fatalf("expected FAILED with BLOCK, got %s %s", p.Status, p.RiskDecision)
+		}
+	}
+}
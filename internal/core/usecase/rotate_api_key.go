@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// RotateAPIKeyUsecase replaces one of the calling merchant's keys with a new
+// key of the same mode. The old key keeps working for the grace period so
+// the merchant can roll out the new one without downtime.
+type RotateAPIKeyUsecase struct {
+	apiKeyRepo ports.APIKeyRepository
+	grace      time.Duration
+	now        func() time.Time
+}
+
+func NewRotateAPIKeyUsecase(
+	apiKeyRepo ports.APIKeyRepository,
+	grace time.Duration,
+) *RotateAPIKeyUsecase {
+	return &RotateAPIKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		grace:      grace,
+		now:        time.Now,
+	}
+}
+
+func (uc *RotateAPIKeyUsecase) Execute(
+	ctx context.Context,
+	keyID int,
+) (*IssuedAPIKey, error) {
+	ctx, span := observability.Tracer().Start(ctx, "RotateAPIKeyUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("api_key.id", keyID))
+
+	issued, err := uc.rotate(ctx, keyID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return issued, nil
+}
+
+func (uc *RotateAPIKeyUsecase) rotate(
+	ctx context.Context,
+	keyID int,
+) (*IssuedAPIKey, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrMerchantRequired
+	}
+
+	old, err := uc.apiKeyRepo.FindByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	// another merchant's key looks the same as one that does not exist
+	if old.MerchantID != tenant.MerchantID || !old.ActiveAt(uc.now()) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	issued, err := issueAPIKey(ctx, uc.apiKeyRepo, old.MerchantID, old.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.apiKeyRepo.Expire(ctx, old.ID, uc.now().Add(uc.grace)); err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
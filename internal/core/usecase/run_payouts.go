@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type RunPayoutsOutput struct {
+	Scheduled int
+	Sent      int
+	Paid      int
+	Failed    int
+}
+
+// RunPayoutsUsecase makes the scheduled payouts that are due, sends pending
+// payouts to the payout provider and records the outcome of those in
+// transit.
+type RunPayoutsUsecase struct {
+	payoutRepo   ports.PayoutRepository
+	balanceRepo  ports.BalanceRepository
+	merchantRepo ports.MerchantRepository
+	provider     ports.PayoutProvider
+	policy       domain.PayoutPolicy
+	destination  string
+	batchSize    int
+}
+
+func NewRunPayoutsUsecase(
+	payoutRepo ports.PayoutRepository,
+	balanceRepo ports.BalanceRepository,
+	merchantRepo ports.MerchantRepository,
+	provider ports.PayoutProvider,
+	policy domain.PayoutPolicy,
+	destination string,
+	batchSize int,
+) *RunPayoutsUsecase {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &RunPayoutsUsecase{
+		payoutRepo:   payoutRepo,
+		balanceRepo:  balanceRepo,
+		merchantRepo: merchantRepo,
+		provider:     provider,
+		policy:       policy,
+		destination:  destination,
+		batchSize:    batchSize,
+	}
+}
+
+func (uc *RunPayoutsUsecase) Execute(
+	ctx context.Context,
+	now time.Time,
+) (*RunPayoutsOutput, error) {
+	ctx, span := observability.Tracer().Start(ctx, "RunPayoutsUseCase.Execute")
+	defer span.End()
+
+	out := &RunPayoutsOutput{}
+	err := errors.Join(
+		uc.schedule(ctx, now, out),
+		uc.send(ctx, now, out),
+		uc.track(ctx, now, out),
+	)
+
+	span.SetAttributes(
+		attribute.Int("payouts.scheduled", out.Scheduled),
+		attribute.Int("payouts.sent", out.Sent),
+		attribute.Int("payouts.paid", out.Paid),
+		attribute.Int("payouts.failed", out.Failed),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return out, err
+	}
+	return out, nil
+}
+
+// schedule pays out, for every active merchant, the available balance of
+// every currency whose schedule period has no payout yet.
+func (uc *RunPayoutsUsecase) schedule(
+	ctx context.Context,
+	now time.Time,
+	out *RunPayoutsOutput,
+) error {
+	if uc.policy.Schedule == domain.PayoutScheduleManual {
+		return nil
+	}
+
+	merchants, err := uc.merchantRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, m := range merchants {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := uc.scheduleMerchant(actingFor(ctx, m.ID), m, now, out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *RunPayoutsUsecase) scheduleMerchant(
+	ctx context.Context,
+	merchant *domain.Merchant,
+	now time.Time,
+	out *RunPayoutsOutput,
+) error {
+	settledBefore := now.Add(-uc.policy.SettlementDelay)
+	balances, err := uc.balanceRepo.Balances(ctx, settledBefore)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, b := range balances {
+		if uc.policy.CheckAmount(b.Available, b.Currency) != nil {
+			continue
+		}
+
+		var last time.Time
+		prev, err := uc.payoutRepo.LastScheduled(ctx, b.Currency)
+		switch {
+		case err == nil:
+			last = prev.CreatedAt
+		case !errors.Is(err, domain.ErrPayoutNotFound):
+			errs = append(errs, err)
+			continue
+		}
+		if !uc.policy.Due(last, now) {
+			continue
+		}
+
+		destination := payoutDestination(merchant, uc.destination)
+		payout := newPayout(b.Available, b.Currency, domain.PayoutTriggerScheduled, destination)
+		err = uc.payoutRepo.CreateWithinBalance(ctx, payout, settledBefore)
+		// a manual payout made since the balance was read is not an error
+		if err != nil && !errors.Is(err, domain.ErrInsufficientBalance) {
+			errs = append(errs, err)
+			continue
+		}
+		if err == nil {
+			out.Scheduled++
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *RunPayoutsUsecase) send(
+	ctx context.Context,
+	now time.Time,
+	out *RunPayoutsOutput,
+) error {
+	pending, err := uc.payoutRepo.ListByStatus(ctx, domain.PayoutStatusPending, uc.batchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, payout := range pending {
+		reference, err := uc.provider.Send(ctx, payout)
+		if err != nil {
+			// left pending for the next run
+			errs = append(errs, err)
+			continue
+		}
+
+		payout.Reference = reference
+		if err := uc.transition(ctx, payout, domain.PayoutStatusInTransit, now); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out.Sent++
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *RunPayoutsUsecase) track(
+	ctx context.Context,
+	now time.Time,
+	out *RunPayoutsOutput,
+) error {
+	inTransit, err := uc.payoutRepo.ListByStatus(ctx, domain.PayoutStatusInTransit, uc.batchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, payout := range inTransit {
+		result, err := uc.provider.Status(ctx, payout.Reference)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !result.Status.IsFinal() {
+			continue
+		}
+
+		payout.FailureReason = result.FailureReason
+		if err := uc.transition(ctx, payout, result.Status, now); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if result.Status == domain.PayoutStatusPaid {
+			out.Paid++
+		} else {
+			out.Failed++
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *RunPayoutsUsecase) transition(
+	ctx context.Context,
+	payout *domain.Payout,
+	next domain.PayoutStatus,
+	now time.Time,
+) error {
+	from := payout.Status
+	if err := payout.TransitionTo(next, now); err != nil {
+		return err
+	}
+	return uc.payoutRepo.UpdateStatus(ctx, payout, from)
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// memorySpendingCounter implements ports.SpendingCounter the way the sqlite
+// counter does: record first, then reject and undo if a limit is exceeded.
+type memorySpendingCounter struct {
+	attempts  map[string]domain.SpendingAttempt
+	gotLimits []string
+}
+
+func newMemorySpendingCounter() *memorySpendingCounter {
+	return &memorySpendingCounter{attempts: map[string]domain.SpendingAttempt{}}
+}
+
+func (c *memorySpendingCounter) Record(ctx context.Context, attempt domain.SpendingAttempt, limits []domain.SpendingLimit) error {
+	c.gotLimits = nil
+	for _, l := range limits {
+		c.gotLimits = append(c.gotLimits, l.Name)
+	}
+	if _, ok := c.attempts[attempt.IdempotencyKey]; ok {
+		return nil
+	}
+	c.attempts[attempt.IdempotencyKey] = attempt
+
+	for _, limit := range limits {
+		var usage domain.SpendingUsage
+		for _, a := range c.attempts {
+			if a.PayerID != attempt.PayerID || !a.At.After(attempt.At.Add(-limit.Window)) {
+				continue
+			}
+			if (limit.Method != "" && a.Method != limit.Method) ||
+				(limit.Currency != "" && a.Currency != limit.Currency) {
+				continue
+			}
+			usage.Count++
+			usage.Amount += a.Amount
+		}
+		if limit.ExceededBy(usage) {
+			delete(c.attempts, attempt.IdempotencyKey)
+			return &domain.LimitExceededError{Limit: limit.Name}
+		}
+	}
+	return nil
+}
+
+func limitedPaymentUsecase(payments *statefulPaymentRepo, counter *memorySpendingCounter, provider *mockPaymentProvider) *CreatePaymentUsecase {
+	return NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider).
+		WithSpendingLimits(counter, []domain.SpendingLimit{
+			{Name: "card_attempts", Method: "credit_card", Window: 10 * time.Minute, MaxCount: 2},
+			{Name: "idr_amount", Currency: "IDR", Window: time.Hour, MaxAmount: 100000},
+			{Name: "usd_amount", Currency: "USD", Window: time.Hour, MaxAmount: 100},
+		})
+}
+
+func limitedInput(key string, amount int) CreatePaymentInput {
+	return CreatePaymentInput{
+		OrderID:        "order_" + key,
+		PayerID:        1,
+		Amount:         amount,
+		Currency:       "IDR",
+		Provider:       "fake",
+		Method:         "credit_card",
+		IdempotencyKey: key,
+	}
+}
+
+func TestCreatePayment_SpendingLimitCountExceeded(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	counter := newMemorySpendingCounter()
+	provider := &mockPaymentProvider{}
+	uc := limitedPaymentUsecase(payments, counter, provider)
+
+	for _, key := range []string{"lim-1", "lim-2"} {
+		if _, err := uc.Execute(context.Background(), limitedInput(key, 1000)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(counter.gotLimits) != 2 || counter.gotLimits[0] != "card_attempts" || counter.gotLimits[1] != "idr_amount" {
+		t.Fatalf("expected only matching limits to be checked, got %v", counter.gotLimits)
+	}
+
+	provider.calledWith = ""
+	_, err := uc.Execute(context.Background(), limitedInput("lim-3", 1000))
+	var limitErr *domain.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "card_attempts" {
+		t.Fatalf("expected card_attempts to be exceeded, got %v", err)
+	}
+	if !errors.Is(err, domain.ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if provider.calledWith != "" || len(payments.payments) != 2 {
+		t.Fatalf("rejected payment must not be processed or stored")
+	}
+}
+
+func TestCreatePayment_SpendingLimitAmountExceeded(t *testing.T) {
+	observability.InitTracer("test")
+
+	counter := newMemorySpendingCounter()
+	uc := limitedPaymentUsecase(newStatefulPaymentRepo(), counter, &mockPaymentProvider{})
+
+	if _, err := uc.Execute(context.Background(), limitedInput("amt-1", 60000)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := uc.Execute(context.Background(), limitedInput("amt-2", 50000))
+	var limitErr *domain.LimitExceededError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "idr_amount" {
+		t.Fatalf("expected idr_amount to be exceeded, got %v", err)
+	}
+
+	// the rejected attempt is not counted, so a smaller payment still fits
+	if _, err := uc.Execute(context.Background(), limitedInput("amt-3", 40000)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreatePayment_SpendingLimitCountsRetryOnce(t *testing.T) {
+	observability.InitTracer("test")
+
+	counter := newMemorySpendingCounter()
+	uc := limitedPaymentUsecase(newStatefulPaymentRepo(), counter, &mockPaymentProvider{err: errors.New("declined")})
+
+	// a declined attempt retried with the same key is one attempt
+	for i := 0; i < 3; i++ {
+		if _, err := uc.Execute(context.Background(), limitedInput("retry-1", 1000)); errors.Is(err, domain.ErrLimitExceeded) {
+			t.Fatalf("retry must not count again: %v", err)
+		}
+	}
+	if len(counter.attempts) != 1 {
+		t.Fatalf("expected one counted attempt, got %d", len(counter.attempts))
+	}
+}
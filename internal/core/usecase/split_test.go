@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+type mockSplitRepo struct {
+	err    error
+	splits []domain.PaymentSplit
+	// inTx is set when the splits were written in a fakeTransactor
+	// transaction.
+	inTx bool
+}
+
+func (r *mockSplitRepo) Create(ctx context.Context, splits []domain.PaymentSplit) error {
+	r.inTx = ctx.Value(fakeTxKey{}) != nil
+	if r.err != nil {
+		return r.err
+	}
+	r.splits = append(r.splits, splits...)
+	return nil
+}
+
+func (r *mockSplitRepo) ListByPaymentID(ctx context.Context, paymentID string) ([]domain.PaymentSplit, error) {
+	var out []domain.PaymentSplit
+	for _, s := range r.splits {
+		if s.PaymentID == paymentID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestAllocateSplits(t *testing.T) {
+	cases := []struct {
+		name   string
+		total  int
+		shares []domain.SplitShare
+		want   []int
+	}{
+		{
+			name:  "fixed amounts",
+			total: 100000,
+			shares: []domain.SplitShare{
+				{Recipient: "seller_a", Amount: 70000},
+				{Recipient: "seller_b", Amount: 30000},
+			},
+			want: []int{70000, 30000},
+		},
+		{
+			name:  "percentages hand out rounding",
+			total: 100,
+			shares: []domain.SplitShare{
+				{Recipient: "seller_a", Percentage: 33.33},
+				{Recipient: "seller_b", Percentage: 33.33},
+				{Recipient: "seller_c", Percentage: 33.34},
+			},
+			want: []int{33, 33, 34},
+		},
+		{
+			name:  "uneven percentages",
+			total: 99999,
+			shares: []domain.SplitShare{
+				{Recipient: "seller_a", Percentage: 12.5},
+				{Recipient: "seller_b", Percentage: 87.5},
+			},
+			want: []int{12500, 87499},
+		},
+		{
+			name:  "mixed",
+			total: 150000,
+			shares: []domain.SplitShare{
+				{Recipient: "platform", Amount: 15000, FeeBearer: true},
+				{Recipient: "seller_a", Percentage: 90},
+			},
+			want: []int{15000, 135000},
+		},
+	}
+
+	for _, tc := range cases {
+		splits, err := domain.AllocateSplits(tc.total, tc.shares)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		sum := 0
+		for i, s := range splits {
+			if s.Amount != tc.want[i] {
+				t.Errorf("%s: %s expected %d, got %d", tc.name, s.Recipient, tc.want[i], s.Amount)
+			}
+			sum += s.Amount
+		}
+		if sum != tc.total {
+			t.Errorf("%s: splits add up to %d, not %d", tc.name, sum, tc.total)
+		}
+	}
+}
+
+func TestAllocateSplits_Rejects(t *testing.T) {
+	cases := map[string][]domain.SplitShare{
+		"short": {
+			{Recipient: "seller_a", Amount: 60000},
+			{Recipient: "seller_b", Amount: 30000},
+		},
+		"over": {
+			{Recipient: "seller_a", Amount: 60000},
+			{Recipient: "seller_b", Percentage: 50},
+		},
+		"duplicate recipient": {
+			{Recipient: "seller_a", Amount: 50000},
+			{Recipient: "seller_a", Amount: 50000},
+		},
+		"amount and percentage": {
+			{Recipient: "seller_a", Amount: 50000, Percentage: 50},
+			{Recipient: "seller_b", Percentage: 50},
+		},
+		"two fee bearers": {
+			{Recipient: "seller_a", Percentage: 50, FeeBearer: true},
+			{Recipient: "seller_b", Percentage: 50, FeeBearer: true},
+		},
+		"three decimals": {
+			{Recipient: "seller_a", Percentage: 33.333},
+			{Recipient: "seller_b", Percentage: 66.667},
+		},
+		"no recipient": {
+			{Amount: 100000},
+		},
+	}
+
+	for name, shares := range cases {
+		if _, err := domain.AllocateSplits(100000, shares); !errors.Is(err, domain.ErrInvalidSplits) {
+			t.Errorf("%s: expected ErrInvalidSplits, got %v", name, err)
+		}
+	}
+}
+
+func TestCreatePayment_PersistsSplits(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	splits := &mockSplitRepo{}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithSplits(splits)
+
+	input := cardPaymentInput("split-1", 100000)
+	input.Splits = []domain.SplitShare{
+		{Recipient: "seller_a", Percentage: 80},
+		{Recipient: "seller_b", Amount: 20000, FeeBearer: true},
+	}
+
+	out, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := NewGetPaymentUsecase(payments).WithSplits(splits).Execute(context.Background(), out.PaymentID)
+	if len(got.Splits) != 2 {
+		t.Fatalf("expected 2 splits on the payment, got %+v", got.Splits)
+	}
+	if got.Splits[0].Amount != 80000 || got.Splits[0].Percentage() != 80 {
+		t.Fatalf("unexpected split: %+v", got.Splits[0])
+	}
+	if got.Splits[1].Amount != 20000 || !got.Splits[1].FeeBearer {
+		t.Fatalf("unexpected split: %+v", got.Splits[1])
+	}
+}
+
+func TestCreatePayment_RejectsSplitsNotAddingUp(t *testing.T) {
+	observability.InitTracer("test")
+
+	payments := newStatefulPaymentRepo()
+	provider := &mockPaymentProvider{}
+	uc := NewCreatePaymentUsecase(payments, activePayers(), newMockPaymentMethodRepo(), provider).
+		WithSplits(&mockSplitRepo{})
+
+	input := cardPaymentInput("split-2", 100000)
+	input.Splits = []domain.SplitShare{{Recipient: "seller_a", Amount: 90000}}
+
+	_, err := uc.Execute(context.Background(), input)
+	if !errors.Is(err, domain.ErrInvalidSplits) {
+		t.Fatalf("expected ErrInvalidSplits, got %v", err)
+	}
+	if provider.calledWith != "" || len(payments.payments) != 0 {
+		t.Fatalf("an invalid split must be rejected before anything else happens")
+	}
+}
+
+func TestCreatePayment_SplitStoreErrorIsReturned(t *testing.T) {
+	observability.InitTracer("test")
+
+	storeErr := errors.New("split store down")
+	reviews := &mockReviewRepo{}
+	uc := NewCreatePaymentUsecase(newStatefulPaymentRepo(), activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+		WithSplits(&mockSplitRepo{err: storeErr}).
+		WithReviews(domain.HoldPolicy{FirstTimePayers: true}, fakeRiskHistory{}, reviews, time.Hour)
+
+	input := cardPaymentInput("split-3", 100000)
+	input.Splits = []domain.SplitShare{{Recipient: "seller_a", Amount: 100000}}
+
+	// the review written after the splits must not hide the failure
+	_, err := uc.Execute(context.Background(), input)
+	if !errors.Is(err, storeErr) {
+		t.Fatalf("expected the split store error, got %v", err)
+	}
+}
+
+type fakeTxKey struct{}
+
+// fakeTransactor records how the last transaction ended.
+type fakeTransactor struct {
+	err error
+}
+
+func (t *fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.err = fn(context.WithValue(ctx, fakeTxKey{}, true))
+	return t.err
+}
+
+func TestCreatePayment_RecordsShareTheTransaction(t *testing.T) {
+	observability.InitTracer("test")
+
+	for _, storeErr := range []error{nil, errors.New("split store down")} {
+		splits := &mockSplitRepo{err: storeErr}
+		tx := &fakeTransactor{}
+		uc := NewCreatePaymentUsecase(newStatefulPaymentRepo(), activePayers(), newMockPaymentMethodRepo(), &mockPaymentProvider{}).
+			WithSplits(splits).
+			WithTransactor(tx)
+
+		input := cardPaymentInput("split-tx", 100000)
+		input.Splits = []domain.SplitShare{{Recipient: "seller_a", Amount: 100000}}
+
+		_, err := uc.Execute(context.Background(), input)
+		if !errors.Is(err, storeErr) {
+			t.Fatalf("expected %v, got %v", storeErr, err)
+		}
+		if !splits.inTx {
+			t.Fatal("expected the splits to be written in the payment's transaction")
+		}
+		// a failed insert ends the transaction with an error, which rolls
+		// the payment back
+		if !errors.Is(tx.err, storeErr) {
+			t.Fatalf("expected the transaction to end with %v, got %v", storeErr, tx.err)
+		}
+	}
+}
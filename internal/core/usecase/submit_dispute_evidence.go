@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// SubmitDisputeEvidenceUsecase hands the uploaded evidence over for review,
+// after which no more evidence is accepted.
+type SubmitDisputeEvidenceUsecase struct {
+	disputeRepo ports.DisputeRepository
+	now         func() time.Time
+}
+
+func NewSubmitDisputeEvidenceUsecase(
+	disputeRepo ports.DisputeRepository,
+) *SubmitDisputeEvidenceUsecase {
+	return &SubmitDisputeEvidenceUsecase{
+		disputeRepo: disputeRepo,
+		now:         time.Now,
+	}
+}
+
+func (uc *SubmitDisputeEvidenceUsecase) Execute(
+	ctx context.Context,
+	disputeID string,
+) (*domain.Dispute, error) {
+	ctx, span := observability.Tracer().Start(ctx, "SubmitDisputeEvidenceUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("dispute.id", disputeID))
+
+	dispute, err := uc.submit(ctx, disputeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (uc *SubmitDisputeEvidenceUsecase) submit(
+	ctx context.Context,
+	disputeID string,
+) (*domain.Dispute, error) {
+	dispute, err := uc.disputeRepo.FindByPublicID(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	if err := acceptsEvidence(dispute, now); err != nil {
+		return nil, err
+	}
+
+	evidence, err := uc.disputeRepo.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(evidence) == 0 {
+		return nil, domain.ErrEvidenceRequired
+	}
+
+	from := dispute.Status
+	if err := dispute.TransitionTo(domain.DisputeStatusUnderReview, now); err != nil {
+		return nil, err
+	}
+	if err := uc.disputeRepo.UpdateStatus(ctx, dispute, from); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+)
+
+// actingFor scopes ctx to the merchant owning an entity that a system path
+// (a worker, a provider webhook or the hosted checkout) is working on, so
+// anything it creates is stored for that merchant.
+func actingFor(ctx context.Context, merchantID int) context.Context {
+	return domain.WithTenant(ctx, domain.Tenant{MerchantID: merchantID})
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type TokenizeCardInput struct {
+	PayerID    int
+	Number     string
+	ExpMonth   int
+	ExpYear    int
+	CVC        string
+	HolderName string
+}
+
+type TokenizeCardUsecase struct {
+	payerRepo         ports.PayerRepository
+	paymentMethodRepo ports.PaymentMethodRepository
+	secretBox         ports.SecretBox
+	now               func() time.Time
+}
+
+func NewTokenizeCardUsecase(
+	payerRepo ports.PayerRepository,
+	paymentMethodRepo ports.PaymentMethodRepository,
+	secretBox ports.SecretBox,
+) *TokenizeCardUsecase {
+	return &TokenizeCardUsecase{
+		payerRepo:         payerRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		secretBox:         secretBox,
+		now:               time.Now,
+	}
+}
+
+func isValidCVC(cvc string) bool {
+	if len(cvc) < 3 || len(cvc) > 4 {
+		return false
+	}
+	for _, r := range cvc {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (uc *TokenizeCardUsecase) validate(input TokenizeCardInput) error {
+	if input.PayerID <= 0 {
+		return errors.New("payer id is required")
+	}
+	if !domain.LuhnValid(input.Number) {
+		return domain.ErrInvalidCardNumber
+	}
+	if input.ExpMonth < 1 || input.ExpMonth > 12 {
+		return errors.New("exp_month must be between 1 and 12")
+	}
+	if domain.CardExpired(input.ExpMonth, input.ExpYear, uc.now()) {
+		return domain.ErrCardExpired
+	}
+	// the CVC is only checked for shape; it must never be stored
+	if !isValidCVC(input.CVC) {
+		return errors.New("cvc is invalid")
+	}
+	return nil
+}
+
+// Execute stores the card in the vault and returns the saved payment method.
+// Saving the same card twice for a payer returns the existing token.
+func (uc *TokenizeCardUsecase) Execute(
+	ctx context.Context,
+	input TokenizeCardInput,
+) (*domain.PaymentMethod, error) {
+	ctx, span := observability.Tracer().Start(ctx, "TokenizeCardUseCase.Execute")
+	defer span.End()
+
+	input.Number = domain.NormalizePAN(input.Number)
+	input.HolderName = strings.TrimSpace(input.HolderName)
+	if input.ExpYear < 100 {
+		input.ExpYear += 2000
+	}
+
+	if err := uc.validate(input); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	payer, err := uc.payerRepo.FindByID(ctx, input.PayerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if !payer.CanPay() {
+		err := domain.ErrPayerNotActive
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	fingerprint := uc.secretBox.Fingerprint([]byte(input.Number))
+
+	existing, err := uc.paymentMethodRepo.FindByFingerprint(ctx, payer.ID, fingerprint)
+	if err == nil &&
+		existing.ExpMonth == input.ExpMonth &&
+		existing.ExpYear == input.ExpYear {
+		return existing, nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrPaymentMethodNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	encrypted, err := uc.secretBox.Seal([]byte(input.Number))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	method := &domain.PaymentMethod{
+		Token:        "pm_" + uuid.NewString(),
+		PayerID:      payer.ID,
+		Type:         domain.PaymentMethodTypeCard,
+		Brand:        domain.DetectCardBrand(input.Number),
+		Last4:        input.Number[len(input.Number)-4:],
+		BIN:          input.Number[:6],
+		ExpMonth:     input.ExpMonth,
+		ExpYear:      input.ExpYear,
+		HolderName:   input.HolderName,
+		EncryptedPAN: encrypted,
+		Fingerprint:  fingerprint,
+		Status:       domain.PaymentMethodStatusActive,
+	}
+
+	if err := uc.paymentMethodRepo.Create(ctx, method); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return method, nil
+}
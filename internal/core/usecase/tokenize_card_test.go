@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// mockPaymentMethodRepo implements ports.PaymentMethodRepository
+type mockPaymentMethodRepo struct {
+	methods map[string]*domain.PaymentMethod
+}
+
+func newMockPaymentMethodRepo(methods ...*domain.PaymentMethod) *mockPaymentMethodRepo {
+	m := &mockPaymentMethodRepo{methods: map[string]*domain.PaymentMethod{}}
+	for _, pm := range methods {
+		m.methods[pm.Token] = pm
+	}
+	return m
+}
+
+func (m *mockPaymentMethodRepo) Create(ctx context.Context, pm *domain.PaymentMethod) error {
+	pm.ID = len(m.methods) + 1
+	m.methods[pm.Token] = pm
+	return nil
+}
+
+func (m *mockPaymentMethodRepo) FindByToken(ctx context.Context, token string) (*domain.PaymentMethod, error) {
+	pm, ok := m.methods[token]
+	if !ok {
+		return nil, domain.ErrPaymentMethodNotFound
+	}
+	return pm, nil
+}
+
+func (m *mockPaymentMethodRepo) FindByFingerprint(ctx context.Context, payerID int, fingerprint string) (*domain.PaymentMethod, error) {
+	for _, pm := range m.methods {
+		if pm.PayerID == payerID && pm.Fingerprint == fingerprint && pm.IsUsable() {
+			return pm, nil
+		}
+	}
+	return nil, domain.ErrPaymentMethodNotFound
+}
+
+func (m *mockPaymentMethodRepo) ListByPayerID(ctx context.Context, payerID int) ([]*domain.PaymentMethod, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockPaymentMethodRepo) UpdateStatus(ctx context.Context, token string, status domain.PaymentMethodStatus) error {
+	pm, ok := m.methods[token]
+	if !ok {
+		return domain.ErrPaymentMethodNotFound
+	}
+	pm.Status = status
+	return nil
+}
+
+// mockSecretBox implements ports.SecretBox with a reversible transform
+type mockSecretBox struct{}
+
+func (mockSecretBox) Seal(plaintext []byte) ([]byte, error) {
+	out := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		out[i] = b ^ 0xAA
+	}
+	return out, nil
+}
+
+func (b mockSecretBox) Open(ciphertext []byte) ([]byte, error) {
+	return b.Seal(ciphertext)
+}
+
+func (mockSecretBox) Fingerprint(data []byte) string {
+	return "fp:" + string(data)
+}
+
+func validCardInput() TokenizeCardInput {
+	return TokenizeCardInput{
+		PayerID:    1,
+		Number:     "4242 4242 4242 4242",
+		ExpMonth:   12,
+		ExpYear:    time.Now().Year() + 2,
+		CVC:        "123",
+		HolderName: "Jane Doe",
+	}
+}
+
+func TestTokenizeCard_Success(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := newMockPaymentMethodRepo()
+	uc := NewTokenizeCardUsecase(activePayers(), repo, mockSecretBox{})
+
+	pm, err := uc.Execute(context.Background(), validCardInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pm.Last4 != "4242" || pm.BIN != "424242" || pm.Brand != domain.CardBrandVisa {
+		t.Fatalf("unexpected card details: last4=%s bin=%s brand=%s", pm.Last4, pm.BIN, pm.Brand)
+	}
+	if pm.Type != domain.PaymentMethodTypeCard {
+		t.Fatalf("expected type %s, got %s", domain.PaymentMethodTypeCard, pm.Type)
+	}
+	if bytes.Contains(pm.EncryptedPAN, []byte("4242424242424242")) {
+		t.Fatalf("PAN must not be stored in clear")
+	}
+	if len(pm.Token) < 4 || pm.Token[:3] != "pm_" {
+		t.Fatalf("expected pm_ token, got %s", pm.Token)
+	}
+}
+
+func TestTokenizeCard_SameCardReturnsExistingToken(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewTokenizeCardUsecase(activePayers(), newMockPaymentMethodRepo(), mockSecretBox{})
+
+	first, err := uc.Execute(context.Background(), validCardInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := uc.Execute(context.Background(), validCardInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Token != second.Token {
+		t.Fatalf("expected same token, got %s and %s", first.Token, second.Token)
+	}
+}
+
+func TestTokenizeCard_Rejections(t *testing.T) {
+	observability.InitTracer("test")
+
+	uc := NewTokenizeCardUsecase(activePayers(), newMockPaymentMethodRepo(), mockSecretBox{})
+
+	badLuhn := validCardInput()
+	badLuhn.Number = "4242424242424241"
+	if _, err := uc.Execute(context.Background(), badLuhn); !errors.Is(err, domain.ErrInvalidCardNumber) {
+		t.Fatalf("expected ErrInvalidCardNumber, got %v", err)
+	}
+
+	expired := validCardInput()
+	expired.ExpYear = time.Now().Year() - 1
+	if _, err := uc.Execute(context.Background(), expired); !errors.Is(err, domain.ErrCardExpired) {
+		t.Fatalf("expected ErrCardExpired, got %v", err)
+	}
+
+	badCVC := validCardInput()
+	badCVC.CVC = "12"
+	if _, err := uc.Execute(context.Background(), badCVC); err == nil {
+		t.Fatalf("expected cvc validation error")
+	}
+}
+
+func TestExecute_WithPaymentMethodToken(t *testing.T) {
+	observability.InitTracer("test")
+
+	pm := &domain.PaymentMethod{
+		Token:    "pm_1",
+		PayerID:  1,
+		Type:     domain.PaymentMethodTypeCard,
+		ExpMonth: 12,
+		ExpYear:  time.Now().Year() + 1,
+		Status:   domain.PaymentMethodStatusActive,
+	}
+	repo := &mockPaymentRepo{}
+	provider := &mockPaymentProvider{}
+	uc := NewCreatePaymentUsecase(repo, activePayers(), newMockPaymentMethodRepo(pm), provider)
+
+	input := CreatePaymentInput{
+		OrderID:            "order_1",
+		PayerID:            1,
+		Amount:             10,
+		Currency:           "USD",
+		Provider:           "FAKE",
+		IdempotencyKey:     "idem-pm-1",
+		PaymentMethodToken: "pm_1",
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.calledWith != domain.PaymentMethodTypeCard {
+		t.Fatalf("expected provider to be called with %s, got %s", domain.PaymentMethodTypeCard, provider.calledWith)
+	}
+	if repo.createdPayment.PaymentMethodToken != "pm_1" {
+		t.Fatalf("expected token to be stored on payment")
+	}
+
+	// another payer must not be able to charge the same instrument
+	input.PayerID = 2
+	input.IdempotencyKey = "idem-pm-2"
+	if _, err := uc.Execute(context.Background(), input); !errors.Is(err, domain.ErrPaymentMethodNotOwned) {
+		t.Fatalf("expected ErrPaymentMethodNotOwned, got %v", err)
+	}
+}
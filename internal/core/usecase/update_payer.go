@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+type UpdatePayerInput struct {
+	PayerInput
+	Status domain.PayerStatus
+}
+
+type UpdatePayerUsecase struct {
+	payerRepo ports.PayerRepository
+}
+
+func NewUpdatePayerUsecase(
+	payerRepo ports.PayerRepository,
+) *UpdatePayerUsecase {
+	return &UpdatePayerUsecase{
+		payerRepo: payerRepo,
+	}
+}
+
+func (uc *UpdatePayerUsecase) Execute(
+	ctx context.Context,
+	id int,
+	input UpdatePayerInput,
+) (*domain.Payer, error) {
+	ctx, span := observability.Tracer().Start(ctx, "UpdatePayerUseCase.Execute")
+	defer span.End()
+
+	input.PayerInput = normalizePayerInput(input.PayerInput)
+	if valid, err := isValidPayerInput(input.PayerInput); !valid {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if input.Status != "" && !input.Status.IsValid() {
+		err := errors.New("status is invalid")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	payer, err := uc.payerRepo.FindByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	payer.Name = input.Name
+	payer.Email = input.Email
+	payer.Country = input.Country
+	payer.ExternalReference = input.ExternalReference
+	if input.Status != "" {
+		payer.Status = input.Status
+	}
+
+	if err := uc.payerRepo.Update(ctx, payer); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return payer, nil
+}
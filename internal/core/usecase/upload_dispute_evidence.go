@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type UploadDisputeEvidenceInput struct {
+	DisputeID   string
+	FileName    string
+	ContentType string
+	Note        string
+	File        io.Reader
+}
+
+type UploadDisputeEvidenceUsecase struct {
+	disputeRepo ports.DisputeRepository
+	store       ports.EvidenceStore
+	maxSize     int64
+	now         func() time.Time
+}
+
+func NewUploadDisputeEvidenceUsecase(
+	disputeRepo ports.DisputeRepository,
+	store ports.EvidenceStore,
+	maxSize int64,
+) *UploadDisputeEvidenceUsecase {
+	return &UploadDisputeEvidenceUsecase{
+		disputeRepo: disputeRepo,
+		store:       store,
+		maxSize:     maxSize,
+		now:         time.Now,
+	}
+}
+
+// Execute stores an evidence file for a dispute that still waits for the
+// merchant's response.
+func (uc *UploadDisputeEvidenceUsecase) Execute(
+	ctx context.Context,
+	input UploadDisputeEvidenceInput,
+) (*domain.DisputeEvidence, error) {
+	ctx, span := observability.Tracer().Start(ctx, "UploadDisputeEvidenceUseCase.Execute")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("dispute.id", input.DisputeID))
+
+	evidence, err := uc.upload(ctx, input)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return evidence, nil
+}
+
+func (uc *UploadDisputeEvidenceUsecase) upload(
+	ctx context.Context,
+	input UploadDisputeEvidenceInput,
+) (*domain.DisputeEvidence, error) {
+	if !domain.EvidenceContentTypes[input.ContentType] {
+		return nil, domain.ErrInvalidEvidence
+	}
+
+	dispute, err := uc.disputeRepo.FindByPublicID(ctx, input.DisputeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := acceptsEvidence(dispute, uc.now()); err != nil {
+		return nil, err
+	}
+
+	path, size, err := uc.store.Save(ctx, dispute.PublicID, input.FileName, input.File, uc.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence := &domain.DisputeEvidence{
+		DisputeID:   dispute.ID,
+		FileName:    input.FileName,
+		ContentType: input.ContentType,
+		Size:        size,
+		Path:        path,
+		Note:        input.Note,
+	}
+	if err := uc.disputeRepo.AddEvidence(ctx, evidence); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// acceptsEvidence reports why the merchant can no longer respond to
+// dispute, if they can't.
+func acceptsEvidence(dispute *domain.Dispute, now time.Time) error {
+	switch {
+	case dispute.Status.IsClosed():
+		return domain.ErrDisputeClosed
+	case dispute.Status != domain.DisputeStatusNeedsResponse:
+		return domain.ErrInvalidDisputeStatus
+	case now.After(dispute.EvidenceDueBy):
+		return domain.ErrEvidenceDeadlinePassed
+	}
+	return nil
+}
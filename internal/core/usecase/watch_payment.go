@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/ports"
+	"payment-service/internal/observability"
+	"slices"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// PaymentWatch is a subscription to a payment's status changes.
+type PaymentWatch struct {
+	// Events are what the watcher has not seen yet, oldest first: the
+	// changes after the event it resumes from, or the payment's current
+	// state for a new watcher.
+	Events []domain.PaymentEvent
+	// Live receives the changes made from now on. It is closed when the
+	// watcher falls behind or the service shuts down, and the watcher should
+	// resume from its last event.
+	Live <-chan domain.PaymentEvent
+	// Close ends the subscription.
+	Close func()
+	// Final is set when the payment already reached a final status, so no
+	// change will follow Events.
+	Final bool
+}
+
+type WatchPaymentUsecase struct {
+	paymentRepo ports.PaymentRepository
+	events      ports.PaymentEvents
+}
+
+func NewWatchPaymentUsecase(
+	paymentRepo ports.PaymentRepository,
+	events ports.PaymentEvents,
+) *WatchPaymentUsecase {
+	return &WatchPaymentUsecase{
+		paymentRepo: paymentRepo,
+		events:      events,
+	}
+}
+
+// Execute subscribes to the payment's status changes. lastEventID is the
+// last event the watcher saw before reconnecting, zero for a new watcher.
+func (uc *WatchPaymentUsecase) Execute(
+	ctx context.Context,
+	publicID string,
+	lastEventID int64,
+) (*PaymentWatch, error) {
+	ctx, span := observability.Tracer().Start(ctx, "WatchPaymentUseCase.Execute")
+	defer span.End()
+
+	// also makes sure the payment belongs to the caller's merchant
+	payment, err := uc.paymentRepo.FindbyPublicID(ctx, publicID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return uc.subscribe(payment, lastEventID), nil
+}
+
+// subscribe merges the events still in memory with the payment's current
+// state. Subscribing after the payment was loaded cannot miss a change: any
+// made since then is newer than the snapshot and part of past.
+func (uc *WatchPaymentUsecase) subscribe(payment *domain.Payment, lastEventID int64) *PaymentWatch {
+	snapshot := domain.NewPaymentEvent(payment, "")
+
+	after := lastEventID
+	if after == 0 {
+		// a new watcher starts from the current state
+		after = snapshot.ID - 1
+	}
+	past, live, cancel := uc.events.Subscribe(payment.PublicID, after)
+
+	// the snapshot stands in for changes missing from memory, e.g. made by
+	// another instance or before a restart
+	events := past
+	seen := slices.ContainsFunc(past, func(e domain.PaymentEvent) bool {
+		return e.ID >= snapshot.ID
+	})
+	if snapshot.ID > after && !seen {
+		events = append(events, snapshot)
+	}
+
+	return &PaymentWatch{
+		Events: events,
+		Live:   live,
+		Close:  cancel,
+		Final:  payment.Status.IsFinal(),
+	}
+}
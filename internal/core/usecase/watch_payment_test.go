@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/observability"
+)
+
+// stubPaymentEvents keeps published events and replays them to
+// subscribers.
+type stubPaymentEvents struct {
+	history []domain.PaymentEvent
+}
+
+func (s *stubPaymentEvents) Publish(event domain.PaymentEvent) {
+	s.history = append(s.history, event)
+}
+
+func (s *stubPaymentEvents) Subscribe(
+	paymentID string,
+	afterID int64,
+) ([]domain.PaymentEvent, <-chan domain.PaymentEvent, func()) {
+	var past []domain.PaymentEvent
+	for _, e := range s.history {
+		if e.ID > afterID {
+			past = append(past, e)
+		}
+	}
+	return past, make(chan domain.PaymentEvent), func() {}
+}
+
+func TestWatchPayment_ResumesFromLastEvent(t *testing.T) {
+	observability.InitTracer("test")
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	payment := &domain.Payment{
+		PublicID:  "pay_1",
+		Status:    domain.PaymentStatusPending,
+		UpdatedAt: created,
+	}
+	pending := domain.NewPaymentEvent(payment, "")
+
+	payment.Status = domain.PaymentStatusRequiresAction
+	payment.UpdatedAt = created.Add(time.Second)
+	requiresAction := domain.NewPaymentEvent(payment, domain.PaymentStatusPending)
+
+	payment.Status = domain.PaymentStatusSuccess
+	payment.UpdatedAt = created.Add(2 * time.Second)
+	success := domain.NewPaymentEvent(payment, domain.PaymentStatusRequiresAction)
+
+	cases := []struct {
+		name        string
+		history     []domain.PaymentEvent
+		lastEventID int64
+		want        []int64
+	}{
+		{"new watcher gets the current state", []domain.PaymentEvent{requiresAction, success}, 0, []int64{success.ID}},
+		{"resume replays missed changes", []domain.PaymentEvent{requiresAction, success}, pending.ID, []int64{requiresAction.ID, success.ID}},
+		{"resume without history gets the current state", nil, pending.ID, []int64{success.ID}},
+		{"resume when up to date gets nothing", []domain.PaymentEvent{success}, success.ID, nil},
+	}
+	for _, tc := range cases {
+		repo := &mockGetPaymentRepo{returned: payment}
+		uc := NewWatchPaymentUsecase(repo, &stubPaymentEvents{history: tc.history})
+
+		watch, err := uc.Execute(context.Background(), "pay_1", tc.lastEventID)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+
+		var got []int64
+		for _, e := range watch.Events {
+			got = append(got, e.ID)
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: expected events %v, got %v", tc.name, tc.want, got)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: expected events %v, got %v", tc.name, tc.want, got)
+			}
+		}
+	}
+}
+
+func TestWatchPayment_UnknownPayment(t *testing.T) {
+	observability.InitTracer("test")
+
+	repo := &mockGetPaymentRepo{err: domain.ErrPaymentNotFound}
+	uc := NewWatchPaymentUsecase(repo, &stubPaymentEvents{})
+
+	if _, err := uc.Execute(context.Background(), "pay_missing", 0); err != domain.ErrPaymentNotFound {
+		t.Fatalf("expected ErrPaymentNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"payment-service/internal/auth"
+	"payment-service/internal/core/domain"
+	paymentv1 "payment-service/internal/grpc/gen/payment/v1"
+)
+
+// methodScopes is the scope each method requires.
+var methodScopes = map[string]string{
+	paymentv1.PaymentService_CreatePayment_FullMethodName: domain.ScopePaymentsWrite,
+	paymentv1.PaymentService_GetPayment_FullMethodName:    domain.ScopePaymentsRead,
+	paymentv1.PaymentService_ListPayments_FullMethodName:  domain.ScopePaymentsRead,
+	paymentv1.PaymentService_WatchPayment_FullMethodName:  domain.ScopePaymentsRead,
+}
+
+// Authenticator authenticates calls the way the HTTP merchant API does,
+// with the token sent as "authorization: Bearer <token>" metadata.
+type Authenticator struct {
+	authn *auth.MerchantAuthenticator
+}
+
+func NewAuthenticator(authn *auth.MerchantAuthenticator) *Authenticator {
+	return &Authenticator{authn: authn}
+}
+
+func (a *Authenticator) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate scopes ctx to the caller's merchant and checks it was
+// granted the scope method requires.
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	principal, tenant, err := a.authn.Authenticate(ctx, bearerToken(ctx))
+	switch {
+	case errors.Is(err, domain.ErrTokenMerchantRequired):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrInvalidToken), errors.Is(err, domain.ErrInvalidAPIKey):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok || !principal.HasScope(scope) {
+		return nil, status.Error(codes.PermissionDenied, "missing scope "+scope)
+	}
+
+	ctx = domain.WithPrincipal(ctx, principal)
+	return domain.WithTenant(ctx, tenant), nil
+}
+
+// bearerToken returns the token of an "authorization: Bearer <token>"
+// metadata entry, or "" when there is none.
+func bearerToken(ctx context.Context) string {
+	scheme, token, ok := strings.Cut(firstMetadata(ctx, "authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// authenticatedStream hands the authenticated context to stream handlers.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
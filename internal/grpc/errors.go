@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"payment-service/internal/core/domain"
+)
+
+// toStatus maps usecase errors to the gRPC codes closest to the statuses
+// the HTTP API answers with. Errors the domain does not name are internal
+// failures.
+func toStatus(err error) error {
+	return status.Error(statusCode(err), err.Error())
+}
+
+func statusCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPayment),
+		errors.Is(err, domain.ErrInvalidSplits),
+		errors.Is(err, domain.ErrInvalidReturnURL),
+		errors.Is(err, domain.ErrUnsupportedBank),
+		errors.Is(err, domain.ErrUnsupportedWallet),
+		errors.Is(err, domain.ErrUnsupportedCurrency):
+		return codes.InvalidArgument
+	case errors.Is(err, domain.ErrPaymentNotFound),
+		errors.Is(err, domain.ErrPayerNotFound),
+		errors.Is(err, domain.ErrPaymentMethodNotFound):
+		return codes.NotFound
+	case errors.Is(err, domain.ErrPaymentMethodNotOwned):
+		return codes.PermissionDenied
+	case errors.Is(err, domain.ErrPayerNotActive),
+		errors.Is(err, domain.ErrPaymentMethodInactive),
+		errors.Is(err, domain.ErrCardExpired),
+		errors.Is(err, domain.ErrCardAuthenticationFailed),
+		errors.Is(err, domain.ErrPaymentBlocked),
+		errors.Is(err, domain.ErrPaymentDeclined),
+		errors.Is(err, domain.ErrInvalidPaymentStatus):
+		return codes.FailedPrecondition
+	case errors.Is(err, domain.ErrPaymentStatusConflict):
+		return codes.Aborted
+	case errors.Is(err, domain.ErrLimitExceeded):
+		return codes.ResourceExhausted
+	case errors.Is(err, domain.ErrProviderUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"payment-service/internal/core/domain"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{&domain.InvalidPaymentError{Reason: "amount must be greater than zero"}, codes.InvalidArgument},
+		{fmt.Errorf("%w: shares exceed the amount", domain.ErrInvalidSplits), codes.InvalidArgument},
+		{domain.ErrInvalidReturnURL, codes.InvalidArgument},
+		{domain.ErrUnsupportedBank, codes.InvalidArgument},
+		{domain.ErrPaymentNotFound, codes.NotFound},
+		{domain.ErrPayerNotFound, codes.NotFound},
+		{domain.ErrPaymentMethodNotOwned, codes.PermissionDenied},
+		{domain.ErrPayerNotActive, codes.FailedPrecondition},
+		{domain.ErrCardExpired, codes.FailedPrecondition},
+		{domain.ErrPaymentBlocked, codes.FailedPrecondition},
+		{fmt.Errorf("%w: insufficient funds", domain.ErrPaymentDeclined), codes.FailedPrecondition},
+		{domain.ErrPaymentStatusConflict, codes.Aborted},
+		{&domain.LimitExceededError{Limit: "daily"}, codes.ResourceExhausted},
+		{domain.ErrProviderUnavailable, codes.Unavailable},
+		{fmt.Errorf("find payer: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{errors.New("database is locked"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := status.Code(toStatus(tt.err)); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
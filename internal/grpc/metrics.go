@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"payment-service/internal/observability"
+)
+
+// UnaryMetrics records the outcome and latency of unary calls.
+func UnaryMetrics() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+// StreamMetrics records the outcome and duration of streams and counts the
+// messages sent on them.
+func StreamMetrics() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, &countingStream{ServerStream: ss, method: info.FullMethod})
+		observe(info.FullMethod, err, start)
+		return err
+	}
+}
+
+func observe(method string, err error, start time.Time) {
+	code := status.Code(err).String()
+	observability.GRPCRequests.WithLabelValues(method, code).Inc()
+	observability.GRPCDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+type countingStream struct {
+	grpc.ServerStream
+	method string
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		observability.GRPCStreamMessages.WithLabelValues(s.method).Inc()
+	}
+	return err
+}
@@ -0,0 +1,309 @@
+// Package grpc serves the payments API to internal services over gRPC. It
+// runs the same usecases as the HTTP handlers.
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	paymentv1 "payment-service/internal/grpc/gen/payment/v1"
+	"payment-service/internal/observability"
+)
+
+// IdempotencyKeyHeader is the metadata key CreatePayment reads the
+// idempotency key from.
+const IdempotencyKeyHeader = "idempotency-key"
+
+// ListPayments page sizes, the same as on the HTTP API.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// PaymentServer implements paymentv1.PaymentServiceServer.
+type PaymentServer struct {
+	paymentv1.UnimplementedPaymentServiceServer
+
+	createPaymentUC     *usecase.CreatePaymentUsecase
+	getPaymentUC        *usecase.GetPaymentUsecase
+	listPayerPaymentsUC *usecase.ListPayerPaymentsUsecase
+	watchPaymentUC      *usecase.WatchPaymentUsecase
+}
+
+func NewPaymentServer(
+	createPaymentUC *usecase.CreatePaymentUsecase,
+	getPaymentUC *usecase.GetPaymentUsecase,
+	listPayerPaymentsUC *usecase.ListPayerPaymentsUsecase,
+	watchPaymentUC *usecase.WatchPaymentUsecase,
+) *PaymentServer {
+	return &PaymentServer{
+		createPaymentUC:     createPaymentUC,
+		getPaymentUC:        getPaymentUC,
+		listPayerPaymentsUC: listPayerPaymentsUC,
+		watchPaymentUC:      watchPaymentUC,
+	}
+}
+
+// NewServer builds the gRPC server. Calls are traced by the OpenTelemetry
+// stats handler, counted by the Prometheus interceptors and then
+// authenticated.
+func NewServer(payments *PaymentServer, auth *Authenticator) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(UnaryMetrics(), auth.Unary()),
+		grpc.ChainStreamInterceptor(StreamMetrics(), auth.Stream()),
+	)
+	paymentv1.RegisterPaymentServiceServer(srv, payments)
+	return srv
+}
+
+func (s *PaymentServer) CreatePayment(
+	ctx context.Context,
+	req *paymentv1.CreatePaymentRequest,
+) (*paymentv1.CreatePaymentResponse, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentServer.CreatePayment")
+	defer span.End()
+
+	idempotencyKey := firstMetadata(ctx, IdempotencyKeyHeader)
+	if idempotencyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "idempotency-key metadata is required")
+	}
+
+	output, err := s.createPaymentUC.Execute(ctx, usecase.CreatePaymentInput{
+		OrderID:        req.GetOrderId(),
+		PayerID:        int(req.GetPayerId()),
+		Amount:         int(req.GetAmount()),
+		Currency:       req.GetCurrency(),
+		Provider:       req.GetProvider(),
+		Method:         req.GetMethod(),
+		IdempotencyKey: idempotencyKey,
+
+		PaymentMethodToken: req.GetPaymentMethod(),
+		BankCode:           req.GetBankCode(),
+		Wallet:             req.GetWallet(),
+		Channel:            req.GetChannel(),
+		ReturnURL:          req.GetReturnUrl(),
+		ClientIP:           clientIP(ctx),
+		Splits:             toSplitShares(req.GetSplits()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toCreatePaymentResponse(output), nil
+}
+
+func (s *PaymentServer) GetPayment(
+	ctx context.Context,
+	req *paymentv1.GetPaymentRequest,
+) (*paymentv1.GetPaymentResponse, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentServer.GetPayment")
+	defer span.End()
+
+	payment, err := s.getPaymentUC.Execute(ctx, req.GetPaymentId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &paymentv1.GetPaymentResponse{Payment: toPayment(payment)}, nil
+}
+
+// ListPayments pages with the offset of the next page as its token.
+func (s *PaymentServer) ListPayments(
+	ctx context.Context,
+	req *paymentv1.ListPaymentsRequest,
+) (*paymentv1.ListPaymentsResponse, error) {
+	ctx, span := observability.Tracer().Start(ctx, "PaymentServer.ListPayments")
+	defer span.End()
+
+	var offset int
+	if token := req.GetPageToken(); token != "" {
+		var err error
+		offset, err = strconv.Atoi(token)
+		if err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+	limit := int(req.GetPageSize())
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, maxPageSize)
+
+	payments, err := s.listPayerPaymentsUC.Execute(ctx, int(req.GetPayerId()), limit, offset)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &paymentv1.ListPaymentsResponse{
+		Payments: make([]*paymentv1.Payment, 0, len(payments)),
+	}
+	for _, p := range payments {
+		res.Payments = append(res.Payments, toPayment(p))
+	}
+	// a full page may be followed by another one
+	if len(payments) == limit {
+		res.NextPageToken = strconv.Itoa(offset + len(payments))
+	}
+	return res, nil
+}
+
+// WatchPayment sends the payment, then its status changes as they are
+// made. The stream ends once the payment reaches a final status or the
+// caller goes away.
+func (s *PaymentServer) WatchPayment(
+	req *paymentv1.WatchPaymentRequest,
+	stream grpc.ServerStreamingServer[paymentv1.WatchPaymentResponse],
+) error {
+	ctx := stream.Context()
+
+	watch, err := s.watchPaymentUC.Execute(ctx, req.GetPaymentId(), 0)
+	if err != nil {
+		return toStatus(err)
+	}
+	defer watch.Close()
+
+	send := func(event domain.PaymentEvent) (bool, error) {
+		err := stream.Send(&paymentv1.WatchPaymentResponse{
+			Payment:        toPayment(&event.Payment),
+			PreviousStatus: string(event.From),
+		})
+		return event.Payment.Status.IsFinal(), err
+	}
+
+	for _, event := range watch.Events {
+		if done, err := send(event); done || err != nil {
+			return err
+		}
+	}
+	if watch.Final {
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-watch.Live:
+			if !ok {
+				return status.Error(codes.Unavailable, "watcher fell behind, watch again")
+			}
+			if done, err := send(event); done || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// firstMetadata returns the first value of the incoming metadata key, or ""
+// when there is none.
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// clientIP is the caller's address, without the port.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func toSplitShares(splits []*paymentv1.Split) []domain.SplitShare {
+	if len(splits) == 0 {
+		return nil
+	}
+	shares := make([]domain.SplitShare, 0, len(splits))
+	for _, s := range splits {
+		shares = append(shares, domain.SplitShare{
+			Recipient:  s.GetRecipient(),
+			Amount:     int(s.GetAmount()),
+			Percentage: s.GetPercentage(),
+			FeeBearer:  s.GetFeeBearer(),
+		})
+	}
+	return shares
+}
+
+func toCreatePaymentResponse(output *usecase.CreatePaymentOutput) *paymentv1.CreatePaymentResponse {
+	res := &paymentv1.CreatePaymentResponse{
+		PaymentId: output.PaymentID,
+		Status:    string(output.Status),
+		ExpiresAt: optionalTimestamp(output.ExpiresAt),
+	}
+	if va := output.VirtualAccount; va != nil {
+		res.PaymentInstructions = &paymentv1.PaymentInstructions{
+			Type:          domain.PaymentMethodBankTransfer,
+			BankCode:      va.BankCode,
+			AccountNumber: va.AccountNumber,
+			Amount:        int64(va.Outstanding()),
+			ExpiresAt:     timestamppb.New(va.ExpiresAt),
+		}
+	}
+	if na := output.NextAction; na != nil {
+		res.NextAction = &paymentv1.NextAction{
+			Type:        string(na.Type),
+			RedirectUrl: na.RedirectURL,
+			Deeplink:    na.Deeplink,
+			QrString:    na.QRString,
+		}
+	}
+	return res
+}
+
+func toPayment(payment *domain.Payment) *paymentv1.Payment {
+	res := &paymentv1.Payment{
+		PaymentId:     payment.PublicID,
+		OrderId:       payment.OrderID,
+		PayerId:       int64(payment.PayerID),
+		Amount:        int64(payment.Amount),
+		Currency:      payment.Currency,
+		Status:        string(payment.Status),
+		Provider:      payment.Provider,
+		Method:        payment.Method,
+		PaymentMethod: payment.PaymentMethodToken,
+		Mode:          string(payment.Mode),
+		CreatedBy:     payment.CreatedBy,
+		CreatedAt:     timestamppb.New(payment.CreatedAt),
+		PaidAt:        optionalTimestamp(payment.PaidAt),
+		ExpiresAt:     optionalTimestamp(payment.ExpiresAt),
+	}
+	for _, s := range payment.Splits {
+		res.Splits = append(res.Splits, &paymentv1.Split{
+			Recipient:  s.Recipient,
+			Amount:     int64(s.Amount),
+			Percentage: s.Percentage(),
+			FeeBearer:  s.FeeBearer,
+		})
+	}
+	return res
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,299 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"payment-service/internal/auth"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	paymentv1 "payment-service/internal/grpc/gen/payment/v1"
+	"payment-service/internal/observability"
+)
+
+// tenantPayments only returns the payments of the merchant in the context,
+// like the SQLite repository.
+type tenantPayments struct {
+	payments []*domain.Payment
+}
+
+func (r *tenantPayments) visible(ctx context.Context, p *domain.Payment) bool {
+	tenant, ok := domain.TenantFromContext(ctx)
+	return ok && tenant.MerchantID == p.MerchantID
+}
+
+func (r *tenantPayments) Create(ctx context.Context, p *domain.Payment) error {
+	return errors.New("not implemented")
+}
+
+func (r *tenantPayments) FindByIdempotencyKey(ctx context.Context, key string) (*domain.Payment, error) {
+	return nil, domain.ErrPaymentNotFound
+}
+
+func (r *tenantPayments) FindbyPublicID(ctx context.Context, publicID string) (*domain.Payment, error) {
+	for _, p := range r.payments {
+		if p.PublicID == publicID && r.visible(ctx, p) {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, domain.ErrPaymentNotFound
+}
+
+func (r *tenantPayments) ListByPayerID(ctx context.Context, payerID int, limit, offset int) ([]*domain.Payment, error) {
+	var out []*domain.Payment
+	for _, p := range r.payments {
+		if p.PayerID == payerID && r.visible(ctx, p) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *tenantPayments) UpdateStatus(ctx context.Context, p *domain.Payment, from domain.PaymentStatus) error {
+	return errors.New("not implemented")
+}
+
+func (r *tenantPayments) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Payment, error) {
+	return nil, nil
+}
+
+func (r *tenantPayments) ListAwaitingReview(ctx context.Context, limit, offset int) ([]*domain.Payment, error) {
+	return nil, nil
+}
+
+// tenantPayers holds payer 1 of merchant 1.
+type tenantPayers struct{}
+
+func (tenantPayers) Create(ctx context.Context, p *domain.Payer) error { return nil }
+func (tenantPayers) Update(ctx context.Context, p *domain.Payer) error { return nil }
+
+func (tenantPayers) FindByID(ctx context.Context, id int) (*domain.Payer, error) {
+	tenant, _ := domain.TenantFromContext(ctx)
+	if id != 1 || tenant.MerchantID != 1 {
+		return nil, domain.ErrPayerNotFound
+	}
+	return &domain.Payer{ID: 1, MerchantID: 1}, nil
+}
+
+func (tenantPayers) FindByEmail(ctx context.Context, email string) (*domain.Payer, error) {
+	return nil, domain.ErrPayerNotFound
+}
+
+func (tenantPayers) List(ctx context.Context, limit, offset int) ([]*domain.Payer, error) {
+	return nil, nil
+}
+
+// apiKeys gives merchant n the secret "sk_n".
+type apiKeys struct{}
+
+func (apiKeys) Execute(ctx context.Context, secret string) (domain.Tenant, error) {
+	switch secret {
+	case "sk_1":
+		return domain.Tenant{MerchantID: 1, Mode: domain.ModeLive, APIKeyID: 1}, nil
+	case "sk_2":
+		return domain.Tenant{MerchantID: 2, Mode: domain.ModeLive, APIKeyID: 2}, nil
+	}
+	return domain.Tenant{}, domain.ErrInvalidAPIKey
+}
+
+type serviceTokens struct{}
+
+func (serviceTokens) Execute(ctx context.Context, merchantID string, mode domain.Mode) (domain.Tenant, error) {
+	switch merchantID {
+	case "":
+		return domain.Tenant{}, domain.ErrTokenMerchantRequired
+	case "mer_1":
+		return domain.Tenant{MerchantID: 1, Mode: domain.ModeLive}, nil
+	}
+	return domain.Tenant{}, domain.ErrInvalidToken
+}
+
+type testServer struct {
+	client paymentv1.PaymentServiceClient
+	key    *rsa.PrivateKey
+}
+
+// startServer serves the payments API over an in-memory listener. Payment
+// pay_1 of payer 1 belongs to merchant 1. CreatePayment and WatchPayment
+// have no usecase, so they fail if a call gets past authentication.
+func startServer(t *testing.T) *testServer {
+	t.Helper()
+	observability.InitTracer("test")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	verifier := auth.NewJWTVerifier(map[string]auth.JWK{"k1": {ID: "k1", Key: &key.PublicKey}}, "", "")
+
+	payments := &tenantPayments{payments: []*domain.Payment{{
+		PublicID:   "pay_1",
+		MerchantID: 1,
+		PayerID:    1,
+		Amount:     1000,
+		Currency:   "IDR",
+		Status:     domain.PaymentStatusPending,
+		CreatedAt:  time.Now(),
+	}}}
+	srv := NewServer(
+		NewPaymentServer(
+			nil,
+			usecase.NewGetPaymentUsecase(payments),
+			usecase.NewListPayerPaymentsUsecase(tenantPayers{}, payments),
+			nil,
+		),
+		NewAuthenticator(auth.NewMerchantAuthenticator(apiKeys{}, serviceTokens{}, verifier)),
+	)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &testServer{client: paymentv1.NewPaymentServiceClient(conn), key: key}
+}
+
+// token signs a service token for merchantID granting scope.
+func (s *testServer) token(t *testing.T, merchantID, scope string) string {
+	t.Helper()
+	claims := &auth.ServiceClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "svc-orders",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Scope:      scope,
+		MerchantID: merchantID,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func withToken(token string) context.Context {
+	ctx := context.Background()
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestAuthenticator_Credentials(t *testing.T) {
+	s := startServer(t)
+
+	tests := []struct {
+		name  string
+		token string
+		want  codes.Code
+	}{
+		{"no token", "", codes.Unauthenticated},
+		{"unknown api key", "sk_9", codes.Unauthenticated},
+		{"api key", "sk_1", codes.OK},
+		{"service token", s.token(t, "mer_1", domain.ScopePaymentsRead), codes.OK},
+		{"service token without merchant", s.token(t, "", domain.ScopePaymentsRead), codes.PermissionDenied},
+		{"service token of unknown merchant", s.token(t, "mer_9", domain.ScopePaymentsRead), codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.client.GetPayment(withToken(tt.token), &paymentv1.GetPaymentRequest{PaymentId: "pay_1"})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("expected %s, got %s: %v", tt.want, got, err)
+			}
+		})
+	}
+
+	t.Run("stream without token", func(t *testing.T) {
+		stream, err := s.client.WatchPayment(context.Background(), &paymentv1.WatchPaymentRequest{PaymentId: "pay_1"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if got := status.Code(err); got != codes.Unauthenticated {
+			t.Fatalf("expected Unauthenticated, got %s: %v", got, err)
+		}
+	})
+}
+
+func TestAuthenticator_MethodScopes(t *testing.T) {
+	s := startServer(t)
+	readOnly := withToken(s.token(t, "mer_1", domain.ScopePaymentsRead))
+
+	if _, err := s.client.GetPayment(readOnly, &paymentv1.GetPaymentRequest{PaymentId: "pay_1"}); err != nil {
+		t.Fatalf("expected read to be allowed, got %v", err)
+	}
+	if _, err := s.client.ListPayments(readOnly, &paymentv1.ListPaymentsRequest{PayerId: 1}); err != nil {
+		t.Fatalf("expected list to be allowed, got %v", err)
+	}
+
+	_, err := s.client.CreatePayment(
+		metadata.AppendToOutgoingContext(readOnly, IdempotencyKeyHeader, "idem-1"),
+		&paymentv1.CreatePaymentRequest{PayerId: 1, Amount: 1000, Currency: "IDR"},
+	)
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a write, got %s: %v", got, err)
+	}
+
+	writeOnly := withToken(s.token(t, "mer_1", domain.ScopePaymentsWrite))
+	_, err = s.client.GetPayment(writeOnly, &paymentv1.GetPaymentRequest{PaymentId: "pay_1"})
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a read, got %s: %v", got, err)
+	}
+	stream, err := s.client.WatchPayment(writeOnly, &paymentv1.WatchPaymentRequest{PaymentId: "pay_1"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a watch, got %s: %v", got, err)
+	}
+}
+
+func TestAuthenticator_TenantScoping(t *testing.T) {
+	s := startServer(t)
+
+	res, err := s.client.GetPayment(withToken("sk_1"), &paymentv1.GetPaymentRequest{PaymentId: "pay_1"})
+	if err != nil || res.GetPayment().GetPaymentId() != "pay_1" {
+		t.Fatalf("expected the owner to see its payment, got %v %v", res, err)
+	}
+
+	// another merchant cannot tell the payment exists
+	_, err = s.client.GetPayment(withToken("sk_2"), &paymentv1.GetPaymentRequest{PaymentId: "pay_1"})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Fatalf("expected NotFound for another merchant, got %s: %v", got, err)
+	}
+	_, err = s.client.ListPayments(withToken("sk_2"), &paymentv1.ListPaymentsRequest{PayerId: 1})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Fatalf("expected NotFound for another merchant's payer, got %s: %v", got, err)
+	}
+
+	list, err := s.client.ListPayments(withToken(s.token(t, "mer_1", domain.ScopePaymentsRead)), &paymentv1.ListPaymentsRequest{PayerId: 1})
+	if err != nil || len(list.GetPayments()) != 1 {
+		t.Fatalf("expected the service acting for merchant 1 to list its payment, got %v %v", list, err)
+	}
+}
@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusReady    Status = "ready"
+	StatusNotReady Status = "not_ready"
+	// StatusDraining is reported once the service started shutting down, so
+	// load balancers stop sending it new requests.
+	StatusDraining Status = "draining"
+)
+
+// Check is one dependency looked at before the service reports ready.
+type Check struct {
+	Name string
+	// Critical checks make the service not ready when they fail; the others
+	// are only reported.
+	Critical bool
+	// Timeout overrides the checker's default for this check.
+	Timeout time.Duration
+	Func    func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Checker runs the readiness checks of the service.
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Drain makes the service report not ready from now on, for the rest of
+// the shutdown.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Ready runs every check at once, each bounded by its own timeout, and
+// reports them in the order they were given.
+func (c *Checker) Ready(ctx context.Context) Report {
+	results := make([]CheckResult, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Checks: results}
+	for _, res := range results {
+		if res.Critical && res.Status != "ok" {
+			report.Status = StatusNotReady
+		}
+	}
+	if c.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	// the check may ignore ctx, so it is not waited for past the timeout
+	done := make(chan error, 1)
+	go func() { done <- check.Func(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("timed out after " + timeout.String())
+	}
+
+	res := CheckResult{
+		Name:       check.Name,
+		Status:     "ok",
+		Critical:   check.Critical,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Status = "fail"
+		res.Error = err.Error()
+	}
+	return res
+}
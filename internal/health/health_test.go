@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("down") }
+	hanging := func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	cases := []struct {
+		name   string
+		checks []Check
+		want   Status
+		errors []string
+	}{
+		{
+			name:   "all ok",
+			checks: []Check{{Name: "db", Critical: true, Func: ok}, {Name: "otel", Func: ok}},
+			want:   StatusReady,
+			errors: []string{"", ""},
+		},
+		{
+			name:   "non-critical failure",
+			checks: []Check{{Name: "db", Critical: true, Func: ok}, {Name: "otel", Func: failing}},
+			want:   StatusReady,
+			errors: []string{"", "down"},
+		},
+		{
+			name:   "critical failure",
+			checks: []Check{{Name: "db", Critical: true, Func: failing}, {Name: "otel", Func: ok}},
+			want:   StatusNotReady,
+			errors: []string{"down", ""},
+		},
+		{
+			name:   "timeout",
+			checks: []Check{{Name: "db", Critical: true, Timeout: 20 * time.Millisecond, Func: hanging}},
+			want:   StatusNotReady,
+			errors: []string{"timed out after 20ms"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := NewChecker(time.Second, tc.checks...).Ready(context.Background())
+			if report.Status != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, report.Status)
+			}
+			for i, res := range report.Checks {
+				if res.Name != tc.checks[i].Name || res.Error != tc.errors[i] {
+					t.Errorf("check %d: got %s/%q, want %s/%q", i, res.Name, res.Error, tc.checks[i].Name, tc.errors[i])
+				}
+				if (res.Status == "ok") != (tc.errors[i] == "") {
+					t.Errorf("check %d: unexpected status %s", i, res.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestChecker_Drain(t *testing.T) {
+	c := NewChecker(time.Second, Check{Name: "db", Critical: true, Func: func(context.Context) error { return nil }})
+	if got := c.Ready(context.Background()).Status; got != StatusReady {
+		t.Fatalf("expected ready, got %s", got)
+	}
+	c.Drain()
+	if got := c.Ready(context.Background()).Status; got != StatusDraining {
+		t.Fatalf("expected draining, got %s", got)
+	}
+}
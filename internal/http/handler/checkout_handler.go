@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var checkoutTemplates = template.Must(
+	template.ParseFS(templateFS, "templates/*.html"),
+)
+
+type checkoutMethod struct {
+	Value string
+	Label string
+}
+
+var checkoutMethods = []checkoutMethod{
+	{Value: "credit_card", Label: "Credit / debit card"},
+	{Value: "bank_transfer", Label: "Bank transfer"},
+	{Value: "ewallet", Label: "E-wallet"},
+}
+
+// checkoutBanks are the banks payers can pick for a bank transfer.
+var checkoutBanks = []checkoutMethod{
+	{Value: "BCA", Label: "BCA"},
+	{Value: "BNI", Label: "BNI"},
+	{Value: "BRI", Label: "BRI"},
+	{Value: "MANDIRI", Label: "Mandiri"},
+	{Value: "PERMATA", Label: "Permata"},
+}
+
+type checkoutForm struct {
+	Name     string
+	Email    string
+	Country  string
+	Method   string
+	BankCode string
+}
+
+type checkoutPage struct {
+	Title          string
+	Link           *domain.PaymentLink
+	Amount         string
+	Methods        []checkoutMethod
+	Banks          []checkoutMethod
+	Form           checkoutForm
+	IdempotencyKey string
+	Error          string
+}
+
+type resultPage struct {
+	Title          string
+	Link           *domain.PaymentLink
+	Amount         string
+	PaymentID      string
+	Status         string
+	PollIntervalMs int
+	// Transfer is set for bank transfers that still wait for the money.
+	Transfer *transferInstructions
+}
+
+type transferInstructions struct {
+	BankCode      string
+	AccountNumber string
+	Amount        string
+	ExpiresAt     string
+}
+
+type unavailablePage struct {
+	Title   string
+	Message string
+}
+
+// CheckoutHandler serves the hosted checkout pages for payment links.
+type CheckoutHandler struct {
+	getPaymentLinkUC    *usecase.GetPaymentLinkUsecase
+	payPaymentLinkUC    *usecase.PayPaymentLinkUsecase
+	getPaymentUC        *usecase.GetPaymentUsecase
+	getVirtualAccountUC *usecase.GetVirtualAccountUsecase
+	// baseURL builds the absolute return url for e-wallet payers.
+	baseURL string
+}
+
+func NewCheckoutHandler(
+	getPaymentLinkUC *usecase.GetPaymentLinkUsecase,
+	payPaymentLinkUC *usecase.PayPaymentLinkUsecase,
+	getPaymentUC *usecase.GetPaymentUsecase,
+	getVirtualAccountUC *usecase.GetVirtualAccountUsecase,
+	baseURL string,
+) *CheckoutHandler {
+	return &CheckoutHandler{
+		getPaymentLinkUC:    getPaymentLinkUC,
+		payPaymentLinkUC:    payPaymentLinkUC,
+		getPaymentUC:        getPaymentUC,
+		getVirtualAccountUC: getVirtualAccountUC,
+		baseURL:             strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func formatAmount(amount int, currency string) string {
+	return fmt.Sprintf("%s %d", currency, amount)
+}
+
+func render(c *gin.Context, status int, name string, data any) {
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := checkoutTemplates.ExecuteTemplate(c.Writer, name, data); err != nil {
+		log.Printf("render %s: %v", name, err)
+	}
+}
+
+func (h *CheckoutHandler) renderUnavailable(c *gin.Context, err error) {
+	status := http.StatusGone
+	message := "It has expired, been used or been turned off by the merchant."
+	if errors.Is(err, domain.ErrPaymentLinkNotFound) {
+		status = http.StatusNotFound
+		message = "The link you followed does not exist."
+	}
+
+	render(c, status, "unavailable", unavailablePage{
+		Title:   "Payment link unavailable",
+		Message: message,
+	})
+}
+
+func (h *CheckoutHandler) Show(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "CheckoutHandler.Show")
+	defer span.End()
+
+	link, err := h.getPaymentLinkUC.Execute(ctx, c.Param("link_id"))
+	if err != nil {
+		h.renderUnavailable(c, err)
+		return
+	}
+	if !link.IsOpen(time.Now()) {
+		h.renderUnavailable(c, domain.ErrPaymentLinkUnavailable)
+		return
+	}
+
+	render(c, http.StatusOK, "checkout", checkoutPage{
+		Title:          "Checkout",
+		Link:           link,
+		Amount:         formatAmount(link.Amount, link.Currency),
+		Methods:        checkoutMethods,
+		Banks:          checkoutBanks,
+		Form:           checkoutForm{Method: checkoutMethods[0].Value},
+		IdempotencyKey: "checkout_" + uuid.NewString(),
+	})
+}
+
+func (h *CheckoutHandler) Submit(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "CheckoutHandler.Submit")
+	defer span.End()
+
+	linkID := c.Param("link_id")
+	link, err := h.getPaymentLinkUC.Execute(ctx, linkID)
+	if err != nil {
+		h.renderUnavailable(c, err)
+		return
+	}
+
+	form := checkoutForm{
+		Name:    c.PostForm("name"),
+		Email:   c.PostForm("email"),
+		Country: c.PostForm("country"),
+		Method:  c.PostForm("method"),
+
+		BankCode: c.PostForm("bank_code"),
+	}
+	c.Set("payment_method", form.Method)
+
+	expMonth, _ := strconv.Atoi(c.PostForm("exp_month"))
+	expYear, _ := strconv.Atoi(c.PostForm("exp_year"))
+	idempotencyKey := c.PostForm("idempotency_key")
+
+	output, err := h.payPaymentLinkUC.Execute(ctx, usecase.PayPaymentLinkInput{
+		LinkID: linkID,
+		Payer: usecase.PayerInput{
+			Name:    form.Name,
+			Email:   form.Email,
+			Country: form.Country,
+		},
+		Method:   form.Method,
+		BankCode: form.BankCode,
+		Card: usecase.TokenizeCardInput{
+			Number:     c.PostForm("card_number"),
+			ExpMonth:   expMonth,
+			ExpYear:    expYear,
+			CVC:        c.PostForm("cvc"),
+			HolderName: c.PostForm("holder_name"),
+		},
+		SaveCard:       c.PostForm("save_card") == "on",
+		ReturnURL:      h.baseURL + "/pay/" + linkID + "/result",
+		ClientIP:       c.ClientIP(),
+		IdempotencyKey: idempotencyKey,
+	})
+	if errors.Is(err, domain.ErrPaymentLinkUnavailable) {
+		h.renderUnavailable(c, err)
+		return
+	}
+	if err != nil {
+		render(c, http.StatusUnprocessableEntity, "checkout", checkoutPage{
+			Title:          "Checkout",
+			Link:           link,
+			Amount:         formatAmount(link.Amount, link.Currency),
+			Methods:        checkoutMethods,
+			Banks:          checkoutBanks,
+			Form:           form,
+			IdempotencyKey: idempotencyKey,
+			Error:          err.Error(),
+		})
+		return
+	}
+
+	// e-wallet payers approve in their wallet first
+	if output.NextAction != nil && output.NextAction.RedirectURL != "" {
+		c.Redirect(http.StatusSeeOther, output.NextAction.RedirectURL)
+		return
+	}
+
+	c.Redirect(
+		http.StatusSeeOther,
+		"/pay/"+linkID+"/result?payment_id="+output.PaymentID,
+	)
+}
+
+func (h *CheckoutHandler) Result(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "CheckoutHandler.Result")
+	defer span.End()
+
+	link, err := h.getPaymentLinkUC.Execute(ctx, c.Param("link_id"))
+	if err != nil {
+		h.renderUnavailable(c, err)
+		return
+	}
+
+	payment, err := h.getPaymentUC.Execute(ctx, c.Query("payment_id"))
+	// only show payments that were made through this link
+	if err != nil || payment.OrderID != link.PublicID {
+		h.renderUnavailable(c, domain.ErrPaymentLinkNotFound)
+		return
+	}
+
+	page := resultPage{
+		Title:          "Payment status",
+		Link:           link,
+		Amount:         formatAmount(payment.Amount, payment.Currency),
+		PaymentID:      payment.PublicID,
+		Status:         string(payment.Status),
+		PollIntervalMs: 2000,
+	}
+	if strings.EqualFold(payment.Method, domain.PaymentMethodBankTransfer) &&
+		payment.Status == domain.PaymentStatusPending {
+		// the status is still worth showing without the instructions
+		va, err := h.getVirtualAccountUC.Execute(ctx, payment.PublicID)
+		if err != nil {
+			log.Printf("load virtual account of %s: %v", payment.PublicID, err)
+		} else {
+			page.Transfer = &transferInstructions{
+				BankCode:      va.BankCode,
+				AccountNumber: va.AccountNumber,
+				Amount:        formatAmount(va.Outstanding(), payment.Currency),
+				ExpiresAt:     va.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+			}
+		}
+	}
+
+	render(c, http.StatusOK, "result", page)
+}
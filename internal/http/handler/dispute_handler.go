@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type disputeEventRequest struct {
+	Event     string `json:"event" binding:"required"`
+	Reference string `json:"reference" binding:"required"`
+
+	// only read for dispute.opened
+	PaymentID     string    `json:"payment_id"`
+	Reason        string    `json:"reason"`
+	Amount        int       `json:"amount"`
+	EvidenceDueBy time.Time `json:"evidence_due_by"`
+}
+
+type disputeResponse struct {
+	ID            string  `json:"id"`
+	PaymentID     string  `json:"payment_id"`
+	Reference     string  `json:"reference"`
+	Reason        string  `json:"reason"`
+	Amount        int     `json:"amount"`
+	Currency      string  `json:"currency"`
+	Status        string  `json:"status"`
+	EvidenceDueBy string  `json:"evidence_due_by"`
+	CreatedAt     string  `json:"created_at"`
+	ClosedAt      *string `json:"closed_at,omitempty"`
+
+	Evidence    []evidenceResponse    `json:"evidence,omitempty"`
+	Adjustments []ledgerEntryResponse `json:"adjustments,omitempty"`
+}
+
+type evidenceResponse struct {
+	ID          int    `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Note        string `json:"note"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ledgerEntryResponse struct {
+	Type        string `json:"type"`
+	Amount      int    `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type listDisputesResponse struct {
+	Data []disputeResponse `json:"data"`
+}
+
+type DisputeHandler struct {
+	handleEventUC    *usecase.HandleDisputeEventUsecase
+	getDisputeUC     *usecase.GetDisputeUsecase
+	listDisputesUC   *usecase.ListPaymentDisputesUsecase
+	uploadEvidenceUC *usecase.UploadDisputeEvidenceUsecase
+	submitEvidenceUC *usecase.SubmitDisputeEvidenceUsecase
+	// webhookToken must be sent in X-Callback-Token.
+	webhookToken string
+}
+
+func NewDisputeHandler(
+	handleEventUC *usecase.HandleDisputeEventUsecase,
+	getDisputeUC *usecase.GetDisputeUsecase,
+	listDisputesUC *usecase.ListPaymentDisputesUsecase,
+	uploadEvidenceUC *usecase.UploadDisputeEvidenceUsecase,
+	submitEvidenceUC *usecase.SubmitDisputeEvidenceUsecase,
+	webhookToken string,
+) *DisputeHandler {
+	return &DisputeHandler{
+		handleEventUC:    handleEventUC,
+		getDisputeUC:     getDisputeUC,
+		listDisputesUC:   listDisputesUC,
+		uploadEvidenceUC: uploadEvidenceUC,
+		submitEvidenceUC: submitEvidenceUC,
+		webhookToken:     webhookToken,
+	}
+}
+
+// Webhook receives dispute events from the provider.
+func (h *DisputeHandler) Webhook(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "DisputeHandler.Webhook")
+	defer span.End()
+
+	if !validCallbackToken(c, h.webhookToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid callback token"})
+		return
+	}
+
+	var req disputeEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dispute, err := h.handleEventUC.Execute(ctx, usecase.HandleDisputeEventInput{
+		Event:         domain.DisputeEvent(req.Event),
+		Reference:     req.Reference,
+		PaymentID:     req.PaymentID,
+		Reason:        req.Reason,
+		Amount:        req.Amount,
+		EvidenceDueBy: req.EvidenceDueBy,
+	})
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toDisputeResponse(dispute))
+}
+
+func (h *DisputeHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "DisputeHandler.Get")
+	defer span.End()
+
+	out, err := h.getDisputeUC.Execute(ctx, c.Param("dispute_id"))
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toDisputeDetailResponse(out))
+}
+
+func (h *DisputeHandler) ListByPayment(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "DisputeHandler.ListByPayment")
+	defer span.End()
+
+	disputes, err := h.listDisputesUC.Execute(ctx, c.Param("public_id"))
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := listDisputesResponse{
+		Data: make([]disputeResponse, 0, len(disputes)),
+	}
+	for _, d := range disputes {
+		resp.Data = append(resp.Data, toDisputeResponse(d))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UploadEvidence accepts a multipart form with the evidence in "file" and
+// an optional "note".
+func (h *DisputeHandler) UploadEvidence(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "DisputeHandler.UploadEvidence")
+	defer span.End()
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	contentType, _, err := mime.ParseMediaType(header.Header.Get("Content-Type"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidEvidence.Error()})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	evidence, err := h.uploadEvidenceUC.Execute(ctx, usecase.UploadDisputeEvidenceInput{
+		DisputeID:   c.Param("dispute_id"),
+		FileName:    header.Filename,
+		ContentType: contentType,
+		Note:        c.PostForm("note"),
+		File:        file,
+	})
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toEvidenceResponse(evidence))
+}
+
+// Submit sends the uploaded evidence to the provider for review.
+func (h *DisputeHandler) Submit(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "DisputeHandler.Submit")
+	defer span.End()
+
+	dispute, err := h.submitEvidenceUC.Execute(ctx, c.Param("dispute_id"))
+	if err != nil {
+		c.JSON(disputeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toDisputeResponse(dispute))
+}
+
+func toDisputeResponse(d *domain.Dispute) disputeResponse {
+	res := disputeResponse{
+		ID:            d.PublicID,
+		PaymentID:     d.PaymentID,
+		Reference:     d.Reference,
+		Reason:        d.Reason,
+		Amount:        d.Amount,
+		Currency:      d.Currency,
+		Status:        string(d.Status),
+		EvidenceDueBy: d.EvidenceDueBy.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt:     d.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if d.ClosedAt != nil {
+		closedAt := d.ClosedAt.Format("2006-01-02T15:04:05Z07:00")
+		res.ClosedAt = &closedAt
+	}
+	return res
+}
+
+// toDisputeDetailResponse is a dispute together with its evidence and
+// ledger adjustments.
+func toDisputeDetailResponse(out *usecase.DisputeOutput) disputeResponse {
+	res := toDisputeResponse(out.Dispute)
+	res.Evidence = make([]evidenceResponse, 0, len(out.Evidence))
+	for _, ev := range out.Evidence {
+		res.Evidence = append(res.Evidence, toEvidenceResponse(ev))
+	}
+	res.Adjustments = make([]ledgerEntryResponse, 0, len(out.Adjustments))
+	for _, e := range out.Adjustments {
+		res.Adjustments = append(res.Adjustments, ledgerEntryResponse{
+			Type:        string(e.Type),
+			Amount:      e.Amount,
+			Currency:    e.Currency,
+			Description: e.Description,
+			CreatedAt:   e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	return res
+}
+
+func toEvidenceResponse(ev *domain.DisputeEvidence) evidenceResponse {
+	return evidenceResponse{
+		ID:          ev.ID,
+		FileName:    ev.FileName,
+		ContentType: ev.ContentType,
+		Size:        ev.Size,
+		Note:        ev.Note,
+		CreatedAt:   ev.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func disputeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrDisputeNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrUnknownDisputeEvent),
+		errors.Is(err, domain.ErrInvalidDisputeAmount),
+		errors.Is(err, domain.ErrInvalidEvidence),
+		errors.Is(err, domain.ErrEvidenceRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrDisputeClosed),
+		errors.Is(err, domain.ErrInvalidDisputeStatus),
+		errors.Is(err, domain.ErrPaymentNotDisputable),
+		errors.Is(err, domain.ErrEvidenceDeadlinePassed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
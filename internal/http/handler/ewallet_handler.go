@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ewalletCallbackRequest struct {
+	PaymentID string `json:"payment_id" binding:"required"`
+	Reference string `json:"reference" binding:"required"`
+	Event     string `json:"event" binding:"required"`
+}
+
+type EWalletHandler struct {
+	handleCallbackUC *usecase.HandleEWalletCallbackUsecase
+	getReturnUC      *usecase.GetEWalletReturnUsecase
+	// callbackToken must be sent in X-Callback-Token.
+	callbackToken string
+}
+
+func NewEWalletHandler(
+	handleCallbackUC *usecase.HandleEWalletCallbackUsecase,
+	getReturnUC *usecase.GetEWalletReturnUsecase,
+	callbackToken string,
+) *EWalletHandler {
+	return &EWalletHandler{
+		handleCallbackUC: handleCallbackUC,
+		getReturnUC:      getReturnUC,
+		callbackToken:    callbackToken,
+	}
+}
+
+// Callback receives status notifications from the wallet.
+func (h *EWalletHandler) Callback(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "EWalletHandler.Callback")
+	defer span.End()
+
+	if !validCallbackToken(c, h.callbackToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid callback token"})
+		return
+	}
+
+	var req ewalletCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := h.handleCallbackUC.Execute(ctx, usecase.HandleEWalletCallbackInput{
+		PaymentID: req.PaymentID,
+		Reference: req.Reference,
+		Event:     domain.EWalletEvent(req.Event),
+	})
+	if err != nil {
+		c.JSON(ewalletErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentResponse(payment))
+}
+
+// Return is where the payer lands after leaving the wallet. They are sent
+// on to the merchant's return url with the payment id and status, or shown
+// the payment when there is none.
+func (h *EWalletHandler) Return(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "EWalletHandler.Return")
+	defer span.End()
+
+	out, err := h.getReturnUC.Execute(ctx, c.Param("public_id"))
+	if err != nil {
+		c.JSON(ewalletErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	returnToMerchant(c, out.ReturnURL, out.Payment)
+}
+
+// returnToMerchant sends the payer to returnURL with the payment id and
+// status added, or shows the payment when there is no return url.
+func returnToMerchant(c *gin.Context, returnURL string, payment *domain.Payment) {
+	if returnURL == "" {
+		c.JSON(http.StatusOK, toPaymentResponse(payment))
+		return
+	}
+
+	target, err := url.Parse(returnURL)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	q := target.Query()
+	q.Set("payment_id", payment.PublicID)
+	q.Set("status", string(payment.Status))
+	target.RawQuery = q.Encode()
+
+	c.Redirect(http.StatusSeeOther, target.String())
+}
+
+func ewalletErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrEWalletAuthorizationNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidPaymentStatus),
+		errors.Is(err, domain.ErrPaymentStatusConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
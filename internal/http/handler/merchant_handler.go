@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createMerchantRequest struct {
+	Name              string `json:"name" binding:"required"`
+	PayoutDestination string `json:"payout_destination"`
+}
+
+type issueAPIKeyRequest struct {
+	Mode string `json:"mode" binding:"required,oneof=live test"`
+}
+
+type apiKeyResponse struct {
+	ID     int    `json:"id"`
+	Mode   string `json:"mode"`
+	Prefix string `json:"prefix"`
+	// Secret is only returned when the key is issued.
+	Secret    string  `json:"secret,omitempty"`
+	CreatedAt string  `json:"created_at"`
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	RevokedAt *string `json:"revoked_at,omitempty"`
+}
+
+type listAPIKeysResponse struct {
+	Data []apiKeyResponse `json:"data"`
+}
+
+type merchantResponse struct {
+	ID                string           `json:"id"`
+	Name              string           `json:"name"`
+	Status            string           `json:"status"`
+	PayoutDestination string           `json:"payout_destination,omitempty"`
+	CreatedAt         string           `json:"created_at"`
+	APIKeys           []apiKeyResponse `json:"api_keys"`
+}
+
+type MerchantHandler struct {
+	createMerchantUC *usecase.CreateMerchantUsecase
+	issueAPIKeyUC    *usecase.IssueAPIKeyUsecase
+	rotateAPIKeyUC   *usecase.RotateAPIKeyUsecase
+	revokeAPIKeyUC   *usecase.RevokeAPIKeyUsecase
+	listAPIKeysUC    *usecase.ListAPIKeysUsecase
+}
+
+func NewMerchantHandler(
+	createMerchantUC *usecase.CreateMerchantUsecase,
+	issueAPIKeyUC *usecase.IssueAPIKeyUsecase,
+	rotateAPIKeyUC *usecase.RotateAPIKeyUsecase,
+	revokeAPIKeyUC *usecase.RevokeAPIKeyUsecase,
+	listAPIKeysUC *usecase.ListAPIKeysUsecase,
+) *MerchantHandler {
+	return &MerchantHandler{
+		createMerchantUC: createMerchantUC,
+		issueAPIKeyUC:    issueAPIKeyUC,
+		rotateAPIKeyUC:   rotateAPIKeyUC,
+		revokeAPIKeyUC:   revokeAPIKeyUC,
+		listAPIKeysUC:    listAPIKeysUC,
+	}
+}
+
+func (h *MerchantHandler) Create(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "MerchantHandler.Create")
+	defer span.End()
+
+	var req createMerchantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	out, err := h.createMerchantUC.Execute(ctx, usecase.CreateMerchantInput{
+		Name:              req.Name,
+		PayoutDestination: req.PayoutDestination,
+	})
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toMerchantResponse(out))
+}
+
+func (h *MerchantHandler) IssueKey(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "MerchantHandler.IssueKey")
+	defer span.End()
+
+	var req issueAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	issued, err := h.issueAPIKeyUC.Execute(ctx, c.Param("merchant_id"), domain.Mode(req.Mode))
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toIssuedAPIKeyResponse(*issued))
+}
+
+func (h *MerchantHandler) RevokeKey(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "MerchantHandler.RevokeKey")
+	defer span.End()
+
+	id, ok := apiKeyIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.revokeAPIKeyUC.Execute(ctx, id); err != nil {
+		c.JSON(merchantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MerchantHandler) ListKeys(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "MerchantHandler.ListKeys")
+	defer span.End()
+
+	keys, err := h.listAPIKeysUC.Execute(ctx)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := listAPIKeysResponse{
+		Data: make([]apiKeyResponse, 0, len(keys)),
+	}
+	for _, k := range keys {
+		resp.Data = append(resp.Data, toAPIKeyResponse(k))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *MerchantHandler) RotateKey(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "MerchantHandler.RotateKey")
+	defer span.End()
+
+	id, ok := apiKeyIDParam(c)
+	if !ok {
+		return
+	}
+
+	issued, err := h.rotateAPIKeyUC.Execute(ctx, id)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toIssuedAPIKeyResponse(*issued))
+}
+
+func apiKeyIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("key_id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid api key id",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func toMerchantResponse(out *usecase.CreateMerchantOutput) merchantResponse {
+	m := out.Merchant
+	res := merchantResponse{
+		ID:                m.PublicID,
+		Name:              m.Name,
+		Status:            string(m.Status),
+		PayoutDestination: m.PayoutDestination,
+		CreatedAt:         m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		APIKeys:           make([]apiKeyResponse, 0, len(out.Keys)),
+	}
+	for _, issued := range out.Keys {
+		res.APIKeys = append(res.APIKeys, toIssuedAPIKeyResponse(issued))
+	}
+	return res
+}
+
+func toAPIKeyResponse(k *domain.APIKey) apiKeyResponse {
+	res := apiKeyResponse{
+		ID:        k.ID,
+		Mode:      string(k.Mode),
+		Prefix:    k.Prefix,
+		CreatedAt: k.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if k.ExpiresAt != nil {
+		expiresAt := k.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+		res.ExpiresAt = &expiresAt
+	}
+	if k.RevokedAt != nil {
+		revokedAt := k.RevokedAt.Format("2006-01-02T15:04:05Z07:00")
+		res.RevokedAt = &revokedAt
+	}
+	return res
+}
+
+func toIssuedAPIKeyResponse(issued usecase.IssuedAPIKey) apiKeyResponse {
+	res := toAPIKeyResponse(issued.Key)
+	res.Secret = issued.Secret
+	return res
+}
+
+func merchantErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrMerchantNotFound),
+		errors.Is(err, domain.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrMerchantNameRequired),
+		errors.Is(err, domain.ErrInvalidAPIKeyMode):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,599 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/http/openapi"
+)
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// pathValues fill in the path parameters of the documented operations.
+var pathValues = map[string]string{
+	"public_id":       "pay_1",
+	"batch_id":        "batch_1",
+	"import_id":       "batch_1",
+	"id":              "1",
+	"token":           "pm_1",
+	"plan_id":         "plan_1",
+	"subscription_id": "sub_1",
+	"link_id":         "plink_1",
+	"dispute_id":      "dp_1",
+	"payout_id":       "po_1",
+	"merchant_id":     "mer_1",
+	"key_id":          "1",
+}
+
+// documentedResponse is a valid request to an operation and the response
+// its handler sends, built with the handler's own response types.
+type documentedResponse struct {
+	operation string
+	// body is sent as JSON, or as the uploaded file when multipart is set.
+	body      string
+	multipart bool
+	status    int
+	respond   func(c *gin.Context)
+}
+
+// TestResponses_MatchDocument sends what every documented operation
+// answers through the response-validating middleware, so a response type
+// that drifts from openapi.yaml fails here rather than in production.
+func TestResponses_MatchDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := now.Add(24 * time.Hour)
+
+	payment := &domain.Payment{
+		PublicID:           "pay_1",
+		OrderID:            "ord_1",
+		PayerID:            1,
+		Amount:             150000,
+		Currency:           "IDR",
+		Status:             domain.PaymentStatusSuccess,
+		Provider:           "FAKE",
+		Method:             domain.PaymentMethodTypeCard,
+		PaymentMethodToken: "pm_1",
+		Mode:               domain.ModeLive,
+		CreatedBy:          "api_key:1",
+		CreatedAt:          now,
+		PaidAt:             &later,
+		ExpiresAt:          &later,
+		ThreeDSStatus:      domain.ThreeDSStatusAuthenticated,
+		LiabilityShift:     true,
+		RiskScore:          40,
+		RiskDecision:       domain.RiskDecisionReview,
+		RiskRules:          []string{domain.RiskRuleAmountThreshold},
+		Splits: []domain.PaymentSplit{
+			{Recipient: "seller_1", Amount: 100000, PercentageBps: 6667, FeeBearer: true},
+			{Recipient: "platform", Amount: 50000},
+		},
+	}
+	// bare has none of the optional fields set.
+	bare := &domain.Payment{
+		PublicID:  "pay_2",
+		OrderID:   "ord_2",
+		PayerID:   1,
+		Amount:    10000,
+		Currency:  "IDR",
+		Status:    domain.PaymentStatusPending,
+		Provider:  "FAKE",
+		Method:    domain.PaymentMethodQR,
+		Mode:      domain.ModeTest,
+		CreatedAt: now,
+	}
+	payments := listPaymentsResponse{
+		Data: []getPaymentResponse{toPaymentResponse(payment), toPaymentResponse(bare)},
+	}
+
+	va := &domain.VirtualAccount{
+		PaymentID:      "pay_1",
+		BankCode:       "BCA",
+		AccountNumber:  "8808123456789",
+		ExpectedAmount: 150000,
+		PaidAmount:     50000,
+		Status:         domain.VirtualAccountStatusPartiallyPaid,
+		ExpiresAt:      later,
+	}
+	created := &usecase.CreatePaymentOutput{
+		PaymentID:      "pay_1",
+		Status:         domain.PaymentStatusRequiresAction,
+		ExpiresAt:      &later,
+		VirtualAccount: va,
+		NextAction: &domain.NextAction{
+			Type:        domain.NextActionRedirect,
+			RedirectURL: "https://wallet.example.com/pay",
+			Deeplink:    "wallet://pay",
+		},
+	}
+
+	batch := &domain.PaymentBatch{
+		PublicID:  "batch_1",
+		Mode:      domain.ModeLive,
+		CreatedBy: "api_key:1",
+		Source:    domain.PaymentBatchSourceCSV,
+		FileName:  "payments.csv",
+		Status:    domain.PaymentBatchStatusCompleted,
+		Items: []domain.PaymentBatchItem{
+			{
+				Position:       0,
+				OrderID:        "ord_1",
+				IdempotencyKey: "key-1",
+				Status:         domain.PaymentBatchItemCreated,
+				PaymentID:      "pay_1",
+				PaymentStatus:  domain.PaymentStatusPending,
+			},
+			{
+				Position:       1,
+				OrderID:        "ord_2",
+				IdempotencyKey: "key-2",
+				Status:         domain.PaymentBatchItemFailed,
+				ErrorCode:      "payer_not_found",
+				Error:          domain.ErrPayerNotFound.Error(),
+			},
+		},
+		CreatedAt:   now,
+		CompletedAt: &later,
+	}
+
+	payer := &domain.Payer{
+		ID:                1,
+		Name:              "Budi",
+		Email:             "budi@example.com",
+		Country:           "ID",
+		ExternalReference: "cust_1",
+		Status:            domain.PayerStatusActive,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	card := &domain.PaymentMethod{
+		Token:      "pm_1",
+		PayerID:    1,
+		Type:       domain.PaymentMethodTypeCard,
+		Brand:      domain.CardBrandVisa,
+		Last4:      "4242",
+		BIN:        "424242",
+		ExpMonth:   12,
+		ExpYear:    2030,
+		HolderName: "BUDI",
+		Status:     domain.PaymentMethodStatusActive,
+		CreatedAt:  now,
+	}
+
+	plan := &domain.Plan{
+		PublicID:      "plan_1",
+		Name:          "Pro",
+		Amount:        99000,
+		Currency:      "IDR",
+		Interval:      domain.PlanIntervalMonth,
+		IntervalCount: 1,
+		TrialDays:     14,
+		Active:        true,
+		CreatedAt:     now,
+	}
+	subscription := &domain.Subscription{
+		PublicID:           "sub_1",
+		PayerID:            1,
+		PaymentMethodToken: "pm_1",
+		Status:             domain.SubscriptionStatusPastDue,
+		Cycle:              2,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   later,
+		FailedAttempts:     1,
+		NextRetryAt:        &later,
+		LastPaymentID:      "pay_1",
+		CanceledAt:         &later,
+		CreatedAt:          now,
+	}
+
+	links := &PaymentLinkHandler{baseURL: "https://pay.example.com"}
+	link := &domain.PaymentLink{
+		PublicID:    "plink_1",
+		Amount:      50000,
+		Currency:    "IDR",
+		Description: "Invoice 42",
+		SingleUse:   true,
+		UsageCount:  1,
+		Status:      domain.PaymentLinkStatusActive,
+		ExpiresAt:   &later,
+		CreatedAt:   now,
+	}
+
+	dispute := &domain.Dispute{
+		PublicID:      "dp_1",
+		PaymentID:     "pay_1",
+		Reference:     "cb_1",
+		Reason:        "fraudulent",
+		Amount:        150000,
+		Currency:      "IDR",
+		Status:        domain.DisputeStatusLost,
+		EvidenceDueBy: later,
+		CreatedAt:     now,
+		ClosedAt:      &later,
+	}
+	evidence := &domain.DisputeEvidence{
+		ID:          1,
+		FileName:    "receipt.pdf",
+		ContentType: "application/pdf",
+		Size:        2048,
+		Note:        "signed delivery receipt",
+		CreatedAt:   now,
+	}
+
+	payout := &domain.Payout{
+		PublicID:      "po_1",
+		Amount:        100000,
+		Currency:      "IDR",
+		Status:        domain.PayoutStatusFailed,
+		Trigger:       domain.PayoutTriggerScheduled,
+		Destination:   "BCA:1234567890",
+		Reference:     "trf_1",
+		FailureReason: "account closed",
+		CreatedAt:     now,
+		SentAt:        &now,
+		CompletedAt:   &later,
+	}
+
+	key := &domain.APIKey{
+		ID:        1,
+		Mode:      domain.ModeLive,
+		Prefix:    "sk_live_abcd",
+		CreatedAt: now,
+		ExpiresAt: &later,
+		RevokedAt: &later,
+	}
+	issued := usecase.IssuedAPIKey{Key: key, Secret: "sk_live_abcd1234"}
+
+	review := &usecase.ReviewOutput{
+		Payment: payment,
+		History: []*domain.PaymentReview{
+			{Action: domain.ReviewActionHeld, Reason: domain.HoldReasonHighAmount, Reviewer: "system", CreatedAt: now},
+			{Action: domain.ReviewActionApproved, Reviewer: "ops@example.com", CreatedAt: later},
+		},
+	}
+
+	json := func(status int, body any) func(c *gin.Context) {
+		return func(c *gin.Context) { c.JSON(status, body) }
+	}
+	noContent := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+	cases := []documentedResponse{
+		{
+			operation: "createPayment",
+			body:      `{"order_id":"ord_1","payer_id":1,"amount":150000,"currency":"IDR","provider":"FAKE","method":"bank_transfer","bank_code":"BCA"}`,
+			status:    http.StatusAccepted,
+			respond:   json(http.StatusAccepted, toCreatePaymentResponse(created)),
+		},
+		{
+			operation: "createPaymentBatch",
+			body:      `{"items":[{"order_id":"ord_1","payer_id":1,"amount":150000,"currency":"IDR","provider":"FAKE","method":"qr","idempotency_key":"key-1"}]}`,
+			status:    http.StatusAccepted,
+			respond:   json(http.StatusAccepted, toPaymentBatchResponse(batch)),
+		},
+		{operation: "getPaymentBatch", status: http.StatusOK, respond: json(http.StatusOK, toPaymentBatchResponse(batch))},
+		{
+			operation: "importPayments",
+			body:      "order_id,payer_id,amount,currency,provider,method,idempotency_key\nord_1,1,150000,IDR,FAKE,qr,key-1\n",
+			multipart: true,
+			status:    http.StatusAccepted,
+			respond:   json(http.StatusAccepted, toPaymentBatchResponse(batch)),
+		},
+		{operation: "getPaymentImport", status: http.StatusOK, respond: json(http.StatusOK, toPaymentBatchResponse(batch))},
+		{
+			operation: "getPaymentImportResult",
+			status:    http.StatusOK,
+			respond: func(c *gin.Context) {
+				c.Header("Content-Type", "text/csv; charset=utf-8")
+				c.Status(http.StatusOK)
+				writePaymentImportResult(c.Writer, batch.Items)
+			},
+		},
+		{operation: "getPayment", status: http.StatusOK, respond: json(http.StatusOK, toPaymentResponse(payment))},
+		{
+			operation: "listPaymentDisputes",
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, listDisputesResponse{Data: []disputeResponse{toDisputeResponse(dispute)}}),
+		},
+		{
+			operation: "ewalletReturn",
+			status:    http.StatusOK,
+			respond:   func(c *gin.Context) { returnToMerchant(c, "", payment) },
+		},
+		{
+			operation: "ewalletReturn",
+			status:    http.StatusSeeOther,
+			respond:   func(c *gin.Context) { returnToMerchant(c, "https://shop.example.com/done", payment) },
+		},
+		{
+			operation: "getPaymentQR",
+			status:    http.StatusOK,
+			respond: json(http.StatusOK, toQRResponse(&usecase.GetPaymentQROutput{
+				Payment: bare,
+				QRCode:  &domain.QRCode{Payload: "00020101021226", ExpiresAt: later},
+			}, "/v1/payments/pay_1/qr?format=png")),
+		},
+		{
+			operation: "getPaymentQR",
+			status:    http.StatusOK,
+			respond: func(c *gin.Context) {
+				png, err := qrcode.Encode("00020101021226", qrcode.Medium, defaultQRSize)
+				if err != nil {
+					t.Fatalf("encode qr: %v", err)
+				}
+				c.Data(http.StatusOK, "image/png", png)
+			},
+		},
+		{
+			operation: "completeThreeDS",
+			status:    http.StatusOK,
+			respond:   func(c *gin.Context) { returnToMerchant(c, "", payment) },
+		},
+		{
+			operation: "completeThreeDSPost",
+			status:    http.StatusSeeOther,
+			respond:   func(c *gin.Context) { returnToMerchant(c, "https://shop.example.com/done", payment) },
+		},
+		{
+			operation: "createPayer",
+			body:      `{"name":"Budi","email":"budi@example.com","country":"ID"}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toPayerResponse(payer)),
+		},
+		{
+			operation: "listPayers",
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, listPayersResponse{Data: []payerResponse{toPayerResponse(payer)}}),
+		},
+		{operation: "getPayer", status: http.StatusOK, respond: json(http.StatusOK, toPayerResponse(payer))},
+		{
+			operation: "updatePayer",
+			body:      `{"name":"Budi","email":"budi@example.com","country":"ID","status":"BLOCKED"}`,
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, toPayerResponse(payer)),
+		},
+		{operation: "deletePayer", status: http.StatusNoContent, respond: noContent},
+		{operation: "listPayerPayments", status: http.StatusOK, respond: json(http.StatusOK, payments)},
+		{
+			operation: "listPayerPaymentMethods",
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, listPaymentMethodsResponse{Data: []paymentMethodResponse{toPaymentMethodResponse(card)}}),
+		},
+		{
+			operation: "createCard",
+			body:      `{"payer_id":1,"number":"4242424242424242","exp_month":12,"exp_year":2030,"cvc":"123"}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toPaymentMethodResponse(card)),
+		},
+		{operation: "getPaymentMethod", status: http.StatusOK, respond: json(http.StatusOK, toPaymentMethodResponse(card))},
+		{operation: "detachPaymentMethod", status: http.StatusNoContent, respond: noContent},
+		{
+			operation: "createPlan",
+			body:      `{"name":"Pro","amount":99000,"currency":"IDR","interval":"month"}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toPlanResponse(plan)),
+		},
+		{
+			operation: "listPlans",
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, listPlansResponse{Data: []planResponse{toPlanResponse(plan)}}),
+		},
+		{operation: "getPlan", status: http.StatusOK, respond: json(http.StatusOK, toPlanResponse(plan))},
+		{
+			operation: "createSubscription",
+			body:      `{"plan_id":"plan_1","payer_id":1,"payment_method":"pm_1"}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toSubscriptionResponse(subscription)),
+		},
+		{operation: "getSubscription", status: http.StatusOK, respond: json(http.StatusOK, toSubscriptionResponse(subscription))},
+		{operation: "pauseSubscription", status: http.StatusOK, respond: json(http.StatusOK, toSubscriptionResponse(subscription))},
+		{operation: "resumeSubscription", status: http.StatusOK, respond: json(http.StatusOK, toSubscriptionResponse(subscription))},
+		{operation: "cancelSubscription", status: http.StatusOK, respond: json(http.StatusOK, toSubscriptionResponse(subscription))},
+		{
+			operation: "createPaymentLink",
+			body:      `{"amount":50000,"currency":"IDR","description":"Invoice 42"}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, links.toResponse(link)),
+		},
+		{operation: "getPaymentLink", status: http.StatusOK, respond: json(http.StatusOK, links.toResponse(link))},
+		{operation: "deactivatePaymentLink", status: http.StatusOK, respond: json(http.StatusOK, links.toResponse(link))},
+		{
+			operation: "getDispute",
+			status:    http.StatusOK,
+			respond: json(http.StatusOK, toDisputeDetailResponse(&usecase.DisputeOutput{
+				Dispute:  dispute,
+				Evidence: []*domain.DisputeEvidence{evidence},
+				Adjustments: []*domain.LedgerEntry{
+					{Type: domain.LedgerEntryChargeback, Amount: -150000, Currency: "IDR", Description: "chargeback cb_1", CreatedAt: later},
+					{Type: domain.LedgerEntryChargebackFee, Amount: -15000, Currency: "IDR", Description: "chargeback fee cb_1", CreatedAt: later},
+				},
+			})),
+		},
+		{
+			operation: "uploadDisputeEvidence",
+			body:      "%PDF-1.4",
+			multipart: true,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toEvidenceResponse(evidence)),
+		},
+		{operation: "submitDisputeEvidence", status: http.StatusOK, respond: json(http.StatusOK, toDisputeResponse(dispute))},
+		{
+			operation: "getBalances",
+			status:    http.StatusOK,
+			respond: json(http.StatusOK, listBalancesResponse{Data: []balanceResponse{
+				toBalanceResponse(domain.Balance{Currency: "IDR", Available: -5000, Pending: 20000, InTransit: 100000}),
+			}}),
+		},
+		{
+			operation: "createPayout",
+			body:      `{"currency":"IDR","amount":100000}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toPayoutResponse(payout)),
+		},
+		{
+			operation: "listPayouts",
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, listPayoutsResponse{Data: []payoutResponse{toPayoutResponse(payout)}}),
+		},
+		{operation: "getPayout", status: http.StatusOK, respond: json(http.StatusOK, toPayoutResponse(payout))},
+		{
+			operation: "listAPIKeys",
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, listAPIKeysResponse{Data: []apiKeyResponse{toAPIKeyResponse(key)}}),
+		},
+		{operation: "rotateAPIKey", status: http.StatusCreated, respond: json(http.StatusCreated, toIssuedAPIKeyResponse(issued))},
+		{
+			operation: "bankTransferWebhook",
+			body:      `{"bank_code":"BCA","account_number":"8808123456789","amount":50000,"reference":"trf_1"}`,
+			status:    http.StatusOK,
+			respond: json(http.StatusOK, toBankCreditResponse(&usecase.HandleBankCreditOutput{
+				PaymentID:      "pay_1",
+				PaymentStatus:  domain.PaymentStatusPending,
+				VirtualAccount: va,
+				Late:           true,
+			})),
+		},
+		{
+			operation: "ewalletWebhook",
+			body:      `{"payment_id":"pay_1","reference":"ew_1","event":"payment.succeeded"}`,
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, toPaymentResponse(payment)),
+		},
+		{
+			operation: "qrWebhook",
+			body:      `{"reference":"qr_1","amount":10000}`,
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, toPaymentResponse(bare)),
+		},
+		{
+			operation: "disputeWebhook",
+			body:      `{"event":"dispute.opened","reference":"cb_1","payment_id":"pay_1","amount":150000,"evidence_due_by":"2026-01-09T03:04:05Z"}`,
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, toDisputeResponse(dispute)),
+		},
+		{operation: "listReviews", status: http.StatusOK, respond: json(http.StatusOK, payments)},
+		{operation: "getReview", status: http.StatusOK, respond: json(http.StatusOK, toReviewResponse(review))},
+		{
+			operation: "approveReview",
+			body:      `{"reviewer":"ops@example.com"}`,
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, toReviewResponse(review)),
+		},
+		{
+			operation: "rejectReview",
+			body:      `{"reviewer":"ops@example.com","reason":"stolen card"}`,
+			status:    http.StatusOK,
+			respond:   json(http.StatusOK, toReviewResponse(review)),
+		},
+		{
+			operation: "createMerchant",
+			body:      `{"name":"Toko Budi","payout_destination":"BCA:1234567890"}`,
+			status:    http.StatusCreated,
+			respond: json(http.StatusCreated, toMerchantResponse(&usecase.CreateMerchantOutput{
+				Merchant: &domain.Merchant{
+					PublicID:          "mer_1",
+					Name:              "Toko Budi",
+					Status:            domain.MerchantStatusActive,
+					PayoutDestination: "BCA:1234567890",
+					CreatedAt:         now,
+				},
+				Keys: []usecase.IssuedAPIKey{issued},
+			})),
+		},
+		{
+			operation: "issueAPIKey",
+			body:      `{"mode":"test"}`,
+			status:    http.StatusCreated,
+			respond:   json(http.StatusCreated, toIssuedAPIKeyResponse(issued)),
+		},
+		{operation: "revokeAPIKey", status: http.StatusNoContent, respond: noContent},
+	}
+
+	type operation struct{ method, path string }
+	operations := map[string]operation{}
+	for path, item := range spec.Document().Paths.Map() {
+		for method, op := range item.Operations() {
+			operations[op.OperationID] = operation{method, path}
+		}
+	}
+
+	// streams are sent as they are written and not validated
+	covered := map[string]bool{"streamPaymentEvents": true}
+	for _, tc := range cases {
+		covered[tc.operation] = true
+
+		op, ok := operations[tc.operation]
+		if !ok {
+			t.Errorf("%s is not in openapi.yaml", tc.operation)
+			continue
+		}
+		path := pathParam.ReplaceAllStringFunc(op.path, func(p string) string {
+			return pathValues[p[1:len(p)-1]]
+		})
+
+		r := gin.New()
+		r.Use(spec.WithResponseValidation().Middleware())
+		r.Handle(op.method, path, tc.respond)
+
+		req := newDocumentedRequest(t, op.method, path, tc)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d: %s", tc.operation, tc.status, w.Code, w.Body.String())
+		}
+	}
+
+	for id := range operations {
+		if !covered[id] {
+			t.Errorf("%s has no response checked against openapi.yaml", id)
+		}
+	}
+}
+
+func newDocumentedRequest(t *testing.T, method, path string, tc documentedResponse) *http.Request {
+	t.Helper()
+
+	if !tc.multipart {
+		req := httptest.NewRequest(method, path, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Idempotency-Key", "key-1")
+		return req
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "upload")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := file.Write([]byte(tc.body)); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
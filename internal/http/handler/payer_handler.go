@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createPayerRequest struct {
+	Name              string `json:"name" binding:"required"`
+	Email             string `json:"email" binding:"required"`
+	Country           string `json:"country" binding:"required"`
+	ExternalReference string `json:"external_reference"`
+}
+
+type updatePayerRequest struct {
+	Name              string `json:"name" binding:"required"`
+	Email             string `json:"email" binding:"required"`
+	Country           string `json:"country" binding:"required"`
+	ExternalReference string `json:"external_reference"`
+	Status            string `json:"status"`
+}
+
+type payerResponse struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Email             string `json:"email"`
+	Country           string `json:"country"`
+	ExternalReference string `json:"external_reference,omitempty"`
+	Status            string `json:"status"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+type listPayersResponse struct {
+	Data []payerResponse `json:"data"`
+}
+
+type listPaymentsResponse struct {
+	Data []getPaymentResponse `json:"data"`
+}
+
+type PayerHandler struct {
+	createPayerUC       *usecase.CreatePayerUsecase
+	getPayerUC          *usecase.GetPayerUsecase
+	listPayersUC        *usecase.ListPayersUsecase
+	updatePayerUC       *usecase.UpdatePayerUsecase
+	deletePayerUC       *usecase.DeletePayerUsecase
+	listPayerPaymentsUC *usecase.ListPayerPaymentsUsecase
+}
+
+func NewPayerHandler(
+	createPayerUC *usecase.CreatePayerUsecase,
+	getPayerUC *usecase.GetPayerUsecase,
+	listPayersUC *usecase.ListPayersUsecase,
+	updatePayerUC *usecase.UpdatePayerUsecase,
+	deletePayerUC *usecase.DeletePayerUsecase,
+	listPayerPaymentsUC *usecase.ListPayerPaymentsUsecase,
+) *PayerHandler {
+	return &PayerHandler{
+		createPayerUC:       createPayerUC,
+		getPayerUC:          getPayerUC,
+		listPayersUC:        listPayersUC,
+		updatePayerUC:       updatePayerUC,
+		deletePayerUC:       deletePayerUC,
+		listPayerPaymentsUC: listPayerPaymentsUC,
+	}
+}
+
+func toPayerResponse(p *domain.Payer) payerResponse {
+	return payerResponse{
+		ID:                p.ID,
+		Name:              p.Name,
+		Email:             p.Email,
+		Country:           p.Country,
+		ExternalReference: p.ExternalReference,
+		Status:            string(p.Status),
+		CreatedAt:         p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:         p.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func payerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPayerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrPayerAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// pageParams reads the optional limit/offset query parameters.
+func pageParams(c *gin.Context) (int, int) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	return limit, offset
+}
+
+func payerIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid payer id",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *PayerHandler) Create(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayerHandler.Create")
+	defer span.End()
+
+	var req createPayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	payer, err := h.createPayerUC.Execute(ctx, usecase.PayerInput{
+		Name:              req.Name,
+		Email:             req.Email,
+		Country:           req.Country,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		c.JSON(payerErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toPayerResponse(payer))
+}
+
+func (h *PayerHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayerHandler.Get")
+	defer span.End()
+
+	id, ok := payerIDParam(c)
+	if !ok {
+		return
+	}
+
+	payer, err := h.getPayerUC.Execute(ctx, id)
+	if err != nil {
+		c.JSON(payerErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPayerResponse(payer))
+}
+
+func (h *PayerHandler) List(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayerHandler.List")
+	defer span.End()
+
+	limit, offset := pageParams(c)
+	payers, err := h.listPayersUC.Execute(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp := listPayersResponse{
+		Data: make([]payerResponse, 0, len(payers)),
+	}
+	for _, p := range payers {
+		resp.Data = append(resp.Data, toPayerResponse(p))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PayerHandler) Update(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayerHandler.Update")
+	defer span.End()
+
+	id, ok := payerIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req updatePayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	payer, err := h.updatePayerUC.Execute(ctx, id, usecase.UpdatePayerInput{
+		PayerInput: usecase.PayerInput{
+			Name:              req.Name,
+			Email:             req.Email,
+			Country:           req.Country,
+			ExternalReference: req.ExternalReference,
+		},
+		Status: domain.PayerStatus(req.Status),
+	})
+	if err != nil {
+		c.JSON(payerErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPayerResponse(payer))
+}
+
+func (h *PayerHandler) Delete(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayerHandler.Delete")
+	defer span.End()
+
+	id, ok := payerIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.deletePayerUC.Execute(ctx, id); err != nil {
+		c.JSON(payerErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PayerHandler) ListPayments(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayerHandler.ListPayments")
+	defer span.End()
+
+	id, ok := payerIDParam(c)
+	if !ok {
+		return
+	}
+
+	limit, offset := pageParams(c)
+	payments, err := h.listPayerPaymentsUC.Execute(ctx, id, limit, offset)
+	if err != nil {
+		c.JSON(payerErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp := listPaymentsResponse{
+		Data: make([]getPaymentResponse, 0, len(payments)),
+	}
+	for _, p := range payments {
+		resp.Data = append(resp.Data, toPaymentResponse(p))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createPaymentBatchRequest struct {
+	Items []paymentBatchItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// paymentBatchItemRequest is a payment of the batch, with the idempotency
+// key that is sent as a header for a single payment.
+type paymentBatchItemRequest struct {
+	createPaymentRequest
+	IdempotencyKey string `json:"idempotency_key" binding:"required"`
+}
+
+type paymentBatchItemResponse struct {
+	Position       int    `json:"position"`
+	OrderID        string `json:"order_id"`
+	IdempotencyKey string `json:"idempotency_key"`
+	Status         string `json:"status"`
+	PaymentID      string `json:"payment_id,omitempty"`
+	PaymentStatus  string `json:"payment_status,omitempty"`
+	ErrorCode      string `json:"error_code,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+type paymentBatchProgressResponse struct {
+	Total     int `json:"total"`
+	Pending   int `json:"pending"`
+	Created   int `json:"created"`
+	Duplicate int `json:"duplicate"`
+	Failed    int `json:"failed"`
+}
+
+type paymentBatchResponse struct {
+	ID          string                       `json:"id"`
+	Status      string                       `json:"status"`
+	Source      string                       `json:"source"`
+	FileName    string                       `json:"file_name,omitempty"`
+	Mode        string                       `json:"mode"`
+	CreatedBy   string                       `json:"created_by,omitempty"`
+	Progress    paymentBatchProgressResponse `json:"progress"`
+	Items       []paymentBatchItemResponse   `json:"items"`
+	CreatedAt   string                       `json:"created_at"`
+	CompletedAt *string                      `json:"completed_at,omitempty"`
+}
+
+// paymentImportResultHeader is the header row of an import's result file.
+var paymentImportResultHeader = []string{
+	"row", "order_id", "idempotency_key", "status",
+	"payment_id", "payment_status", "error_code", "error",
+}
+
+type PaymentBatchHandler struct {
+	createPaymentBatchUC *usecase.CreatePaymentBatchUsecase
+	getPaymentBatchUC    *usecase.GetPaymentBatchUsecase
+	importPaymentsUC     *usecase.ImportPaymentsUsecase
+}
+
+func NewPaymentBatchHandler(
+	createPaymentBatchUC *usecase.CreatePaymentBatchUsecase,
+	getPaymentBatchUC *usecase.GetPaymentBatchUsecase,
+	importPaymentsUC *usecase.ImportPaymentsUsecase,
+) *PaymentBatchHandler {
+	return &PaymentBatchHandler{
+		createPaymentBatchUC: createPaymentBatchUC,
+		getPaymentBatchUC:    getPaymentBatchUC,
+		importPaymentsUC:     importPaymentsUC,
+	}
+}
+
+// Create accepts the batch and returns it with every item pending. The
+// payments are created in the background; poll the batch for the results.
+func (h *PaymentBatchHandler) Create(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentBatchHandler.Create")
+	defer span.End()
+
+	var req createPaymentBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]usecase.CreatePaymentInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, usecase.CreatePaymentInput{
+			OrderID:        item.OrderID,
+			PayerID:        item.PayerID,
+			Amount:         item.Amount,
+			Currency:       item.Currency,
+			Provider:       item.Provider,
+			Method:         item.Method,
+			IdempotencyKey: item.IdempotencyKey,
+
+			PaymentMethodToken: item.PaymentMethod,
+			BankCode:           item.BankCode,
+			Wallet:             item.Wallet,
+			Channel:            item.Channel,
+			ReturnURL:          item.ReturnURL,
+			Splits:             toSplitShares(item.Splits),
+		})
+	}
+
+	batch, err := h.createPaymentBatchUC.Execute(ctx, usecase.CreatePaymentBatchInput{
+		Items:    items,
+		ClientIP: c.ClientIP(),
+	})
+	if err != nil {
+		c.JSON(paymentBatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", "/v1/payments/batch/"+batch.PublicID)
+	c.JSON(http.StatusAccepted, toPaymentBatchResponse(batch))
+}
+
+func (h *PaymentBatchHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentBatchHandler.Get")
+	defer span.End()
+
+	batch, err := h.getPaymentBatchUC.Execute(ctx, c.Param("batch_id"))
+	if err != nil {
+		c.JSON(paymentBatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentBatchResponse(batch))
+}
+
+// Import accepts a CSV file of payments in the multipart field "file" and
+// returns the import job. Its rows are created in the background like the
+// items of a batch.
+func (h *PaymentBatchHandler) Import(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentBatchHandler.Import")
+	defer span.End()
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	batch, err := h.importPaymentsUC.Execute(ctx, usecase.ImportPaymentsInput{
+		FileName: header.Filename,
+		File:     file,
+		ClientIP: c.ClientIP(),
+	})
+	if err != nil {
+		c.JSON(paymentBatchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", "/v1/payments/imports/"+batch.PublicID)
+	c.JSON(http.StatusAccepted, toPaymentBatchResponse(batch))
+}
+
+func (h *PaymentBatchHandler) GetImport(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentBatchHandler.GetImport")
+	defer span.End()
+
+	batch, ok := h.findImport(ctx, c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentBatchResponse(batch))
+}
+
+// ImportResult downloads the outcome of every row of a finished import as
+// CSV, in the order of the uploaded file's rows.
+func (h *PaymentBatchHandler) ImportResult(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentBatchHandler.ImportResult")
+	defer span.End()
+
+	batch, ok := h.findImport(ctx, c)
+	if !ok {
+		return
+	}
+	if batch.Status != domain.PaymentBatchStatusCompleted {
+		c.Header("Retry-After", "5")
+		c.JSON(http.StatusConflict, gin.H{"error": "import is still processing"})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+batch.PublicID+`-result.csv"`)
+	c.Status(http.StatusOK)
+
+	writePaymentImportResult(c.Writer, batch.Items)
+}
+
+// writePaymentImportResult writes one CSV row per item. Merchant-supplied
+// values are escaped so a spreadsheet does not run them as formulas.
+func writePaymentImportResult(out io.Writer, items []domain.PaymentBatchItem) {
+	w := csv.NewWriter(out)
+	_ = w.Write(paymentImportResultHeader)
+	for _, item := range items {
+		_ = w.Write([]string{
+			strconv.Itoa(item.Position + 1),
+			csvCell(item.OrderID),
+			csvCell(item.IdempotencyKey),
+			string(item.Status),
+			item.PaymentID,
+			string(item.PaymentStatus),
+			item.ErrorCode,
+			csvCell(item.Error),
+		})
+	}
+	w.Flush()
+}
+
+// csvCell prefixes a value that a spreadsheet would read as a formula with
+// a quote, so it is shown as text instead.
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// findImport loads the import named in the path, writing the error
+// response when there is none.
+func (h *PaymentBatchHandler) findImport(ctx context.Context, c *gin.Context) (*domain.PaymentBatch, bool) {
+	batch, err := h.getPaymentBatchUC.Execute(ctx, c.Param("import_id"))
+	if err == nil && batch.Source != domain.PaymentBatchSourceCSV {
+		err = domain.ErrPaymentBatchNotFound
+	}
+	if err != nil {
+		c.JSON(paymentBatchErrorStatus(err), gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return batch, true
+}
+
+func toPaymentBatchResponse(b *domain.PaymentBatch) paymentBatchResponse {
+	progress := b.Progress()
+	res := paymentBatchResponse{
+		ID:        b.PublicID,
+		Status:    string(b.Status),
+		Source:    string(b.Source),
+		FileName:  b.FileName,
+		Mode:      string(b.Mode),
+		CreatedBy: b.CreatedBy,
+		Progress: paymentBatchProgressResponse{
+			Total:     len(b.Items),
+			Pending:   progress[domain.PaymentBatchItemPending],
+			Created:   progress[domain.PaymentBatchItemCreated],
+			Duplicate: progress[domain.PaymentBatchItemDuplicate],
+			Failed:    progress[domain.PaymentBatchItemFailed],
+		},
+		Items:     make([]paymentBatchItemResponse, 0, len(b.Items)),
+		CreatedAt: b.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	for _, item := range b.Items {
+		res.Items = append(res.Items, paymentBatchItemResponse{
+			Position:       item.Position,
+			OrderID:        item.OrderID,
+			IdempotencyKey: item.IdempotencyKey,
+			Status:         string(item.Status),
+			PaymentID:      item.PaymentID,
+			PaymentStatus:  string(item.PaymentStatus),
+			ErrorCode:      item.ErrorCode,
+			Error:          item.Error,
+		})
+	}
+	if b.CompletedAt != nil {
+		completedAt := b.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		res.CompletedAt = &completedAt
+	}
+	return res
+}
+
+func paymentBatchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPaymentBatchNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrEmptyPaymentBatch),
+		errors.Is(err, domain.ErrPaymentBatchTooLarge),
+		errors.Is(err, domain.ErrDuplicateBatchItemKeys),
+		errors.Is(err, domain.ErrInvalidPaymentFile):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrPaymentFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"payment-service/internal/core/domain"
+)
+
+func TestWritePaymentImportResult_EscapesFormulas(t *testing.T) {
+	tests := []struct {
+		orderID string
+		want    string
+	}{
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"\rcmd", "'\rcmd"},
+		{"ORD-1", "ORD-1"},
+		{"", ""},
+	}
+	items := make([]domain.PaymentBatchItem, len(tests))
+	for i, tt := range tests {
+		items[i] = domain.PaymentBatchItem{
+			Position:       i,
+			OrderID:        tt.orderID,
+			IdempotencyKey: "key-" + tt.orderID,
+			Status:         domain.PaymentBatchItemFailed,
+			Error:          tt.orderID,
+		}
+	}
+
+	var buf bytes.Buffer
+	writePaymentImportResult(&buf, items)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read result: %v", err)
+	}
+	if len(rows) != len(tests)+1 {
+		t.Fatalf("expected %d rows, got %d", len(tests)+1, len(rows))
+	}
+	for i, tt := range tests {
+		row := rows[i+1]
+		if row[1] != tt.want || row[7] != tt.want {
+			t.Errorf("order %q: expected %q, got order %q and error %q", tt.orderID, tt.want, row[1], row[7])
+		}
+		if row[2] != "key-"+tt.orderID {
+			t.Errorf("order %q: idempotency key %q was not left alone", tt.orderID, row[2])
+		}
+	}
+}
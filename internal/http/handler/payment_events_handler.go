@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamRetry is how long browsers wait before reconnecting a dropped event
+// stream.
+const streamRetry = 3 * time.Second
+
+type paymentEventResponse struct {
+	getPaymentResponse
+	// PreviousStatus is empty for the payment's current state.
+	PreviousStatus string `json:"previous_status,omitempty"`
+}
+
+// streamLimiter caps the number of open event streams, in total and per
+// merchant.
+type streamLimiter struct {
+	mu          sync.Mutex
+	open        int
+	perMerchant map[int]int
+
+	max            int
+	maxPerMerchant int
+}
+
+// acquire reserves a stream for merchantID. release must be called once it
+// is closed.
+func (l *streamLimiter) acquire(merchantID int) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.open >= l.max || l.perMerchant[merchantID] >= l.maxPerMerchant {
+		return nil, false
+	}
+	l.open++
+	l.perMerchant[merchantID]++
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.open--
+		if l.perMerchant[merchantID]--; l.perMerchant[merchantID] == 0 {
+			delete(l.perMerchant, merchantID)
+		}
+	}, true
+}
+
+type PaymentEventsHandler struct {
+	watchPaymentUC *usecase.WatchPaymentUsecase
+
+	// heartbeat is how often a comment is sent on idle streams so proxies
+	// and clients can tell the connection is alive.
+	heartbeat time.Duration
+	streams   *streamLimiter
+}
+
+func NewPaymentEventsHandler(
+	watchPaymentUC *usecase.WatchPaymentUsecase,
+	heartbeat time.Duration,
+	maxStreams, maxStreamsPerMerchant int,
+) *PaymentEventsHandler {
+	return &PaymentEventsHandler{
+		watchPaymentUC: watchPaymentUC,
+		heartbeat:      heartbeat,
+		streams: &streamLimiter{
+			perMerchant:    make(map[int]int),
+			max:            maxStreams,
+			maxPerMerchant: maxStreamsPerMerchant,
+		},
+	}
+}
+
+// Stream sends the payment's status changes as Server-Sent Events until it
+// reaches a final status or the client goes away.
+func (h *PaymentEventsHandler) Stream(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentEventsHandler.Stream")
+	defer span.End()
+
+	var lastEventID int64
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid Last-Event-ID",
+			})
+			return
+		}
+		lastEventID = id
+	}
+
+	release, ok := h.streams.acquire(c.GetInt("merchant_id"))
+	if !ok {
+		c.Header("Retry-After", strconv.Itoa(int(streamRetry.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": "too many open event streams",
+		})
+		return
+	}
+	defer release()
+
+	watch, err := h.watchPaymentUC.Execute(ctx, c.Param("public_id"), lastEventID)
+	if errors.Is(err, domain.ErrPaymentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer watch.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// keeps nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := &eventWriter{c: c}
+	w.printf("retry: %d\n\n", streamRetry.Milliseconds())
+
+	for _, event := range watch.Events {
+		if w.send(event) {
+			return
+		}
+	}
+	if w.err != nil || watch.Final {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watch.Live:
+			if !ok {
+				// fell behind or shutting down; the client resumes from its
+				// last event
+				return
+			}
+			if w.send(event) {
+				return
+			}
+		case <-heartbeat.C:
+			w.printf(": heartbeat\n\n")
+		}
+		if w.err != nil {
+			return
+		}
+	}
+}
+
+// eventWriter writes events and flushes each one to the client. It stops
+// writing after the first error.
+type eventWriter struct {
+	c    *gin.Context
+	last int64
+	err  error
+}
+
+func (w *eventWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	if _, w.err = fmt.Fprintf(w.c.Writer, format, args...); w.err == nil {
+		w.c.Writer.Flush()
+	}
+}
+
+// send writes event unless an event at least as new was already sent. It
+// reports whether the payment reached a final status and the stream is done.
+func (w *eventWriter) send(event domain.PaymentEvent) (done bool) {
+	if event.ID <= w.last {
+		return false
+	}
+	data, err := json.Marshal(paymentEventResponse{
+		getPaymentResponse: toPaymentResponse(&event.Payment),
+		PreviousStatus:     string(event.From),
+	})
+	if err != nil {
+		w.err = err
+		return true
+	}
+	w.printf("id: %d\nevent: payment.status\ndata: %s\n\n", event.ID, data)
+	w.last = event.ID
+	return event.Payment.Status.IsFinal()
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"payment-service/internal/core/domain"
 	"payment-service/internal/core/usecase"
 	"payment-service/internal/observability"
 
@@ -14,25 +16,89 @@ type createPaymentRequest struct {
 	Amount   int    `json:"amount" binding:"required"`
 	Currency string `json:"currency" binding:"required"`
 	Provider string `json:"provider" binding:"required"`
-	Method   string `json:"method" binding:"required"`
+	// either method or a saved payment_method token must be given
+	Method        string `json:"method" binding:"required_without=PaymentMethod"`
+	PaymentMethod string `json:"payment_method"`
+	// bank_code is required for bank_transfer
+	BankCode string `json:"bank_code"`
+	// wallet, channel and return_url apply to ewallet
+	Wallet    string `json:"wallet"`
+	Channel   string `json:"channel" binding:"omitempty,oneof=web mobile qr"`
+	ReturnURL string `json:"return_url"`
+	// splits divide a marketplace payment between recipients
+	Splits []splitRequest `json:"splits" binding:"omitempty,dive"`
+}
+
+type splitRequest struct {
+	Recipient string `json:"recipient" binding:"required"`
+	// either amount or percentage must be given
+	Amount     int     `json:"amount" binding:"gte=0"`
+	Percentage float64 `json:"percentage" binding:"gte=0,lte=100"`
+	FeeBearer  bool    `json:"fee_bearer"`
+}
+
+type paymentInstructionsResponse struct {
+	Type          string `json:"type"`
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	Amount        int    `json:"amount"`
+	ExpiresAt     string `json:"expires_at"`
+}
+
+type nextActionResponse struct {
+	Type        string `json:"type"`
+	RedirectURL string `json:"redirect_url,omitempty"`
+	Deeplink    string `json:"deeplink,omitempty"`
+	QRString    string `json:"qr_string,omitempty"`
 }
 
 type createPaymentResponse struct {
-	PaymentID string `json:"payment_id"`
-	Status    string `json:"status"`
+	PaymentID           string                       `json:"payment_id"`
+	Status              string                       `json:"status"`
+	ExpiresAt           string                       `json:"expires_at,omitempty"`
+	PaymentInstructions *paymentInstructionsResponse `json:"payment_instructions,omitempty"`
+	NextAction          *nextActionResponse          `json:"next_action,omitempty"`
 }
 
 type getPaymentResponse struct {
-	PaymentID string `json:"payment_id"`
-	OrderID   string `json:"order_id"`
-	PayerID   int    `json:"payer_id"`
-	Amount    int    `json:"amount"`
-	Currency  string `json:"currency"`
-	Status    string `json:"status"`
-	Provider  string `json:"provider"`
-	Method    string `json:"method"`
-	CreatedAt string `json:"created_at"`
-	PaidAt    string `json:"paid_at,omitempty"`
+	PaymentID     string `json:"payment_id"`
+	OrderID       string `json:"order_id"`
+	PayerID       int    `json:"payer_id"`
+	Amount        int    `json:"amount"`
+	Currency      string `json:"currency"`
+	Status        string `json:"status"`
+	Provider      string `json:"provider"`
+	Method        string `json:"method"`
+	PaymentMethod string `json:"payment_method,omitempty"`
+	Mode          string `json:"mode"`
+	CreatedBy     string `json:"created_by,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	PaidAt        string `json:"paid_at,omitempty"`
+	ExpiresAt     string `json:"expires_at,omitempty"`
+	// ThreeDS is only present for authenticated card payments.
+	ThreeDS *threeDSResponse `json:"three_d_secure,omitempty"`
+	// Risk is only present for payments scored by the risk engine.
+	Risk *riskResponse `json:"risk,omitempty"`
+	// Splits is only present for split payments.
+	Splits []splitResponse `json:"splits,omitempty"`
+}
+
+type splitResponse struct {
+	Recipient  string  `json:"recipient"`
+	Amount     int     `json:"amount"`
+	Percentage float64 `json:"percentage,omitempty"`
+	FeeBearer  bool    `json:"fee_bearer"`
+}
+
+type threeDSResponse struct {
+	Status         string `json:"status"`
+	LiabilityShift bool   `json:"liability_shift"`
+}
+
+type riskResponse struct {
+	Score    int      `json:"score"`
+	Decision string   `json:"decision"`
+	Rules    []string `json:"rules"`
 }
 
 type PaymentHandler struct {
@@ -62,7 +128,12 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 		})
 		return
 	}
-	c.Set("payment_method", req.Method)
+	paymentMethod := req.Method
+	if paymentMethod == "" {
+		// the vault only holds cards
+		paymentMethod = domain.PaymentMethodTypeCard
+	}
+	c.Set("payment_method", paymentMethod)
 
 	// 🔑 Idempotency-Key wajib dari header
 	idempotencyKey := c.GetHeader("Idempotency-Key")
@@ -83,8 +154,30 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 			Provider:       req.Provider,
 			Method:         req.Method,
 			IdempotencyKey: idempotencyKey,
+
+			PaymentMethodToken: req.PaymentMethod,
+			BankCode:           req.BankCode,
+			Wallet:             req.Wallet,
+			Channel:            req.Channel,
+			ReturnURL:          req.ReturnURL,
+			ClientIP:           c.ClientIP(),
+			Splits:             toSplitShares(req.Splits),
 		},
 	)
+	var limitErr *domain.LimitExceededError
+	if errors.As(err, &limitErr) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": domain.ErrLimitExceeded.Error(),
+			"limit": limitErr.Limit,
+		})
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidSplits) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error": err.Error(),
@@ -92,10 +185,52 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusAccepted, createPaymentResponse{
+	c.JSON(http.StatusAccepted, toCreatePaymentResponse(output))
+}
+
+func toSplitShares(req []splitRequest) []domain.SplitShare {
+	if len(req) == 0 {
+		return nil
+	}
+	shares := make([]domain.SplitShare, 0, len(req))
+	for _, s := range req {
+		shares = append(shares, domain.SplitShare{
+			Recipient:  s.Recipient,
+			Amount:     s.Amount,
+			Percentage: s.Percentage,
+			FeeBearer:  s.FeeBearer,
+		})
+	}
+	return shares
+}
+
+func toCreatePaymentResponse(output *usecase.CreatePaymentOutput) createPaymentResponse {
+	res := createPaymentResponse{
 		PaymentID: output.PaymentID,
 		Status:    string(output.Status),
-	})
+		ExpiresAt: formatOptionalTime(output.ExpiresAt),
+	}
+
+	if va := output.VirtualAccount; va != nil {
+		res.PaymentInstructions = &paymentInstructionsResponse{
+			Type:          domain.PaymentMethodBankTransfer,
+			BankCode:      va.BankCode,
+			AccountNumber: va.AccountNumber,
+			Amount:        va.Outstanding(),
+			ExpiresAt:     va.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	if na := output.NextAction; na != nil {
+		res.NextAction = &nextActionResponse{
+			Type:        string(na.Type),
+			RedirectURL: na.RedirectURL,
+			Deeplink:    na.Deeplink,
+			QRString:    na.QRString,
+		}
+	}
+
+	return res
 }
 
 func (h *PaymentHandler) Get(c *gin.Context) {
@@ -115,12 +250,16 @@ func (h *PaymentHandler) Get(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, toPaymentResponse(payment))
+}
+
+func toPaymentResponse(payment *domain.Payment) getPaymentResponse {
 	var paidAt string
 	if payment.PaidAt != nil {
 		paidAt = payment.PaidAt.Format("2006-01-02T15:04:05Z07:00")
 	}
 
-	c.JSON(http.StatusOK, getPaymentResponse{
+	res := getPaymentResponse{
 		PaymentID: payment.PublicID,
 		OrderID:   payment.OrderID,
 		PayerID:   payment.PayerID,
@@ -129,7 +268,39 @@ func (h *PaymentHandler) Get(c *gin.Context) {
 		Status:    string(payment.Status),
 		Provider:  payment.Provider,
 		Method:    payment.Method,
+		Mode:      string(payment.Mode),
+		CreatedBy: payment.CreatedBy,
 		CreatedAt: payment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		PaidAt:    paidAt,
-	})
+		ExpiresAt: formatOptionalTime(payment.ExpiresAt),
+
+		PaymentMethod: payment.PaymentMethodToken,
+	}
+	if payment.ThreeDSStatus != "" {
+		res.ThreeDS = &threeDSResponse{
+			Status:         string(payment.ThreeDSStatus),
+			LiabilityShift: payment.LiabilityShift,
+		}
+	}
+	if payment.RiskDecision != "" {
+		rules := payment.RiskRules
+		if rules == nil {
+			rules = []string{}
+		}
+		res.Risk = &riskResponse{
+			Score:    payment.RiskScore,
+			Decision: string(payment.RiskDecision),
+			Rules:    rules,
+		}
+	}
+	for _, s := range payment.Splits {
+		res.Splits = append(res.Splits, splitResponse{
+			Recipient:  s.Recipient,
+			Amount:     s.Amount,
+			Percentage: s.Percentage(),
+			FeeBearer:  s.FeeBearer,
+		})
+	}
+
+	return res
 }
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createPaymentLinkRequest struct {
+	Amount      int    `json:"amount" binding:"required"`
+	Currency    string `json:"currency" binding:"required"`
+	Description string `json:"description"`
+	// single-use unless explicitly set to false
+	SingleUse *bool      `json:"single_use"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type paymentLinkResponse struct {
+	LinkID      string `json:"link_id"`
+	URL         string `json:"url"`
+	Amount      int    `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description,omitempty"`
+	SingleUse   bool   `json:"single_use"`
+	UsageCount  int    `json:"usage_count"`
+	Status      string `json:"status"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type PaymentLinkHandler struct {
+	createPaymentLinkUC     *usecase.CreatePaymentLinkUsecase
+	getPaymentLinkUC        *usecase.GetPaymentLinkUsecase
+	deactivatePaymentLinkUC *usecase.DeactivatePaymentLinkUsecase
+	baseURL                 string
+}
+
+func NewPaymentLinkHandler(
+	createPaymentLinkUC *usecase.CreatePaymentLinkUsecase,
+	getPaymentLinkUC *usecase.GetPaymentLinkUsecase,
+	deactivatePaymentLinkUC *usecase.DeactivatePaymentLinkUsecase,
+	baseURL string,
+) *PaymentLinkHandler {
+	return &PaymentLinkHandler{
+		createPaymentLinkUC:     createPaymentLinkUC,
+		getPaymentLinkUC:        getPaymentLinkUC,
+		deactivatePaymentLinkUC: deactivatePaymentLinkUC,
+		baseURL:                 strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (h *PaymentLinkHandler) toResponse(l *domain.PaymentLink) paymentLinkResponse {
+	return paymentLinkResponse{
+		LinkID:      l.PublicID,
+		URL:         h.baseURL + "/pay/" + l.PublicID,
+		Amount:      l.Amount,
+		Currency:    l.Currency,
+		Description: l.Description,
+		SingleUse:   l.SingleUse,
+		UsageCount:  l.UsageCount,
+		Status:      string(l.StatusAt(time.Now())),
+		ExpiresAt:   formatOptionalTime(l.ExpiresAt),
+		CreatedAt:   l.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func paymentLinkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPaymentLinkNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+func (h *PaymentLinkHandler) Create(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentLinkHandler.Create")
+	defer span.End()
+
+	var req createPaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	singleUse := true
+	if req.SingleUse != nil {
+		singleUse = *req.SingleUse
+	}
+
+	link, err := h.createPaymentLinkUC.Execute(ctx, usecase.CreatePaymentLinkInput{
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Description: req.Description,
+		SingleUse:   singleUse,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		c.JSON(paymentLinkErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.toResponse(link))
+}
+
+func (h *PaymentLinkHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentLinkHandler.Get")
+	defer span.End()
+
+	link, err := h.getPaymentLinkUC.Execute(ctx, c.Param("link_id"))
+	if err != nil {
+		c.JSON(paymentLinkErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toResponse(link))
+}
+
+func (h *PaymentLinkHandler) Deactivate(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentLinkHandler.Deactivate")
+	defer span.End()
+
+	link, err := h.deactivatePaymentLinkUC.Execute(ctx, c.Param("link_id"))
+	if err != nil {
+		c.JSON(paymentLinkErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toResponse(link))
+}
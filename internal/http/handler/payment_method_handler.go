@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createCardRequest struct {
+	PayerID    int    `json:"payer_id" binding:"required"`
+	Number     string `json:"number" binding:"required"`
+	ExpMonth   int    `json:"exp_month" binding:"required"`
+	ExpYear    int    `json:"exp_year" binding:"required"`
+	CVC        string `json:"cvc" binding:"required"`
+	HolderName string `json:"holder_name"`
+}
+
+type paymentMethodResponse struct {
+	PaymentMethod string `json:"payment_method"`
+	PayerID       int    `json:"payer_id"`
+	Type          string `json:"type"`
+	Brand         string `json:"brand"`
+	Last4         string `json:"last4"`
+	BIN           string `json:"bin"`
+	ExpMonth      int    `json:"exp_month"`
+	ExpYear       int    `json:"exp_year"`
+	HolderName    string `json:"holder_name,omitempty"`
+	Status        string `json:"status"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type listPaymentMethodsResponse struct {
+	Data []paymentMethodResponse `json:"data"`
+}
+
+type PaymentMethodHandler struct {
+	tokenizeCardUC        *usecase.TokenizeCardUsecase
+	getPaymentMethodUC    *usecase.GetPaymentMethodUsecase
+	listPaymentMethodsUC  *usecase.ListPaymentMethodsUsecase
+	detachPaymentMethodUC *usecase.DetachPaymentMethodUsecase
+}
+
+func NewPaymentMethodHandler(
+	tokenizeCardUC *usecase.TokenizeCardUsecase,
+	getPaymentMethodUC *usecase.GetPaymentMethodUsecase,
+	listPaymentMethodsUC *usecase.ListPaymentMethodsUsecase,
+	detachPaymentMethodUC *usecase.DetachPaymentMethodUsecase,
+) *PaymentMethodHandler {
+	return &PaymentMethodHandler{
+		tokenizeCardUC:        tokenizeCardUC,
+		getPaymentMethodUC:    getPaymentMethodUC,
+		listPaymentMethodsUC:  listPaymentMethodsUC,
+		detachPaymentMethodUC: detachPaymentMethodUC,
+	}
+}
+
+func toPaymentMethodResponse(m *domain.PaymentMethod) paymentMethodResponse {
+	return paymentMethodResponse{
+		PaymentMethod: m.Token,
+		PayerID:       m.PayerID,
+		Type:          m.Type,
+		Brand:         string(m.Brand),
+		Last4:         m.Last4,
+		BIN:           m.BIN,
+		ExpMonth:      m.ExpMonth,
+		ExpYear:       m.ExpYear,
+		HolderName:    m.HolderName,
+		Status:        string(m.Status),
+		CreatedAt:     m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func paymentMethodErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPaymentMethodNotFound),
+		errors.Is(err, domain.ErrPayerNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+func (h *PaymentMethodHandler) CreateCard(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentMethodHandler.CreateCard")
+	defer span.End()
+
+	var req createCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	method, err := h.tokenizeCardUC.Execute(ctx, usecase.TokenizeCardInput{
+		PayerID:    req.PayerID,
+		Number:     req.Number,
+		ExpMonth:   req.ExpMonth,
+		ExpYear:    req.ExpYear,
+		CVC:        req.CVC,
+		HolderName: req.HolderName,
+	})
+	if err != nil {
+		c.JSON(paymentMethodErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toPaymentMethodResponse(method))
+}
+
+func (h *PaymentMethodHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentMethodHandler.Get")
+	defer span.End()
+
+	method, err := h.getPaymentMethodUC.Execute(ctx, c.Param("token"))
+	if err != nil {
+		c.JSON(paymentMethodErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentMethodResponse(method))
+}
+
+func (h *PaymentMethodHandler) ListByPayer(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentMethodHandler.ListByPayer")
+	defer span.End()
+
+	payerID, ok := payerIDParam(c)
+	if !ok {
+		return
+	}
+
+	methods, err := h.listPaymentMethodsUC.Execute(ctx, payerID)
+	if err != nil {
+		c.JSON(paymentMethodErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp := listPaymentMethodsResponse{
+		Data: make([]paymentMethodResponse, 0, len(methods)),
+	}
+	for _, m := range methods {
+		resp.Data = append(resp.Data, toPaymentMethodResponse(m))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PaymentMethodHandler) Detach(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PaymentMethodHandler.Detach")
+	defer span.End()
+
+	if err := h.detachPaymentMethodUC.Execute(ctx, c.Param("token")); err != nil {
+		c.JSON(paymentMethodErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
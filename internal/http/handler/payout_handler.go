@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createPayoutRequest struct {
+	Currency string `json:"currency" binding:"required"`
+	// Amount defaults to the whole available balance.
+	Amount int `json:"amount" binding:"gte=0"`
+}
+
+type payoutResponse struct {
+	ID            string  `json:"id"`
+	Amount        int     `json:"amount"`
+	Currency      string  `json:"currency"`
+	Status        string  `json:"status"`
+	Trigger       string  `json:"trigger"`
+	Destination   string  `json:"destination"`
+	Reference     string  `json:"reference,omitempty"`
+	FailureReason string  `json:"failure_reason,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+	SentAt        *string `json:"sent_at,omitempty"`
+	CompletedAt   *string `json:"completed_at,omitempty"`
+}
+
+type listPayoutsResponse struct {
+	Data []payoutResponse `json:"data"`
+}
+
+type balanceResponse struct {
+	Currency  string `json:"currency"`
+	Available int    `json:"available"`
+	Pending   int    `json:"pending"`
+	InTransit int    `json:"in_transit"`
+}
+
+type listBalancesResponse struct {
+	Data []balanceResponse `json:"data"`
+}
+
+type PayoutHandler struct {
+	getBalancesUC  *usecase.GetBalancesUsecase
+	createPayoutUC *usecase.CreatePayoutUsecase
+	getPayoutUC    *usecase.GetPayoutUsecase
+	listPayoutsUC  *usecase.ListPayoutsUsecase
+}
+
+func NewPayoutHandler(
+	getBalancesUC *usecase.GetBalancesUsecase,
+	createPayoutUC *usecase.CreatePayoutUsecase,
+	getPayoutUC *usecase.GetPayoutUsecase,
+	listPayoutsUC *usecase.ListPayoutsUsecase,
+) *PayoutHandler {
+	return &PayoutHandler{
+		getBalancesUC:  getBalancesUC,
+		createPayoutUC: createPayoutUC,
+		getPayoutUC:    getPayoutUC,
+		listPayoutsUC:  listPayoutsUC,
+	}
+}
+
+func (h *PayoutHandler) Balances(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayoutHandler.Balances")
+	defer span.End()
+
+	balances, err := h.getBalancesUC.Execute(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := listBalancesResponse{
+		Data: make([]balanceResponse, 0, len(balances)),
+	}
+	for _, b := range balances {
+		resp.Data = append(resp.Data, toBalanceResponse(b))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PayoutHandler) Create(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayoutHandler.Create")
+	defer span.End()
+
+	var req createPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payout, err := h.createPayoutUC.Execute(ctx, usecase.CreatePayoutInput{
+		Currency: req.Currency,
+		Amount:   req.Amount,
+	})
+	if err != nil {
+		c.JSON(payoutErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toPayoutResponse(payout))
+}
+
+func (h *PayoutHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayoutHandler.Get")
+	defer span.End()
+
+	payout, err := h.getPayoutUC.Execute(ctx, c.Param("payout_id"))
+	if err != nil {
+		c.JSON(payoutErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPayoutResponse(payout))
+}
+
+func (h *PayoutHandler) List(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "PayoutHandler.List")
+	defer span.End()
+
+	limit, offset := pageParams(c)
+	payouts, err := h.listPayoutsUC.Execute(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := listPayoutsResponse{
+		Data: make([]payoutResponse, 0, len(payouts)),
+	}
+	for _, p := range payouts {
+		resp.Data = append(resp.Data, toPayoutResponse(p))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toBalanceResponse(b domain.Balance) balanceResponse {
+	return balanceResponse{
+		Currency:  b.Currency,
+		Available: b.Available,
+		Pending:   b.Pending,
+		InTransit: b.InTransit,
+	}
+}
+
+func toPayoutResponse(p *domain.Payout) payoutResponse {
+	res := payoutResponse{
+		ID:            p.PublicID,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        string(p.Status),
+		Trigger:       string(p.Trigger),
+		Destination:   p.Destination,
+		Reference:     p.Reference,
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if p.SentAt != nil {
+		sentAt := p.SentAt.Format("2006-01-02T15:04:05Z07:00")
+		res.SentAt = &sentAt
+	}
+	if p.CompletedAt != nil {
+		completedAt := p.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
+		res.CompletedAt = &completedAt
+	}
+	return res
+}
+
+func payoutErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPayoutNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidPayoutAmount),
+		errors.Is(err, domain.ErrPayoutBelowMinimum):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInsufficientBalance):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
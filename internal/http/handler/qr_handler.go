@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+type qrResponse struct {
+	PaymentID string `json:"payment_id"`
+	Status    string `json:"status"`
+	Payload   string `json:"payload"`
+	ImageURL  string `json:"image_url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type qrPaymentRequest struct {
+	Reference       string `json:"reference" binding:"required"`
+	Amount          int    `json:"amount" binding:"required"`
+	IssuerReference string `json:"issuer_reference"`
+}
+
+type QRHandler struct {
+	getPaymentQRUC    *usecase.GetPaymentQRUsecase
+	handleQRPaymentUC *usecase.HandleQRPaymentUsecase
+	// callbackToken must be sent in X-Callback-Token.
+	callbackToken string
+}
+
+func NewQRHandler(
+	getPaymentQRUC *usecase.GetPaymentQRUsecase,
+	handleQRPaymentUC *usecase.HandleQRPaymentUsecase,
+	callbackToken string,
+) *QRHandler {
+	return &QRHandler{
+		getPaymentQRUC:    getPaymentQRUC,
+		handleQRPaymentUC: handleQRPaymentUC,
+		callbackToken:     callbackToken,
+	}
+}
+
+// Get serves the payment's QR as JSON, or as a PNG image when asked for
+// with ?format=png or an Accept: image/png header.
+func (h *QRHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "QRHandler.Get")
+	defer span.End()
+
+	out, err := h.getPaymentQRUC.Execute(ctx, c.Param("public_id"))
+	if err != nil {
+		c.JSON(qrErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if wantsPNG(c) {
+		size := defaultQRSize
+		if raw := c.Query("size"); raw != "" {
+			size, err = strconv.Atoi(raw)
+			if err != nil || size < 64 || size > maxQRSize {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "size must be between 64 and 1024"})
+				return
+			}
+		}
+
+		png, err := qrcode.Encode(out.QRCode.Payload, qrcode.Medium, size)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", png)
+		return
+	}
+
+	c.JSON(http.StatusOK, toQRResponse(out, c.Request.URL.Path+"?format=png"))
+}
+
+// toQRResponse is the payment's QR as JSON, with imageURL serving it as PNG.
+func toQRResponse(out *usecase.GetPaymentQROutput, imageURL string) qrResponse {
+	return qrResponse{
+		PaymentID: out.Payment.PublicID,
+		Status:    string(out.Payment.Status),
+		Payload:   out.QRCode.Payload,
+		ImageURL:  imageURL,
+		ExpiresAt: out.QRCode.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func wantsPNG(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return format == "png"
+	}
+	return strings.Contains(c.GetHeader("Accept"), "image/png")
+}
+
+// Webhook receives scan-and-pay notifications from the QR network.
+func (h *QRHandler) Webhook(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "QRHandler.Webhook")
+	defer span.End()
+
+	if !validCallbackToken(c, h.callbackToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid callback token"})
+		return
+	}
+
+	var req qrPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := h.handleQRPaymentUC.Execute(ctx, usecase.HandleQRPaymentInput{
+		Reference:       req.Reference,
+		Amount:          req.Amount,
+		IssuerReference: req.IssuerReference,
+	})
+	if err != nil {
+		c.JSON(qrErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPaymentResponse(payment))
+}
+
+func qrErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrQRCodeNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrQRCodeExpired):
+		return http.StatusGone
+	case errors.Is(err, domain.ErrInvalidPaymentStatus),
+		errors.Is(err, domain.ErrPaymentStatusConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
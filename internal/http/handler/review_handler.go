@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+
+	"github.com/gin-gonic/gin"
+)
+
+type reviewDecisionRequest struct {
+	Reviewer string `json:"reviewer" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+type reviewEntryResponse struct {
+	Action    string `json:"action"`
+	Reason    string `json:"reason"`
+	Reviewer  string `json:"reviewer"`
+	CreatedAt string `json:"created_at"`
+}
+
+type reviewResponse struct {
+	Payment getPaymentResponse    `json:"payment"`
+	History []reviewEntryResponse `json:"history"`
+}
+
+type ReviewHandler struct {
+	listReviewsUC  *usecase.ListReviewsUsecase
+	getReviewUC    *usecase.GetReviewUsecase
+	decideReviewUC *usecase.DecideReviewUsecase
+}
+
+func NewReviewHandler(
+	listReviewsUC *usecase.ListReviewsUsecase,
+	getReviewUC *usecase.GetReviewUsecase,
+	decideReviewUC *usecase.DecideReviewUsecase,
+) *ReviewHandler {
+	return &ReviewHandler{
+		listReviewsUC:  listReviewsUC,
+		getReviewUC:    getReviewUC,
+		decideReviewUC: decideReviewUC,
+	}
+}
+
+func (h *ReviewHandler) List(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "ReviewHandler.List")
+	defer span.End()
+
+	limit, offset := pageParams(c)
+	payments, err := h.listReviewsUC.Execute(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := listPaymentsResponse{
+		Data: make([]getPaymentResponse, 0, len(payments)),
+	}
+	for _, p := range payments {
+		resp.Data = append(resp.Data, toPaymentResponse(p))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ReviewHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "ReviewHandler.Get")
+	defer span.End()
+
+	out, err := h.getReviewUC.Execute(ctx, c.Param("public_id"))
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toReviewResponse(out))
+}
+
+func (h *ReviewHandler) Approve(c *gin.Context) {
+	h.decide(c, true)
+}
+
+func (h *ReviewHandler) Reject(c *gin.Context) {
+	h.decide(c, false)
+}
+
+func (h *ReviewHandler) decide(c *gin.Context, approve bool) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "ReviewHandler.Decide")
+	defer span.End()
+
+	var req reviewDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	out, err := h.decideReviewUC.Execute(ctx, usecase.DecideReviewInput{
+		PaymentID: c.Param("public_id"),
+		Approve:   approve,
+		Reason:    req.Reason,
+		Reviewer:  req.Reviewer,
+	})
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toReviewResponse(out))
+}
+
+func toReviewResponse(out *usecase.ReviewOutput) reviewResponse {
+	resp := reviewResponse{
+		Payment: toPaymentResponse(out.Payment),
+		History: make([]reviewEntryResponse, 0, len(out.History)),
+	}
+	for _, r := range out.History {
+		resp.History = append(resp.History, reviewEntryResponse{
+			Action:    string(r.Action),
+			Reason:    r.Reason,
+			Reviewer:  r.Reviewer,
+			CreatedAt: r.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	return resp
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrReviewerRequired),
+		errors.Is(err, domain.ErrReviewReasonRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrPaymentNotInReview),
+		errors.Is(err, domain.ErrReviewExpired),
+		errors.Is(err, domain.ErrPaymentStatusConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
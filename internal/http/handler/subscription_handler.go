@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createPlanRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Amount        int    `json:"amount" binding:"required"`
+	Currency      string `json:"currency" binding:"required"`
+	Interval      string `json:"interval" binding:"required"`
+	IntervalCount int    `json:"interval_count"`
+	TrialDays     int    `json:"trial_days"`
+}
+
+type planResponse struct {
+	PlanID        string `json:"plan_id"`
+	Name          string `json:"name"`
+	Amount        int    `json:"amount"`
+	Currency      string `json:"currency"`
+	Interval      string `json:"interval"`
+	IntervalCount int    `json:"interval_count"`
+	TrialDays     int    `json:"trial_days"`
+	Active        bool   `json:"active"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type listPlansResponse struct {
+	Data []planResponse `json:"data"`
+}
+
+type createSubscriptionRequest struct {
+	PlanID        string `json:"plan_id" binding:"required"`
+	PayerID       int    `json:"payer_id" binding:"required"`
+	PaymentMethod string `json:"payment_method" binding:"required"`
+}
+
+type subscriptionResponse struct {
+	SubscriptionID     string `json:"subscription_id"`
+	PayerID            int    `json:"payer_id"`
+	PaymentMethod      string `json:"payment_method"`
+	Status             string `json:"status"`
+	Cycle              int    `json:"cycle"`
+	CurrentPeriodStart string `json:"current_period_start"`
+	CurrentPeriodEnd   string `json:"current_period_end"`
+	FailedAttempts     int    `json:"failed_attempts"`
+	NextRetryAt        string `json:"next_retry_at,omitempty"`
+	LastPaymentID      string `json:"last_payment_id,omitempty"`
+	CanceledAt         string `json:"canceled_at,omitempty"`
+	CreatedAt          string `json:"created_at"`
+}
+
+type SubscriptionHandler struct {
+	createPlanUC         *usecase.CreatePlanUsecase
+	getPlanUC            *usecase.GetPlanUsecase
+	listPlansUC          *usecase.ListPlansUsecase
+	createSubscriptionUC *usecase.CreateSubscriptionUsecase
+	getSubscriptionUC    *usecase.GetSubscriptionUsecase
+	pauseSubscriptionUC  *usecase.PauseSubscriptionUsecase
+	resumeSubscriptionUC *usecase.ResumeSubscriptionUsecase
+	cancelSubscriptionUC *usecase.CancelSubscriptionUsecase
+}
+
+func NewSubscriptionHandler(
+	createPlanUC *usecase.CreatePlanUsecase,
+	getPlanUC *usecase.GetPlanUsecase,
+	listPlansUC *usecase.ListPlansUsecase,
+	createSubscriptionUC *usecase.CreateSubscriptionUsecase,
+	getSubscriptionUC *usecase.GetSubscriptionUsecase,
+	pauseSubscriptionUC *usecase.PauseSubscriptionUsecase,
+	resumeSubscriptionUC *usecase.ResumeSubscriptionUsecase,
+	cancelSubscriptionUC *usecase.CancelSubscriptionUsecase,
+) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		createPlanUC:         createPlanUC,
+		getPlanUC:            getPlanUC,
+		listPlansUC:          listPlansUC,
+		createSubscriptionUC: createSubscriptionUC,
+		getSubscriptionUC:    getSubscriptionUC,
+		pauseSubscriptionUC:  pauseSubscriptionUC,
+		resumeSubscriptionUC: resumeSubscriptionUC,
+		cancelSubscriptionUC: cancelSubscriptionUC,
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02T15:04:05Z07:00")
+}
+
+func toPlanResponse(p *domain.Plan) planResponse {
+	return planResponse{
+		PlanID:        p.PublicID,
+		Name:          p.Name,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Interval:      string(p.Interval),
+		IntervalCount: p.IntervalCount,
+		TrialDays:     p.TrialDays,
+		Active:        p.Active,
+		CreatedAt:     p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func toSubscriptionResponse(s *domain.Subscription) subscriptionResponse {
+	return subscriptionResponse{
+		SubscriptionID:     s.PublicID,
+		PayerID:            s.PayerID,
+		PaymentMethod:      s.PaymentMethodToken,
+		Status:             string(s.Status),
+		Cycle:              s.Cycle,
+		CurrentPeriodStart: s.CurrentPeriodStart.Format("2006-01-02T15:04:05Z07:00"),
+		CurrentPeriodEnd:   s.CurrentPeriodEnd.Format("2006-01-02T15:04:05Z07:00"),
+		FailedAttempts:     s.FailedAttempts,
+		NextRetryAt:        formatOptionalTime(s.NextRetryAt),
+		LastPaymentID:      s.LastPaymentID,
+		CanceledAt:         formatOptionalTime(s.CanceledAt),
+		CreatedAt:          s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+func subscriptionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPlanNotFound),
+		errors.Is(err, domain.ErrSubscriptionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidSubscriptionChange):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+func (h *SubscriptionHandler) CreatePlan(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.CreatePlan")
+	defer span.End()
+
+	var req createPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	plan, err := h.createPlanUC.Execute(ctx, usecase.CreatePlanInput{
+		Name:          req.Name,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		Interval:      domain.PlanInterval(req.Interval),
+		IntervalCount: req.IntervalCount,
+		TrialDays:     req.TrialDays,
+	})
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toPlanResponse(plan))
+}
+
+func (h *SubscriptionHandler) GetPlan(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.GetPlan")
+	defer span.End()
+
+	plan, err := h.getPlanUC.Execute(ctx, c.Param("plan_id"))
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPlanResponse(plan))
+}
+
+func (h *SubscriptionHandler) ListPlans(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.ListPlans")
+	defer span.End()
+
+	limit, offset := pageParams(c)
+	plans, err := h.listPlansUC.Execute(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp := listPlansResponse{
+		Data: make([]planResponse, 0, len(plans)),
+	}
+	for _, p := range plans {
+		resp.Data = append(resp.Data, toPlanResponse(p))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *SubscriptionHandler) Create(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.Create")
+	defer span.End()
+
+	var req createSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.createSubscriptionUC.Execute(ctx, usecase.CreateSubscriptionInput{
+		PlanID:             req.PlanID,
+		PayerID:            req.PayerID,
+		PaymentMethodToken: req.PaymentMethod,
+	})
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, toSubscriptionResponse(subscription))
+}
+
+func (h *SubscriptionHandler) Get(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.Get")
+	defer span.End()
+
+	subscription, err := h.getSubscriptionUC.Execute(ctx, c.Param("subscription_id"))
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toSubscriptionResponse(subscription))
+}
+
+func (h *SubscriptionHandler) Pause(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.Pause")
+	defer span.End()
+
+	subscription, err := h.pauseSubscriptionUC.Execute(ctx, c.Param("subscription_id"))
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toSubscriptionResponse(subscription))
+}
+
+func (h *SubscriptionHandler) Resume(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.Resume")
+	defer span.End()
+
+	subscription, err := h.resumeSubscriptionUC.Execute(ctx, c.Param("subscription_id"))
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toSubscriptionResponse(subscription))
+}
+
+func (h *SubscriptionHandler) Cancel(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "SubscriptionHandler.Cancel")
+	defer span.End()
+
+	subscription, err := h.cancelSubscriptionUC.Execute(ctx, c.Param("subscription_id"))
+	if err != nil {
+		c.JSON(subscriptionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toSubscriptionResponse(subscription))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ThreeDSHandler struct {
+	completeThreeDSUC *usecase.CompleteThreeDSUsecase
+}
+
+func NewThreeDSHandler(completeThreeDSUC *usecase.CompleteThreeDSUsecase) *ThreeDSHandler {
+	return &ThreeDSHandler{completeThreeDSUC: completeThreeDSUC}
+}
+
+// Complete is where the issuer sends the payer after the challenge. The
+// payment's authorization is resumed and the payer returned to the merchant.
+func (h *ThreeDSHandler) Complete(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "ThreeDSHandler.Complete")
+	defer span.End()
+
+	out, err := h.completeThreeDSUC.Execute(ctx, c.Param("public_id"))
+	if err != nil {
+		c.JSON(threeDSErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	returnToMerchant(c, out.ReturnURL, out.Payment)
+}
+
+func threeDSErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrThreeDSNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrThreeDSPending),
+		errors.Is(err, domain.ErrInvalidPaymentStatus),
+		errors.Is(err, domain.ErrPaymentStatusConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"payment-service/internal/core/domain"
+	"payment-service/internal/core/usecase"
+	"payment-service/internal/observability"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bankCreditRequest struct {
+	BankCode      string    `json:"bank_code" binding:"required"`
+	AccountNumber string    `json:"account_number" binding:"required"`
+	Amount        int       `json:"amount" binding:"required"`
+	Reference     string    `json:"reference" binding:"required"`
+	ReceivedAt    time.Time `json:"received_at"`
+}
+
+type bankCreditResponse struct {
+	PaymentID            string `json:"payment_id"`
+	PaymentStatus        string `json:"payment_status"`
+	VirtualAccountStatus string `json:"virtual_account_status"`
+	PaidAmount           int    `json:"paid_amount"`
+	Outstanding          int    `json:"outstanding"`
+	Duplicate            bool   `json:"duplicate"`
+	Late                 bool   `json:"late"`
+}
+
+type WebhookHandler struct {
+	handleBankCreditUC *usecase.HandleBankCreditUsecase
+	// callbackToken must be sent in X-Callback-Token.
+	callbackToken string
+}
+
+func NewWebhookHandler(
+	handleBankCreditUC *usecase.HandleBankCreditUsecase,
+	callbackToken string,
+) *WebhookHandler {
+	return &WebhookHandler{
+		handleBankCreditUC: handleBankCreditUC,
+		callbackToken:      callbackToken,
+	}
+}
+
+// validCallbackToken reports whether the request carries want in
+// X-Callback-Token. Without a configured token every request is refused.
+func validCallbackToken(c *gin.Context, want string) bool {
+	if want == "" {
+		return false
+	}
+	got := c.GetHeader("X-Callback-Token")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+func (h *WebhookHandler) BankTransfer(c *gin.Context) {
+	ctx, span := observability.Tracer().Start(c.Request.Context(), "WebhookHandler.BankTransfer")
+	defer span.End()
+
+	if !validCallbackToken(c, h.callbackToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid callback token"})
+		return
+	}
+
+	var req bankCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	out, err := h.handleBankCreditUC.Execute(ctx, usecase.HandleBankCreditInput{
+		BankCode:      req.BankCode,
+		AccountNumber: req.AccountNumber,
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+		ReceivedAt:    req.ReceivedAt,
+	})
+	if err != nil {
+		c.JSON(bankCreditErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toBankCreditResponse(out))
+}
+
+func toBankCreditResponse(out *usecase.HandleBankCreditOutput) bankCreditResponse {
+	return bankCreditResponse{
+		PaymentID:            out.PaymentID,
+		PaymentStatus:        string(out.PaymentStatus),
+		VirtualAccountStatus: string(out.VirtualAccount.Status),
+		PaidAmount:           out.VirtualAccount.PaidAmount,
+		Outstanding:          out.VirtualAccount.Outstanding(),
+		Duplicate:            out.Duplicate,
+		Late:                 out.Late,
+	}
+}
+
+func bankCreditErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrVirtualAccountNotFound),
+		errors.Is(err, domain.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrPaymentStatusConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
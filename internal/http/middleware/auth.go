@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"payment-service/internal/auth"
+	"payment-service/internal/core/domain"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bearerToken returns the token of an "Authorization: Bearer <token>"
+// header, or "" when there is none.
+func bearerToken(c *gin.Context) string {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// authenticated records who the request is made by and, when it acts for a
+// merchant, scopes the request context to it so every repository call made
+// for the request only sees that merchant's data.
+func authenticated(c *gin.Context, principal domain.Principal, tenant *domain.Tenant) {
+	ctx := domain.WithPrincipal(c.Request.Context(), principal)
+	if tenant != nil {
+		ctx = domain.WithTenant(ctx, *tenant)
+		c.Set("merchant_id", tenant.MerchantID)
+		if tenant.APIKeyID > 0 {
+			c.Set("api_key_id", tenant.APIKeyID)
+		}
+	}
+	c.Request = c.Request.WithContext(ctx)
+	c.Set("subject", principal.Subject)
+}
+
+// MerchantAuth authenticates callers of the merchant API with authn and
+// scopes the request to the merchant they act for.
+func MerchantAuth(authn *auth.MerchantAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, tenant, err := authn.Authenticate(c.Request.Context(), bearerToken(c))
+		switch {
+		case errors.Is(err, domain.ErrTokenMerchantRequired):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, domain.ErrInvalidToken), errors.Is(err, domain.ErrInvalidAPIKey):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		authenticated(c, principal, &tenant)
+		c.Next()
+	}
+}
+
+// AdminAuth guards the admin API with a shared bearer token or a JWT. With
+// neither configured every request is rejected.
+func AdminAuth(token string, verifier *auth.JWTVerifier) gin.HandlerFunc {
+	admin := domain.Principal{Subject: "admin", Scopes: []string{domain.ScopeAdmin}}
+
+	return func(c *gin.Context) {
+		bearer := bearerToken(c)
+		switch {
+		case token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1:
+			authenticated(c, admin, nil)
+		case verifier != nil && auth.LooksLikeJWT(bearer):
+			claims, err := verifier.Verify(bearer)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": domain.ErrInvalidToken.Error()})
+				return
+			}
+			authenticated(c, claims.Principal(), nil)
+		default:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects callers that were not granted scope. It runs after
+// MerchantAuth or AdminAuth.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := domain.PrincipalFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+			return
+		}
+		if !principal.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing scope " + scope})
+			return
+		}
+		c.Next()
+	}
+}
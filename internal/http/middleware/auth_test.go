@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"payment-service/internal/auth"
+)
+
+func adminEngine(token string, verifier *auth.JWTVerifier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", AdminAuth(token, verifier), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		bearer string
+		want   int
+	}{
+		{"valid token", "adm", "adm", http.StatusNoContent},
+		{"wrong token", "adm", "nope", http.StatusUnauthorized},
+		{"missing token", "adm", "", http.StatusUnauthorized},
+		{"nothing configured", "", "", http.StatusUnauthorized},
+		{"nothing configured with bearer", "", "anything", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			w := httptest.NewRecorder()
+			adminEngine(tt.token, nil).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"payment-service/internal/observability"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// What a rate limit counts requests by.
+const (
+	// RateLimitByAPIKey gives every API key or service token its own
+	// bucket. Unauthenticated requests fall back to their IP.
+	RateLimitByAPIKey = "api_key"
+	RateLimitByIP     = "ip"
+	// RateLimitByRoute shares one bucket between all callers of a route.
+	RateLimitByRoute = "route"
+)
+
+// RateLimit is a token bucket: Burst requests at once, refilled at Rate
+// requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+	By    string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// TokenBuckets holds the buckets of one rate limit. It is kept in memory,
+// so every instance of the service enforces its limits on its own.
+type TokenBuckets struct {
+	mu      sync.Mutex
+	limit   RateLimit
+	buckets map[string]*bucket
+	pruned  time.Time
+}
+
+func NewTokenBuckets(limit RateLimit) *TokenBuckets {
+	return &TokenBuckets{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// refillTime is how long an empty bucket takes to fill up again. Buckets
+// idle for that long are full and can be forgotten.
+func (b *TokenBuckets) refillTime() time.Duration {
+	return time.Duration(float64(b.limit.Burst) / b.limit.Rate * float64(time.Second))
+}
+
+// Take spends a token of key's bucket. It reports whether one was left,
+// how many remain and how long until the next one is available.
+func (b *TokenBuckets) Take(key string, now time.Time) (ok bool, remaining int, wait time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if idle := b.refillTime(); now.Sub(b.pruned) > idle {
+		for k, bk := range b.buckets {
+			if now.Sub(bk.last) >= idle {
+				delete(b.buckets, k)
+			}
+		}
+		b.pruned = now
+	}
+
+	bk, found := b.buckets[key]
+	if !found {
+		bk = &bucket{tokens: float64(b.limit.Burst), last: now}
+		b.buckets[key] = bk
+	}
+	if elapsed := now.Sub(bk.last).Seconds(); elapsed > 0 {
+		bk.tokens = math.Min(float64(b.limit.Burst), bk.tokens+elapsed*b.limit.Rate)
+		bk.last = now
+	}
+
+	if bk.tokens < 1 {
+		wait = time.Duration((1 - bk.tokens) / b.limit.Rate * float64(time.Second))
+		return false, 0, wait
+	}
+	bk.tokens--
+	return true, int(bk.tokens), 0
+}
+
+// RateLimiter throttles requests per route class, e.g. payment creation,
+// the rest of the merchant API or provider webhooks.
+type RateLimiter struct {
+	classes map[string]*TokenBuckets
+	now     func() time.Time
+}
+
+// NewRateLimiter enforces limits by route class. Classes without a limit,
+// or with a zero rate or burst, are not throttled.
+func NewRateLimiter(limits map[string]RateLimit) *RateLimiter {
+	classes := make(map[string]*TokenBuckets, len(limits))
+	for class, limit := range limits {
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			continue
+		}
+		classes[class] = NewTokenBuckets(limit)
+	}
+	return &RateLimiter{classes: classes, now: time.Now}
+}
+
+// Limit throttles the routes it is added to with the limit of class. It
+// must run after authentication for limits counted by API key. Responses
+// carry RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset; throttled
+// requests get a 429 problem response with Retry-After.
+func (l *RateLimiter) Limit(class string) gin.HandlerFunc {
+	buckets, ok := l.classes[class]
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+	limit := buckets.limit
+
+	return func(c *gin.Context) {
+		by, key := rateLimitKey(c, limit.By)
+		allowed, remaining, wait := buckets.Take(by+":"+key, l.now())
+
+		// seconds until the bucket is full again, or until the next request
+		// is let through once it is empty
+		reset := time.Duration(float64(limit.Burst-remaining) / limit.Rate * float64(time.Second))
+		if !allowed {
+			reset = wait
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(buckets.refillTime())))
+
+		if allowed {
+			c.Next()
+			return
+		}
+
+		observability.RateLimited.WithLabelValues(class, by).Inc()
+
+		retryAfter := ceilSeconds(wait)
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.Header("Content-Type", "application/problem+json")
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"type":   "about:blank",
+			"title":  http.StatusText(http.StatusTooManyRequests),
+			"status": http.StatusTooManyRequests,
+			"detail": fmt.Sprintf("rate limit for %s exceeded, retry after %ds", class, retryAfter),
+		})
+	}
+}
+
+// rateLimitKey picks the bucket a request is counted in and reports what it
+// was keyed by.
+func rateLimitKey(c *gin.Context, by string) (string, string) {
+	switch by {
+	case RateLimitByRoute:
+		return RateLimitByRoute, c.Request.Method + " " + c.FullPath()
+	case RateLimitByAPIKey:
+		if subject := c.GetString("subject"); subject != "" {
+			return RateLimitByAPIKey, subject
+		}
+	}
+	return RateLimitByIP, c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
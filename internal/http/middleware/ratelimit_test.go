@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTokenBuckets_Take(t *testing.T) {
+	b := NewTokenBuckets(RateLimit{Rate: 2, Burst: 3})
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, want := range []int{2, 1, 0} {
+		ok, remaining, _ := b.Take("k", now)
+		if !ok || remaining != want {
+			t.Fatalf("take %d: expected ok with %d left, got %v %d", i+1, want, ok, remaining)
+		}
+	}
+
+	ok, _, wait := b.Take("k", now)
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("expected an empty bucket to wait 500ms, got %v %s", ok, wait)
+	}
+
+	// other keys have their own bucket
+	if ok, _, _ := b.Take("other", now); !ok {
+		t.Fatalf("expected another key to be let through")
+	}
+
+	// refilled at the rate, never above the burst
+	if ok, remaining, _ := b.Take("k", now.Add(500*time.Millisecond)); !ok || remaining != 0 {
+		t.Fatalf("expected one token after 500ms, got %v %d", ok, remaining)
+	}
+	if ok, remaining, _ := b.Take("k", now.Add(time.Hour)); !ok || remaining != 2 {
+		t.Fatalf("expected a full bucket after an hour, got %v %d", ok, remaining)
+	}
+}
+
+// limitedEngine serves /limited behind the limit of class "test", with the
+// caller authenticated as the subject in the X-Test-Subject header.
+func limitedEngine(limit RateLimit, now func() time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	l := NewRateLimiter(map[string]RateLimit{"test": limit})
+	l.now = now
+
+	r := gin.New()
+	_ = r.SetTrustedProxies(nil)
+	r.Use(func(c *gin.Context) {
+		if subject := c.GetHeader("X-Test-Subject"); subject != "" {
+			c.Set("subject", subject)
+		}
+	})
+	r.GET("/limited", l.Limit("test"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.GET("/other", l.Limit("test"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.GET("/unlimited", l.Limit("none"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func limitedRequest(r *gin.Engine, path, remoteAddr string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_Headers(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := limitedEngine(RateLimit{Rate: 0.5, Burst: 2, By: RateLimitByIP}, func() time.Time { return now })
+
+	w := limitedRequest(r, "/limited", "10.0.0.1:1234", nil)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected the first request through, got %d", w.Code)
+	}
+	for header, want := range map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "1",
+		"RateLimit-Reset":     "2",
+		"RateLimit-Policy":    "2;w=4",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Fatalf("expected %s %q, got %q", header, want, got)
+		}
+	}
+	if w.Header().Get("Retry-After") != "" {
+		t.Fatalf("Retry-After is only sent on throttled requests")
+	}
+
+	limitedRequest(r, "/limited", "10.0.0.1:1234", nil)
+	w = limitedRequest(r, "/limited", "10.0.0.1:1234", nil)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+	if got := w.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Fatalf("expected no requests remaining, got %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Fatalf("expected a problem response, got %q", got)
+	}
+
+	// classes without a limit are not throttled
+	for range 5 {
+		if w := limitedRequest(r, "/unlimited", "10.0.0.1:1234", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected unlimited route through, got %d", w.Code)
+		}
+	}
+}
+
+func TestRateLimiter_Keys(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("api key", func(t *testing.T) {
+		r := limitedEngine(RateLimit{Rate: 1, Burst: 1, By: RateLimitByAPIKey}, clock)
+
+		if w := limitedRequest(r, "/limited", "10.0.0.1:1", map[string]string{"X-Test-Subject": "api_key:1"}); w.Code != http.StatusNoContent {
+			t.Fatalf("expected first key through, got %d", w.Code)
+		}
+		// the same key from another address shares the bucket
+		if w := limitedRequest(r, "/limited", "10.0.0.2:1", map[string]string{"X-Test-Subject": "api_key:1"}); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected the key to be throttled, got %d", w.Code)
+		}
+		// another key from the same address does not
+		if w := limitedRequest(r, "/limited", "10.0.0.1:1", map[string]string{"X-Test-Subject": "api_key:2"}); w.Code != http.StatusNoContent {
+			t.Fatalf("expected another key through, got %d", w.Code)
+		}
+		// unauthenticated requests fall back to the address
+		if w := limitedRequest(r, "/limited", "10.0.0.1:1", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected an anonymous caller through, got %d", w.Code)
+		}
+		if w := limitedRequest(r, "/limited", "10.0.0.1:1", nil); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected the address to be throttled, got %d", w.Code)
+		}
+	})
+
+	t.Run("ip", func(t *testing.T) {
+		r := limitedEngine(RateLimit{Rate: 1, Burst: 1, By: RateLimitByIP}, clock)
+
+		limitedRequest(r, "/limited", "10.0.0.1:1", map[string]string{"X-Test-Subject": "api_key:1"})
+		if w := limitedRequest(r, "/limited", "10.0.0.1:2", map[string]string{"X-Test-Subject": "api_key:2"}); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected keys on one address to share a bucket, got %d", w.Code)
+		}
+		if w := limitedRequest(r, "/limited", "10.0.0.2:1", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected another address through, got %d", w.Code)
+		}
+	})
+
+	t.Run("spoofed forwarded for", func(t *testing.T) {
+		r := limitedEngine(RateLimit{Rate: 1, Burst: 1, By: RateLimitByIP}, clock)
+
+		limitedRequest(r, "/limited", "10.0.0.1:1", map[string]string{"X-Forwarded-For": "1.1.1.1"})
+		w := limitedRequest(r, "/limited", "10.0.0.1:1", map[string]string{"X-Forwarded-For": "2.2.2.2"})
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("a new X-Forwarded-For must not get a new bucket, got %d", w.Code)
+		}
+	})
+
+	t.Run("route", func(t *testing.T) {
+		r := limitedEngine(RateLimit{Rate: 1, Burst: 1, By: RateLimitByRoute}, clock)
+
+		limitedRequest(r, "/limited", "10.0.0.1:1", nil)
+		if w := limitedRequest(r, "/limited", "10.0.0.2:1", nil); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected callers of a route to share a bucket, got %d", w.Code)
+		}
+		if w := limitedRequest(r, "/other", "10.0.0.2:1", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected another route through, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testSigningSecret = "s3cret"
+
+func signingEngine(s *RequestSigning) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(s.Middleware("/exempt"))
+	handler := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	r.POST("/signed", handler)
+	r.POST("/upload", handler)
+	r.POST("/exempt", handler)
+	return r
+}
+
+func signedRequest(uri string, body []byte, ts time.Time, nonce string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, uri, bytes.NewReader(body))
+	req.Header.Set(HeaderSignatureKeyID, "k1")
+	req.Header.Set(HeaderSignatureTimestamp, strconv.FormatInt(ts.Unix(), 10))
+	req.Header.Set(HeaderSignatureNonce, nonce)
+	req.Header.Set(HeaderSignature, Sign(testSigningSecret, http.MethodPost, uri, ts.Unix(), nonce, body))
+	return req
+}
+
+// countingReader records whether the body was read at all.
+type countingReader struct {
+	io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestRequestSigning(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"amount":1000}`)
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want int
+	}{
+		{
+			name: "valid signature",
+			req:  func() *http.Request { return signedRequest("/signed", body, now, "n1") },
+			want: http.StatusOK,
+		},
+		{
+			name: "tampered body",
+			req: func() *http.Request {
+				req := signedRequest("/signed", body, now, "n2")
+				req.Body = io.NopCloser(strings.NewReader(`{"amount":9000}`))
+				return req
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "tampered path",
+			req: func() *http.Request {
+				req := signedRequest("/signed", body, now, "n3")
+				req.URL.RawQuery = "refund=1"
+				return req
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "stale timestamp",
+			req:  func() *http.Request { return signedRequest("/signed", body, now.Add(-6*time.Minute), "n4") },
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "future timestamp",
+			req:  func() *http.Request { return signedRequest("/signed", body, now.Add(6*time.Minute), "n5") },
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "unknown key",
+			req: func() *http.Request {
+				req := signedRequest("/signed", body, now, "n6")
+				req.Header.Set(HeaderSignatureKeyID, "k2")
+				return req
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "missing headers",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/signed", bytes.NewReader(body))
+			},
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "exempt route",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/exempt", bytes.NewReader(body))
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "body over the default limit",
+			req: func() *http.Request {
+				return signedRequest("/signed", bytes.Repeat([]byte("a"), maxSignedBody+1), now, "n7")
+			},
+			want: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "upload within its own limit",
+			req: func() *http.Request {
+				return signedRequest("/upload", bytes.Repeat([]byte("a"), maxSignedBody+1), now, "n8")
+			},
+			want: http.StatusOK,
+		},
+	}
+
+	s := NewRequestSigning(map[string]string{"k1": testSigningSecret}, 5*time.Minute).
+		WithBodyLimit("/upload", 2<<20)
+	s.now = func() time.Time { return now }
+	r := signingEngine(s)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, tt.req())
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestSigning_ReplayedNonce(t *testing.T) {
+	now := time.Now()
+	s := NewRequestSigning(map[string]string{"k1": testSigningSecret}, 5*time.Minute)
+	r := signingEngine(s)
+
+	for i, want := range []int{http.StatusOK, http.StatusUnauthorized} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, signedRequest("/signed", []byte("{}"), now, "same"))
+		if w.Code != want {
+			t.Fatalf("attempt %d: expected %d, got %d", i+1, want, w.Code)
+		}
+	}
+
+	// a badly signed request must not use up the nonce of a real caller
+	bad := signedRequest("/signed", []byte("{}"), now, "fresh")
+	bad.Header.Set(HeaderSignature, strings.Repeat("0", 64))
+	r.ServeHTTP(httptest.NewRecorder(), bad)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, signedRequest("/signed", []byte("{}"), now, "fresh"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the real request to pass, got %d", w.Code)
+	}
+}
+
+func TestRequestSigning_HeadersCheckedBeforeBody(t *testing.T) {
+	s := NewRequestSigning(map[string]string{"k1": testSigningSecret}, 5*time.Minute)
+	r := signingEngine(s)
+
+	body := &countingReader{Reader: bytes.NewReader(bytes.Repeat([]byte("a"), 4096))}
+	req := httptest.NewRequest(http.MethodPost, "/signed", body)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", w.Code)
+	}
+	if body.read != 0 {
+		t.Fatalf("expected the body to be left unread, %d bytes were read", body.read)
+	}
+
+	// a declared length over the limit is refused without reading either
+	body = &countingReader{Reader: bytes.NewReader(nil)}
+	req = signedRequest("/signed", nil, time.Now(), "n1")
+	req.Body = io.NopCloser(body)
+	req.ContentLength = maxSignedBody + 1
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge || body.read != 0 {
+		t.Fatalf("expected 413 without reading, got %d after %d bytes", w.Code, body.read)
+	}
+}
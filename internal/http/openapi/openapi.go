@@ -0,0 +1,184 @@
+// Package openapi holds the OpenAPI 3 document of the /v1 API and validates
+// requests, and in tests responses, against it.
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+// Spec is the loaded API document.
+type Spec struct {
+	doc    *openapi3.T
+	router routers.Router
+	json   []byte
+
+	validateResponses bool
+}
+
+// Load parses and checks the embedded document.
+func Load() (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	return &Spec{doc: doc, router: router, json: data}, nil
+}
+
+// WithResponseValidation makes the middleware check responses too. It
+// buffers every response, so it is meant for tests and test deployments.
+func (s *Spec) WithResponseValidation() *Spec {
+	s.validateResponses = true
+	return s
+}
+
+// Document is the parsed document.
+func (s *Spec) Document() *openapi3.T {
+	return s.doc
+}
+
+// Version is the document's info.version.
+func (s *Spec) Version() string {
+	return s.doc.Info.Version
+}
+
+// ServeJSON serves the document as JSON.
+func (s *Spec) ServeJSON(c *gin.Context) {
+	c.Header("X-API-Version", s.Version())
+	c.Data(http.StatusOK, "application/json; charset=utf-8", s.json)
+}
+
+func (s *Spec) options() *openapi3filter.Options {
+	return &openapi3filter.Options{
+		// authentication is left to the auth middlewares
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		// defaults are applied by the handlers, not written into requests
+		SkipSettingDefaults: true,
+	}
+}
+
+// Middleware rejects requests that do not match the document with 400.
+// Requests to paths the document does not describe are let through; the
+// drift test keeps the document and the router in step.
+func (s *Spec) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route, params, err := s.router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: params,
+			Route:      route,
+			Options:    s.options(),
+		}
+		if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": requestError(err)})
+			return
+		}
+
+		// streams are sent as they are written and cannot be held back
+		if !s.validateResponses || isEventStream(route) {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		status := w.ResponseWriter.Status()
+		err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 status,
+			Header:                 w.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(w.body.Bytes())),
+			Options:                s.options(),
+		})
+		if err != nil {
+			log.Printf("openapi: %s %s: response does not match the document: %v", c.Request.Method, route.Path, err)
+			w.Header().Del("Content-Length")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "response does not match the API document: " + err.Error(),
+			})
+			return
+		}
+		if w.body.Len() > 0 {
+			_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		}
+	}
+}
+
+// isEventStream reports whether the operation answers with Server-Sent
+// Events.
+func isEventStream(route *routers.Route) bool {
+	ok := route.Operation.Responses.Status(http.StatusOK)
+	return ok != nil && ok.Value != nil && ok.Value.Content.Get("text/event-stream") != nil
+}
+
+// requestError is the message of a validation error without the schema
+// dump kin-openapi appends to it.
+func requestError(err error) string {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return err.Error()
+	}
+	var schemaErr *openapi3.SchemaError
+	if errors.As(reqErr.Err, &schemaErr) {
+		msg := schemaErr.Reason
+		if path := schemaErr.JSONPointer(); len(path) > 0 {
+			msg = strings.Join(path, ".") + ": " + msg
+		}
+		if reqErr.Parameter != nil {
+			return fmt.Sprintf("parameter %q: %s", reqErr.Parameter.Name, msg)
+		}
+		return "request body: " + msg
+	}
+	return reqErr.Error()
+}
+
+// bufferedWriter holds the response back until it has been validated.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,180 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"payment-service/internal/core/domain"
+	"payment-service/internal/http/middleware"
+	"payment-service/internal/http/openapi"
+	"payment-service/internal/http/router"
+)
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// TestDocumentMatchesRouter fails when a /v1 route is added, removed or
+// renamed without updating openapi.yaml, or the other way round. What the
+// handlers answer is checked in handler.TestResponses_MatchDocument.
+func TestDocumentMatchesRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	r := gin.New()
+	router.Register(r, router.Deps{
+		RequestSigning: middleware.NewRequestSigning(nil, time.Minute),
+		RateLimiter:    middleware.NewRateLimiter(nil),
+		Spec:           spec,
+	})
+
+	routes := map[string]bool{}
+	for _, rt := range r.Routes() {
+		if strings.HasPrefix(rt.Path, "/v1/") {
+			routes[rt.Method+" "+ginParam.ReplaceAllString(rt.Path, "{$1}")] = true
+		}
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec.Document().Paths.Map() {
+		for method := range item.Operations() {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for _, op := range sortedKeys(routes) {
+		if !documented[op] {
+			t.Errorf("%s is routed but not in openapi.yaml", op)
+		}
+	}
+	for _, op := range sortedKeys(documented) {
+		if !routes[op] {
+			t.Errorf("%s is in openapi.yaml but not routed", op)
+		}
+	}
+}
+
+func TestMiddleware_ValidatesRequestsAndResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	payment := gin.H{
+		"payment_id": "pay_1", "order_id": "o", "payer_id": 1, "amount": 100,
+		"currency": "IDR", "status": "PENDING", "provider": "FAKE", "method": "qr",
+		"mode": "live", "created_at": "2026-01-02T03:04:05Z",
+	}
+
+	r := gin.New()
+	v1 := r.Group("/v1", spec.WithResponseValidation().Middleware())
+	v1.POST("/payers", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	v1.GET("/payments/:public_id", func(c *gin.Context) {
+		if c.Param("public_id") == "pay_broken" {
+			c.JSON(http.StatusOK, gin.H{"payment_id": "pay_broken"})
+			return
+		}
+		c.JSON(http.StatusOK, payment)
+	})
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"valid response", http.MethodGet, "/v1/payments/pay_1", "", http.StatusOK},
+		{"response missing fields", http.MethodGet, "/v1/payments/pay_broken", "", http.StatusInternalServerError},
+		{"request missing field", http.MethodPost, "/v1/payers", `{"name":"a","email":"a@b.c"}`, http.StatusBadRequest},
+		{"request with wrong type", http.MethodPost, "/v1/payers", `{"name":"a","email":"a@b.c","country":7}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d: %s", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestRouter_ValidatesAfterAuthentication checks that a malformed request
+// from a caller without a key is answered 401, not 400, and that requests
+// over the limit are throttled before their body is looked at.
+func TestRouter_ValidatesAfterAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	merchantAuth := func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer sk_test" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing api key"})
+			return
+		}
+		principal := domain.Principal{Subject: "api_key:1", Scopes: domain.MerchantScopes}
+		c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), principal))
+		c.Set("subject", principal.Subject)
+	}
+
+	r := gin.New()
+	router.Register(r, router.Deps{
+		MerchantAuth:   merchantAuth,
+		RequestSigning: middleware.NewRequestSigning(nil, time.Minute),
+		RateLimiter: middleware.NewRateLimiter(map[string]middleware.RateLimit{
+			"merchant": {Rate: 0.001, Burst: 2, By: middleware.RateLimitByAPIKey},
+		}),
+		Spec: spec,
+	})
+
+	cases := []struct {
+		name string
+		auth string
+		want int
+	}{
+		{"no api key", "", http.StatusUnauthorized},
+		{"authenticated", "Bearer sk_test", http.StatusBadRequest},
+		{"authenticated again", "Bearer sk_test", http.StatusBadRequest},
+		{"over the limit", "Bearer sk_test", http.StatusTooManyRequests},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/v1/payers", strings.NewReader(`{"name":7}`))
+		req.Header.Set("Content-Type", "application/json")
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d: %s", tc.name, tc.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
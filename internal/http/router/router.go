@@ -3,16 +3,203 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 
+	"payment-service/internal/core/domain"
 	"payment-service/internal/http/handler"
+	"payment-service/internal/http/middleware"
+	"payment-service/internal/http/openapi"
 )
 
-func Register(r *gin.Engine, paymentHandler *handler.PaymentHandler) {
-	v1 := r.Group("/v1")
+// NewEngine returns the engine the routes are registered on. The client
+// address is only taken from X-Forwarded-For when the request comes through
+// one of trustedProxies, so callers cannot pick their own rate limit bucket
+// or get around the risk blocklist by sending the header themselves.
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Deps is what the routes are served by.
+type Deps struct {
+	Payments       *handler.PaymentHandler
+	PaymentEvents  *handler.PaymentEventsHandler
+	PaymentBatches *handler.PaymentBatchHandler
+	Payers         *handler.PayerHandler
+	PaymentMethods *handler.PaymentMethodHandler
+	Subscriptions  *handler.SubscriptionHandler
+	PaymentLinks   *handler.PaymentLinkHandler
+	Checkout       *handler.CheckoutHandler
+	Webhooks       *handler.WebhookHandler
+	EWallet        *handler.EWalletHandler
+	QR             *handler.QRHandler
+	ThreeDS        *handler.ThreeDSHandler
+	Reviews        *handler.ReviewHandler
+	Disputes       *handler.DisputeHandler
+	Payouts        *handler.PayoutHandler
+	Merchants      *handler.MerchantHandler
+
+	// MerchantAuth authenticates the merchant API and AdminAuth the admin
+	// API.
+	MerchantAuth   gin.HandlerFunc
+	AdminAuth      gin.HandlerFunc
+	RequestSigning *middleware.RequestSigning
+	RateLimiter    *middleware.RateLimiter
+	// Spec is the API document requests are validated against.
+	Spec *openapi.Spec
+}
+
+func Register(r *gin.Engine, d Deps) {
+	r.GET("/openapi.json", d.Spec.ServeJSON)
+
+	v1 := r.Group("/v1", d.RequestSigning.Middleware(
+		// payers' browsers and payment providers cannot sign requests;
+		// the pages are reached by unguessable payment ids and callbacks
+		// carry their own tokens
+		"/v1/payments/:public_id/return",
+		"/v1/payments/:public_id/qr",
+		"/v1/payments/:public_id/3ds/complete",
+		"/v1/webhooks/bank_transfer",
+		"/v1/webhooks/ewallet",
+		"/v1/webhooks/qr",
+		"/v1/webhooks/disputes",
+	))
+
+	// requests are checked against the API document only once the caller
+	// is authenticated and within its rate limits
+	validate := d.Spec.Middleware()
+
+	// pages the payer is sent to by a wallet, QR or 3-D Secure flow
+	payerPages := v1.Group("/payments", d.RateLimiter.Limit("public"), validate)
 	{
-		payments := v1.Group("/payments")
+		payerPages.GET("/:public_id/return", d.EWallet.Return)
+		payerPages.GET("/:public_id/qr", d.QR.Get)
+		payerPages.GET("/:public_id/3ds/complete", d.ThreeDS.Complete)
+		payerPages.POST("/:public_id/3ds/complete", d.ThreeDS.Complete)
+	}
+
+	read := middleware.RequireScope(domain.ScopePaymentsRead)
+	write := middleware.RequireScope(domain.ScopePaymentsWrite)
+
+	// merchant API, scoped to the merchant owning the API key or named by
+	// the service token
+	authenticated := v1.Group("", d.MerchantAuth, d.RateLimiter.Limit("merchant"))
+	merchant := authenticated.Group("", validate)
+	{
+		creates := authenticated.Group("/payments", write, d.RateLimiter.Limit("payments_create"), validate)
 		{
-			payments.POST("", paymentHandler.Create)
-			payments.GET("/:public_id", paymentHandler.Get)
+			creates.POST("", d.Payments.Create)
+			creates.POST("/batch", d.PaymentBatches.Create)
+			creates.POST("/imports", d.PaymentBatches.Import)
 		}
+
+		payments := merchant.Group("/payments")
+		{
+			payments.GET("/batch/:batch_id", read, d.PaymentBatches.Get)
+			payments.GET("/imports/:import_id", read, d.PaymentBatches.GetImport)
+			payments.GET("/imports/:import_id/result", read, d.PaymentBatches.ImportResult)
+			payments.GET("/:public_id", read, d.Payments.Get)
+			payments.GET("/:public_id/events", read, d.PaymentEvents.Stream)
+			payments.GET("/:public_id/disputes", read, d.Disputes.ListByPayment)
+		}
+
+		payers := merchant.Group("/payers")
+		{
+			payers.POST("", write, d.Payers.Create)
+			payers.GET("", read, d.Payers.List)
+			payers.GET("/:id", read, d.Payers.Get)
+			payers.PUT("/:id", write, d.Payers.Update)
+			payers.DELETE("/:id", write, d.Payers.Delete)
+			payers.GET("/:id/payments", read, d.Payers.ListPayments)
+			payers.GET("/:id/payment_methods", read, d.PaymentMethods.ListByPayer)
+		}
+
+		paymentMethods := merchant.Group("/payment_methods")
+		{
+			paymentMethods.POST("/cards", write, d.PaymentMethods.CreateCard)
+			paymentMethods.GET("/:token", read, d.PaymentMethods.Get)
+			paymentMethods.DELETE("/:token", write, d.PaymentMethods.Detach)
+		}
+
+		plans := merchant.Group("/plans")
+		{
+			plans.POST("", write, d.Subscriptions.CreatePlan)
+			plans.GET("", read, d.Subscriptions.ListPlans)
+			plans.GET("/:plan_id", read, d.Subscriptions.GetPlan)
+		}
+
+		subscriptions := merchant.Group("/subscriptions")
+		{
+			subscriptions.POST("", write, d.Subscriptions.Create)
+			subscriptions.GET("/:subscription_id", read, d.Subscriptions.Get)
+			subscriptions.POST("/:subscription_id/pause", write, d.Subscriptions.Pause)
+			subscriptions.POST("/:subscription_id/resume", write, d.Subscriptions.Resume)
+			subscriptions.POST("/:subscription_id/cancel", write, d.Subscriptions.Cancel)
+		}
+
+		paymentLinks := merchant.Group("/payment_links")
+		{
+			paymentLinks.POST("", write, d.PaymentLinks.Create)
+			paymentLinks.GET("/:link_id", read, d.PaymentLinks.Get)
+			paymentLinks.POST("/:link_id/deactivate", write, d.PaymentLinks.Deactivate)
+		}
+
+		disputes := merchant.Group("/disputes")
+		{
+			disputes.GET("/:dispute_id", read, d.Disputes.Get)
+			disputes.POST("/:dispute_id/evidence", write, d.Disputes.UploadEvidence)
+			disputes.POST("/:dispute_id/submit", write, d.Disputes.Submit)
+		}
+
+		merchant.GET("/balance", read, d.Payouts.Balances)
+
+		payouts := merchant.Group("/payouts")
+		{
+			payouts.POST("", write, d.Payouts.Create)
+			payouts.GET("", read, d.Payouts.List)
+			payouts.GET("/:payout_id", read, d.Payouts.Get)
+		}
+
+		apiKeys := merchant.Group("/api_keys")
+		{
+			apiKeys.GET("", read, d.Merchants.ListKeys)
+			apiKeys.POST("/:key_id/rotate", write, d.Merchants.RotateKey)
+		}
+	}
+
+	// provider callbacks, authenticated by their own tokens
+	webhooks := v1.Group("/webhooks", d.RateLimiter.Limit("webhooks"), validate)
+	{
+		webhooks.POST("/bank_transfer", d.Webhooks.BankTransfer)
+		webhooks.POST("/ewallet", d.EWallet.Callback)
+		webhooks.POST("/qr", d.QR.Webhook)
+		webhooks.POST("/disputes", d.Disputes.Webhook)
+	}
+
+	// limited before authentication so admin tokens cannot be guessed at
+	// full speed
+	admin := v1.Group("/admin", d.RateLimiter.Limit("admin"), d.AdminAuth, middleware.RequireScope(domain.ScopeAdmin), validate)
+	{
+		// manual review queue
+		reviews := admin.Group("/reviews")
+		{
+			reviews.GET("", d.Reviews.List)
+			reviews.GET("/:public_id", d.Reviews.Get)
+			reviews.POST("/:public_id/approve", d.Reviews.Approve)
+			reviews.POST("/:public_id/reject", d.Reviews.Reject)
+		}
+
+		admin.POST("/merchants", d.Merchants.Create)
+		admin.POST("/merchants/:merchant_id/api_keys", d.Merchants.IssueKey)
+		admin.DELETE("/api_keys/:key_id", d.Merchants.RevokeKey)
+	}
+
+	// hosted checkout pages
+	checkout := r.Group("/pay", d.RateLimiter.Limit("public"))
+	{
+		checkout.GET("/:link_id", d.Checkout.Show)
+		checkout.POST("/:link_id", d.Checkout.Submit)
+		checkout.GET("/:link_id/result", d.Checkout.Result)
 	}
 }
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewEngine_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		remote  string
+		want    string
+	}{
+		{"no trusted proxies", nil, "10.0.0.1:1234", "10.0.0.1"},
+		{"untrusted peer", []string{"192.168.0.0/16"}, "10.0.0.1:1234", "10.0.0.1"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.1:1234", "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewEngine(tt.proxies)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if got := w.Body.String(); got != tt.want {
+				t.Fatalf("expected client %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := NewEngine([]string{"not-an-ip"}); err == nil {
+		t.Fatalf("expected an invalid proxy to be rejected")
+	}
+}
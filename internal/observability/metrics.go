@@ -38,6 +38,63 @@ var (
 		},
 		[]string{"operation"},
 	)
+
+	SubscriptionCharges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "subscription_charges_total",
+			Help: "Subscription billing attempts by result",
+		},
+		[]string{"result"},
+	)
+
+	RiskDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "risk_decisions_total",
+			Help: "Payments scored by the risk engine by decision",
+		},
+		[]string{"decision"},
+	)
+
+	LimitRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "spending_limit_rejections_total",
+			Help: "Payments rejected by spending limits by rule",
+		},
+		[]string{"rule"},
+	)
+
+	RateLimited = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_rate_limited_total",
+			Help: "Requests rejected by rate limits by route class and what the limit is keyed by",
+		},
+		[]string{"class", "key"},
+	)
+
+	GRPCRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of gRPC calls by method and status code",
+		},
+		[]string{"method", "code"},
+	)
+
+	GRPCDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "gRPC call latency, for streams the time the stream was open",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "code"},
+	)
+
+	GRPCStreamMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of messages sent on gRPC streams by method",
+		},
+		[]string{"method"},
+	)
 )
 
 func InitMetrics() {
@@ -45,4 +102,11 @@ func InitMetrics() {
 	prometheus.MustRegister(HTTPDuration)
 	prometheus.MustRegister(DBQueryDuration)
 	prometheus.MustRegister(DBErrors)
+	prometheus.MustRegister(SubscriptionCharges)
+	prometheus.MustRegister(RiskDecisions)
+	prometheus.MustRegister(LimitRejections)
+	prometheus.MustRegister(RateLimited)
+	prometheus.MustRegister(GRPCRequests)
+	prometheus.MustRegister(GRPCDuration)
+	prometheus.MustRegister(GRPCStreamMessages)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -72,14 +73,44 @@ func newTracerProvider(ctx context.Context) (*traceSDK.TracerProvider, error) {
 		return nil, err
 	}
 
+	exporter = &statusExporter{SpanExporter: traceExporter}
 	tracerProvider := traceSDK.NewTracerProvider(
-		traceSDK.WithBatcher(traceExporter,
+		traceSDK.WithBatcher(exporter,
 			// Default is 5s. Set to 1s for demonstrative purposes.
 			traceSDK.WithBatchTimeout(time.Second)),
 	)
 	return tracerProvider, nil
 }
 
+// statusExporter remembers whether the latest span export went through.
+type statusExporter struct {
+	traceSDK.SpanExporter
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+func (e *statusExporter) ExportSpans(ctx context.Context, spans []traceSDK.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.mu.Lock()
+	e.lastErr = err
+	e.mu.Unlock()
+	return err
+}
+
+var exporter *statusExporter
+
+// TraceExporterStatus returns the error of the latest span export, nil when
+// it succeeded or nothing was exported yet.
+func TraceExporterStatus() error {
+	if exporter == nil {
+		return errors.New("tracing is not set up")
+	}
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	return exporter.lastErr
+}
+
 var tracer trace.Tracer
 
 func InitTracer(serviceName string) {
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"payment-service/internal/core/usecase"
+)
+
+// BatchScheduler periodically creates the payments of submitted batches.
+type BatchScheduler struct {
+	processUC *usecase.ProcessPaymentBatchesUsecase
+	interval  time.Duration
+}
+
+func NewBatchScheduler(
+	processUC *usecase.ProcessPaymentBatchesUsecase,
+	interval time.Duration,
+) *BatchScheduler {
+	return &BatchScheduler{
+		processUC: processUC,
+		interval:  interval,
+	}
+}
+
+// Run blocks until ctx is canceled. A run already started is finished
+// first, so no item is left between its provider call and its result.
+func (s *BatchScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			out, err := s.processUC.Execute(context.WithoutCancel(ctx), now)
+			if err != nil {
+				log.Printf("payment batch run failed: %v", err)
+			}
+			if out != nil && out.Created+out.Duplicate+out.Failed+out.Retrying+out.Completed > 0 {
+				log.Printf(
+					"payment batch run: created=%d duplicate=%d failed=%d retrying=%d completed=%d",
+					out.Created,
+					out.Duplicate,
+					out.Failed,
+					out.Retrying,
+					out.Completed,
+				)
+			}
+		}
+	}
+}
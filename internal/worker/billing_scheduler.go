@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"payment-service/internal/core/usecase"
+)
+
+// BillingScheduler periodically charges due subscriptions.
+type BillingScheduler struct {
+	billUC   *usecase.BillSubscriptionsUsecase
+	interval time.Duration
+}
+
+func NewBillingScheduler(
+	billUC *usecase.BillSubscriptionsUsecase,
+	interval time.Duration,
+) *BillingScheduler {
+	return &BillingScheduler{
+		billUC:   billUC,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is canceled, after finishing the current run so no
+// subscription is charged without its renewal being recorded.
+func (s *BillingScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			out, err := s.billUC.Execute(context.WithoutCancel(ctx), now)
+			if err != nil {
+				log.Printf("billing run failed: %v", err)
+			}
+			if out != nil && out.Charged+out.Failed > 0 {
+				log.Printf(
+					"billing run: charged=%d failed=%d canceled=%d",
+					out.Charged,
+					out.Failed,
+					out.Canceled,
+				)
+			}
+		}
+	}
+}
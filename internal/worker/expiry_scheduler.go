@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"payment-service/internal/core/usecase"
+)
+
+// ExpiryScheduler periodically expires payments that were never paid.
+type ExpiryScheduler struct {
+	expireUC *usecase.ExpirePaymentsUsecase
+	interval time.Duration
+}
+
+func NewExpiryScheduler(
+	expireUC *usecase.ExpirePaymentsUsecase,
+	interval time.Duration,
+) *ExpiryScheduler {
+	return &ExpiryScheduler{
+		expireUC: expireUC,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is canceled. The run in progress is finished first.
+func (s *ExpiryScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, err := s.expireUC.Execute(context.WithoutCancel(ctx), now)
+			if err != nil {
+				log.Printf("expiry run failed: %v", err)
+			}
+			if expired > 0 {
+				log.Printf("expiry run: expired=%d", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"payment-service/internal/core/usecase"
+)
+
+// PayoutScheduler periodically makes due payouts and follows up on those
+// in transit.
+type PayoutScheduler struct {
+	runUC    *usecase.RunPayoutsUsecase
+	interval time.Duration
+}
+
+func NewPayoutScheduler(
+	runUC *usecase.RunPayoutsUsecase,
+	interval time.Duration,
+) *PayoutScheduler {
+	return &PayoutScheduler{
+		runUC:    runUC,
+		interval: interval,
+	}
+}
+
+// Run blocks until ctx is canceled. The current run is finished first so
+// payouts sent to the provider are recorded.
+func (s *PayoutScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			out, err := s.runUC.Execute(context.WithoutCancel(ctx), now)
+			if err != nil {
+				log.Printf("payout run failed: %v", err)
+			}
+			if out != nil && out.Scheduled+out.Sent+out.Paid+out.Failed > 0 {
+				log.Printf(
+					"payout run: scheduled=%d sent=%d paid=%d failed=%d",
+					out.Scheduled,
+					out.Sent,
+					out.Paid,
+					out.Failed,
+				)
+			}
+		}
+	}
+}